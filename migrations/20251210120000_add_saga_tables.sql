@@ -0,0 +1,32 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS saga_instances (
+    order_id TEXT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    total BIGINT NOT NULL,
+    products JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    current_step VARCHAR(50) NOT NULL DEFAULT '',
+    step_status VARCHAR(30) NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saga_instances_status_updated ON saga_instances(status, updated_at);
+
+CREATE TABLE IF NOT EXISTS saga_steps (
+    id BIGSERIAL PRIMARY KEY,
+    order_id TEXT NOT NULL REFERENCES saga_instances(order_id) ON DELETE CASCADE,
+    step VARCHAR(50) NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saga_steps_order ON saga_steps(order_id, id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_saga_steps_order;
+DROP TABLE IF EXISTS saga_steps;
+DROP INDEX IF EXISTS idx_saga_instances_status_updated;
+DROP TABLE IF EXISTS saga_instances;
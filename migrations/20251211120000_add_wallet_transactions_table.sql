@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS wallet_transactions (
+    transaction_id TEXT PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES wallets(user_id),
+    amount BIGINT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_user ON wallet_transactions(user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_wallet_transactions_user;
+DROP TABLE IF EXISTS wallet_transactions;
@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS product_reservations (
+    order_id TEXT NOT NULL,
+    product_id BIGINT NOT NULL REFERENCES products(productID),
+    qty INT NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (order_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_reservations_status_expires ON product_reservations(status, expires_at) WHERE status = 'RESERVED';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_product_reservations_status_expires;
+DROP TABLE IF EXISTS product_reservations;
@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE wallet_transactions ADD COLUMN IF NOT EXISTS expires_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_reserved_expiry ON wallet_transactions(status, expires_at) WHERE status = 'RESERVED';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_wallet_transactions_reserved_expiry;
+ALTER TABLE wallet_transactions DROP COLUMN IF EXISTS expires_at;
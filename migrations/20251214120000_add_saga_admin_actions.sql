@@ -0,0 +1,21 @@
+-- +goose Up
+-- Журнал действий операторов над сагами; без FK - отклонённые действия
+-- по несуществующим заказам тоже пишутся
+CREATE TABLE IF NOT EXISTS saga_admin_actions (
+    id BIGSERIAL PRIMARY KEY,
+    order_id TEXT NOT NULL,
+    action VARCHAR(30) NOT NULL,
+    operator TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    result VARCHAR(20) NOT NULL,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saga_admin_actions_order ON saga_admin_actions(order_id, id);
+CREATE INDEX IF NOT EXISTS idx_saga_admin_actions_created ON saga_admin_actions(created_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_saga_admin_actions_created;
+DROP INDEX IF EXISTS idx_saga_admin_actions_order;
+DROP TABLE IF EXISTS saga_admin_actions;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Неудачные оформления заказа, о которых cart-service узнал из OrderFailed
+CREATE TABLE IF NOT EXISTS checkout_failures (
+    order_id TEXT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    message TEXT NOT NULL DEFAULT '',
+    total BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_checkout_failures_user ON checkout_failures(user_id, created_at DESC);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_checkout_failures_user;
+DROP TABLE IF EXISTS checkout_failures;
@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS attempts INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS last_error TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- Раньше failed был конечным статусом: такие события уже пробовали отправить один раз
+UPDATE outbox SET attempts = 1 WHERE status = 'failed';
+
+DROP INDEX IF EXISTS idx_outbox_status_created;
+CREATE INDEX IF NOT EXISTS idx_outbox_due ON outbox(next_attempt_at) WHERE status IN ('pending', 'failed');
+CREATE INDEX IF NOT EXISTS idx_outbox_dead ON outbox(created_at) WHERE status = 'dead';
+
+-- Действие над событием outbox, а не над сагой
+ALTER TABLE saga_admin_actions ADD COLUMN IF NOT EXISTS event_id BIGINT;
+
+-- +goose Down
+ALTER TABLE saga_admin_actions DROP COLUMN IF EXISTS event_id;
+
+DROP INDEX IF EXISTS idx_outbox_dead;
+DROP INDEX IF EXISTS idx_outbox_due;
+CREATE INDEX IF NOT EXISTS idx_outbox_status_created ON outbox(status, created_at) WHERE status = 'pending';
+
+UPDATE outbox SET status = 'failed' WHERE status = 'dead';
+
+ALTER TABLE outbox
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS last_error,
+    DROP COLUMN IF EXISTS attempts;
@@ -0,0 +1,10 @@
+-- +goose Up
+-- Аренда событий outbox: реплика, захватившая пачку, публикует её одна, пока не истечёт locked_until
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS locked_by TEXT,
+    ADD COLUMN IF NOT EXISTS locked_until TIMESTAMP WITH TIME ZONE;
+
+-- +goose Down
+ALTER TABLE outbox
+    DROP COLUMN IF EXISTS locked_until,
+    DROP COLUMN IF EXISTS locked_by;
@@ -0,0 +1,24 @@
+-- +goose Up
+-- Архив отправленных событий outbox. Секции по месяцам создаёт задача архивации:
+-- старые месяцы удаляются целиком через DROP секции, без DELETE по строкам.
+CREATE TABLE IF NOT EXISTS outbox_archive (
+    id BIGINT NOT NULL,
+    aggregate_id TEXT NOT NULL,
+    aggregate_type VARCHAR(50) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE,
+    processed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, processed_at)
+) PARTITION BY RANGE (processed_at);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_archive_aggregate ON outbox_archive(aggregate_id);
+
+-- Выборка кандидатов в архив
+CREATE INDEX IF NOT EXISTS idx_outbox_processed ON outbox(processed_at) WHERE status = 'processed';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_processed;
+DROP TABLE IF EXISTS outbox_archive;
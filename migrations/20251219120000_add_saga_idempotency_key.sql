@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE saga_instances ADD COLUMN IF NOT EXISTS idempotency_key TEXT;
+
+-- Повтор checkout с тем же ключом возвращает уже созданный заказ, а не создаёт новый
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saga_instances_idempotency
+    ON saga_instances(user_id, idempotency_key)
+    WHERE idempotency_key IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_saga_instances_idempotency;
+ALTER TABLE saga_instances DROP COLUMN IF EXISTS idempotency_key;
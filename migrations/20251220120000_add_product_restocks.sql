@@ -0,0 +1,17 @@
+-- +goose Up
+-- Возвраты списанного товара на склад (отмена заказа, возврат). restock_id делает операцию
+-- идемпотентной, а сумма по order_id не может превысить списанное по заказу
+CREATE TABLE IF NOT EXISTS product_restocks (
+    restock_id TEXT NOT NULL,
+    order_id TEXT NOT NULL,
+    product_id BIGINT NOT NULL REFERENCES products(productID),
+    qty INT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (restock_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_restocks_order ON product_restocks(order_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_product_restocks_order;
+DROP TABLE IF EXISTS product_restocks;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Отмена заказа продолжает сагу оформления в той же строке: kind показывает, какая сага идёт сейчас,
+-- а история шагов остаётся общей для заказа
+ALTER TABLE saga_instances ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'checkout';
+
+-- +goose Down
+ALTER TABLE saga_instances DROP COLUMN IF EXISTS kind;
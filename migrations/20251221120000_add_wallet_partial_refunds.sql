@@ -0,0 +1,9 @@
+-- +goose Up
+-- A partial refund is its own REFUNDED transaction pointing at the committed transaction it refunds
+ALTER TABLE wallet_transactions ADD COLUMN IF NOT EXISTS parent_transaction_id TEXT REFERENCES wallet_transactions(transaction_id);
+
+CREATE INDEX IF NOT EXISTS idx_wallet_transactions_parent ON wallet_transactions(parent_transaction_id) WHERE parent_transaction_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_wallet_transactions_parent;
+ALTER TABLE wallet_transactions DROP COLUMN IF EXISTS parent_transaction_id;
@@ -0,0 +1,26 @@
+-- +goose Up
+-- A return covers some items of a delivered order. Its amount is filled in once the refund is done.
+CREATE TABLE IF NOT EXISTS order_returns (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'REQUESTED',
+    reason TEXT NOT NULL DEFAULT '',
+    amount BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_returns_order ON order_returns(order_id);
+
+CREATE TABLE IF NOT EXISTS order_return_items (
+    return_id UUID NOT NULL REFERENCES order_returns(id) ON DELETE CASCADE,
+    product_id BIGINT NOT NULL,
+    quantity INT NOT NULL,
+    PRIMARY KEY (return_id, product_id)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS order_return_items;
+DROP INDEX IF EXISTS idx_order_returns_order;
+DROP TABLE IF EXISTS order_returns;
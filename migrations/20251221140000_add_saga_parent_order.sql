@@ -0,0 +1,9 @@
+-- +goose Up
+-- Возврат товаров идёт отдельной сагой с ID возврата в order_id; parent_order_id - заказ, к которому он относится
+ALTER TABLE saga_instances ADD COLUMN IF NOT EXISTS parent_order_id TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_saga_instances_parent_order ON saga_instances(parent_order_id) WHERE parent_order_id IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_saga_instances_parent_order;
+ALTER TABLE saga_instances DROP COLUMN IF EXISTS parent_order_id;
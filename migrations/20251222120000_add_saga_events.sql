@@ -0,0 +1,30 @@
+-- +goose Up
+-- Хронология заказа: все переходы саг, неудачные попытки вызовов и действия операторов.
+-- Только дописывается; order_id - заказ покупателя (для саги возврата - исходный заказ),
+-- saga_id - сага, к которой относится событие. Без FK - хронология переживает удаление саги.
+CREATE TABLE IF NOT EXISTS saga_events (
+    id BIGSERIAL PRIMARY KEY,
+    order_id TEXT NOT NULL,
+    saga_id TEXT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    step VARCHAR(50) NOT NULL DEFAULT '',
+    status VARCHAR(30) NOT NULL DEFAULT '',
+    attempt INT NOT NULL DEFAULT 0,
+    detail TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_saga_events_order ON saga_events(order_id, id);
+
+-- Переносим уже записанную историю шагов, чтобы у старых заказов хронология не была пустой
+INSERT INTO saga_events (order_id, saga_id, kind, type, step, status, error, created_at)
+SELECT COALESCE(i.parent_order_id, i.order_id), s.order_id, i.kind, 'step', s.step, s.status, s.error, s.created_at
+FROM saga_steps s
+JOIN saga_instances i ON i.order_id = s.order_id
+ORDER BY s.id;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_saga_events_order;
+DROP TABLE IF EXISTS saga_events;
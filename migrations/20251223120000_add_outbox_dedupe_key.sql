@@ -0,0 +1,10 @@
+-- +goose Up
+-- Ключ дедупликации события: сага, повторившая шаг outbox после рестарта, не пишет событие второй раз.
+-- В архив ключ не переносится - шаг повторяется сразу после рестарта, задолго до архивации.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS dedupe_key TEXT;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_outbox_dedupe_key ON outbox(dedupe_key) WHERE dedupe_key IS NOT NULL;
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_dedupe_key;
+ALTER TABLE outbox DROP COLUMN IF EXISTS dedupe_key;
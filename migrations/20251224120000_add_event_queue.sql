@@ -0,0 +1,27 @@
+-- +goose Up
+-- Очередь событий в Postgres - транспорт для локальных запусков без Kafka (EVENT_TRANSPORT=postgres).
+-- Сообщения только дописываются; каждая группа подписчиков хранит в event_queue_offsets
+-- id последнего подтверждённого сообщения топика, как offset в Kafka.
+CREATE TABLE IF NOT EXISTS event_queue (
+    id BIGSERIAL PRIMARY KEY,
+    topic TEXT NOT NULL,
+    msg_key TEXT NOT NULL DEFAULT '',
+    headers JSONB NOT NULL DEFAULT '{}',
+    payload BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_event_queue_topic ON event_queue(topic, id);
+
+CREATE TABLE IF NOT EXISTS event_queue_offsets (
+    group_name TEXT NOT NULL,
+    topic TEXT NOT NULL,
+    last_id BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_name, topic)
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS event_queue_offsets;
+DROP INDEX IF EXISTS idx_event_queue_topic;
+DROP TABLE IF EXISTS event_queue;
@@ -0,0 +1,30 @@
+-- +goose Up
+-- Каталог: категория и бренд для фильтров, tsvector для полнотекстового поиска по названию и описанию.
+-- Конфигурация 'simple' без стемминга: в каталоге смешаны русские и английские названия.
+ALTER TABLE products ADD COLUMN IF NOT EXISTS category VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS brand VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE products ADD COLUMN IF NOT EXISTS search_vector TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', COALESCE(productName, '')), 'A') ||
+    setweight(to_tsvector('simple', COALESCE(productDescription, '')), 'B')
+) STORED;
+
+-- Курсор сортировки по новизне сравнивает created_at, поэтому NULL в нём быть не должно
+UPDATE products SET created_at = to_timestamp(0) WHERE created_at IS NULL;
+ALTER TABLE products ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_products_search ON products USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_products_category ON products (category);
+CREATE INDEX IF NOT EXISTS idx_products_brand ON products (brand);
+CREATE INDEX IF NOT EXISTS idx_products_price ON products (productPrice, productID);
+CREATE INDEX IF NOT EXISTS idx_products_created ON products (created_at, productID);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_products_created;
+DROP INDEX IF EXISTS idx_products_price;
+DROP INDEX IF EXISTS idx_products_brand;
+DROP INDEX IF EXISTS idx_products_category;
+DROP INDEX IF EXISTS idx_products_search;
+ALTER TABLE products ALTER COLUMN created_at DROP NOT NULL;
+ALTER TABLE products DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE products DROP COLUMN IF EXISTS brand;
+ALTER TABLE products DROP COLUMN IF EXISTS category;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Причина провала checkout для пользователя: insufficient_funds, out_of_stock или internal_error.
+-- В error остаётся полная цепочка ошибок - она только для операторов.
+ALTER TABLE saga_instances ADD COLUMN IF NOT EXISTS failure_reason VARCHAR(50) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE saga_instances DROP COLUMN IF EXISTS failure_reason;
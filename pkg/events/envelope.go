@@ -0,0 +1,103 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SpecVersion - версия спецификации CloudEvents, по которой собран конверт
+const SpecVersion = "1.0"
+
+// ContentType - конверт целиком лежит в теле сообщения (structured mode)
+const ContentType = "application/cloudevents+json"
+
+// LegacyVersion - версия схемы событий, отправленных без конверта: тело - это сразу данные
+const LegacyVersion = 0
+
+// Заголовки Kafka по CloudEvents Kafka binding. По ним consumer узнаёт тип и версию события,
+// не разбирая тело.
+const (
+	HeaderID            = "ce_id"
+	HeaderSpecVersion   = "ce_specversion"
+	HeaderType          = "ce_type"
+	HeaderSource        = "ce_source"
+	HeaderTime          = "ce_time"
+	HeaderSchemaVersion = "ce_schemaversion"
+	HeaderContentType   = "content-type"
+)
+
+// ErrUnsupportedVersion - consumer не знает версию схемы события и не может привести её к своей
+var ErrUnsupportedVersion = errors.New("unsupported event schema version")
+
+// Envelope - событие в формате CloudEvents. SchemaVersion - расширение CloudEvents:
+// версия схемы Data для этого Type, меняется при любом несовместимом изменении полей.
+type Envelope struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Type            string          `json:"type"`
+	Source          string          `json:"source"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	SchemaVersion   int             `json:"schemaversion"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// New заворачивает data в конверт. id должен быть одинаковым у повторов одного события,
+// чтобы consumer мог их отбросить.
+func New(id, eventType, source string, version int, at time.Time, data any) (Envelope, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to marshal %s data: %w", eventType, err)
+	}
+	return Envelope{
+		SpecVersion:     SpecVersion,
+		ID:              id,
+		Type:            eventType,
+		Source:          source,
+		Time:            at.UTC(),
+		DataContentType: "application/json",
+		SchemaVersion:   version,
+		Data:            raw,
+	}, nil
+}
+
+// Decode разбирает тело сообщения. Тело без specversion - событие старого формата без конверта:
+// оно возвращается как Data с LegacyVersion, чтобы consumer сам привёл его к текущей схеме.
+func Decode(payload []byte) (Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return Envelope{}, fmt.Errorf("failed to decode event: %w", err)
+	}
+	if envelope.SpecVersion == "" {
+		return Envelope{SchemaVersion: LegacyVersion, Data: payload}, nil
+	}
+	if envelope.SpecVersion != SpecVersion {
+		return Envelope{}, fmt.Errorf("unsupported cloudevents specversion %q", envelope.SpecVersion)
+	}
+	return envelope, nil
+}
+
+// Header - заголовок сообщения
+type Header struct {
+	Key   string
+	Value string
+}
+
+// Headers - заголовки сообщения с атрибутами конверта; у события без конверта их нет
+func (e Envelope) Headers() []Header {
+	if e.SpecVersion == "" {
+		return nil
+	}
+	return []Header{
+		{Key: HeaderID, Value: e.ID},
+		{Key: HeaderSpecVersion, Value: e.SpecVersion},
+		{Key: HeaderType, Value: e.Type},
+		{Key: HeaderSource, Value: e.Source},
+		{Key: HeaderTime, Value: e.Time.Format(time.RFC3339Nano)},
+		{Key: HeaderSchemaVersion, Value: strconv.Itoa(e.SchemaVersion)},
+		{Key: HeaderContentType, Value: ContentType},
+	}
+}
@@ -0,0 +1,64 @@
+package events
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvelope(t *testing.T) {
+	at := time.Date(2025, 12, 23, 12, 0, 0, 0, time.UTC)
+	data := map[string]any{"order_id": "order-1"}
+
+	t.Run("Round Trip", func(t *testing.T) {
+		envelope, err := New("order-1:OrderCompleted", "OrderCompleted", "saga-orchestrator", 1, at, data)
+		require.NoError(t, err)
+		payload, err := json.Marshal(envelope)
+		require.NoError(t, err)
+
+		decoded, err := Decode(payload)
+
+		require.NoError(t, err)
+		assert.Equal(t, SpecVersion, decoded.SpecVersion)
+		assert.Equal(t, "order-1:OrderCompleted", decoded.ID)
+		assert.Equal(t, "OrderCompleted", decoded.Type)
+		assert.Equal(t, 1, decoded.SchemaVersion)
+		assert.True(t, at.Equal(decoded.Time))
+		assert.JSONEq(t, `{"order_id":"order-1"}`, string(decoded.Data))
+	})
+
+	t.Run("Payload Without Envelope Is Legacy", func(t *testing.T) {
+		payload := []byte(`{"order_id":"order-1","event_type":"OrderCompleted"}`)
+
+		decoded, err := Decode(payload)
+
+		require.NoError(t, err)
+		assert.Equal(t, LegacyVersion, decoded.SchemaVersion)
+		assert.Equal(t, payload, []byte(decoded.Data))
+		assert.Nil(t, decoded.Headers())
+	})
+
+	t.Run("Unknown Spec Version", func(t *testing.T) {
+		_, err := Decode([]byte(`{"specversion":"0.3","type":"OrderCompleted"}`))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Headers Carry Type And Version", func(t *testing.T) {
+		envelope, err := New("order-1:OrderCompleted", "OrderCompleted", "saga-orchestrator", 2, at, data)
+		require.NoError(t, err)
+
+		headers := make(map[string]string)
+		for _, h := range envelope.Headers() {
+			headers[h.Key] = h.Value
+		}
+
+		assert.Equal(t, "OrderCompleted", headers[HeaderType])
+		assert.Equal(t, "2", headers[HeaderSchemaVersion])
+		assert.Equal(t, "order-1:OrderCompleted", headers[HeaderID])
+		assert.Equal(t, ContentType, headers[HeaderContentType])
+	})
+}
@@ -0,0 +1,225 @@
+//go:build cgo
+
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"go.uber.org/zap"
+)
+
+// kafkaReadTimeout - сколько подписчик ждёт сообщение, прежде чем снова проверить ctx
+const kafkaReadTimeout = 100 * time.Millisecond
+
+var errDeliveryTimeout = errors.New("kafka delivery report timeout")
+
+func (c KafkaConfig) configMap(log *zap.SugaredLogger, values kafka.ConfigMap) *kafka.ConfigMap {
+	config := kafka.ConfigMap{"bootstrap.servers": c.Brokers}
+	for key, value := range values {
+		config[key] = value
+	}
+
+	// Add SASL/SSL configuration if credentials are provided (for Yandex Cloud Kafka)
+	if c.SASLUsername != "" && c.SASLPassword != "" {
+		config["security.protocol"] = c.SecurityProtocol
+		config["sasl.mechanism"] = c.SASLMechanism
+		config["sasl.username"] = c.SASLUsername
+		config["sasl.password"] = c.SASLPassword
+		if c.SSLCAPath != "" {
+			config["ssl.ca.location"] = c.SSLCAPath
+		}
+
+		log.Infow("Kafka configured with SASL/SSL",
+			"security.protocol", c.SecurityProtocol,
+			"sasl.mechanism", c.SASLMechanism,
+			"ssl.ca.location", c.SSLCAPath,
+		)
+	} else {
+		log.Info("Kafka configured without SASL/SSL (local mode)")
+	}
+	return &config
+}
+
+// producer - часть kafka.Producer, которой пользуется KafkaPublisher
+type producer interface {
+	Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error
+	Close()
+}
+
+// KafkaPublisher отправляет сообщения в Kafka и ждёт отчётов о доставке
+type KafkaPublisher struct {
+	producer producer
+	log      *zap.SugaredLogger
+	// deliveryTimeout - сколько ждём отчётов о доставке пачки, прежде чем считать остаток неотправленным
+	deliveryTimeout time.Duration
+}
+
+func NewKafkaPublisher(cfg KafkaConfig, log *zap.SugaredLogger) (*KafkaPublisher, error) {
+	p, err := kafka.NewProducer(cfg.configMap(log, kafka.ConfigMap{
+		"acks":               "all",
+		"retries":            10,
+		"enable.idempotence": true,
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return &KafkaPublisher{
+		producer:        p,
+		log:             log,
+		deliveryTimeout: KafkaDeliveryTimeout,
+	}, nil
+}
+
+// Publish отправляет все сообщения без ожидания между ними и собирает отчёты о доставке.
+// Сообщение, отчёт по которому не пришёл за deliveryTimeout, считается неотправленным.
+func (p *KafkaPublisher) Publish(ctx context.Context, topic string, msgs []Message) []error {
+	errs := make([]error, len(msgs))
+	deliveryChan := make(chan kafka.Event, len(msgs))
+	waiting := 0
+
+	for i, msg := range msgs {
+		err := p.producer.Produce(&kafka.Message{
+			TopicPartition: kafka.TopicPartition{
+				Topic:     &topic,
+				Partition: kafka.PartitionAny,
+			},
+			Key:     []byte(msg.Key),
+			Value:   msg.Value,
+			Headers: kafkaHeaders(msg.Headers),
+			Opaque:  i,
+		}, deliveryChan)
+		if err != nil {
+			p.log.Errorw("failed to produce message", "error", err, "topic", topic, "key", msg.Key)
+			errs[i] = err
+			continue
+		}
+		// Пока отчёт не пришёл, сообщение считается неотправленным
+		errs[i] = errDeliveryTimeout
+		waiting++
+	}
+
+	timer := time.NewTimer(p.deliveryTimeout)
+	defer timer.Stop()
+
+	for waiting > 0 {
+		select {
+		case e := <-deliveryChan:
+			m, ok := e.(*kafka.Message)
+			if !ok {
+				p.log.Warnw("unexpected event type from delivery channel", "event", e)
+				continue
+			}
+			i, ok := m.Opaque.(int)
+			if !ok || i < 0 || i >= len(errs) {
+				p.log.Warnw("delivery report for unknown message", "key", string(m.Key))
+				continue
+			}
+			waiting--
+			errs[i] = m.TopicPartition.Error
+			if m.TopicPartition.Error != nil {
+				p.log.Errorw("kafka delivery failed", "error", m.TopicPartition.Error, "topic", topic, "key", msgs[i].Key)
+				continue
+			}
+			p.log.Debugw("kafka delivery confirmed",
+				"topic", topic,
+				"key", msgs[i].Key,
+				"partition", m.TopicPartition.Partition,
+				"offset", m.TopicPartition.Offset,
+			)
+		case <-timer.C:
+			p.log.Errorw("timed out waiting for kafka delivery reports", "missing", waiting, "batch", len(msgs))
+			return errs
+		case <-ctx.Done():
+			p.log.Warnw("kafka batch interrupted", "missing", waiting, "batch", len(msgs))
+			return errs
+		}
+	}
+	return errs
+}
+
+func (p *KafkaPublisher) Close() error {
+	p.producer.Close()
+	return nil
+}
+
+// KafkaSubscriber читает топик в consumer group и коммитит offset после обработки сообщения
+type KafkaSubscriber struct {
+	consumer *kafka.Consumer
+	log      *zap.SugaredLogger
+}
+
+func NewKafkaSubscriber(cfg KafkaConfig, group string, log *zap.SugaredLogger) (*KafkaSubscriber, error) {
+	c, err := kafka.NewConsumer(cfg.configMap(log, kafka.ConfigMap{
+		"group.id":          group,
+		"auto.offset.reset": "earliest",
+	}))
+	if err != nil {
+		return nil, err
+	}
+	return &KafkaSubscriber{consumer: c, log: log}, nil
+}
+
+// Subscribe коммитит offset только после того, как обработчик подтвердил сообщение.
+// Неподтверждённое сообщение не коммитится и придёт снова после рестарта или ребалансировки группы.
+func (s *KafkaSubscriber) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	if err := s.consumer.SubscribeTopics([]string{topic}, nil); err != nil {
+		return err
+	}
+
+	s.log.Infow("Kafka consumer started", "topic", topic)
+	for {
+		select {
+		case <-ctx.Done():
+			s.log.Infow("Kafka consumer stopped", "topic", topic)
+			return nil
+		default:
+		}
+
+		msg, err := s.consumer.ReadMessage(kafkaReadTimeout)
+		if err != nil {
+			if kafkaErr, ok := err.(kafka.Error); ok && kafkaErr.Code() == kafka.ErrTimedOut {
+				continue
+			}
+			s.log.Errorw("Error reading message", "error", err)
+			continue
+		}
+		s.log.Debugw("Received message",
+			"topic", topic,
+			"partition", msg.TopicPartition.Partition,
+			"offset", msg.TopicPartition.Offset,
+			"key", string(msg.Key),
+		)
+
+		message := Message{Key: string(msg.Key), Value: msg.Value, Headers: make(map[string]string, len(msg.Headers))}
+		for _, h := range msg.Headers {
+			message.Headers[h.Key] = string(h.Value)
+		}
+		if err := handler(ctx, message); err != nil {
+			// НЕ коммитим offset при ошибке - сообщение будет обработано повторно
+			continue
+		}
+
+		// Коммитим offset только после успешной обработки
+		if _, err := s.consumer.CommitMessage(msg); err != nil {
+			s.log.Errorw("Error committing offset", "error", err, "key", string(msg.Key))
+		}
+	}
+}
+
+func (s *KafkaSubscriber) Close() error {
+	return s.consumer.Close()
+}
+
+func kafkaHeaders(headers map[string]string) []kafka.Header {
+	if len(headers) == 0 {
+		return nil
+	}
+	result := make([]kafka.Header, 0, len(headers))
+	for key, value := range headers {
+		result = append(result, kafka.Header{Key: key, Value: []byte(value)})
+	}
+	return result
+}
@@ -0,0 +1,21 @@
+//go:build !cgo
+
+package pubsub
+
+import (
+	"errors"
+
+	"go.uber.org/zap"
+)
+
+// Клиент Kafka (librdkafka) собирается только с cgo. Без него сервис собирается
+// и работает на транспортах postgres и memory, а выбор kafka возвращает ошибку.
+var errKafkaWithoutCgo = errors.New("kafka transport requires a build with cgo enabled")
+
+func NewKafkaPublisher(KafkaConfig, *zap.SugaredLogger) (Publisher, error) {
+	return nil, errKafkaWithoutCgo
+}
+
+func NewKafkaSubscriber(KafkaConfig, string, *zap.SugaredLogger) (Subscriber, error) {
+	return nil, errKafkaWithoutCgo
+}
@@ -0,0 +1,92 @@
+//go:build cgo
+
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+// fakeProducer сразу кладёт отчёт о доставке в канал, как librdkafka из своего потока
+type fakeProducer struct {
+	produceErr  map[string]error // ключ сообщения -> ошибка Produce
+	deliveryErr map[string]error // ключ сообщения -> ошибка в отчёте о доставке
+	lost        map[string]bool  // отчёт о доставке не придёт
+	produced    []*kafka.Message
+}
+
+func (f *fakeProducer) Produce(msg *kafka.Message, deliveryChan chan kafka.Event) error {
+	key := string(msg.Key)
+	if err := f.produceErr[key]; err != nil {
+		return err
+	}
+	f.produced = append(f.produced, msg)
+	if !f.lost[key] {
+		report := *msg
+		report.TopicPartition.Error = f.deliveryErr[key]
+		deliveryChan <- &report
+	}
+	return nil
+}
+
+func (f *fakeProducer) Close() {}
+
+func newTestKafkaPublisher(producer *fakeProducer) *KafkaPublisher {
+	return &KafkaPublisher{
+		producer:        producer,
+		log:             zap.NewNop().Sugar(),
+		deliveryTimeout: 100 * time.Millisecond,
+	}
+}
+
+func TestKafkaPublisher_Publish(t *testing.T) {
+	t.Run("All Delivered", func(t *testing.T) {
+		producer := &fakeProducer{}
+		publisher := newTestKafkaPublisher(producer)
+
+		errs := publisher.Publish(context.Background(), "orders", []Message{
+			{Key: "order-1", Value: []byte(`{}`), Headers: map[string]string{"ce_type": "OrderCompleted"}},
+			{Key: "order-2", Value: []byte(`{}`)},
+		})
+
+		assert.Equal(t, []error{nil, nil}, errs)
+		if assert.Len(t, producer.produced, 2) {
+			assert.Equal(t, "orders", *producer.produced[0].TopicPartition.Topic)
+			assert.Equal(t, []kafka.Header{{Key: "ce_type", Value: []byte("OrderCompleted")}}, producer.produced[0].Headers)
+			assert.Nil(t, producer.produced[1].Headers)
+		}
+	})
+
+	t.Run("Failures Reported Per Message", func(t *testing.T) {
+		producer := &fakeProducer{
+			produceErr:  map[string]error{"order-1": errors.New("queue full")},
+			deliveryErr: map[string]error{"order-2": kafka.NewError(kafka.ErrMsgTimedOut, "timed out", false)},
+			lost:        map[string]bool{"order-3": true},
+		}
+		publisher := newTestKafkaPublisher(producer)
+
+		errs := publisher.Publish(context.Background(), "orders", messages("order-1", "order-2", "order-3", "order-4"))
+
+		assert.EqualError(t, errs[0], "queue full")
+		assert.Error(t, errs[1])
+		assert.ErrorIs(t, errs[2], errDeliveryTimeout)
+		assert.NoError(t, errs[3])
+	})
+
+	t.Run("Nothing Delivered Before Timeout", func(t *testing.T) {
+		producer := &fakeProducer{lost: map[string]bool{"order-1": true, "order-2": true}}
+		publisher := newTestKafkaPublisher(producer)
+
+		errs := publisher.Publish(context.Background(), "orders", messages("order-1", "order-2"))
+
+		for _, err := range errs {
+			assert.ErrorIs(t, err, errDeliveryTimeout)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package pubsub
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// defaultRetryInterval - пауза перед повтором сообщения, если интервал не задан в конфиге
+const defaultRetryInterval = time.Second
+
+// Broker - брокер внутри процесса для локальных запусков и тестов. Топик - журнал сообщений
+// в памяти, у каждой группы подписчиков своя позиция в нём, как offset в Kafka.
+// Сообщения не переживают рестарт процесса.
+type Broker struct {
+	mu     sync.Mutex
+	topics map[string]*memoryTopic
+	groups map[memoryGroupKey]*memoryGroup
+	// retryInterval - через сколько подписчик повторяет сообщение, которое обработчик не подтвердил
+	retryInterval time.Duration
+	log           *zap.SugaredLogger
+}
+
+type memoryTopic struct {
+	msgs []Message
+	// notify закрывается при публикации и заменяется новым - так ждущие подписчики просыпаются все сразу
+	notify chan struct{}
+}
+
+type memoryGroupKey struct {
+	group string
+	topic string
+}
+
+type memoryGroup struct {
+	// turn - очередь подписок группы: сообщение обрабатывает та, что держит turn
+	turn   chan struct{}
+	offset int
+}
+
+func NewBroker(retryInterval time.Duration, log *zap.SugaredLogger) *Broker {
+	if retryInterval <= 0 {
+		retryInterval = defaultRetryInterval
+	}
+	return &Broker{
+		topics:        make(map[string]*memoryTopic),
+		groups:        make(map[memoryGroupKey]*memoryGroup),
+		retryInterval: retryInterval,
+		log:           log,
+	}
+}
+
+// Publish дописывает сообщения в журнал топика; сохранены они сразу, поэтому ошибок нет
+func (b *Broker) Publish(ctx context.Context, topic string, msgs []Message) []error {
+	errs := make([]error, len(msgs))
+	if err := ctx.Err(); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := b.topic(topic)
+	for _, msg := range msgs {
+		// Копия, чтобы publisher мог переиспользовать свои буферы
+		msg.Value = slices.Clone(msg.Value)
+		t.msgs = append(t.msgs, msg)
+	}
+	close(t.notify)
+	t.notify = make(chan struct{})
+	return errs
+}
+
+func (b *Broker) Close() error {
+	return nil
+}
+
+// Subscriber возвращает подписчика в группе group. Подписки одной группы делят позицию
+// в топике, и каждое сообщение обрабатывает одна из них.
+func (b *Broker) Subscriber(group string) Subscriber {
+	return &memorySubscriber{broker: b, group: group}
+}
+
+// topic вызывается под b.mu
+func (b *Broker) topic(name string) *memoryTopic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &memoryTopic{notify: make(chan struct{})}
+		b.topics[name] = t
+	}
+	return t
+}
+
+func (b *Broker) group(group, topic string) *memoryGroup {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := memoryGroupKey{group: group, topic: topic}
+	g, ok := b.groups[key]
+	if !ok {
+		g = &memoryGroup{turn: make(chan struct{}, 1)}
+		b.groups[key] = g
+	}
+	return g
+}
+
+// next возвращает сообщение по offset, а если его ещё нет - канал, который закроется при публикации
+func (b *Broker) next(topic string, offset int) (Message, bool, <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := b.topic(topic)
+	if offset < len(t.msgs) {
+		return t.msgs[offset], true, nil
+	}
+	return Message{}, false, t.notify
+}
+
+type memorySubscriber struct {
+	broker *Broker
+	group  string
+}
+
+// Subscribe обрабатывает сообщения строго по порядку: неподтверждённое сообщение повторяется
+// через retryInterval, и следующие ждут его
+func (s *memorySubscriber) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	g := s.broker.group(s.group, topic)
+	s.broker.log.Infow("memory subscriber started", "topic", topic, "group", s.group)
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.broker.log.Infow("memory subscriber stopped", "topic", topic, "group", s.group)
+			return nil
+		case g.turn <- struct{}{}:
+		}
+
+		msg, ok, notify := s.broker.next(topic, g.offset)
+		if !ok {
+			<-g.turn
+			select {
+			case <-ctx.Done():
+			case <-notify:
+			}
+			continue
+		}
+
+		err := handler(ctx, msg)
+		if err == nil {
+			g.offset++
+		}
+		<-g.turn
+		if err != nil {
+			s.broker.log.Warnw("message not acknowledged, will retry", "topic", topic, "group", s.group, "key", msg.Key, "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(s.broker.retryInterval):
+			}
+		}
+	}
+}
+
+func (s *memorySubscriber) Close() error {
+	return nil
+}
@@ -0,0 +1,135 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// recorder - обработчик, запоминающий ключи подтверждённых сообщений
+type recorder struct {
+	mu   sync.Mutex
+	keys []string
+	// fail - сколько раз подряд не подтверждать сообщение с этим ключом
+	fail map[string]int
+}
+
+func (r *recorder) handle(_ context.Context, msg Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fail[msg.Key] > 0 {
+		r.fail[msg.Key]--
+		return errors.New("not ready")
+	}
+	r.keys = append(r.keys, msg.Key)
+	return nil
+}
+
+func (r *recorder) received() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.keys...)
+}
+
+func messages(keys ...string) []Message {
+	msgs := make([]Message, 0, len(keys))
+	for _, key := range keys {
+		msgs = append(msgs, Message{Key: key, Value: []byte(`{}`)})
+	}
+	return msgs
+}
+
+// subscribe запускает подписку до конца теста
+func subscribe(t *testing.T, subscriber Subscriber, topic string, handler Handler) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		assert.NoError(t, subscriber.Subscribe(ctx, topic, handler))
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestBroker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Delivers In Order Including Messages Published Before Subscribe", func(t *testing.T) {
+		broker := NewBroker(time.Millisecond, zap.NewNop().Sugar())
+		assert.Equal(t, []error{nil, nil}, broker.Publish(ctx, "orders", messages("order-1", "order-2")))
+
+		r := &recorder{}
+		subscribe(t, broker.Subscriber("cart"), "orders", r.handle)
+		broker.Publish(ctx, "orders", messages("order-3"))
+		broker.Publish(ctx, "payments", messages("payment-1"))
+
+		require.Eventually(t, func() bool { return len(r.received()) == 3 }, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"order-1", "order-2", "order-3"}, r.received())
+	})
+
+	t.Run("Unacknowledged Message Is Redelivered Before The Next One", func(t *testing.T) {
+		broker := NewBroker(time.Millisecond, zap.NewNop().Sugar())
+		r := &recorder{fail: map[string]int{"order-1": 3}}
+		subscribe(t, broker.Subscriber("cart"), "orders", r.handle)
+
+		broker.Publish(ctx, "orders", messages("order-1", "order-2"))
+
+		require.Eventually(t, func() bool { return len(r.received()) == 2 }, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"order-1", "order-2"}, r.received())
+	})
+
+	t.Run("Each Group Gets Every Message Once", func(t *testing.T) {
+		broker := NewBroker(time.Millisecond, zap.NewNop().Sugar())
+		cart, audit := &recorder{}, &recorder{}
+		// Две подписки одной группы делят сообщения, другая группа получает все
+		subscribe(t, broker.Subscriber("cart"), "orders", cart.handle)
+		subscribe(t, broker.Subscriber("cart"), "orders", cart.handle)
+		subscribe(t, broker.Subscriber("audit"), "orders", audit.handle)
+
+		keys := make([]string, 0, 100)
+		for i := 0; i < 100; i++ {
+			keys = append(keys, fmt.Sprintf("order-%d", i))
+		}
+		broker.Publish(ctx, "orders", messages(keys...))
+
+		require.Eventually(t, func() bool {
+			return len(cart.received()) == len(keys) && len(audit.received()) == len(keys)
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, keys, cart.received())
+		assert.Equal(t, keys, audit.received())
+	})
+
+	t.Run("Resubscribe Continues From Group Offset", func(t *testing.T) {
+		broker := NewBroker(time.Millisecond, zap.NewNop().Sugar())
+		broker.Publish(ctx, "orders", messages("order-1"))
+
+		first := &recorder{}
+		subCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			//nolint:errcheck // подписка памяти не возвращает ошибок
+			_ = broker.Subscriber("cart").Subscribe(subCtx, "orders", first.handle)
+		}()
+		require.Eventually(t, func() bool { return len(first.received()) == 1 }, time.Second, time.Millisecond)
+		cancel()
+		<-done
+
+		broker.Publish(ctx, "orders", messages("order-2"))
+		second := &recorder{}
+		subscribe(t, broker.Subscriber("cart"), "orders", second.handle)
+
+		require.Eventually(t, func() bool { return len(second.received()) == 1 }, time.Second, time.Millisecond)
+		assert.Equal(t, []string{"order-2"}, second.received())
+	})
+}
@@ -0,0 +1,217 @@
+package pubsub
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+)
+
+// postgresBatchSize - сколько сообщений подписчик берёт за один опрос
+const postgresBatchSize = 100
+
+// PostgresPublisher пишет сообщения в таблицу event_queue
+type PostgresPublisher struct {
+	db *sql.DB
+}
+
+func NewPostgresPublisher(db *sql.DB) *PostgresPublisher {
+	return &PostgresPublisher{db: db}
+}
+
+// Publish пишет пачку одной транзакцией, поэтому ошибка у всех сообщений пачки общая
+func (p *PostgresPublisher) Publish(ctx context.Context, topic string, msgs []Message) []error {
+	errs := make([]error, len(msgs))
+	if err := p.publish(ctx, topic, msgs); err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+	}
+	return errs
+}
+
+func (p *PostgresPublisher) publish(ctx context.Context, topic string, msgs []Message) (err error) {
+	keys := make([]string, len(msgs))
+	headers := make([]string, len(msgs))
+	payloads := make([][]byte, len(msgs))
+	for i, msg := range msgs {
+		h, err := json.Marshal(msg.Headers)
+		if err != nil {
+			return fmt.Errorf("failed to marshal headers: %w", err)
+		}
+		keys[i], headers[i], payloads[i] = msg.Key, string(h), msg.Value
+	}
+
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // откат после ошибки, исходная ошибка важнее
+			_ = tx.Rollback()
+		}
+	}()
+
+	// Публикации в топик идут по одной: id из последовательности выдаётся под блокировкой,
+	// и транзакции коммитятся в порядке id. Иначе подписчик мог бы сдвинуть offset за id,
+	// который ещё не закоммичен, и навсегда пропустить это сообщение.
+	if _, err = tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, topic); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO event_queue (topic, msg_key, headers, payload)
+		SELECT $1, m.msg_key, m.headers::jsonb, m.payload
+		FROM unnest($2::text[], $3::text[], $4::bytea[]) WITH ORDINALITY AS m(msg_key, headers, payload, n)
+		ORDER BY m.n
+	`, topic, pq.Array(keys), pq.Array(headers), pq.Array(payloads)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (p *PostgresPublisher) Close() error {
+	return nil
+}
+
+// PostgresSubscriber опрашивает event_queue и двигает offset группы после каждого подтверждённого сообщения
+type PostgresSubscriber struct {
+	db           *sql.DB
+	group        string
+	pollInterval time.Duration
+	log          *zap.SugaredLogger
+}
+
+func NewPostgresSubscriber(db *sql.DB, group string, pollInterval time.Duration, log *zap.SugaredLogger) *PostgresSubscriber {
+	if pollInterval <= 0 {
+		pollInterval = defaultRetryInterval
+	}
+	return &PostgresSubscriber{db: db, group: group, pollInterval: pollInterval, log: log}
+}
+
+// Subscribe обрабатывает сообщения по порядку id. Неподтверждённое сообщение останавливает
+// чтение топика до следующего опроса - тогда оно придёт повторно, а следующие за ним подождут.
+func (s *PostgresSubscriber) Subscribe(ctx context.Context, topic string, handler Handler) error {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	s.log.Infow("postgres subscriber started", "topic", topic, "group", s.group, "interval", s.pollInterval)
+	for {
+		more, err := s.poll(ctx, topic, handler)
+		if err != nil && ctx.Err() == nil {
+			s.log.Errorw("failed to poll event queue", "error", err, "topic", topic, "group", s.group)
+		}
+		// Полная пачка - в очереди, скорее всего, есть ещё: читаем сразу, не дожидаясь интервала
+		if more {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			s.log.Infow("postgres subscriber stopped", "topic", topic, "group", s.group)
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll обрабатывает пачку сообщений после offset группы и сохраняет новый offset.
+// Строка offset держится FOR UPDATE до конца транзакции, поэтому подписки одной группы
+// не обработают одно сообщение дважды. Возвращает true, если пачка была полной и подтверждена целиком.
+func (s *PostgresSubscriber) poll(ctx context.Context, topic string, handler Handler) (more bool, err error) {
+	// Транзакция без отмены: при остановке offset уже обработанных сообщений нужно записать,
+	// а отмена ctx откатила бы её целиком
+	saveCtx := context.WithoutCancel(ctx)
+	tx, err := s.db.BeginTx(saveCtx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		if err != nil {
+			//nolint:errcheck // откат после ошибки, исходная ошибка важнее
+			_ = tx.Rollback()
+		}
+	}()
+
+	if _, err = tx.ExecContext(ctx, `
+		INSERT INTO event_queue_offsets (group_name, topic) VALUES ($1, $2)
+		ON CONFLICT (group_name, topic) DO NOTHING
+	`, s.group, topic); err != nil {
+		return false, err
+	}
+	var offset int64
+	if err = tx.QueryRowContext(ctx, `
+		SELECT last_id FROM event_queue_offsets WHERE group_name = $1 AND topic = $2 FOR UPDATE
+	`, s.group, topic).Scan(&offset); err != nil {
+		return false, err
+	}
+
+	msgs, ids, err := s.fetch(ctx, tx, topic, offset)
+	if err != nil {
+		return false, err
+	}
+
+	acked := 0
+	for i, msg := range msgs {
+		if handleErr := handler(ctx, msg); handleErr != nil {
+			s.log.Warnw("message not acknowledged, will retry", "topic", topic, "group", s.group, "id", ids[i], "key", msg.Key, "error", handleErr)
+			break
+		}
+		offset = ids[i]
+		acked++
+	}
+
+	if acked > 0 {
+		if _, err = tx.ExecContext(saveCtx, `
+			UPDATE event_queue_offsets SET last_id = $3, updated_at = NOW() WHERE group_name = $1 AND topic = $2
+		`, s.group, topic, offset); err != nil {
+			return false, err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return false, err
+	}
+	return len(msgs) == postgresBatchSize && acked == len(msgs), nil
+}
+
+func (s *PostgresSubscriber) fetch(ctx context.Context, tx *sql.Tx, topic string, offset int64) ([]Message, []int64, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, msg_key, headers, payload
+		FROM event_queue
+		WHERE topic = $1 AND id > $2
+		ORDER BY id
+		LIMIT $3
+	`, topic, offset, postgresBatchSize)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	var (
+		msgs []Message
+		ids  []int64
+	)
+	for rows.Next() {
+		var (
+			id      int64
+			msg     Message
+			headers []byte
+		)
+		if err := rows.Scan(&id, &msg.Key, &headers, &msg.Value); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan event_queue row: %w", err)
+		}
+		if err := json.Unmarshal(headers, &msg.Headers); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode headers of message %d: %w", id, err)
+		}
+		msgs = append(msgs, msg)
+		ids = append(ids, id)
+	}
+	return msgs, ids, rows.Err()
+}
+
+func (s *PostgresSubscriber) Close() error {
+	return nil
+}
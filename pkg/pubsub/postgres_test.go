@@ -0,0 +1,149 @@
+package pubsub
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// openTestDB создаёт для теста отдельную схему с таблицами очереди.
+// Тесты с Postgres запускаются, только если задан PUBSUB_TEST_PG_DSN.
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("PUBSUB_TEST_PG_DSN")
+	if dsn == "" {
+		t.Skip("PUBSUB_TEST_PG_DSN is not set")
+	}
+
+	admin, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	schema := fmt.Sprintf("pubsub_test_%d", time.Now().UnixNano())
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE") //nolint:errcheck
+		admin.Close()
+	})
+
+	// search_path передаётся как параметр соединения, чтобы его получило каждое соединение пула
+	if strings.Contains(dsn, "://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn += sep + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+	db, err := sql.Open("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`
+		CREATE TABLE event_queue (
+			id BIGSERIAL PRIMARY KEY,
+			topic TEXT NOT NULL,
+			msg_key TEXT NOT NULL DEFAULT '',
+			headers JSONB NOT NULL DEFAULT '{}',
+			payload BYTEA NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+		);
+		CREATE TABLE event_queue_offsets (
+			group_name TEXT NOT NULL,
+			topic TEXT NOT NULL,
+			last_id BIGINT NOT NULL DEFAULT 0,
+			updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (group_name, topic)
+		)
+	`)
+	require.NoError(t, err)
+	return db
+}
+
+func TestPostgresQueue(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	publisher := NewPostgresPublisher(db)
+	log := zap.NewNop().Sugar()
+
+	t.Run("Delivers Messages With Headers In Order", func(t *testing.T) {
+		errs := publisher.Publish(ctx, "orders", []Message{
+			{Key: "order-1", Value: []byte(`{"n":1}`), Headers: map[string]string{"ce_type": "OrderCompleted"}},
+			{Key: "order-2", Value: []byte(`{"n":2}`)},
+		})
+		require.Equal(t, []error{nil, nil}, errs)
+
+		var (
+			mu  sync.Mutex
+			got []Message
+		)
+		subscribe(t, NewPostgresSubscriber(db, "cart", 10*time.Millisecond, log), "orders", func(_ context.Context, msg Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, msg)
+			return nil
+		})
+
+		require.Eventually(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+			return len(got) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, "order-1", got[0].Key)
+		assert.Equal(t, `{"n":1}`, string(got[0].Value))
+		assert.Equal(t, map[string]string{"ce_type": "OrderCompleted"}, got[0].Headers)
+		assert.Equal(t, "order-2", got[1].Key)
+	})
+
+	t.Run("Unacknowledged Message Is Redelivered", func(t *testing.T) {
+		publisher.Publish(ctx, "returns", messages("return-1", "return-2"))
+
+		r := &recorder{fail: map[string]int{"return-1": 2}}
+		subscribe(t, NewPostgresSubscriber(db, "cart", 10*time.Millisecond, log), "returns", r.handle)
+
+		require.Eventually(t, func() bool { return len(r.received()) == 2 }, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, []string{"return-1", "return-2"}, r.received())
+
+		var offset int64
+		require.NoError(t, db.QueryRow(`SELECT last_id FROM event_queue_offsets WHERE group_name = 'cart' AND topic = 'returns'`).Scan(&offset))
+		assert.NotZero(t, offset)
+	})
+
+	t.Run("Concurrent Subscribers Of A Group Share Messages", func(t *testing.T) {
+		const total, subscribers = 500, 4
+		keys := make([]string, 0, total)
+		for i := 0; i < total; i++ {
+			keys = append(keys, fmt.Sprintf("event-%d", i))
+		}
+		// Публикуем параллельно, чтобы проверить, что ни одно сообщение не пропадает за offset
+		var wg sync.WaitGroup
+		for i := 0; i < total; i += 50 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, err := range publisher.Publish(ctx, "events", messages(keys[i:i+50]...)) {
+					assert.NoError(t, err)
+				}
+			}()
+		}
+
+		r := &recorder{}
+		for i := 0; i < subscribers; i++ {
+			subscribe(t, NewPostgresSubscriber(db, "cart", 10*time.Millisecond, log), "events", r.handle)
+		}
+		wg.Wait()
+
+		require.Eventually(t, func() bool { return len(r.received()) >= total }, 10*time.Second, 10*time.Millisecond)
+		assert.ElementsMatch(t, keys, r.received())
+	})
+}
@@ -0,0 +1,111 @@
+package pubsub
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Транспорты событий, из которых сервис выбирает конфигом
+const (
+	TransportKafka    = "kafka"
+	TransportPostgres = "postgres"
+	// TransportMemory - брокер внутри процесса: события доходят только до подписчиков этого же процесса
+	TransportMemory = "memory"
+)
+
+// KafkaDeliveryTimeout - сколько Publish ждёт отчётов Kafka о доставке пачки
+const KafkaDeliveryTimeout = 5 * time.Second
+
+// KafkaConfig - подключение к Kafka; SASL/SSL включается, если заданы логин и пароль
+type KafkaConfig struct {
+	Brokers          string
+	SASLUsername     string
+	SASLPassword     string
+	SSLCAPath        string
+	SecurityProtocol string
+	SASLMechanism    string
+}
+
+// Message - сообщение транспорта. Key задаёт порядок: сообщения с одним ключом доставляются
+// в том порядке, в каком опубликованы.
+type Message struct {
+	Key     string
+	Value   []byte
+	Headers map[string]string
+}
+
+// Publisher отправляет пачку сообщений и возвращает ошибку по каждому в том же порядке.
+// nil в ответе - транспорт подтвердил, что сообщение сохранено и дойдёт до подписчиков.
+type Publisher interface {
+	Publish(ctx context.Context, topic string, msgs []Message) []error
+	Close() error
+}
+
+// Handler обрабатывает одно сообщение. Ошибка - сообщение не подтверждено и придёт повторно,
+// поэтому обработчик должен быть идемпотентным. Сообщение, которое не получится обработать
+// никогда, обработчик подтверждает, вернув nil, иначе оно остановит чтение топика.
+type Handler func(ctx context.Context, msg Message) error
+
+// Subscriber читает топик в своей группе подписчиков: каждое сообщение получает одна подписка
+// группы, подтверждённые сообщения после рестарта не приходят снова.
+type Subscriber interface {
+	// Subscribe читает топик, пока не отменён ctx; ошибку возвращает, только если подписаться не удалось
+	Subscribe(ctx context.Context, topic string, handler Handler) error
+	Close() error
+}
+
+// Config - выбор транспорта и его настройки
+type Config struct {
+	Transport string
+	Kafka     KafkaConfig
+	// PollInterval - как часто подписчик postgres проверяет новые сообщения
+	// и через сколько повторяет сообщение, которое обработчик не подтвердил
+	PollInterval time.Duration
+}
+
+// NewPublisher создаёт publisher выбранного транспорта; db нужна только для postgres
+func NewPublisher(cfg Config, db *sql.DB, log *zap.SugaredLogger) (Publisher, error) {
+	switch cfg.Transport {
+	case TransportKafka:
+		return NewKafkaPublisher(cfg.Kafka, log)
+	case TransportPostgres:
+		return NewPostgresPublisher(db), nil
+	case TransportMemory:
+		return sharedBroker(cfg, log), nil
+	default:
+		return nil, fmt.Errorf("unknown event transport %q", cfg.Transport)
+	}
+}
+
+// NewSubscriber создаёт подписчика выбранного транспорта в группе group; db нужна только для postgres
+func NewSubscriber(cfg Config, group string, db *sql.DB, log *zap.SugaredLogger) (Subscriber, error) {
+	switch cfg.Transport {
+	case TransportKafka:
+		return NewKafkaSubscriber(cfg.Kafka, group, log)
+	case TransportPostgres:
+		return NewPostgresSubscriber(db, group, cfg.PollInterval, log), nil
+	case TransportMemory:
+		return sharedBroker(cfg, log).Subscriber(group), nil
+	default:
+		return nil, fmt.Errorf("unknown event transport %q", cfg.Transport)
+	}
+}
+
+var (
+	brokerOnce sync.Once
+	broker     *Broker
+)
+
+// sharedBroker - один брокер на процесс, чтобы publisher и подписчики, созданные по одному
+// конфигу в разных местах, видели одни и те же топики
+func sharedBroker(cfg Config, log *zap.SugaredLogger) *Broker {
+	brokerOnce.Do(func() {
+		broker = NewBroker(cfg.PollInterval, log)
+	})
+	return broker
+}
@@ -437,6 +437,651 @@ func (x *OrderEvent) GetTotal() int64 {
 	return 0
 }
 
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_orders_order_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CancelOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_orders_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelOrderResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ConfirmCancellationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmCancellationRequest) Reset() {
+	*x = ConfirmCancellationRequest{}
+	mi := &file_orders_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmCancellationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmCancellationRequest) ProtoMessage() {}
+
+func (x *ConfirmCancellationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmCancellationRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmCancellationRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ConfirmCancellationRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type ConfirmCancellationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmCancellationResponse) Reset() {
+	*x = ConfirmCancellationResponse{}
+	mi := &file_orders_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmCancellationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmCancellationResponse) ProtoMessage() {}
+
+func (x *ConfirmCancellationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmCancellationResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmCancellationResponse) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{11}
+}
+
+// status: REQUESTED, APPROVED, COMPLETED or REJECTED; amount is known once the return is approved
+type OrderReturn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnId      string                 `protobuf:"bytes,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items         []*OrderItem           `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Amount        int64                  `protobuf:"varint,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderReturn) Reset() {
+	*x = OrderReturn{}
+	mi := &file_orders_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderReturn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderReturn) ProtoMessage() {}
+
+func (x *OrderReturn) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderReturn.ProtoReflect.Descriptor instead.
+func (*OrderReturn) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *OrderReturn) GetReturnId() string {
+	if x != nil {
+		return x.ReturnId
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *OrderReturn) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *OrderReturn) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderReturn) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type RequestReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId        int64                  `protobuf:"varint,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items         []*OrderItem           `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestReturnRequest) Reset() {
+	*x = RequestReturnRequest{}
+	mi := &file_orders_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestReturnRequest) ProtoMessage() {}
+
+func (x *RequestReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestReturnRequest.ProtoReflect.Descriptor instead.
+func (*RequestReturnRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RequestReturnRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *RequestReturnRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RequestReturnRequest) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *RequestReturnRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ApproveReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnId      string                 `protobuf:"bytes,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveReturnRequest) Reset() {
+	*x = ApproveReturnRequest{}
+	mi := &file_orders_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveReturnRequest) ProtoMessage() {}
+
+func (x *ApproveReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveReturnRequest.ProtoReflect.Descriptor instead.
+func (*ApproveReturnRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ApproveReturnRequest) GetReturnId() string {
+	if x != nil {
+		return x.ReturnId
+	}
+	return ""
+}
+
+type RejectReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnId      string                 `protobuf:"bytes,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RejectReturnRequest) Reset() {
+	*x = RejectReturnRequest{}
+	mi := &file_orders_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RejectReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RejectReturnRequest) ProtoMessage() {}
+
+func (x *RejectReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RejectReturnRequest.ProtoReflect.Descriptor instead.
+func (*RejectReturnRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *RejectReturnRequest) GetReturnId() string {
+	if x != nil {
+		return x.ReturnId
+	}
+	return ""
+}
+
+type ReturnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Return        *OrderReturn           `protobuf:"bytes,1,opt,name=return,proto3" json:"return,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReturnResponse) Reset() {
+	*x = ReturnResponse{}
+	mi := &file_orders_order_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReturnResponse) ProtoMessage() {}
+
+func (x *ReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReturnResponse.ProtoReflect.Descriptor instead.
+func (*ReturnResponse) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ReturnResponse) GetReturn() *OrderReturn {
+	if x != nil {
+		return x.Return
+	}
+	return nil
+}
+
+type ListReturnsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReturnsRequest) Reset() {
+	*x = ListReturnsRequest{}
+	mi := &file_orders_order_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReturnsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReturnsRequest) ProtoMessage() {}
+
+func (x *ListReturnsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReturnsRequest.ProtoReflect.Descriptor instead.
+func (*ListReturnsRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListReturnsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type ListReturnsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Returns       []*OrderReturn         `protobuf:"bytes,1,rep,name=returns,proto3" json:"returns,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListReturnsResponse) Reset() {
+	*x = ListReturnsResponse{}
+	mi := &file_orders_order_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListReturnsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListReturnsResponse) ProtoMessage() {}
+
+func (x *ListReturnsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListReturnsResponse.ProtoReflect.Descriptor instead.
+func (*ListReturnsResponse) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListReturnsResponse) GetReturns() []*OrderReturn {
+	if x != nil {
+		return x.Returns
+	}
+	return nil
+}
+
+type CompleteReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnId      string                 `protobuf:"bytes,1,opt,name=return_id,json=returnId,proto3" json:"return_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteReturnRequest) Reset() {
+	*x = CompleteReturnRequest{}
+	mi := &file_orders_order_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteReturnRequest) ProtoMessage() {}
+
+func (x *CompleteReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteReturnRequest.ProtoReflect.Descriptor instead.
+func (*CompleteReturnRequest) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CompleteReturnRequest) GetReturnId() string {
+	if x != nil {
+		return x.ReturnId
+	}
+	return ""
+}
+
+func (x *CompleteReturnRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+type CompleteReturnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteReturnResponse) Reset() {
+	*x = CompleteReturnResponse{}
+	mi := &file_orders_order_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteReturnResponse) ProtoMessage() {}
+
+func (x *CompleteReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_order_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteReturnResponse.ProtoReflect.Descriptor instead.
+func (*CompleteReturnResponse) Descriptor() ([]byte, []int) {
+	return file_orders_order_proto_rawDescGZIP(), []int{20}
+}
+
 var File_orders_order_proto protoreflect.FileDescriptor
 
 const file_orders_order_proto_rawDesc = "" +
@@ -467,12 +1112,54 @@ const file_orders_order_proto_rawDesc = "" +
 	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12,\n" +
 	"\x05items\x18\x03 \x03(\v2\x16.proto_order.OrderItemR\x05items\x12\x16\n" +
 	"\x06status\x18\x04 \x01(\tR\x06status\x12\x14\n" +
-	"\x05total\x18\x05 \x01(\x03R\x05total2\xf1\x01\n" +
+	"\x05total\x18\x05 \x01(\x03R\x05total\"H\n" +
+	"\x12CancelOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\"-\n" +
+	"\x13CancelOrderResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"7\n" +
+	"\x1aConfirmCancellationRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"\x1d\n" +
+	"\x1bConfirmCancellationResponse\"\xd4\x01\n" +
+	"\vOrderReturn\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\tR\breturnId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\x03R\x06userId\x12,\n" +
+	"\x05items\x18\x04 \x03(\v2\x16.proto_order.OrderItemR\x05items\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06amount\x18\a \x01(\x03R\x06amount\"\x90\x01\n" +
+	"\x14RequestReturnRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12,\n" +
+	"\x05items\x18\x03 \x03(\v2\x16.proto_order.OrderItemR\x05items\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"3\n" +
+	"\x14ApproveReturnRequest\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\tR\breturnId\"2\n" +
+	"\x13RejectReturnRequest\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\tR\breturnId\"B\n" +
+	"\x0eReturnResponse\x120\n" +
+	"\x06return\x18\x01 \x01(\v2\x18.proto_order.OrderReturnR\x06return\"/\n" +
+	"\x12ListReturnsRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"I\n" +
+	"\x13ListReturnsResponse\x122\n" +
+	"\areturns\x18\x01 \x03(\v2\x18.proto_order.OrderReturnR\areturns\"L\n" +
+	"\x15CompleteReturnRequest\x12\x1b\n" +
+	"\treturn_id\x18\x01 \x01(\tR\breturnId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\"\x18\n" +
+	"\x16CompleteReturnResponse2\xcb\x06\n" +
 	"\x05Order\x12P\n" +
 	"\vCreateOrder\x12\x1f.proto_order.CreateOrderRequest\x1a .proto_order.CreateOrderResponse\x12G\n" +
 	"\bGetOrder\x12\x1c.proto_order.GetOrderRequest\x1a\x1d.proto_order.GetOrderResponse\x12M\n" +
 	"\n" +
-	"ListOrders\x12\x1e.proto_order.ListOrdersRequest\x1a\x1f.proto_order.ListOrdersResponseB0Z.github.com/vsespontanno/eCommerce/proto/ordersb\x06proto3"
+	"ListOrders\x12\x1e.proto_order.ListOrdersRequest\x1a\x1f.proto_order.ListOrdersResponse\x12P\n" +
+	"\vCancelOrder\x12\x1f.proto_order.CancelOrderRequest\x1a .proto_order.CancelOrderResponse\x12h\n" +
+	"\x13ConfirmCancellation\x12'.proto_order.ConfirmCancellationRequest\x1a(.proto_order.ConfirmCancellationResponse\x12O\n" +
+	"\rRequestReturn\x12!.proto_order.RequestReturnRequest\x1a\x1b.proto_order.ReturnResponse\x12O\n" +
+	"\rApproveReturn\x12!.proto_order.ApproveReturnRequest\x1a\x1b.proto_order.ReturnResponse\x12M\n" +
+	"\fRejectReturn\x12 .proto_order.RejectReturnRequest\x1a\x1b.proto_order.ReturnResponse\x12P\n" +
+	"\vListReturns\x12\x1f.proto_order.ListReturnsRequest\x1a .proto_order.ListReturnsResponse\x12Y\n" +
+	"\x0eCompleteReturn\x12\".proto_order.CompleteReturnRequest\x1a#.proto_order.CompleteReturnResponseB0Z.github.com/vsespontanno/eCommerce/proto/ordersb\x06proto3"
 
 var (
 	file_orders_order_proto_rawDescOnce sync.Once
@@ -486,33 +1173,64 @@ func file_orders_order_proto_rawDescGZIP() []byte {
 	return file_orders_order_proto_rawDescData
 }
 
-var file_orders_order_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_orders_order_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_orders_order_proto_goTypes = []any{
-	(*CreateOrderRequest)(nil),  // 0: proto_order.CreateOrderRequest
-	(*CreateOrderResponse)(nil), // 1: proto_order.CreateOrderResponse
-	(*GetOrderRequest)(nil),     // 2: proto_order.GetOrderRequest
-	(*GetOrderResponse)(nil),    // 3: proto_order.GetOrderResponse
-	(*ListOrdersRequest)(nil),   // 4: proto_order.ListOrdersRequest
-	(*ListOrdersResponse)(nil),  // 5: proto_order.ListOrdersResponse
-	(*OrderItem)(nil),           // 6: proto_order.OrderItem
-	(*OrderEvent)(nil),          // 7: proto_order.OrderEvent
+	(*CreateOrderRequest)(nil),          // 0: proto_order.CreateOrderRequest
+	(*CreateOrderResponse)(nil),         // 1: proto_order.CreateOrderResponse
+	(*GetOrderRequest)(nil),             // 2: proto_order.GetOrderRequest
+	(*GetOrderResponse)(nil),            // 3: proto_order.GetOrderResponse
+	(*ListOrdersRequest)(nil),           // 4: proto_order.ListOrdersRequest
+	(*ListOrdersResponse)(nil),          // 5: proto_order.ListOrdersResponse
+	(*OrderItem)(nil),                   // 6: proto_order.OrderItem
+	(*OrderEvent)(nil),                  // 7: proto_order.OrderEvent
+	(*CancelOrderRequest)(nil),          // 8: proto_order.CancelOrderRequest
+	(*CancelOrderResponse)(nil),         // 9: proto_order.CancelOrderResponse
+	(*ConfirmCancellationRequest)(nil),  // 10: proto_order.ConfirmCancellationRequest
+	(*ConfirmCancellationResponse)(nil), // 11: proto_order.ConfirmCancellationResponse
+	(*OrderReturn)(nil),                 // 12: proto_order.OrderReturn
+	(*RequestReturnRequest)(nil),        // 13: proto_order.RequestReturnRequest
+	(*ApproveReturnRequest)(nil),        // 14: proto_order.ApproveReturnRequest
+	(*RejectReturnRequest)(nil),         // 15: proto_order.RejectReturnRequest
+	(*ReturnResponse)(nil),              // 16: proto_order.ReturnResponse
+	(*ListReturnsRequest)(nil),          // 17: proto_order.ListReturnsRequest
+	(*ListReturnsResponse)(nil),         // 18: proto_order.ListReturnsResponse
+	(*CompleteReturnRequest)(nil),       // 19: proto_order.CompleteReturnRequest
+	(*CompleteReturnResponse)(nil),      // 20: proto_order.CompleteReturnResponse
 }
 var file_orders_order_proto_depIdxs = []int32{
-	7, // 0: proto_order.CreateOrderRequest.order:type_name -> proto_order.OrderEvent
-	7, // 1: proto_order.GetOrderResponse.order:type_name -> proto_order.OrderEvent
-	3, // 2: proto_order.ListOrdersResponse.orders:type_name -> proto_order.GetOrderResponse
-	6, // 3: proto_order.OrderEvent.items:type_name -> proto_order.OrderItem
-	0, // 4: proto_order.Order.CreateOrder:input_type -> proto_order.CreateOrderRequest
-	2, // 5: proto_order.Order.GetOrder:input_type -> proto_order.GetOrderRequest
-	4, // 6: proto_order.Order.ListOrders:input_type -> proto_order.ListOrdersRequest
-	1, // 7: proto_order.Order.CreateOrder:output_type -> proto_order.CreateOrderResponse
-	3, // 8: proto_order.Order.GetOrder:output_type -> proto_order.GetOrderResponse
-	5, // 9: proto_order.Order.ListOrders:output_type -> proto_order.ListOrdersResponse
-	7, // [7:10] is the sub-list for method output_type
-	4, // [4:7] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	7,  // 0: proto_order.CreateOrderRequest.order:type_name -> proto_order.OrderEvent
+	7,  // 1: proto_order.GetOrderResponse.order:type_name -> proto_order.OrderEvent
+	3,  // 2: proto_order.ListOrdersResponse.orders:type_name -> proto_order.GetOrderResponse
+	6,  // 3: proto_order.OrderEvent.items:type_name -> proto_order.OrderItem
+	6,  // 4: proto_order.OrderReturn.items:type_name -> proto_order.OrderItem
+	6,  // 5: proto_order.RequestReturnRequest.items:type_name -> proto_order.OrderItem
+	12, // 6: proto_order.ReturnResponse.return:type_name -> proto_order.OrderReturn
+	12, // 7: proto_order.ListReturnsResponse.returns:type_name -> proto_order.OrderReturn
+	0,  // 8: proto_order.Order.CreateOrder:input_type -> proto_order.CreateOrderRequest
+	2,  // 9: proto_order.Order.GetOrder:input_type -> proto_order.GetOrderRequest
+	4,  // 10: proto_order.Order.ListOrders:input_type -> proto_order.ListOrdersRequest
+	8,  // 11: proto_order.Order.CancelOrder:input_type -> proto_order.CancelOrderRequest
+	10, // 12: proto_order.Order.ConfirmCancellation:input_type -> proto_order.ConfirmCancellationRequest
+	13, // 13: proto_order.Order.RequestReturn:input_type -> proto_order.RequestReturnRequest
+	14, // 14: proto_order.Order.ApproveReturn:input_type -> proto_order.ApproveReturnRequest
+	15, // 15: proto_order.Order.RejectReturn:input_type -> proto_order.RejectReturnRequest
+	17, // 16: proto_order.Order.ListReturns:input_type -> proto_order.ListReturnsRequest
+	19, // 17: proto_order.Order.CompleteReturn:input_type -> proto_order.CompleteReturnRequest
+	1,  // 18: proto_order.Order.CreateOrder:output_type -> proto_order.CreateOrderResponse
+	3,  // 19: proto_order.Order.GetOrder:output_type -> proto_order.GetOrderResponse
+	5,  // 20: proto_order.Order.ListOrders:output_type -> proto_order.ListOrdersResponse
+	9,  // 21: proto_order.Order.CancelOrder:output_type -> proto_order.CancelOrderResponse
+	11, // 22: proto_order.Order.ConfirmCancellation:output_type -> proto_order.ConfirmCancellationResponse
+	16, // 23: proto_order.Order.RequestReturn:output_type -> proto_order.ReturnResponse
+	16, // 24: proto_order.Order.ApproveReturn:output_type -> proto_order.ReturnResponse
+	16, // 25: proto_order.Order.RejectReturn:output_type -> proto_order.ReturnResponse
+	18, // 26: proto_order.Order.ListReturns:output_type -> proto_order.ListReturnsResponse
+	20, // 27: proto_order.Order.CompleteReturn:output_type -> proto_order.CompleteReturnResponse
+	18, // [18:28] is the sub-list for method output_type
+	8,  // [8:18] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_orders_order_proto_init() }
@@ -526,7 +1244,7 @@ func file_orders_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_orders_order_proto_rawDesc), len(file_orders_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   8,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -24,7 +24,8 @@ const (
 // ReserveProductsRequest contains products to reserve
 type ReserveProductsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*ProductSaga         `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"` // List of products with quantities to reserve
+	Products      []*ProductSaga         `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`              // List of products with quantities to reserve
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // Order (transaction) ID, makes the call idempotent per order
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,6 +67,13 @@ func (x *ReserveProductsRequest) GetProducts() []*ProductSaga {
 	return nil
 }
 
+func (x *ReserveProductsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
 // ProductSaga represents a product in saga transaction
 type ProductSaga struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -175,7 +183,8 @@ func (x *ReserveProductsResponse) GetError() string {
 // ReleaseProductsRequest contains products to release
 type ReleaseProductsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*ProductSaga         `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"` // List of products with quantities to release
+	Products      []*ProductSaga         `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`              // List of products with quantities to release
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // Order (transaction) ID, makes the call idempotent per order
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -217,6 +226,13 @@ func (x *ReleaseProductsRequest) GetProducts() []*ProductSaga {
 	return nil
 }
 
+func (x *ReleaseProductsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
 // ReleaseProductsResponse indicates release result
 type ReleaseProductsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -273,7 +289,8 @@ func (x *ReleaseProductsResponse) GetError() string {
 // CommitProductsRequest contains products to commit
 type CommitProductsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*ProductSaga         `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"` // List of products with quantities to commit
+	Products      []*ProductSaga         `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`              // List of products with quantities to commit
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"` // Order (transaction) ID, makes the call idempotent per order
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -315,6 +332,13 @@ func (x *CommitProductsRequest) GetProducts() []*ProductSaga {
 	return nil
 }
 
+func (x *CommitProductsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
 // CommitProductsResponse indicates commit result
 type CommitProductsResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -368,6 +392,120 @@ func (x *CommitProductsResponse) GetError() string {
 	return ""
 }
 
+// RestockProductsRequest contains products to return to stock
+type RestockProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Products      []*ProductSaga         `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`                    // List of products with quantities to restock
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`       // Order whose committed inventory is restocked
+	RestockId     string                 `protobuf:"bytes,3,opt,name=restock_id,json=restockId,proto3" json:"restock_id,omitempty"` // Restock operation ID, makes the call idempotent; order_id if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestockProductsRequest) Reset() {
+	*x = RestockProductsRequest{}
+	mi := &file_products_products_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestockProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestockProductsRequest) ProtoMessage() {}
+
+func (x *RestockProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_products_products_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestockProductsRequest.ProtoReflect.Descriptor instead.
+func (*RestockProductsRequest) Descriptor() ([]byte, []int) {
+	return file_products_products_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RestockProductsRequest) GetProducts() []*ProductSaga {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *RestockProductsRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *RestockProductsRequest) GetRestockId() string {
+	if x != nil {
+		return x.RestockId
+	}
+	return ""
+}
+
+// RestockProductsResponse indicates restock result
+type RestockProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"` // True if restock successful
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`      // Error message if failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestockProductsResponse) Reset() {
+	*x = RestockProductsResponse{}
+	mi := &file_products_products_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestockProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestockProductsResponse) ProtoMessage() {}
+
+func (x *RestockProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_products_products_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestockProductsResponse.ProtoReflect.Descriptor instead.
+func (*RestockProductsResponse) Descriptor() ([]byte, []int) {
+	return file_products_products_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RestockProductsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RestockProductsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 // GetProductByIDRequest contains product ID to retrieve
 type GetProductByIDRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -378,7 +516,7 @@ type GetProductByIDRequest struct {
 
 func (x *GetProductByIDRequest) Reset() {
 	*x = GetProductByIDRequest{}
-	mi := &file_products_products_proto_msgTypes[7]
+	mi := &file_products_products_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -390,7 +528,7 @@ func (x *GetProductByIDRequest) String() string {
 func (*GetProductByIDRequest) ProtoMessage() {}
 
 func (x *GetProductByIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_products_products_proto_msgTypes[7]
+	mi := &file_products_products_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -403,7 +541,7 @@ func (x *GetProductByIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductByIDRequest.ProtoReflect.Descriptor instead.
 func (*GetProductByIDRequest) Descriptor() ([]byte, []int) {
-	return file_products_products_proto_rawDescGZIP(), []int{7}
+	return file_products_products_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetProductByIDRequest) GetId() int64 {
@@ -424,7 +562,7 @@ type GetProductByIDResponse struct {
 
 func (x *GetProductByIDResponse) Reset() {
 	*x = GetProductByIDResponse{}
-	mi := &file_products_products_proto_msgTypes[8]
+	mi := &file_products_products_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -436,7 +574,7 @@ func (x *GetProductByIDResponse) String() string {
 func (*GetProductByIDResponse) ProtoMessage() {}
 
 func (x *GetProductByIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_products_products_proto_msgTypes[8]
+	mi := &file_products_products_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -449,7 +587,7 @@ func (x *GetProductByIDResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductByIDResponse.ProtoReflect.Descriptor instead.
 func (*GetProductByIDResponse) Descriptor() ([]byte, []int) {
-	return file_products_products_proto_rawDescGZIP(), []int{8}
+	return file_products_products_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GetProductByIDResponse) GetError() string {
@@ -476,7 +614,7 @@ type GetProductsByIDRequest struct {
 
 func (x *GetProductsByIDRequest) Reset() {
 	*x = GetProductsByIDRequest{}
-	mi := &file_products_products_proto_msgTypes[9]
+	mi := &file_products_products_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -488,7 +626,7 @@ func (x *GetProductsByIDRequest) String() string {
 func (*GetProductsByIDRequest) ProtoMessage() {}
 
 func (x *GetProductsByIDRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_products_products_proto_msgTypes[9]
+	mi := &file_products_products_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -501,7 +639,7 @@ func (x *GetProductsByIDRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductsByIDRequest.ProtoReflect.Descriptor instead.
 func (*GetProductsByIDRequest) Descriptor() ([]byte, []int) {
-	return file_products_products_proto_rawDescGZIP(), []int{9}
+	return file_products_products_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *GetProductsByIDRequest) GetIds() []int64 {
@@ -522,7 +660,7 @@ type GetProductsByIDResponse struct {
 
 func (x *GetProductsByIDResponse) Reset() {
 	*x = GetProductsByIDResponse{}
-	mi := &file_products_products_proto_msgTypes[10]
+	mi := &file_products_products_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -534,7 +672,7 @@ func (x *GetProductsByIDResponse) String() string {
 func (*GetProductsByIDResponse) ProtoMessage() {}
 
 func (x *GetProductsByIDResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_products_products_proto_msgTypes[10]
+	mi := &file_products_products_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -547,7 +685,7 @@ func (x *GetProductsByIDResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetProductsByIDResponse.ProtoReflect.Descriptor instead.
 func (*GetProductsByIDResponse) Descriptor() ([]byte, []int) {
-	return file_products_products_proto_rawDescGZIP(), []int{10}
+	return file_products_products_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *GetProductsByIDResponse) GetError() string {
@@ -577,7 +715,7 @@ type Product struct {
 
 func (x *Product) Reset() {
 	*x = Product{}
-	mi := &file_products_products_proto_msgTypes[11]
+	mi := &file_products_products_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -589,7 +727,7 @@ func (x *Product) String() string {
 func (*Product) ProtoMessage() {}
 
 func (x *Product) ProtoReflect() protoreflect.Message {
-	mi := &file_products_products_proto_msgTypes[11]
+	mi := &file_products_products_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -602,7 +740,7 @@ func (x *Product) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Product.ProtoReflect.Descriptor instead.
 func (*Product) Descriptor() ([]byte, []int) {
-	return file_products_products_proto_rawDescGZIP(), []int{11}
+	return file_products_products_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *Product) GetId() int64 {
@@ -637,24 +775,35 @@ var File_products_products_proto protoreflect.FileDescriptor
 
 const file_products_products_proto_rawDesc = "" +
 	"\n" +
-	"\x17products/products.proto\x12\x0eproto_products\"Q\n" +
+	"\x17products/products.proto\x12\x0eproto_products\"l\n" +
 	"\x16ReserveProductsRequest\x127\n" +
-	"\bproducts\x18\x01 \x03(\v2\x1b.proto_products.ProductSagaR\bproducts\"9\n" +
+	"\bproducts\x18\x01 \x03(\v2\x1b.proto_products.ProductSagaR\bproducts\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\"9\n" +
 	"\vProductSaga\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1a\n" +
 	"\bquantity\x18\x02 \x01(\x03R\bquantity\"I\n" +
 	"\x17ReserveProductsResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"Q\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"l\n" +
 	"\x16ReleaseProductsRequest\x127\n" +
-	"\bproducts\x18\x01 \x03(\v2\x1b.proto_products.ProductSagaR\bproducts\"I\n" +
+	"\bproducts\x18\x01 \x03(\v2\x1b.proto_products.ProductSagaR\bproducts\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\"I\n" +
 	"\x17ReleaseProductsResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"P\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"k\n" +
 	"\x15CommitProductsRequest\x127\n" +
-	"\bproducts\x18\x01 \x03(\v2\x1b.proto_products.ProductSagaR\bproducts\"H\n" +
+	"\bproducts\x18\x01 \x03(\v2\x1b.proto_products.ProductSagaR\bproducts\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\"H\n" +
 	"\x16CommitProductsResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x8b\x01\n" +
+	"\x16RestockProductsRequest\x127\n" +
+	"\bproducts\x18\x01 \x03(\v2\x1b.proto_products.ProductSagaR\bproducts\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"restock_id\x18\x03 \x01(\tR\trestockId\"I\n" +
+	"\x17RestockProductsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
 	"\x05error\x18\x02 \x01(\tR\x05error\"'\n" +
 	"\x15GetProductByIDRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\"a\n" +
@@ -673,11 +822,12 @@ const file_products_products_proto_rawDesc = "" +
 	"\x05price\x18\x04 \x01(\x03R\x05price2\xcb\x01\n" +
 	"\bProducts\x12_\n" +
 	"\x0eGetProductByID\x12%.proto_products.GetProductByIDRequest\x1a&.proto_products.GetProductByIDResponse\x12^\n" +
-	"\vGetProducts\x12&.proto_products.GetProductsByIDRequest\x1a'.proto_products.GetProductsByIDResponse2\xb7\x02\n" +
+	"\vGetProducts\x12&.proto_products.GetProductsByIDRequest\x1a'.proto_products.GetProductsByIDResponse2\x9b\x03\n" +
 	"\fSagaProducts\x12b\n" +
 	"\x0fReserveProducts\x12&.proto_products.ReserveProductsRequest\x1a'.proto_products.ReserveProductsResponse\x12b\n" +
 	"\x0fReleaseProducts\x12&.proto_products.ReleaseProductsRequest\x1a'.proto_products.ReleaseProductsResponse\x12_\n" +
-	"\x0eCommitProducts\x12%.proto_products.CommitProductsRequest\x1a&.proto_products.CommitProductsResponseB2Z0github.com/vsespontanno/eCommerce/proto/productsb\x06proto3"
+	"\x0eCommitProducts\x12%.proto_products.CommitProductsRequest\x1a&.proto_products.CommitProductsResponse\x12b\n" +
+	"\x0fRestockProducts\x12&.proto_products.RestockProductsRequest\x1a'.proto_products.RestockProductsResponseB2Z0github.com/vsespontanno/eCommerce/proto/productsb\x06proto3"
 
 var (
 	file_products_products_proto_rawDescOnce sync.Once
@@ -691,7 +841,7 @@ func file_products_products_proto_rawDescGZIP() []byte {
 	return file_products_products_proto_rawDescData
 }
 
-var file_products_products_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_products_products_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_products_products_proto_goTypes = []any{
 	(*ReserveProductsRequest)(nil),  // 0: proto_products.ReserveProductsRequest
 	(*ProductSaga)(nil),             // 1: proto_products.ProductSaga
@@ -700,33 +850,38 @@ var file_products_products_proto_goTypes = []any{
 	(*ReleaseProductsResponse)(nil), // 4: proto_products.ReleaseProductsResponse
 	(*CommitProductsRequest)(nil),   // 5: proto_products.CommitProductsRequest
 	(*CommitProductsResponse)(nil),  // 6: proto_products.CommitProductsResponse
-	(*GetProductByIDRequest)(nil),   // 7: proto_products.GetProductByIDRequest
-	(*GetProductByIDResponse)(nil),  // 8: proto_products.GetProductByIDResponse
-	(*GetProductsByIDRequest)(nil),  // 9: proto_products.GetProductsByIDRequest
-	(*GetProductsByIDResponse)(nil), // 10: proto_products.GetProductsByIDResponse
-	(*Product)(nil),                 // 11: proto_products.Product
+	(*RestockProductsRequest)(nil),  // 7: proto_products.RestockProductsRequest
+	(*RestockProductsResponse)(nil), // 8: proto_products.RestockProductsResponse
+	(*GetProductByIDRequest)(nil),   // 9: proto_products.GetProductByIDRequest
+	(*GetProductByIDResponse)(nil),  // 10: proto_products.GetProductByIDResponse
+	(*GetProductsByIDRequest)(nil),  // 11: proto_products.GetProductsByIDRequest
+	(*GetProductsByIDResponse)(nil), // 12: proto_products.GetProductsByIDResponse
+	(*Product)(nil),                 // 13: proto_products.Product
 }
 var file_products_products_proto_depIdxs = []int32{
 	1,  // 0: proto_products.ReserveProductsRequest.products:type_name -> proto_products.ProductSaga
 	1,  // 1: proto_products.ReleaseProductsRequest.products:type_name -> proto_products.ProductSaga
 	1,  // 2: proto_products.CommitProductsRequest.products:type_name -> proto_products.ProductSaga
-	11, // 3: proto_products.GetProductByIDResponse.product:type_name -> proto_products.Product
-	11, // 4: proto_products.GetProductsByIDResponse.products:type_name -> proto_products.Product
-	7,  // 5: proto_products.Products.GetProductByID:input_type -> proto_products.GetProductByIDRequest
-	9,  // 6: proto_products.Products.GetProducts:input_type -> proto_products.GetProductsByIDRequest
-	0,  // 7: proto_products.SagaProducts.ReserveProducts:input_type -> proto_products.ReserveProductsRequest
-	3,  // 8: proto_products.SagaProducts.ReleaseProducts:input_type -> proto_products.ReleaseProductsRequest
-	5,  // 9: proto_products.SagaProducts.CommitProducts:input_type -> proto_products.CommitProductsRequest
-	8,  // 10: proto_products.Products.GetProductByID:output_type -> proto_products.GetProductByIDResponse
-	10, // 11: proto_products.Products.GetProducts:output_type -> proto_products.GetProductsByIDResponse
-	2,  // 12: proto_products.SagaProducts.ReserveProducts:output_type -> proto_products.ReserveProductsResponse
-	4,  // 13: proto_products.SagaProducts.ReleaseProducts:output_type -> proto_products.ReleaseProductsResponse
-	6,  // 14: proto_products.SagaProducts.CommitProducts:output_type -> proto_products.CommitProductsResponse
-	10, // [10:15] is the sub-list for method output_type
-	5,  // [5:10] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	1,  // 3: proto_products.RestockProductsRequest.products:type_name -> proto_products.ProductSaga
+	13, // 4: proto_products.GetProductByIDResponse.product:type_name -> proto_products.Product
+	13, // 5: proto_products.GetProductsByIDResponse.products:type_name -> proto_products.Product
+	9,  // 6: proto_products.Products.GetProductByID:input_type -> proto_products.GetProductByIDRequest
+	11, // 7: proto_products.Products.GetProducts:input_type -> proto_products.GetProductsByIDRequest
+	0,  // 8: proto_products.SagaProducts.ReserveProducts:input_type -> proto_products.ReserveProductsRequest
+	3,  // 9: proto_products.SagaProducts.ReleaseProducts:input_type -> proto_products.ReleaseProductsRequest
+	5,  // 10: proto_products.SagaProducts.CommitProducts:input_type -> proto_products.CommitProductsRequest
+	7,  // 11: proto_products.SagaProducts.RestockProducts:input_type -> proto_products.RestockProductsRequest
+	10, // 12: proto_products.Products.GetProductByID:output_type -> proto_products.GetProductByIDResponse
+	12, // 13: proto_products.Products.GetProducts:output_type -> proto_products.GetProductsByIDResponse
+	2,  // 14: proto_products.SagaProducts.ReserveProducts:output_type -> proto_products.ReserveProductsResponse
+	4,  // 15: proto_products.SagaProducts.ReleaseProducts:output_type -> proto_products.ReleaseProductsResponse
+	6,  // 16: proto_products.SagaProducts.CommitProducts:output_type -> proto_products.CommitProductsResponse
+	8,  // 17: proto_products.SagaProducts.RestockProducts:output_type -> proto_products.RestockProductsResponse
+	12, // [12:18] is the sub-list for method output_type
+	6,  // [6:12] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_products_products_proto_init() }
@@ -740,7 +895,7 @@ func file_products_products_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_products_products_proto_rawDesc), len(file_products_products_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
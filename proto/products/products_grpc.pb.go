@@ -170,6 +170,7 @@ const (
 	SagaProducts_ReserveProducts_FullMethodName = "/proto_products.SagaProducts/ReserveProducts"
 	SagaProducts_ReleaseProducts_FullMethodName = "/proto_products.SagaProducts/ReleaseProducts"
 	SagaProducts_CommitProducts_FullMethodName  = "/proto_products.SagaProducts/CommitProducts"
+	SagaProducts_RestockProducts_FullMethodName = "/proto_products.SagaProducts/RestockProducts"
 )
 
 // SagaProductsClient is the client API for SagaProducts service.
@@ -188,6 +189,9 @@ type SagaProductsClient interface {
 	// CommitProducts commits the reservation and decrements inventory (saga commit)
 	// Final step when transaction is successful
 	CommitProducts(ctx context.Context, in *CommitProductsRequest, opts ...grpc.CallOption) (*CommitProductsResponse, error)
+	// RestockProducts returns committed inventory of an order back to stock (order cancellation, returns)
+	// Idempotent per restock_id; the total restocked per order never exceeds what was committed
+	RestockProducts(ctx context.Context, in *RestockProductsRequest, opts ...grpc.CallOption) (*RestockProductsResponse, error)
 }
 
 type sagaProductsClient struct {
@@ -228,6 +232,16 @@ func (c *sagaProductsClient) CommitProducts(ctx context.Context, in *CommitProdu
 	return out, nil
 }
 
+func (c *sagaProductsClient) RestockProducts(ctx context.Context, in *RestockProductsRequest, opts ...grpc.CallOption) (*RestockProductsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestockProductsResponse)
+	err := c.cc.Invoke(ctx, SagaProducts_RestockProducts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SagaProductsServer is the server API for SagaProducts service.
 // All implementations must embed UnimplementedSagaProductsServer
 // for forward compatibility.
@@ -244,6 +258,9 @@ type SagaProductsServer interface {
 	// CommitProducts commits the reservation and decrements inventory (saga commit)
 	// Final step when transaction is successful
 	CommitProducts(context.Context, *CommitProductsRequest) (*CommitProductsResponse, error)
+	// RestockProducts returns committed inventory of an order back to stock (order cancellation, returns)
+	// Idempotent per restock_id; the total restocked per order never exceeds what was committed
+	RestockProducts(context.Context, *RestockProductsRequest) (*RestockProductsResponse, error)
 	mustEmbedUnimplementedSagaProductsServer()
 }
 
@@ -263,6 +280,9 @@ func (UnimplementedSagaProductsServer) ReleaseProducts(context.Context, *Release
 func (UnimplementedSagaProductsServer) CommitProducts(context.Context, *CommitProductsRequest) (*CommitProductsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CommitProducts not implemented")
 }
+func (UnimplementedSagaProductsServer) RestockProducts(context.Context, *RestockProductsRequest) (*RestockProductsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RestockProducts not implemented")
+}
 func (UnimplementedSagaProductsServer) mustEmbedUnimplementedSagaProductsServer() {}
 func (UnimplementedSagaProductsServer) testEmbeddedByValue()                      {}
 
@@ -338,6 +358,24 @@ func _SagaProducts_CommitProducts_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SagaProducts_RestockProducts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestockProductsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaProductsServer).RestockProducts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SagaProducts_RestockProducts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaProductsServer).RestockProducts(ctx, req.(*RestockProductsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // SagaProducts_ServiceDesc is the grpc.ServiceDesc for SagaProducts service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -357,6 +395,10 @@ var SagaProducts_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CommitProducts",
 			Handler:    _SagaProducts_CommitProducts_Handler,
 		},
+		{
+			MethodName: "RestockProducts",
+			Handler:    _SagaProducts_RestockProducts_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "products/products.proto",
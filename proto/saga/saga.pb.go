@@ -22,11 +22,13 @@ const (
 )
 
 type StartCheckoutRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserID        int64                  `protobuf:"varint,1,opt,name=userID,proto3" json:"userID,omitempty"`
-	Cart          []*Cart                `protobuf:"bytes,2,rep,name=cart,proto3" json:"cart,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserID int64                  `protobuf:"varint,1,opt,name=userID,proto3" json:"userID,omitempty"`
+	Cart   []*Cart                `protobuf:"bytes,2,rep,name=cart,proto3" json:"cart,omitempty"`
+	// Повтор запроса с тем же ключом возвращает уже созданный заказ; пустой ключ - без дедупликации
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotencyKey,proto3" json:"idempotencyKey,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *StartCheckoutRequest) Reset() {
@@ -73,10 +75,25 @@ func (x *StartCheckoutRequest) GetCart() []*Cart {
 	return nil
 }
 
+func (x *StartCheckoutRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+// Если цены в корзине устарели, заказ не создаётся: error = "cart prices are out of date",
+// а quote содержит ту же корзину по текущим ценам каталога
 type StartCheckoutResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderID       string                 `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderID string                 `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	Error   string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Quote   []*Cart                `protobuf:"bytes,3,rep,name=quote,proto3" json:"quote,omitempty"`
+	// replayed - заказ уже был создан раньше с тем же ключом идемпотентности;
+	// status и reason - его текущий итог, как в GetCheckoutStatusResponse
+	Replayed      bool   `protobuf:"varint,4,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	Status        string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Reason        string `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -125,6 +142,34 @@ func (x *StartCheckoutResponse) GetError() string {
 	return ""
 }
 
+func (x *StartCheckoutResponse) GetQuote() []*Cart {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+func (x *StartCheckoutResponse) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+func (x *StartCheckoutResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *StartCheckoutResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 type Cart struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductID     int64                  `protobuf:"varint,1,opt,name=productID,proto3" json:"productID,omitempty"`
@@ -185,24 +230,421 @@ func (x *Cart) GetQuantity() int64 {
 	return 0
 }
 
+type GetCheckoutStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	UserID        int64                  `protobuf:"varint,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheckoutStatusRequest) Reset() {
+	*x = GetCheckoutStatusRequest{}
+	mi := &file_saga_saga_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheckoutStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckoutStatusRequest) ProtoMessage() {}
+
+func (x *GetCheckoutStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckoutStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetCheckoutStatusRequest) Descriptor() ([]byte, []int) {
+	return file_saga_saga_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetCheckoutStatusRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *GetCheckoutStatusRequest) GetUserID() int64 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+// status: PENDING, COMPLETED, FAILED, CANCELLING или CANCELLED; reason заполняется только для FAILED
+// кодом причины: insufficient_funds, out_of_stock или internal_error
+type GetCheckoutStatusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCheckoutStatusResponse) Reset() {
+	*x = GetCheckoutStatusResponse{}
+	mi := &file_saga_saga_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCheckoutStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCheckoutStatusResponse) ProtoMessage() {}
+
+func (x *GetCheckoutStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCheckoutStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetCheckoutStatusResponse) Descriptor() ([]byte, []int) {
+	return file_saga_saga_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetCheckoutStatusResponse) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *GetCheckoutStatusResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetCheckoutStatusResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	UserID        int64                  `protobuf:"varint,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_saga_saga_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_saga_saga_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CancelOrderRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetUserID() int64 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_saga_saga_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_saga_saga_proto_rawDescGZIP(), []int{6}
+}
+
+type ReturnItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductID     int64                  `protobuf:"varint,1,opt,name=productID,proto3" json:"productID,omitempty"`
+	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReturnItem) Reset() {
+	*x = ReturnItem{}
+	mi := &file_saga_saga_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReturnItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReturnItem) ProtoMessage() {}
+
+func (x *ReturnItem) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReturnItem.ProtoReflect.Descriptor instead.
+func (*ReturnItem) Descriptor() ([]byte, []int) {
+	return file_saga_saga_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReturnItem) GetProductID() int64 {
+	if x != nil {
+		return x.ProductID
+	}
+	return 0
+}
+
+func (x *ReturnItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type StartReturnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReturnID      string                 `protobuf:"bytes,1,opt,name=returnID,proto3" json:"returnID,omitempty"`
+	OrderID       string                 `protobuf:"bytes,2,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	UserID        int64                  `protobuf:"varint,3,opt,name=userID,proto3" json:"userID,omitempty"`
+	Items         []*ReturnItem          `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartReturnRequest) Reset() {
+	*x = StartReturnRequest{}
+	mi := &file_saga_saga_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartReturnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartReturnRequest) ProtoMessage() {}
+
+func (x *StartReturnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartReturnRequest.ProtoReflect.Descriptor instead.
+func (*StartReturnRequest) Descriptor() ([]byte, []int) {
+	return file_saga_saga_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *StartReturnRequest) GetReturnID() string {
+	if x != nil {
+		return x.ReturnID
+	}
+	return ""
+}
+
+func (x *StartReturnRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *StartReturnRequest) GetUserID() int64 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *StartReturnRequest) GetItems() []*ReturnItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// amount - сумма, которая вернётся пользователю
+type StartReturnResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Amount        int64                  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartReturnResponse) Reset() {
+	*x = StartReturnResponse{}
+	mi := &file_saga_saga_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartReturnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartReturnResponse) ProtoMessage() {}
+
+func (x *StartReturnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartReturnResponse.ProtoReflect.Descriptor instead.
+func (*StartReturnResponse) Descriptor() ([]byte, []int) {
+	return file_saga_saga_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StartReturnResponse) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
 var File_saga_saga_proto protoreflect.FileDescriptor
 
 const file_saga_saga_proto_rawDesc = "" +
 	"\n" +
 	"\x0fsaga/saga.proto\x12\n" +
-	"proto_saga\"T\n" +
+	"proto_saga\"|\n" +
 	"\x14StartCheckoutRequest\x12\x16\n" +
 	"\x06userID\x18\x01 \x01(\x03R\x06userID\x12$\n" +
-	"\x04cart\x18\x02 \x03(\v2\x10.proto_saga.CartR\x04cart\"G\n" +
+	"\x04cart\x18\x02 \x03(\v2\x10.proto_saga.CartR\x04cart\x12&\n" +
+	"\x0eidempotencyKey\x18\x03 \x01(\tR\x0eidempotencyKey\"\xbb\x01\n" +
 	"\x15StartCheckoutResponse\x12\x18\n" +
 	"\aorderID\x18\x01 \x01(\tR\aorderID\x12\x14\n" +
-	"\x05error\x18\x02 \x01(\tR\x05error\"V\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12&\n" +
+	"\x05quote\x18\x03 \x03(\v2\x10.proto_saga.CartR\x05quote\x12\x1a\n" +
+	"\breplayed\x18\x04 \x01(\bR\breplayed\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\"V\n" +
 	"\x04Cart\x12\x1c\n" +
 	"\tproductID\x18\x01 \x01(\x03R\tproductID\x12\x14\n" +
 	"\x05price\x18\x02 \x01(\x03R\x05price\x12\x1a\n" +
-	"\bquantity\x18\x03 \x01(\x03R\bquantity2\\\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\"L\n" +
+	"\x18GetCheckoutStatusRequest\x12\x18\n" +
+	"\aorderID\x18\x01 \x01(\tR\aorderID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\x03R\x06userID\"e\n" +
+	"\x19GetCheckoutStatusResponse\x12\x18\n" +
+	"\aorderID\x18\x01 \x01(\tR\aorderID\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"F\n" +
+	"\x12CancelOrderRequest\x12\x18\n" +
+	"\aorderID\x18\x01 \x01(\tR\aorderID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\x03R\x06userID\"\x15\n" +
+	"\x13CancelOrderResponse\"F\n" +
+	"\n" +
+	"ReturnItem\x12\x1c\n" +
+	"\tproductID\x18\x01 \x01(\x03R\tproductID\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\"\x90\x01\n" +
+	"\x12StartReturnRequest\x12\x1a\n" +
+	"\breturnID\x18\x01 \x01(\tR\breturnID\x12\x18\n" +
+	"\aorderID\x18\x02 \x01(\tR\aorderID\x12\x16\n" +
+	"\x06userID\x18\x03 \x01(\x03R\x06userID\x12,\n" +
+	"\x05items\x18\x04 \x03(\v2\x16.proto_saga.ReturnItemR\x05items\"-\n" +
+	"\x13StartReturnResponse\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount2\xde\x02\n" +
 	"\x04Saga\x12T\n" +
-	"\rStartCheckout\x12 .proto_saga.StartCheckoutRequest\x1a!.proto_saga.StartCheckoutResponseB.Z,github.com/vsespontanno/eCommerce/proto/sagab\x06proto3"
+	"\rStartCheckout\x12 .proto_saga.StartCheckoutRequest\x1a!.proto_saga.StartCheckoutResponse\x12`\n" +
+	"\x11GetCheckoutStatus\x12$.proto_saga.GetCheckoutStatusRequest\x1a%.proto_saga.GetCheckoutStatusResponse\x12N\n" +
+	"\vCancelOrder\x12\x1e.proto_saga.CancelOrderRequest\x1a\x1f.proto_saga.CancelOrderResponse\x12N\n" +
+	"\vStartReturn\x12\x1e.proto_saga.StartReturnRequest\x1a\x1f.proto_saga.StartReturnResponseB.Z,github.com/vsespontanno/eCommerce/proto/sagab\x06proto3"
 
 var (
 	file_saga_saga_proto_rawDescOnce sync.Once
@@ -216,21 +658,36 @@ func file_saga_saga_proto_rawDescGZIP() []byte {
 	return file_saga_saga_proto_rawDescData
 }
 
-var file_saga_saga_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_saga_saga_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
 var file_saga_saga_proto_goTypes = []any{
-	(*StartCheckoutRequest)(nil),  // 0: proto_saga.StartCheckoutRequest
-	(*StartCheckoutResponse)(nil), // 1: proto_saga.StartCheckoutResponse
-	(*Cart)(nil),                  // 2: proto_saga.Cart
+	(*StartCheckoutRequest)(nil),      // 0: proto_saga.StartCheckoutRequest
+	(*StartCheckoutResponse)(nil),     // 1: proto_saga.StartCheckoutResponse
+	(*Cart)(nil),                      // 2: proto_saga.Cart
+	(*GetCheckoutStatusRequest)(nil),  // 3: proto_saga.GetCheckoutStatusRequest
+	(*GetCheckoutStatusResponse)(nil), // 4: proto_saga.GetCheckoutStatusResponse
+	(*CancelOrderRequest)(nil),        // 5: proto_saga.CancelOrderRequest
+	(*CancelOrderResponse)(nil),       // 6: proto_saga.CancelOrderResponse
+	(*ReturnItem)(nil),                // 7: proto_saga.ReturnItem
+	(*StartReturnRequest)(nil),        // 8: proto_saga.StartReturnRequest
+	(*StartReturnResponse)(nil),       // 9: proto_saga.StartReturnResponse
 }
 var file_saga_saga_proto_depIdxs = []int32{
 	2, // 0: proto_saga.StartCheckoutRequest.cart:type_name -> proto_saga.Cart
-	0, // 1: proto_saga.Saga.StartCheckout:input_type -> proto_saga.StartCheckoutRequest
-	1, // 2: proto_saga.Saga.StartCheckout:output_type -> proto_saga.StartCheckoutResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	2, // 1: proto_saga.StartCheckoutResponse.quote:type_name -> proto_saga.Cart
+	7, // 2: proto_saga.StartReturnRequest.items:type_name -> proto_saga.ReturnItem
+	0, // 3: proto_saga.Saga.StartCheckout:input_type -> proto_saga.StartCheckoutRequest
+	3, // 4: proto_saga.Saga.GetCheckoutStatus:input_type -> proto_saga.GetCheckoutStatusRequest
+	5, // 5: proto_saga.Saga.CancelOrder:input_type -> proto_saga.CancelOrderRequest
+	8, // 6: proto_saga.Saga.StartReturn:input_type -> proto_saga.StartReturnRequest
+	1, // 7: proto_saga.Saga.StartCheckout:output_type -> proto_saga.StartCheckoutResponse
+	4, // 8: proto_saga.Saga.GetCheckoutStatus:output_type -> proto_saga.GetCheckoutStatusResponse
+	6, // 9: proto_saga.Saga.CancelOrder:output_type -> proto_saga.CancelOrderResponse
+	9, // 10: proto_saga.Saga.StartReturn:output_type -> proto_saga.StartReturnResponse
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
 }
 
 func init() { file_saga_saga_proto_init() }
@@ -244,7 +701,7 @@ func file_saga_saga_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_saga_saga_proto_rawDesc), len(file_saga_saga_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   10,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,1426 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.1
+// source: saga/saga_admin.proto
+
+package saga
+
+import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Время везде - unix seconds
+type SagaSummary struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	UserID        int64                  `protobuf:"varint,2,opt,name=userID,proto3" json:"userID,omitempty"`
+	Total         int64                  `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	CurrentStep   string                 `protobuf:"bytes,5,opt,name=currentStep,proto3" json:"currentStep,omitempty"`
+	StepStatus    string                 `protobuf:"bytes,6,opt,name=stepStatus,proto3" json:"stepStatus,omitempty"`
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,8,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,9,opt,name=updatedAt,proto3" json:"updatedAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SagaSummary) Reset() {
+	*x = SagaSummary{}
+	mi := &file_saga_saga_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SagaSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SagaSummary) ProtoMessage() {}
+
+func (x *SagaSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SagaSummary.ProtoReflect.Descriptor instead.
+func (*SagaSummary) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SagaSummary) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *SagaSummary) GetUserID() int64 {
+	if x != nil {
+		return x.UserID
+	}
+	return 0
+}
+
+func (x *SagaSummary) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *SagaSummary) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SagaSummary) GetCurrentStep() string {
+	if x != nil {
+		return x.CurrentStep
+	}
+	return ""
+}
+
+func (x *SagaSummary) GetStepStatus() string {
+	if x != nil {
+		return x.StepStatus
+	}
+	return ""
+}
+
+func (x *SagaSummary) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SagaSummary) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *SagaSummary) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type SagaStep struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Step          string                 `protobuf:"bytes,1,opt,name=step,proto3" json:"step,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,4,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SagaStep) Reset() {
+	*x = SagaStep{}
+	mi := &file_saga_saga_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SagaStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SagaStep) ProtoMessage() {}
+
+func (x *SagaStep) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SagaStep.ProtoReflect.Descriptor instead.
+func (*SagaStep) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SagaStep) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *SagaStep) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SagaStep) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SagaStep) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// result: succeeded, failed или rejected
+type AdminAction struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderID   string                 `protobuf:"bytes,2,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	Action    string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	Operator  string                 `protobuf:"bytes,4,opt,name=operator,proto3" json:"operator,omitempty"`
+	Reason    string                 `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	Result    string                 `protobuf:"bytes,6,opt,name=result,proto3" json:"result,omitempty"`
+	Error     string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt int64                  `protobuf:"varint,8,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	// eventID - событие outbox для requeue_event, 0 для действий над сагой
+	EventID       int64 `protobuf:"varint,9,opt,name=eventID,proto3" json:"eventID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminAction) Reset() {
+	*x = AdminAction{}
+	mi := &file_saga_saga_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminAction) ProtoMessage() {}
+
+func (x *AdminAction) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminAction.ProtoReflect.Descriptor instead.
+func (*AdminAction) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AdminAction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AdminAction) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *AdminAction) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AdminAction) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *AdminAction) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AdminAction) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *AdminAction) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AdminAction) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *AdminAction) GetEventID() int64 {
+	if x != nil {
+		return x.EventID
+	}
+	return 0
+}
+
+// type: saga_status (status - статус саги), step (status - статус шага),
+// attempt (неудачная попытка вызова detail внутри step) или admin_action (status - результат действия);
+// sagaID - ID заказа или, для возврата, ID возврата
+type TimelineEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderID       string                 `protobuf:"bytes,2,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	SagaID        string                 `protobuf:"bytes,3,opt,name=sagaID,proto3" json:"sagaID,omitempty"`
+	Kind          string                 `protobuf:"bytes,4,opt,name=kind,proto3" json:"kind,omitempty"`
+	Type          string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	Step          string                 `protobuf:"bytes,6,opt,name=step,proto3" json:"step,omitempty"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Attempt       int32                  `protobuf:"varint,8,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	Detail        string                 `protobuf:"bytes,9,opt,name=detail,proto3" json:"detail,omitempty"`
+	Error         string                 `protobuf:"bytes,10,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,11,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TimelineEvent) Reset() {
+	*x = TimelineEvent{}
+	mi := &file_saga_saga_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TimelineEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TimelineEvent) ProtoMessage() {}
+
+func (x *TimelineEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TimelineEvent.ProtoReflect.Descriptor instead.
+func (*TimelineEvent) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TimelineEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TimelineEvent) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetSagaID() string {
+	if x != nil {
+		return x.SagaID
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetStep() string {
+	if x != nil {
+		return x.Step
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *TimelineEvent) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TimelineEvent) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// status: pending, failed или dead; payload - JSON события
+type OutboxEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	AggregateID   string                 `protobuf:"bytes,2,opt,name=aggregateID,proto3" json:"aggregateID,omitempty"`
+	EventType     string                 `protobuf:"bytes,3,opt,name=eventType,proto3" json:"eventType,omitempty"`
+	Payload       string                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts      int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError     string                 `protobuf:"bytes,7,opt,name=lastError,proto3" json:"lastError,omitempty"`
+	NextAttemptAt int64                  `protobuf:"varint,8,opt,name=nextAttemptAt,proto3" json:"nextAttemptAt,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,9,opt,name=createdAt,proto3" json:"createdAt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboxEvent) Reset() {
+	*x = OutboxEvent{}
+	mi := &file_saga_saga_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboxEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxEvent) ProtoMessage() {}
+
+func (x *OutboxEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxEvent.ProtoReflect.Descriptor instead.
+func (*OutboxEvent) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OutboxEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OutboxEvent) GetAggregateID() string {
+	if x != nil {
+		return x.AggregateID
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *OutboxEvent) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetNextAttemptAt() int64 {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return 0
+}
+
+func (x *OutboxEvent) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+// status пустой - любой статус; olderThanSeconds - сколько сага не обновлялась
+type ListSagasRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Status           string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	OlderThanSeconds int64                  `protobuf:"varint,2,opt,name=olderThanSeconds,proto3" json:"olderThanSeconds,omitempty"`
+	Limit            int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ListSagasRequest) Reset() {
+	*x = ListSagasRequest{}
+	mi := &file_saga_saga_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSagasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSagasRequest) ProtoMessage() {}
+
+func (x *ListSagasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSagasRequest.ProtoReflect.Descriptor instead.
+func (*ListSagasRequest) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ListSagasRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListSagasRequest) GetOlderThanSeconds() int64 {
+	if x != nil {
+		return x.OlderThanSeconds
+	}
+	return 0
+}
+
+func (x *ListSagasRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListSagasResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sagas         []*SagaSummary         `protobuf:"bytes,1,rep,name=sagas,proto3" json:"sagas,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSagasResponse) Reset() {
+	*x = ListSagasResponse{}
+	mi := &file_saga_saga_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSagasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSagasResponse) ProtoMessage() {}
+
+func (x *ListSagasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSagasResponse.ProtoReflect.Descriptor instead.
+func (*ListSagasResponse) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListSagasResponse) GetSagas() []*SagaSummary {
+	if x != nil {
+		return x.Sagas
+	}
+	return nil
+}
+
+type GetSagaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSagaRequest) Reset() {
+	*x = GetSagaRequest{}
+	mi := &file_saga_saga_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSagaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSagaRequest) ProtoMessage() {}
+
+func (x *GetSagaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSagaRequest.ProtoReflect.Descriptor instead.
+func (*GetSagaRequest) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetSagaRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+type GetSagaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Saga          *SagaSummary           `protobuf:"bytes,1,opt,name=saga,proto3" json:"saga,omitempty"`
+	Steps         []*SagaStep            `protobuf:"bytes,2,rep,name=steps,proto3" json:"steps,omitempty"`
+	Actions       []*AdminAction         `protobuf:"bytes,3,rep,name=actions,proto3" json:"actions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSagaResponse) Reset() {
+	*x = GetSagaResponse{}
+	mi := &file_saga_saga_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSagaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSagaResponse) ProtoMessage() {}
+
+func (x *GetSagaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSagaResponse.ProtoReflect.Descriptor instead.
+func (*GetSagaResponse) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetSagaResponse) GetSaga() *SagaSummary {
+	if x != nil {
+		return x.Saga
+	}
+	return nil
+}
+
+func (x *GetSagaResponse) GetSteps() []*SagaStep {
+	if x != nil {
+		return x.Steps
+	}
+	return nil
+}
+
+func (x *GetSagaResponse) GetActions() []*AdminAction {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+// reason обязателен для resolve. Оператор определяется по токену запроса
+type SagaActionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SagaActionRequest) Reset() {
+	*x = SagaActionRequest{}
+	mi := &file_saga_saga_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SagaActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SagaActionRequest) ProtoMessage() {}
+
+func (x *SagaActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SagaActionRequest.ProtoReflect.Descriptor instead.
+func (*SagaActionRequest) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SagaActionRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *SagaActionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SagaActionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Saga          *SagaSummary           `protobuf:"bytes,1,opt,name=saga,proto3" json:"saga,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SagaActionResponse) Reset() {
+	*x = SagaActionResponse{}
+	mi := &file_saga_saga_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SagaActionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SagaActionResponse) ProtoMessage() {}
+
+func (x *SagaActionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SagaActionResponse.ProtoReflect.Descriptor instead.
+func (*SagaActionResponse) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SagaActionResponse) GetSaga() *SagaSummary {
+	if x != nil {
+		return x.Saga
+	}
+	return nil
+}
+
+type GetOrderTimelineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderTimelineRequest) Reset() {
+	*x = GetOrderTimelineRequest{}
+	mi := &file_saga_saga_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderTimelineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderTimelineRequest) ProtoMessage() {}
+
+func (x *GetOrderTimelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderTimelineRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderTimelineRequest) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetOrderTimelineRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+// events - в порядке записи
+type GetOrderTimelineResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*TimelineEvent       `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderTimelineResponse) Reset() {
+	*x = GetOrderTimelineResponse{}
+	mi := &file_saga_saga_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderTimelineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderTimelineResponse) ProtoMessage() {}
+
+func (x *GetOrderTimelineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderTimelineResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderTimelineResponse) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetOrderTimelineResponse) GetEvents() []*TimelineEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type ListAdminActionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderID       string                 `protobuf:"bytes,1,opt,name=orderID,proto3" json:"orderID,omitempty"`
+	Operator      string                 `protobuf:"bytes,2,opt,name=operator,proto3" json:"operator,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAdminActionsRequest) Reset() {
+	*x = ListAdminActionsRequest{}
+	mi := &file_saga_saga_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAdminActionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAdminActionsRequest) ProtoMessage() {}
+
+func (x *ListAdminActionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAdminActionsRequest.ProtoReflect.Descriptor instead.
+func (*ListAdminActionsRequest) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListAdminActionsRequest) GetOrderID() string {
+	if x != nil {
+		return x.OrderID
+	}
+	return ""
+}
+
+func (x *ListAdminActionsRequest) GetOperator() string {
+	if x != nil {
+		return x.Operator
+	}
+	return ""
+}
+
+func (x *ListAdminActionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListAdminActionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Actions       []*AdminAction         `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAdminActionsResponse) Reset() {
+	*x = ListAdminActionsResponse{}
+	mi := &file_saga_saga_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAdminActionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAdminActionsResponse) ProtoMessage() {}
+
+func (x *ListAdminActionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAdminActionsResponse.ProtoReflect.Descriptor instead.
+func (*ListAdminActionsResponse) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListAdminActionsResponse) GetActions() []*AdminAction {
+	if x != nil {
+		return x.Actions
+	}
+	return nil
+}
+
+type ListDeadEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadEventsRequest) Reset() {
+	*x = ListDeadEventsRequest{}
+	mi := &file_saga_saga_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadEventsRequest) ProtoMessage() {}
+
+func (x *ListDeadEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadEventsRequest) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListDeadEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListDeadEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*OutboxEvent         `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadEventsResponse) Reset() {
+	*x = ListDeadEventsResponse{}
+	mi := &file_saga_saga_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadEventsResponse) ProtoMessage() {}
+
+func (x *ListDeadEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadEventsResponse) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ListDeadEventsResponse) GetEvents() []*OutboxEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// Оператор определяется по токену запроса
+type RequeueEventRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueEventRequest) Reset() {
+	*x = RequeueEventRequest{}
+	mi := &file_saga_saga_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueEventRequest) ProtoMessage() {}
+
+func (x *RequeueEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueEventRequest.ProtoReflect.Descriptor instead.
+func (*RequeueEventRequest) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RequeueEventRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *RequeueEventRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RequeueEventResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *OutboxEvent           `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueEventResponse) Reset() {
+	*x = RequeueEventResponse{}
+	mi := &file_saga_saga_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueEventResponse) ProtoMessage() {}
+
+func (x *RequeueEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_saga_saga_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueEventResponse.ProtoReflect.Descriptor instead.
+func (*RequeueEventResponse) Descriptor() ([]byte, []int) {
+	return file_saga_saga_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RequeueEventResponse) GetEvent() *OutboxEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+var File_saga_saga_admin_proto protoreflect.FileDescriptor
+
+const file_saga_saga_admin_proto_rawDesc = "" +
+	"\n" +
+	"\x15saga/saga_admin.proto\x12\n" +
+	"proto_saga\x1a\x1cgoogle/api/annotations.proto\"\x81\x02\n" +
+	"\vSagaSummary\x12\x18\n" +
+	"\aorderID\x18\x01 \x01(\tR\aorderID\x12\x16\n" +
+	"\x06userID\x18\x02 \x01(\x03R\x06userID\x12\x14\n" +
+	"\x05total\x18\x03 \x01(\x03R\x05total\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12 \n" +
+	"\vcurrentStep\x18\x05 \x01(\tR\vcurrentStep\x12\x1e\n" +
+	"\n" +
+	"stepStatus\x18\x06 \x01(\tR\n" +
+	"stepStatus\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1c\n" +
+	"\tcreatedAt\x18\b \x01(\x03R\tcreatedAt\x12\x1c\n" +
+	"\tupdatedAt\x18\t \x01(\x03R\tupdatedAt\"j\n" +
+	"\bSagaStep\x12\x12\n" +
+	"\x04step\x18\x01 \x01(\tR\x04step\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1c\n" +
+	"\tcreatedAt\x18\x04 \x01(\x03R\tcreatedAt\"\xe9\x01\n" +
+	"\vAdminAction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aorderID\x18\x02 \x01(\tR\aorderID\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x1a\n" +
+	"\boperator\x18\x04 \x01(\tR\boperator\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06result\x18\x06 \x01(\tR\x06result\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1c\n" +
+	"\tcreatedAt\x18\b \x01(\x03R\tcreatedAt\x12\x18\n" +
+	"\aeventID\x18\t \x01(\x03R\aeventID\"\x8b\x02\n" +
+	"\rTimelineEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x18\n" +
+	"\aorderID\x18\x02 \x01(\tR\aorderID\x12\x16\n" +
+	"\x06sagaID\x18\x03 \x01(\tR\x06sagaID\x12\x12\n" +
+	"\x04kind\x18\x04 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12\x12\n" +
+	"\x04step\x18\x06 \x01(\tR\x04step\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x18\n" +
+	"\aattempt\x18\b \x01(\x05R\aattempt\x12\x16\n" +
+	"\x06detail\x18\t \x01(\tR\x06detail\x12\x14\n" +
+	"\x05error\x18\n" +
+	" \x01(\tR\x05error\x12\x1c\n" +
+	"\tcreatedAt\x18\v \x01(\x03R\tcreatedAt\"\x8d\x02\n" +
+	"\vOutboxEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12 \n" +
+	"\vaggregateID\x18\x02 \x01(\tR\vaggregateID\x12\x1c\n" +
+	"\teventType\x18\x03 \x01(\tR\teventType\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\tR\apayload\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12\x1c\n" +
+	"\tlastError\x18\a \x01(\tR\tlastError\x12$\n" +
+	"\rnextAttemptAt\x18\b \x01(\x03R\rnextAttemptAt\x12\x1c\n" +
+	"\tcreatedAt\x18\t \x01(\x03R\tcreatedAt\"l\n" +
+	"\x10ListSagasRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12*\n" +
+	"\x10olderThanSeconds\x18\x02 \x01(\x03R\x10olderThanSeconds\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"B\n" +
+	"\x11ListSagasResponse\x12-\n" +
+	"\x05sagas\x18\x01 \x03(\v2\x17.proto_saga.SagaSummaryR\x05sagas\"*\n" +
+	"\x0eGetSagaRequest\x12\x18\n" +
+	"\aorderID\x18\x01 \x01(\tR\aorderID\"\x9d\x01\n" +
+	"\x0fGetSagaResponse\x12+\n" +
+	"\x04saga\x18\x01 \x01(\v2\x17.proto_saga.SagaSummaryR\x04saga\x12*\n" +
+	"\x05steps\x18\x02 \x03(\v2\x14.proto_saga.SagaStepR\x05steps\x121\n" +
+	"\aactions\x18\x03 \x03(\v2\x17.proto_saga.AdminActionR\aactions\"U\n" +
+	"\x11SagaActionRequest\x12\x18\n" +
+	"\aorderID\x18\x01 \x01(\tR\aorderID\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reasonJ\x04\b\x02\x10\x03R\boperator\"A\n" +
+	"\x12SagaActionResponse\x12+\n" +
+	"\x04saga\x18\x01 \x01(\v2\x17.proto_saga.SagaSummaryR\x04saga\"3\n" +
+	"\x17GetOrderTimelineRequest\x12\x18\n" +
+	"\aorderID\x18\x01 \x01(\tR\aorderID\"M\n" +
+	"\x18GetOrderTimelineResponse\x121\n" +
+	"\x06events\x18\x01 \x03(\v2\x19.proto_saga.TimelineEventR\x06events\"e\n" +
+	"\x17ListAdminActionsRequest\x12\x18\n" +
+	"\aorderID\x18\x01 \x01(\tR\aorderID\x12\x1a\n" +
+	"\boperator\x18\x02 \x01(\tR\boperator\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\"M\n" +
+	"\x18ListAdminActionsResponse\x121\n" +
+	"\aactions\x18\x01 \x03(\v2\x17.proto_saga.AdminActionR\aactions\"-\n" +
+	"\x15ListDeadEventsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\"I\n" +
+	"\x16ListDeadEventsResponse\x12/\n" +
+	"\x06events\x18\x01 \x03(\v2\x17.proto_saga.OutboxEventR\x06events\"M\n" +
+	"\x13RequeueEventRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reasonJ\x04\b\x02\x10\x03R\boperator\"E\n" +
+	"\x14RequeueEventResponse\x12-\n" +
+	"\x05event\x18\x01 \x01(\v2\x17.proto_saga.OutboxEventR\x05event2\xac\b\n" +
+	"\tSagaAdmin\x12^\n" +
+	"\tListSagas\x12\x1c.proto_saga.ListSagasRequest\x1a\x1d.proto_saga.ListSagasResponse\"\x14\x82\xd3\xe4\x93\x02\x0e\x12\f/admin/sagas\x12b\n" +
+	"\aGetSaga\x12\x1a.proto_saga.GetSagaRequest\x1a\x1b.proto_saga.GetSagaResponse\"\x1e\x82\xd3\xe4\x93\x02\x18\x12\x16/admin/sagas/{orderID}\x12s\n" +
+	"\tRetrySaga\x12\x1d.proto_saga.SagaActionRequest\x1a\x1e.proto_saga.SagaActionResponse\"'\x82\xd3\xe4\x93\x02!:\x01*\"\x1c/admin/sagas/{orderID}/retry\x12}\n" +
+	"\x0eCompensateSaga\x12\x1d.proto_saga.SagaActionRequest\x1a\x1e.proto_saga.SagaActionResponse\",\x82\xd3\xe4\x93\x02&:\x01*\"!/admin/sagas/{orderID}/compensate\x12w\n" +
+	"\vResolveSaga\x12\x1d.proto_saga.SagaActionRequest\x1a\x1e.proto_saga.SagaActionResponse\")\x82\xd3\xe4\x93\x02#:\x01*\"\x1e/admin/sagas/{orderID}/resolve\x12\x87\x01\n" +
+	"\x10GetOrderTimeline\x12#.proto_saga.GetOrderTimelineRequest\x1a$.proto_saga.GetOrderTimelineResponse\"(\x82\xd3\xe4\x93\x02\"\x12 /admin/orders/{orderID}/timeline\x12u\n" +
+	"\x10ListAdminActions\x12#.proto_saga.ListAdminActionsRequest\x1a$.proto_saga.ListAdminActionsResponse\"\x16\x82\xd3\xe4\x93\x02\x10\x12\x0e/admin/actions\x12s\n" +
+	"\x0eListDeadEvents\x12!.proto_saga.ListDeadEventsRequest\x1a\".proto_saga.ListDeadEventsResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/admin/outbox/dead\x12x\n" +
+	"\fRequeueEvent\x12\x1f.proto_saga.RequeueEventRequest\x1a .proto_saga.RequeueEventResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/admin/outbox/{id}/requeueB.Z,github.com/vsespontanno/eCommerce/proto/sagab\x06proto3"
+
+var (
+	file_saga_saga_admin_proto_rawDescOnce sync.Once
+	file_saga_saga_admin_proto_rawDescData []byte
+)
+
+func file_saga_saga_admin_proto_rawDescGZIP() []byte {
+	file_saga_saga_admin_proto_rawDescOnce.Do(func() {
+		file_saga_saga_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_saga_saga_admin_proto_rawDesc), len(file_saga_saga_admin_proto_rawDesc)))
+	})
+	return file_saga_saga_admin_proto_rawDescData
+}
+
+var file_saga_saga_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_saga_saga_admin_proto_goTypes = []any{
+	(*SagaSummary)(nil),              // 0: proto_saga.SagaSummary
+	(*SagaStep)(nil),                 // 1: proto_saga.SagaStep
+	(*AdminAction)(nil),              // 2: proto_saga.AdminAction
+	(*TimelineEvent)(nil),            // 3: proto_saga.TimelineEvent
+	(*OutboxEvent)(nil),              // 4: proto_saga.OutboxEvent
+	(*ListSagasRequest)(nil),         // 5: proto_saga.ListSagasRequest
+	(*ListSagasResponse)(nil),        // 6: proto_saga.ListSagasResponse
+	(*GetSagaRequest)(nil),           // 7: proto_saga.GetSagaRequest
+	(*GetSagaResponse)(nil),          // 8: proto_saga.GetSagaResponse
+	(*SagaActionRequest)(nil),        // 9: proto_saga.SagaActionRequest
+	(*SagaActionResponse)(nil),       // 10: proto_saga.SagaActionResponse
+	(*GetOrderTimelineRequest)(nil),  // 11: proto_saga.GetOrderTimelineRequest
+	(*GetOrderTimelineResponse)(nil), // 12: proto_saga.GetOrderTimelineResponse
+	(*ListAdminActionsRequest)(nil),  // 13: proto_saga.ListAdminActionsRequest
+	(*ListAdminActionsResponse)(nil), // 14: proto_saga.ListAdminActionsResponse
+	(*ListDeadEventsRequest)(nil),    // 15: proto_saga.ListDeadEventsRequest
+	(*ListDeadEventsResponse)(nil),   // 16: proto_saga.ListDeadEventsResponse
+	(*RequeueEventRequest)(nil),      // 17: proto_saga.RequeueEventRequest
+	(*RequeueEventResponse)(nil),     // 18: proto_saga.RequeueEventResponse
+}
+var file_saga_saga_admin_proto_depIdxs = []int32{
+	0,  // 0: proto_saga.ListSagasResponse.sagas:type_name -> proto_saga.SagaSummary
+	0,  // 1: proto_saga.GetSagaResponse.saga:type_name -> proto_saga.SagaSummary
+	1,  // 2: proto_saga.GetSagaResponse.steps:type_name -> proto_saga.SagaStep
+	2,  // 3: proto_saga.GetSagaResponse.actions:type_name -> proto_saga.AdminAction
+	0,  // 4: proto_saga.SagaActionResponse.saga:type_name -> proto_saga.SagaSummary
+	3,  // 5: proto_saga.GetOrderTimelineResponse.events:type_name -> proto_saga.TimelineEvent
+	2,  // 6: proto_saga.ListAdminActionsResponse.actions:type_name -> proto_saga.AdminAction
+	4,  // 7: proto_saga.ListDeadEventsResponse.events:type_name -> proto_saga.OutboxEvent
+	4,  // 8: proto_saga.RequeueEventResponse.event:type_name -> proto_saga.OutboxEvent
+	5,  // 9: proto_saga.SagaAdmin.ListSagas:input_type -> proto_saga.ListSagasRequest
+	7,  // 10: proto_saga.SagaAdmin.GetSaga:input_type -> proto_saga.GetSagaRequest
+	9,  // 11: proto_saga.SagaAdmin.RetrySaga:input_type -> proto_saga.SagaActionRequest
+	9,  // 12: proto_saga.SagaAdmin.CompensateSaga:input_type -> proto_saga.SagaActionRequest
+	9,  // 13: proto_saga.SagaAdmin.ResolveSaga:input_type -> proto_saga.SagaActionRequest
+	11, // 14: proto_saga.SagaAdmin.GetOrderTimeline:input_type -> proto_saga.GetOrderTimelineRequest
+	13, // 15: proto_saga.SagaAdmin.ListAdminActions:input_type -> proto_saga.ListAdminActionsRequest
+	15, // 16: proto_saga.SagaAdmin.ListDeadEvents:input_type -> proto_saga.ListDeadEventsRequest
+	17, // 17: proto_saga.SagaAdmin.RequeueEvent:input_type -> proto_saga.RequeueEventRequest
+	6,  // 18: proto_saga.SagaAdmin.ListSagas:output_type -> proto_saga.ListSagasResponse
+	8,  // 19: proto_saga.SagaAdmin.GetSaga:output_type -> proto_saga.GetSagaResponse
+	10, // 20: proto_saga.SagaAdmin.RetrySaga:output_type -> proto_saga.SagaActionResponse
+	10, // 21: proto_saga.SagaAdmin.CompensateSaga:output_type -> proto_saga.SagaActionResponse
+	10, // 22: proto_saga.SagaAdmin.ResolveSaga:output_type -> proto_saga.SagaActionResponse
+	12, // 23: proto_saga.SagaAdmin.GetOrderTimeline:output_type -> proto_saga.GetOrderTimelineResponse
+	14, // 24: proto_saga.SagaAdmin.ListAdminActions:output_type -> proto_saga.ListAdminActionsResponse
+	16, // 25: proto_saga.SagaAdmin.ListDeadEvents:output_type -> proto_saga.ListDeadEventsResponse
+	18, // 26: proto_saga.SagaAdmin.RequeueEvent:output_type -> proto_saga.RequeueEventResponse
+	18, // [18:27] is the sub-list for method output_type
+	9,  // [9:18] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
+}
+
+func init() { file_saga_saga_admin_proto_init() }
+func file_saga_saga_admin_proto_init() {
+	if File_saga_saga_admin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_saga_saga_admin_proto_rawDesc), len(file_saga_saga_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_saga_saga_admin_proto_goTypes,
+		DependencyIndexes: file_saga_saga_admin_proto_depIdxs,
+		MessageInfos:      file_saga_saga_admin_proto_msgTypes,
+	}.Build()
+	File_saga_saga_admin_proto = out.File
+	file_saga_saga_admin_proto_goTypes = nil
+	file_saga_saga_admin_proto_depIdxs = nil
+}
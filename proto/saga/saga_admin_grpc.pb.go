@@ -0,0 +1,433 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.0
+// - protoc             v6.33.1
+// source: saga/saga_admin.proto
+
+package saga
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	SagaAdmin_ListSagas_FullMethodName        = "/proto_saga.SagaAdmin/ListSagas"
+	SagaAdmin_GetSaga_FullMethodName          = "/proto_saga.SagaAdmin/GetSaga"
+	SagaAdmin_RetrySaga_FullMethodName        = "/proto_saga.SagaAdmin/RetrySaga"
+	SagaAdmin_CompensateSaga_FullMethodName   = "/proto_saga.SagaAdmin/CompensateSaga"
+	SagaAdmin_ResolveSaga_FullMethodName      = "/proto_saga.SagaAdmin/ResolveSaga"
+	SagaAdmin_GetOrderTimeline_FullMethodName = "/proto_saga.SagaAdmin/GetOrderTimeline"
+	SagaAdmin_ListAdminActions_FullMethodName = "/proto_saga.SagaAdmin/ListAdminActions"
+	SagaAdmin_ListDeadEvents_FullMethodName   = "/proto_saga.SagaAdmin/ListDeadEvents"
+	SagaAdmin_RequeueEvent_FullMethodName     = "/proto_saga.SagaAdmin/RequeueEvent"
+)
+
+// SagaAdminClient is the client API for SagaAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// SagaAdmin - операторский API для зависших и упавших саг (только внутренняя сеть)
+type SagaAdminClient interface {
+	ListSagas(ctx context.Context, in *ListSagasRequest, opts ...grpc.CallOption) (*ListSagasResponse, error)
+	GetSaga(ctx context.Context, in *GetSagaRequest, opts ...grpc.CallOption) (*GetSagaResponse, error)
+	RetrySaga(ctx context.Context, in *SagaActionRequest, opts ...grpc.CallOption) (*SagaActionResponse, error)
+	CompensateSaga(ctx context.Context, in *SagaActionRequest, opts ...grpc.CallOption) (*SagaActionResponse, error)
+	ResolveSaga(ctx context.Context, in *SagaActionRequest, opts ...grpc.CallOption) (*SagaActionResponse, error)
+	// GetOrderTimeline - всё, что происходило с заказом: саги оформления, отмены и возвратов,
+	// переходы их шагов, неудачные попытки вызовов и действия операторов
+	GetOrderTimeline(ctx context.Context, in *GetOrderTimelineRequest, opts ...grpc.CallOption) (*GetOrderTimelineResponse, error)
+	ListAdminActions(ctx context.Context, in *ListAdminActionsRequest, opts ...grpc.CallOption) (*ListAdminActionsResponse, error)
+	ListDeadEvents(ctx context.Context, in *ListDeadEventsRequest, opts ...grpc.CallOption) (*ListDeadEventsResponse, error)
+	RequeueEvent(ctx context.Context, in *RequeueEventRequest, opts ...grpc.CallOption) (*RequeueEventResponse, error)
+}
+
+type sagaAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSagaAdminClient(cc grpc.ClientConnInterface) SagaAdminClient {
+	return &sagaAdminClient{cc}
+}
+
+func (c *sagaAdminClient) ListSagas(ctx context.Context, in *ListSagasRequest, opts ...grpc.CallOption) (*ListSagasResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSagasResponse)
+	err := c.cc.Invoke(ctx, SagaAdmin_ListSagas_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaAdminClient) GetSaga(ctx context.Context, in *GetSagaRequest, opts ...grpc.CallOption) (*GetSagaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSagaResponse)
+	err := c.cc.Invoke(ctx, SagaAdmin_GetSaga_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaAdminClient) RetrySaga(ctx context.Context, in *SagaActionRequest, opts ...grpc.CallOption) (*SagaActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SagaActionResponse)
+	err := c.cc.Invoke(ctx, SagaAdmin_RetrySaga_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaAdminClient) CompensateSaga(ctx context.Context, in *SagaActionRequest, opts ...grpc.CallOption) (*SagaActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SagaActionResponse)
+	err := c.cc.Invoke(ctx, SagaAdmin_CompensateSaga_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaAdminClient) ResolveSaga(ctx context.Context, in *SagaActionRequest, opts ...grpc.CallOption) (*SagaActionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SagaActionResponse)
+	err := c.cc.Invoke(ctx, SagaAdmin_ResolveSaga_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaAdminClient) GetOrderTimeline(ctx context.Context, in *GetOrderTimelineRequest, opts ...grpc.CallOption) (*GetOrderTimelineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderTimelineResponse)
+	err := c.cc.Invoke(ctx, SagaAdmin_GetOrderTimeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaAdminClient) ListAdminActions(ctx context.Context, in *ListAdminActionsRequest, opts ...grpc.CallOption) (*ListAdminActionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAdminActionsResponse)
+	err := c.cc.Invoke(ctx, SagaAdmin_ListAdminActions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaAdminClient) ListDeadEvents(ctx context.Context, in *ListDeadEventsRequest, opts ...grpc.CallOption) (*ListDeadEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeadEventsResponse)
+	err := c.cc.Invoke(ctx, SagaAdmin_ListDeadEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaAdminClient) RequeueEvent(ctx context.Context, in *RequeueEventRequest, opts ...grpc.CallOption) (*RequeueEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequeueEventResponse)
+	err := c.cc.Invoke(ctx, SagaAdmin_RequeueEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// SagaAdminServer is the server API for SagaAdmin service.
+// All implementations must embed UnimplementedSagaAdminServer
+// for forward compatibility.
+//
+// SagaAdmin - операторский API для зависших и упавших саг (только внутренняя сеть)
+type SagaAdminServer interface {
+	ListSagas(context.Context, *ListSagasRequest) (*ListSagasResponse, error)
+	GetSaga(context.Context, *GetSagaRequest) (*GetSagaResponse, error)
+	RetrySaga(context.Context, *SagaActionRequest) (*SagaActionResponse, error)
+	CompensateSaga(context.Context, *SagaActionRequest) (*SagaActionResponse, error)
+	ResolveSaga(context.Context, *SagaActionRequest) (*SagaActionResponse, error)
+	// GetOrderTimeline - всё, что происходило с заказом: саги оформления, отмены и возвратов,
+	// переходы их шагов, неудачные попытки вызовов и действия операторов
+	GetOrderTimeline(context.Context, *GetOrderTimelineRequest) (*GetOrderTimelineResponse, error)
+	ListAdminActions(context.Context, *ListAdminActionsRequest) (*ListAdminActionsResponse, error)
+	ListDeadEvents(context.Context, *ListDeadEventsRequest) (*ListDeadEventsResponse, error)
+	RequeueEvent(context.Context, *RequeueEventRequest) (*RequeueEventResponse, error)
+	mustEmbedUnimplementedSagaAdminServer()
+}
+
+// UnimplementedSagaAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedSagaAdminServer struct{}
+
+func (UnimplementedSagaAdminServer) ListSagas(context.Context, *ListSagasRequest) (*ListSagasResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSagas not implemented")
+}
+func (UnimplementedSagaAdminServer) GetSaga(context.Context, *GetSagaRequest) (*GetSagaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetSaga not implemented")
+}
+func (UnimplementedSagaAdminServer) RetrySaga(context.Context, *SagaActionRequest) (*SagaActionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RetrySaga not implemented")
+}
+func (UnimplementedSagaAdminServer) CompensateSaga(context.Context, *SagaActionRequest) (*SagaActionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CompensateSaga not implemented")
+}
+func (UnimplementedSagaAdminServer) ResolveSaga(context.Context, *SagaActionRequest) (*SagaActionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResolveSaga not implemented")
+}
+func (UnimplementedSagaAdminServer) GetOrderTimeline(context.Context, *GetOrderTimelineRequest) (*GetOrderTimelineResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOrderTimeline not implemented")
+}
+func (UnimplementedSagaAdminServer) ListAdminActions(context.Context, *ListAdminActionsRequest) (*ListAdminActionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAdminActions not implemented")
+}
+func (UnimplementedSagaAdminServer) ListDeadEvents(context.Context, *ListDeadEventsRequest) (*ListDeadEventsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDeadEvents not implemented")
+}
+func (UnimplementedSagaAdminServer) RequeueEvent(context.Context, *RequeueEventRequest) (*RequeueEventResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RequeueEvent not implemented")
+}
+func (UnimplementedSagaAdminServer) mustEmbedUnimplementedSagaAdminServer() {}
+func (UnimplementedSagaAdminServer) testEmbeddedByValue()                   {}
+
+// UnsafeSagaAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SagaAdminServer will
+// result in compilation errors.
+type UnsafeSagaAdminServer interface {
+	mustEmbedUnimplementedSagaAdminServer()
+}
+
+func RegisterSagaAdminServer(s grpc.ServiceRegistrar, srv SagaAdminServer) {
+	// If the following call panics, it indicates UnimplementedSagaAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&SagaAdmin_ServiceDesc, srv)
+}
+
+func _SagaAdmin_ListSagas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSagasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaAdminServer).ListSagas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SagaAdmin_ListSagas_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaAdminServer).ListSagas(ctx, req.(*ListSagasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SagaAdmin_GetSaga_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSagaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaAdminServer).GetSaga(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SagaAdmin_GetSaga_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaAdminServer).GetSaga(ctx, req.(*GetSagaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SagaAdmin_RetrySaga_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SagaActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaAdminServer).RetrySaga(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SagaAdmin_RetrySaga_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaAdminServer).RetrySaga(ctx, req.(*SagaActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SagaAdmin_CompensateSaga_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SagaActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaAdminServer).CompensateSaga(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SagaAdmin_CompensateSaga_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaAdminServer).CompensateSaga(ctx, req.(*SagaActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SagaAdmin_ResolveSaga_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SagaActionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaAdminServer).ResolveSaga(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SagaAdmin_ResolveSaga_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaAdminServer).ResolveSaga(ctx, req.(*SagaActionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SagaAdmin_GetOrderTimeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderTimelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaAdminServer).GetOrderTimeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SagaAdmin_GetOrderTimeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaAdminServer).GetOrderTimeline(ctx, req.(*GetOrderTimelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SagaAdmin_ListAdminActions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAdminActionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaAdminServer).ListAdminActions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SagaAdmin_ListAdminActions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaAdminServer).ListAdminActions(ctx, req.(*ListAdminActionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SagaAdmin_ListDeadEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaAdminServer).ListDeadEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SagaAdmin_ListDeadEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaAdminServer).ListDeadEvents(ctx, req.(*ListDeadEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SagaAdmin_RequeueEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequeueEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaAdminServer).RequeueEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SagaAdmin_RequeueEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaAdminServer).RequeueEvent(ctx, req.(*RequeueEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SagaAdmin_ServiceDesc is the grpc.ServiceDesc for SagaAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SagaAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "proto_saga.SagaAdmin",
+	HandlerType: (*SagaAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListSagas",
+			Handler:    _SagaAdmin_ListSagas_Handler,
+		},
+		{
+			MethodName: "GetSaga",
+			Handler:    _SagaAdmin_GetSaga_Handler,
+		},
+		{
+			MethodName: "RetrySaga",
+			Handler:    _SagaAdmin_RetrySaga_Handler,
+		},
+		{
+			MethodName: "CompensateSaga",
+			Handler:    _SagaAdmin_CompensateSaga_Handler,
+		},
+		{
+			MethodName: "ResolveSaga",
+			Handler:    _SagaAdmin_ResolveSaga_Handler,
+		},
+		{
+			MethodName: "GetOrderTimeline",
+			Handler:    _SagaAdmin_GetOrderTimeline_Handler,
+		},
+		{
+			MethodName: "ListAdminActions",
+			Handler:    _SagaAdmin_ListAdminActions_Handler,
+		},
+		{
+			MethodName: "ListDeadEvents",
+			Handler:    _SagaAdmin_ListDeadEvents_Handler,
+		},
+		{
+			MethodName: "RequeueEvent",
+			Handler:    _SagaAdmin_RequeueEvent_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "saga/saga_admin.proto",
+}
@@ -19,7 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Saga_StartCheckout_FullMethodName = "/proto_saga.Saga/StartCheckout"
+	Saga_StartCheckout_FullMethodName     = "/proto_saga.Saga/StartCheckout"
+	Saga_GetCheckoutStatus_FullMethodName = "/proto_saga.Saga/GetCheckoutStatus"
+	Saga_CancelOrder_FullMethodName       = "/proto_saga.Saga/CancelOrder"
+	Saga_StartReturn_FullMethodName       = "/proto_saga.Saga/StartReturn"
 )
 
 // SagaClient is the client API for Saga service.
@@ -27,6 +30,13 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type SagaClient interface {
 	StartCheckout(ctx context.Context, in *StartCheckoutRequest, opts ...grpc.CallOption) (*StartCheckoutResponse, error)
+	GetCheckoutStatus(ctx context.Context, in *GetCheckoutStatusRequest, opts ...grpc.CallOption) (*GetCheckoutStatusResponse, error)
+	// CancelOrder запускает отмену оформленного заказа: возврат денег, возврат товаров на склад
+	// и перевод заказа в Cancelled. Повторный вызов для уже отменяемого заказа ничего не делает.
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
+	// StartReturn запускает возврат части товаров оформленного заказа: частичный возврат денег
+	// по ценам заказа и возврат товаров на склад. Повтор с тем же returnID ничего не делает.
+	StartReturn(ctx context.Context, in *StartReturnRequest, opts ...grpc.CallOption) (*StartReturnResponse, error)
 }
 
 type sagaClient struct {
@@ -47,11 +57,48 @@ func (c *sagaClient) StartCheckout(ctx context.Context, in *StartCheckoutRequest
 	return out, nil
 }
 
+func (c *sagaClient) GetCheckoutStatus(ctx context.Context, in *GetCheckoutStatusRequest, opts ...grpc.CallOption) (*GetCheckoutStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCheckoutStatusResponse)
+	err := c.cc.Invoke(ctx, Saga_GetCheckoutStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelOrderResponse)
+	err := c.cc.Invoke(ctx, Saga_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *sagaClient) StartReturn(ctx context.Context, in *StartReturnRequest, opts ...grpc.CallOption) (*StartReturnResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartReturnResponse)
+	err := c.cc.Invoke(ctx, Saga_StartReturn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SagaServer is the server API for Saga service.
 // All implementations must embed UnimplementedSagaServer
 // for forward compatibility.
 type SagaServer interface {
 	StartCheckout(context.Context, *StartCheckoutRequest) (*StartCheckoutResponse, error)
+	GetCheckoutStatus(context.Context, *GetCheckoutStatusRequest) (*GetCheckoutStatusResponse, error)
+	// CancelOrder запускает отмену оформленного заказа: возврат денег, возврат товаров на склад
+	// и перевод заказа в Cancelled. Повторный вызов для уже отменяемого заказа ничего не делает.
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	// StartReturn запускает возврат части товаров оформленного заказа: частичный возврат денег
+	// по ценам заказа и возврат товаров на склад. Повтор с тем же returnID ничего не делает.
+	StartReturn(context.Context, *StartReturnRequest) (*StartReturnResponse, error)
 	mustEmbedUnimplementedSagaServer()
 }
 
@@ -65,6 +112,15 @@ type UnimplementedSagaServer struct{}
 func (UnimplementedSagaServer) StartCheckout(context.Context, *StartCheckoutRequest) (*StartCheckoutResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method StartCheckout not implemented")
 }
+func (UnimplementedSagaServer) GetCheckoutStatus(context.Context, *GetCheckoutStatusRequest) (*GetCheckoutStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCheckoutStatus not implemented")
+}
+func (UnimplementedSagaServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedSagaServer) StartReturn(context.Context, *StartReturnRequest) (*StartReturnResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method StartReturn not implemented")
+}
 func (UnimplementedSagaServer) mustEmbedUnimplementedSagaServer() {}
 func (UnimplementedSagaServer) testEmbeddedByValue()              {}
 
@@ -104,6 +160,60 @@ func _Saga_StartCheckout_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Saga_GetCheckoutStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCheckoutStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaServer).GetCheckoutStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Saga_GetCheckoutStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaServer).GetCheckoutStatus(ctx, req.(*GetCheckoutStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Saga_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Saga_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Saga_StartReturn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartReturnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SagaServer).StartReturn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Saga_StartReturn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SagaServer).StartReturn(ctx, req.(*StartReturnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Saga_ServiceDesc is the grpc.ServiceDesc for Saga service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +225,18 @@ var Saga_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "StartCheckout",
 			Handler:    _Saga_StartCheckout_Handler,
 		},
+		{
+			MethodName: "GetCheckoutStatus",
+			Handler:    _Saga_GetCheckoutStatus_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _Saga_CancelOrder_Handler,
+		},
+		{
+			MethodName: "StartReturn",
+			Handler:    _Saga_StartReturn_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "saga/saga.proto",
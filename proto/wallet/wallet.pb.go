@@ -361,6 +361,129 @@ func (x *CommitFundsResponse) GetMessage() string {
 	return ""
 }
 
+// RefundFundsRequest credits committed funds back (compensation after commit)
+type RefundFundsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        int64                  `protobuf:"varint,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	TransactionId string                 `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"` // Transaction that was committed
+	// Partial refund: idempotency key of this refund. Several partial refunds of one transaction
+	// may not exceed its committed amount. Empty refunds the whole committed amount.
+	RefundId      string `protobuf:"bytes,4,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundFundsRequest) Reset() {
+	*x = RefundFundsRequest{}
+	mi := &file_wallet_wallet_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundFundsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundFundsRequest) ProtoMessage() {}
+
+func (x *RefundFundsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_wallet_wallet_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundFundsRequest.ProtoReflect.Descriptor instead.
+func (*RefundFundsRequest) Descriptor() ([]byte, []int) {
+	return file_wallet_wallet_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RefundFundsRequest) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *RefundFundsRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RefundFundsRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *RefundFundsRequest) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+type RefundFundsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundFundsResponse) Reset() {
+	*x = RefundFundsResponse{}
+	mi := &file_wallet_wallet_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundFundsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundFundsResponse) ProtoMessage() {}
+
+func (x *RefundFundsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_wallet_wallet_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundFundsResponse.ProtoReflect.Descriptor instead.
+func (*RefundFundsResponse) Descriptor() ([]byte, []int) {
+	return file_wallet_wallet_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RefundFundsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RefundFundsResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 // TopUpRequest adds funds to user wallet
 type TopUpRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -371,7 +494,7 @@ type TopUpRequest struct {
 
 func (x *TopUpRequest) Reset() {
 	*x = TopUpRequest{}
-	mi := &file_wallet_wallet_proto_msgTypes[6]
+	mi := &file_wallet_wallet_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -383,7 +506,7 @@ func (x *TopUpRequest) String() string {
 func (*TopUpRequest) ProtoMessage() {}
 
 func (x *TopUpRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_wallet_wallet_proto_msgTypes[6]
+	mi := &file_wallet_wallet_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -396,7 +519,7 @@ func (x *TopUpRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TopUpRequest.ProtoReflect.Descriptor instead.
 func (*TopUpRequest) Descriptor() ([]byte, []int) {
-	return file_wallet_wallet_proto_rawDescGZIP(), []int{6}
+	return file_wallet_wallet_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *TopUpRequest) GetAmount() int64 {
@@ -417,7 +540,7 @@ type TopUpResponse struct {
 
 func (x *TopUpResponse) Reset() {
 	*x = TopUpResponse{}
-	mi := &file_wallet_wallet_proto_msgTypes[7]
+	mi := &file_wallet_wallet_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -429,7 +552,7 @@ func (x *TopUpResponse) String() string {
 func (*TopUpResponse) ProtoMessage() {}
 
 func (x *TopUpResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_wallet_wallet_proto_msgTypes[7]
+	mi := &file_wallet_wallet_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -442,7 +565,7 @@ func (x *TopUpResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TopUpResponse.ProtoReflect.Descriptor instead.
 func (*TopUpResponse) Descriptor() ([]byte, []int) {
-	return file_wallet_wallet_proto_rawDescGZIP(), []int{7}
+	return file_wallet_wallet_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *TopUpResponse) GetSuccess() bool {
@@ -474,7 +597,7 @@ type BalanceRequest struct {
 
 func (x *BalanceRequest) Reset() {
 	*x = BalanceRequest{}
-	mi := &file_wallet_wallet_proto_msgTypes[8]
+	mi := &file_wallet_wallet_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -486,7 +609,7 @@ func (x *BalanceRequest) String() string {
 func (*BalanceRequest) ProtoMessage() {}
 
 func (x *BalanceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_wallet_wallet_proto_msgTypes[8]
+	mi := &file_wallet_wallet_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -499,7 +622,7 @@ func (x *BalanceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BalanceRequest.ProtoReflect.Descriptor instead.
 func (*BalanceRequest) Descriptor() ([]byte, []int) {
-	return file_wallet_wallet_proto_rawDescGZIP(), []int{8}
+	return file_wallet_wallet_proto_rawDescGZIP(), []int{10}
 }
 
 type BalanceResponse struct {
@@ -513,7 +636,7 @@ type BalanceResponse struct {
 
 func (x *BalanceResponse) Reset() {
 	*x = BalanceResponse{}
-	mi := &file_wallet_wallet_proto_msgTypes[9]
+	mi := &file_wallet_wallet_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -525,7 +648,7 @@ func (x *BalanceResponse) String() string {
 func (*BalanceResponse) ProtoMessage() {}
 
 func (x *BalanceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_wallet_wallet_proto_msgTypes[9]
+	mi := &file_wallet_wallet_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -538,7 +661,7 @@ func (x *BalanceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BalanceResponse.ProtoReflect.Descriptor instead.
 func (*BalanceResponse) Descriptor() ([]byte, []int) {
-	return file_wallet_wallet_proto_rawDescGZIP(), []int{9}
+	return file_wallet_wallet_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *BalanceResponse) GetBalance() int64 {
@@ -570,7 +693,7 @@ type CreateWalletRequest struct {
 
 func (x *CreateWalletRequest) Reset() {
 	*x = CreateWalletRequest{}
-	mi := &file_wallet_wallet_proto_msgTypes[10]
+	mi := &file_wallet_wallet_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -582,7 +705,7 @@ func (x *CreateWalletRequest) String() string {
 func (*CreateWalletRequest) ProtoMessage() {}
 
 func (x *CreateWalletRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_wallet_wallet_proto_msgTypes[10]
+	mi := &file_wallet_wallet_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -595,7 +718,7 @@ func (x *CreateWalletRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateWalletRequest.ProtoReflect.Descriptor instead.
 func (*CreateWalletRequest) Descriptor() ([]byte, []int) {
-	return file_wallet_wallet_proto_rawDescGZIP(), []int{10}
+	return file_wallet_wallet_proto_rawDescGZIP(), []int{12}
 }
 
 type CreateWalletResponse struct {
@@ -608,7 +731,7 @@ type CreateWalletResponse struct {
 
 func (x *CreateWalletResponse) Reset() {
 	*x = CreateWalletResponse{}
-	mi := &file_wallet_wallet_proto_msgTypes[11]
+	mi := &file_wallet_wallet_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -620,7 +743,7 @@ func (x *CreateWalletResponse) String() string {
 func (*CreateWalletResponse) ProtoMessage() {}
 
 func (x *CreateWalletResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_wallet_wallet_proto_msgTypes[11]
+	mi := &file_wallet_wallet_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -633,7 +756,7 @@ func (x *CreateWalletResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateWalletResponse.ProtoReflect.Descriptor instead.
 func (*CreateWalletResponse) Descriptor() ([]byte, []int) {
-	return file_wallet_wallet_proto_rawDescGZIP(), []int{11}
+	return file_wallet_wallet_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *CreateWalletResponse) GetSuccess() bool {
@@ -675,6 +798,14 @@ const file_wallet_wallet_proto_rawDesc = "" +
 	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\"I\n" +
 	"\x13CommitFundsResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x89\x01\n" +
+	"\x12RefundFundsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\x03R\x06userId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12%\n" +
+	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\x12\x1b\n" +
+	"\trefund_id\x18\x04 \x01(\tR\brefundId\"I\n" +
+	"\x13RefundFundsResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
 	"\amessage\x18\x02 \x01(\tR\amessage\"&\n" +
 	"\fTopUpRequest\x12\x16\n" +
 	"\x06amount\x18\x01 \x01(\x03R\x06amount\"d\n" +
@@ -695,11 +826,12 @@ const file_wallet_wallet_proto_rawDesc = "" +
 	"\vWalletTopUP\x12p\n" +
 	"\fCreateWallet\x12!.proto_wallet.CreateWalletRequest\x1a\".proto_wallet.CreateWalletResponse\"\x19\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/wallet/create\x12_\n" +
 	"\aBalance\x12\x1c.proto_wallet.BalanceRequest\x1a\x1d.proto_wallet.BalanceResponse\"\x17\x82\xd3\xe4\x93\x02\x11\x12\x0f/wallet/balance\x12Z\n" +
-	"\x05TopUp\x12\x1a.proto_wallet.TopUpRequest\x1a\x1b.proto_wallet.TopUpResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/wallet/topup2\x8a\x02\n" +
+	"\x05TopUp\x12\x1a.proto_wallet.TopUpRequest\x1a\x1b.proto_wallet.TopUpResponse\"\x18\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/wallet/topup2\xde\x02\n" +
 	"\x06Wallet\x12U\n" +
 	"\fReserveFunds\x12!.proto_wallet.ReserveFundsRequest\x1a\".proto_wallet.ReserveFundsResponse\x12U\n" +
 	"\fReleaseFunds\x12!.proto_wallet.ReleaseFundsRequest\x1a\".proto_wallet.ReleaseFundsResponse\x12R\n" +
-	"\vCommitFunds\x12 .proto_wallet.CommitFundsRequest\x1a!.proto_wallet.CommitFundsResponseB0Z.github.com/vsespontanno/eCommerce/proto/walletb\x06proto3"
+	"\vCommitFunds\x12 .proto_wallet.CommitFundsRequest\x1a!.proto_wallet.CommitFundsResponse\x12R\n" +
+	"\vRefundFunds\x12 .proto_wallet.RefundFundsRequest\x1a!.proto_wallet.RefundFundsResponseB0Z.github.com/vsespontanno/eCommerce/proto/walletb\x06proto3"
 
 var (
 	file_wallet_wallet_proto_rawDescOnce sync.Once
@@ -713,7 +845,7 @@ func file_wallet_wallet_proto_rawDescGZIP() []byte {
 	return file_wallet_wallet_proto_rawDescData
 }
 
-var file_wallet_wallet_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_wallet_wallet_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_wallet_wallet_proto_goTypes = []any{
 	(*ReserveFundsRequest)(nil),  // 0: proto_wallet.ReserveFundsRequest
 	(*ReserveFundsResponse)(nil), // 1: proto_wallet.ReserveFundsResponse
@@ -721,28 +853,32 @@ var file_wallet_wallet_proto_goTypes = []any{
 	(*ReleaseFundsResponse)(nil), // 3: proto_wallet.ReleaseFundsResponse
 	(*CommitFundsRequest)(nil),   // 4: proto_wallet.CommitFundsRequest
 	(*CommitFundsResponse)(nil),  // 5: proto_wallet.CommitFundsResponse
-	(*TopUpRequest)(nil),         // 6: proto_wallet.TopUpRequest
-	(*TopUpResponse)(nil),        // 7: proto_wallet.TopUpResponse
-	(*BalanceRequest)(nil),       // 8: proto_wallet.BalanceRequest
-	(*BalanceResponse)(nil),      // 9: proto_wallet.BalanceResponse
-	(*CreateWalletRequest)(nil),  // 10: proto_wallet.CreateWalletRequest
-	(*CreateWalletResponse)(nil), // 11: proto_wallet.CreateWalletResponse
+	(*RefundFundsRequest)(nil),   // 6: proto_wallet.RefundFundsRequest
+	(*RefundFundsResponse)(nil),  // 7: proto_wallet.RefundFundsResponse
+	(*TopUpRequest)(nil),         // 8: proto_wallet.TopUpRequest
+	(*TopUpResponse)(nil),        // 9: proto_wallet.TopUpResponse
+	(*BalanceRequest)(nil),       // 10: proto_wallet.BalanceRequest
+	(*BalanceResponse)(nil),      // 11: proto_wallet.BalanceResponse
+	(*CreateWalletRequest)(nil),  // 12: proto_wallet.CreateWalletRequest
+	(*CreateWalletResponse)(nil), // 13: proto_wallet.CreateWalletResponse
 }
 var file_wallet_wallet_proto_depIdxs = []int32{
-	10, // 0: proto_wallet.WalletTopUP.CreateWallet:input_type -> proto_wallet.CreateWalletRequest
-	8,  // 1: proto_wallet.WalletTopUP.Balance:input_type -> proto_wallet.BalanceRequest
-	6,  // 2: proto_wallet.WalletTopUP.TopUp:input_type -> proto_wallet.TopUpRequest
+	12, // 0: proto_wallet.WalletTopUP.CreateWallet:input_type -> proto_wallet.CreateWalletRequest
+	10, // 1: proto_wallet.WalletTopUP.Balance:input_type -> proto_wallet.BalanceRequest
+	8,  // 2: proto_wallet.WalletTopUP.TopUp:input_type -> proto_wallet.TopUpRequest
 	0,  // 3: proto_wallet.Wallet.ReserveFunds:input_type -> proto_wallet.ReserveFundsRequest
 	2,  // 4: proto_wallet.Wallet.ReleaseFunds:input_type -> proto_wallet.ReleaseFundsRequest
 	4,  // 5: proto_wallet.Wallet.CommitFunds:input_type -> proto_wallet.CommitFundsRequest
-	11, // 6: proto_wallet.WalletTopUP.CreateWallet:output_type -> proto_wallet.CreateWalletResponse
-	9,  // 7: proto_wallet.WalletTopUP.Balance:output_type -> proto_wallet.BalanceResponse
-	7,  // 8: proto_wallet.WalletTopUP.TopUp:output_type -> proto_wallet.TopUpResponse
-	1,  // 9: proto_wallet.Wallet.ReserveFunds:output_type -> proto_wallet.ReserveFundsResponse
-	3,  // 10: proto_wallet.Wallet.ReleaseFunds:output_type -> proto_wallet.ReleaseFundsResponse
-	5,  // 11: proto_wallet.Wallet.CommitFunds:output_type -> proto_wallet.CommitFundsResponse
-	6,  // [6:12] is the sub-list for method output_type
-	0,  // [0:6] is the sub-list for method input_type
+	6,  // 6: proto_wallet.Wallet.RefundFunds:input_type -> proto_wallet.RefundFundsRequest
+	13, // 7: proto_wallet.WalletTopUP.CreateWallet:output_type -> proto_wallet.CreateWalletResponse
+	11, // 8: proto_wallet.WalletTopUP.Balance:output_type -> proto_wallet.BalanceResponse
+	9,  // 9: proto_wallet.WalletTopUP.TopUp:output_type -> proto_wallet.TopUpResponse
+	1,  // 10: proto_wallet.Wallet.ReserveFunds:output_type -> proto_wallet.ReserveFundsResponse
+	3,  // 11: proto_wallet.Wallet.ReleaseFunds:output_type -> proto_wallet.ReleaseFundsResponse
+	5,  // 12: proto_wallet.Wallet.CommitFunds:output_type -> proto_wallet.CommitFundsResponse
+	7,  // 13: proto_wallet.Wallet.RefundFunds:output_type -> proto_wallet.RefundFundsResponse
+	7,  // [7:14] is the sub-list for method output_type
+	0,  // [0:7] is the sub-list for method input_type
 	0,  // [0:0] is the sub-list for extension type_name
 	0,  // [0:0] is the sub-list for extension extendee
 	0,  // [0:0] is the sub-list for field type_name
@@ -759,7 +895,7 @@ func file_wallet_wallet_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_wallet_wallet_proto_rawDesc), len(file_wallet_wallet_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   12,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -204,6 +204,7 @@ const (
 	Wallet_ReserveFunds_FullMethodName = "/proto_wallet.Wallet/ReserveFunds"
 	Wallet_ReleaseFunds_FullMethodName = "/proto_wallet.Wallet/ReleaseFunds"
 	Wallet_CommitFunds_FullMethodName  = "/proto_wallet.Wallet/CommitFunds"
+	Wallet_RefundFunds_FullMethodName  = "/proto_wallet.Wallet/RefundFunds"
 )
 
 // WalletClient is the client API for Wallet service.
@@ -215,6 +216,7 @@ type WalletClient interface {
 	ReserveFunds(ctx context.Context, in *ReserveFundsRequest, opts ...grpc.CallOption) (*ReserveFundsResponse, error)
 	ReleaseFunds(ctx context.Context, in *ReleaseFundsRequest, opts ...grpc.CallOption) (*ReleaseFundsResponse, error)
 	CommitFunds(ctx context.Context, in *CommitFundsRequest, opts ...grpc.CallOption) (*CommitFundsResponse, error)
+	RefundFunds(ctx context.Context, in *RefundFundsRequest, opts ...grpc.CallOption) (*RefundFundsResponse, error)
 }
 
 type walletClient struct {
@@ -255,6 +257,16 @@ func (c *walletClient) CommitFunds(ctx context.Context, in *CommitFundsRequest,
 	return out, nil
 }
 
+func (c *walletClient) RefundFunds(ctx context.Context, in *RefundFundsRequest, opts ...grpc.CallOption) (*RefundFundsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefundFundsResponse)
+	err := c.cc.Invoke(ctx, Wallet_RefundFunds_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // WalletServer is the server API for Wallet service.
 // All implementations must embed UnimplementedWalletServer
 // for forward compatibility.
@@ -264,6 +276,7 @@ type WalletServer interface {
 	ReserveFunds(context.Context, *ReserveFundsRequest) (*ReserveFundsResponse, error)
 	ReleaseFunds(context.Context, *ReleaseFundsRequest) (*ReleaseFundsResponse, error)
 	CommitFunds(context.Context, *CommitFundsRequest) (*CommitFundsResponse, error)
+	RefundFunds(context.Context, *RefundFundsRequest) (*RefundFundsResponse, error)
 	mustEmbedUnimplementedWalletServer()
 }
 
@@ -283,6 +296,9 @@ func (UnimplementedWalletServer) ReleaseFunds(context.Context, *ReleaseFundsRequ
 func (UnimplementedWalletServer) CommitFunds(context.Context, *CommitFundsRequest) (*CommitFundsResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CommitFunds not implemented")
 }
+func (UnimplementedWalletServer) RefundFunds(context.Context, *RefundFundsRequest) (*RefundFundsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RefundFunds not implemented")
+}
 func (UnimplementedWalletServer) mustEmbedUnimplementedWalletServer() {}
 func (UnimplementedWalletServer) testEmbeddedByValue()                {}
 
@@ -358,6 +374,24 @@ func _Wallet_CommitFunds_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Wallet_RefundFunds_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefundFundsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WalletServer).RefundFunds(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Wallet_RefundFunds_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WalletServer).RefundFunds(ctx, req.(*RefundFundsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Wallet_ServiceDesc is the grpc.ServiceDesc for Wallet service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -377,6 +411,10 @@ var Wallet_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CommitFunds",
 			Handler:    _Wallet_CommitFunds_Handler,
 		},
+		{
+			MethodName: "RefundFunds",
+			Handler:    _Wallet_RefundFunds_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "wallet/wallet.proto",
@@ -9,6 +9,7 @@ import (
 
 	_ "github.com/lib/pq"
 	"github.com/vsespontanno/eCommerce/pkg/logger"
+	"github.com/vsespontanno/eCommerce/pkg/pubsub"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/app"
 	applicationCart "github.com/vsespontanno/eCommerce/services/cart-service/internal/application/cart"
 	applicationOrder "github.com/vsespontanno/eCommerce/services/cart-service/internal/application/order"
@@ -73,42 +74,35 @@ func main() {
 	sagaService := applicationSaga.NewSagaService(logger.Log, redisStore, sagaClient)
 	rateLimiter := middleware.NewRateLimiter(redisClient, cfg.RateLimitRPS)
 	orderService := applicationOrder.NewOrderCompleteService(logger.Log, pgStore, redisCleaner, orderClient)
+	failService := applicationOrder.NewOrderFailService(logger.Log, pgStore)
 	jobUpdater := jobs.NewCartSyncJob(pgStore, redisUpdater, logger.Log, time.Second*15)
 
 	app := app.New(logger.Log, cfg.HTTPPort, cartService)
 	grpcJWTClientPort := cfg.GRPCJWTClientPort
 	jwtClient := jwtClient.NewJwtClient(grpcJWTClientPort)
 
-	var kafkaConsumer *messaging.KafkaConsumer
-	var kafkaCancel context.CancelFunc
-
-	if cfg.KafkaBroker != "" {
-		var err error
-		kafkaConsumer, err = messaging.NewKafkaConsumer(
-			cfg.KafkaBroker,
-			cfg.KafkaGroup,
-			cfg.KafkaTopic,
-			cfg.KafkaSASLUsername,
-			cfg.KafkaSASLPassword,
-			cfg.KafkaSSLCAPath,
-			cfg.KafkaSecurityProtocol,
-			cfg.KafkaSASLMechanism,
-			logger.Log,
-			orderService,
-		)
+	var orderConsumer *messaging.Consumer
+	var consumerCancel context.CancelFunc
+
+	if cfg.EventTransport != "" {
+		subscriber, err := pubsub.NewSubscriber(cfg.PubSub(), cfg.KafkaGroup, pg.DB, logger.Log)
 		if err != nil {
-			logger.Log.Warnw("Failed to connect to Kafka, continuing without it", "error", err)
+			logger.Log.Warnw("Failed to create event subscriber, continuing without it", "transport", cfg.EventTransport, "error", err)
 		} else {
-			logger.Log.Info("Kafka consumer initialized successfully")
-			kafkaCtx, cancel := context.WithCancel(context.Background())
-			kafkaCancel = cancel
-			kafkaConsumer.Poll(kafkaCtx)
+			logger.Log.Infow("Event consumer initialized successfully", "transport", cfg.EventTransport)
+			if cfg.EventTransport == pubsub.TransportMemory {
+				logger.Log.Warn("In-memory event transport receives events only from this process")
+			}
+			orderConsumer = messaging.NewConsumer(subscriber, cfg.KafkaTopic, logger.Log, orderService, failService)
+			consumerCtx, cancel := context.WithCancel(context.Background())
+			consumerCancel = cancel
+			orderConsumer.Poll(consumerCtx)
 		}
 	} else {
-		logger.Log.Info("Kafka broker not configured, running without Kafka consumer")
+		logger.Log.Info("Event transport not configured, running without order events consumer")
 	}
 
-	handler := handlers.New(cartService, logger.Log, jwtClient, rateLimiter, sagaService)
+	handler := handlers.New(cartService, logger.Log, jwtClient, rateLimiter, sagaService, failService)
 	handler.RegisterRoutes(app.HTTPApp.Router())
 
 	go func() {
@@ -125,13 +119,13 @@ func main() {
 	<-stop
 	logger.Log.Info("Shutting down server...")
 
-	// Останавливаем Kafka consumer если он был инициализирован
-	if kafkaConsumer != nil {
-		if kafkaCancel != nil {
-			kafkaCancel()
+	// Останавливаем consumer событий если он был инициализирован
+	if orderConsumer != nil {
+		if consumerCancel != nil {
+			consumerCancel()
 		}
-		kafkaConsumer.Close()
-		logger.Log.Info("Kafka consumer stopped")
+		orderConsumer.Close()
+		logger.Log.Info("Order events consumer stopped")
 	}
 
 	// Останавливаем HTTP сервер
@@ -0,0 +1,61 @@
+package order
+
+import (
+	"context"
+
+	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/order/entity"
+	"github.com/vsespontanno/eCommerce/services/cart-service/internal/infrastructure/metrics"
+	"go.uber.org/zap"
+)
+
+// RecentFailuresLimit - сколько последних неудачных оформлений показываем пользователю
+const RecentFailuresLimit = 20
+
+type FailureStore interface {
+	SaveCheckoutFailure(ctx context.Context, order *entity.OrderEvent) error
+	CheckoutFailures(ctx context.Context, userID int64, limit int) ([]entity.CheckoutFailure, error)
+}
+
+// FailService обрабатывает OrderFailed: корзина остаётся как есть,
+// чтобы пользователь мог пополнить кошелёк или поправить состав и повторить заказ
+type FailService struct {
+	logger *zap.SugaredLogger
+	store  FailureStore
+}
+
+func NewOrderFailService(logger *zap.SugaredLogger, store FailureStore) *FailService {
+	return &FailService{
+		logger: logger,
+		store:  store,
+	}
+}
+
+// FailOrder записывает неудачу оформления для пользователя
+func (f *FailService) FailOrder(ctx context.Context, order *entity.OrderEvent) error {
+	if order.Reason == "" {
+		order.Reason = entity.FailureInternal
+	}
+
+	if err := f.store.SaveCheckoutFailure(ctx, order); err != nil {
+		f.logger.Errorw("Failed to record checkout failure",
+			"orderID", order.OrderID,
+			"userID", order.UserID,
+			"reason", order.Reason,
+			"error", err,
+		)
+		return err
+	}
+
+	metrics.CheckoutFailuresTotal.WithLabelValues(order.Reason).Inc()
+	f.logger.Infow("Checkout failure recorded, cart kept",
+		"orderID", order.OrderID,
+		"userID", order.UserID,
+		"reason", order.Reason,
+	)
+	return nil
+}
+
+// CheckoutFailures возвращает последние неудачные оформления заказов пользователя
+func (f *FailService) CheckoutFailures(ctx context.Context, userID int64) ([]entity.CheckoutFailure, error) {
+	return f.store.CheckoutFailures(ctx, userID, RecentFailuresLimit)
+}
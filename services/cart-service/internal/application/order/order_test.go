@@ -132,3 +132,89 @@ func TestCompleteService_CompleteOrder(t *testing.T) {
 		mockRedis.AssertExpectations(t)
 	})
 }
+
+type MockFailureStore struct {
+	mock.Mock
+}
+
+func (m *MockFailureStore) SaveCheckoutFailure(ctx context.Context, order *entity.OrderEvent) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+func (m *MockFailureStore) CheckoutFailures(ctx context.Context, userID int64, limit int) ([]entity.CheckoutFailure, error) {
+	args := m.Called(ctx, userID, limit)
+	failures, _ := args.Get(0).([]entity.CheckoutFailure)
+	return failures, args.Error(1)
+}
+
+func TestFailService_FailOrder(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	t.Run("Success", func(t *testing.T) {
+		mockStore := new(MockFailureStore)
+		service := NewOrderFailService(logger, mockStore)
+
+		orderEvent := &entity.OrderEvent{
+			OrderID: "order-123",
+			UserID:  1,
+			Reason:  entity.FailureOutOfStock,
+		}
+
+		mockStore.On("SaveCheckoutFailure", mock.Anything, orderEvent).Return(nil)
+
+		err := service.FailOrder(context.Background(), orderEvent)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.FailureOutOfStock, orderEvent.Reason)
+		mockStore.AssertExpectations(t)
+	})
+
+	t.Run("Empty Reason Defaults To Internal", func(t *testing.T) {
+		mockStore := new(MockFailureStore)
+		service := NewOrderFailService(logger, mockStore)
+
+		orderEvent := &entity.OrderEvent{
+			OrderID: "order-123",
+			UserID:  1,
+		}
+
+		mockStore.On("SaveCheckoutFailure", mock.Anything, orderEvent).Return(nil)
+
+		err := service.FailOrder(context.Background(), orderEvent)
+
+		assert.NoError(t, err)
+		assert.Equal(t, entity.FailureInternal, orderEvent.Reason)
+	})
+
+	t.Run("Store Error", func(t *testing.T) {
+		mockStore := new(MockFailureStore)
+		service := NewOrderFailService(logger, mockStore)
+
+		orderEvent := &entity.OrderEvent{
+			OrderID: "order-123",
+			UserID:  1,
+			Reason:  entity.FailureInsufficientFunds,
+		}
+
+		mockStore.On("SaveCheckoutFailure", mock.Anything, orderEvent).Return(errors.New("db error"))
+
+		err := service.FailOrder(context.Background(), orderEvent)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestFailService_CheckoutFailures(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	mockStore := new(MockFailureStore)
+	service := NewOrderFailService(logger, mockStore)
+
+	mockStore.On("CheckoutFailures", mock.Anything, int64(1), RecentFailuresLimit).Return([]entity.CheckoutFailure{{OrderID: "order-123"}}, nil)
+
+	failures, err := service.CheckoutFailures(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Len(t, failures, 1)
+	mockStore.AssertExpectations(t)
+}
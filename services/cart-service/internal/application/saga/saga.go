@@ -2,17 +2,22 @@ package saga
 
 import (
 	"context"
+	"errors"
 
+	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/cart/entity"
+	orderEntity "github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/order/entity"
 	"go.uber.org/zap"
 )
 
 type Saga interface {
-	StartCheckout(ctx context.Context, userID int64, cart *entity.Cart) (string, error)
+	StartCheckout(ctx context.Context, userID int64, idempotencyKey string, cart *entity.Cart) (*orderEntity.CheckoutResult, error)
+	GetCheckoutStatus(ctx context.Context, userID int64, orderID string) (*orderEntity.CheckoutStatus, error)
 }
 
 type Carter interface {
 	GetCartProducts(ctx context.Context, userID int64) (*entity.Cart, error)
+	SaveCart(ctx context.Context, userID int64, cart *entity.Cart) error
 }
 
 type Service struct {
@@ -29,13 +34,23 @@ func NewSagaService(sugarLogger *zap.SugaredLogger, redisStore Carter, sagaClien
 	}
 }
 
-func (s *Service) Checkout(ctx context.Context, userID int64) (string, error) {
+// Checkout оформляет заказ из корзины. Повтор с тем же idempotencyKey возвращает исходный заказ
+func (s *Service) Checkout(ctx context.Context, userID int64, idempotencyKey string) (*orderEntity.CheckoutResult, error) {
 	cart, err := s.redisStore.GetCartProducts(ctx, userID)
 	if err != nil {
 		s.sugarLogger.Errorf("error while getting cart from store: %v", err)
-		return "", err
+		return nil, err
+	}
+	resp, err := s.sagaClient.StartCheckout(ctx, userID, idempotencyKey, cart)
+	var pricesChanged *apperrors.PricesChangedError
+	if errors.As(err, &pricesChanged) {
+		// Обновляем цены в корзине, чтобы повторный checkout прошёл по актуальным
+		if saveErr := s.redisStore.SaveCart(ctx, userID, pricesChanged.Quote); saveErr != nil {
+			s.sugarLogger.Errorf("error while saving re-quoted cart: %v", saveErr)
+			return nil, saveErr
+		}
+		return nil, err
 	}
-	resp, err := s.sagaClient.StartCheckout(ctx, userID, cart)
 	if err != nil {
 		s.sugarLogger.Errorf("error while starting checkout: %v", err)
 		return resp, err
@@ -44,3 +59,12 @@ func (s *Service) Checkout(ctx context.Context, userID int64) (string, error) {
 	return resp, nil
 
 }
+
+func (s *Service) CheckoutStatus(ctx context.Context, userID int64, orderID string) (*orderEntity.CheckoutStatus, error) {
+	checkoutStatus, err := s.sagaClient.GetCheckoutStatus(ctx, userID, orderID)
+	if err != nil {
+		s.sugarLogger.Errorf("error while getting checkout status: %v", err)
+		return nil, err
+	}
+	return checkoutStatus, nil
+}
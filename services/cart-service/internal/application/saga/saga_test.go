@@ -7,7 +7,9 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/cart/entity"
+	orderEntity "github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/order/entity"
 	"go.uber.org/zap"
 )
 
@@ -24,13 +26,27 @@ func (m *MockCarter) GetCartProducts(ctx context.Context, userID int64) (*entity
 	return args.Get(0).(*entity.Cart), args.Error(1)
 }
 
+func (m *MockCarter) SaveCart(ctx context.Context, userID int64, cart *entity.Cart) error {
+	args := m.Called(ctx, userID, cart)
+	return args.Error(0)
+}
+
 type MockSagaClient struct {
 	mock.Mock
 }
 
-func (m *MockSagaClient) StartCheckout(ctx context.Context, userID int64, cart *entity.Cart) (string, error) {
-	args := m.Called(ctx, userID, cart)
-	return args.String(0), args.Error(1)
+func (m *MockSagaClient) StartCheckout(ctx context.Context, userID int64, idempotencyKey string, cart *entity.Cart) (*orderEntity.CheckoutResult, error) {
+	args := m.Called(ctx, userID, idempotencyKey, cart)
+	result, _ := args.Get(0).(*orderEntity.CheckoutResult)
+	return result, args.Error(1)
+}
+
+func (m *MockSagaClient) GetCheckoutStatus(ctx context.Context, userID int64, orderID string) (*orderEntity.CheckoutStatus, error) {
+	args := m.Called(ctx, userID, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orderEntity.CheckoutStatus), args.Error(1)
 }
 
 func TestService_Checkout(t *testing.T) {
@@ -48,12 +64,14 @@ func TestService_Checkout(t *testing.T) {
 		}
 
 		mockCarter.On("GetCartProducts", mock.Anything, int64(1)).Return(cart, nil)
-		mockSaga.On("StartCheckout", mock.Anything, int64(1), cart).Return("order-123", nil)
+		mockSaga.On("StartCheckout", mock.Anything, int64(1), "key-1", cart).Return(&orderEntity.CheckoutResult{
+			CheckoutStatus: orderEntity.CheckoutStatus{OrderID: "order-123", Status: "PENDING"},
+		}, nil)
 
-		orderID, err := service.Checkout(context.Background(), 1)
+		result, err := service.Checkout(context.Background(), 1, "key-1")
 
 		assert.NoError(t, err)
-		assert.Equal(t, "order-123", orderID)
+		assert.Equal(t, "order-123", result.OrderID)
 		mockCarter.AssertExpectations(t)
 		mockSaga.AssertExpectations(t)
 	})
@@ -65,12 +83,12 @@ func TestService_Checkout(t *testing.T) {
 
 		mockCarter.On("GetCartProducts", mock.Anything, int64(1)).Return(nil, errors.New("redis error"))
 
-		orderID, err := service.Checkout(context.Background(), 1)
+		result, err := service.Checkout(context.Background(), 1, "")
 
 		assert.Error(t, err)
-		assert.Empty(t, orderID)
+		assert.Nil(t, result)
 		mockCarter.AssertExpectations(t)
-		mockSaga.AssertNotCalled(t, "StartCheckout", mock.Anything, mock.Anything, mock.Anything)
+		mockSaga.AssertNotCalled(t, "StartCheckout", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
 	})
 
 	t.Run("StartCheckout Error", func(t *testing.T) {
@@ -85,13 +103,91 @@ func TestService_Checkout(t *testing.T) {
 		}
 
 		mockCarter.On("GetCartProducts", mock.Anything, int64(1)).Return(cart, nil)
-		mockSaga.On("StartCheckout", mock.Anything, int64(1), cart).Return("", errors.New("saga error"))
+		mockSaga.On("StartCheckout", mock.Anything, int64(1), "", cart).Return(nil, errors.New("saga error"))
 
-		orderID, err := service.Checkout(context.Background(), 1)
+		result, err := service.Checkout(context.Background(), 1, "")
 
 		assert.Error(t, err)
-		assert.Empty(t, orderID)
+		assert.Nil(t, result)
 		mockCarter.AssertExpectations(t)
 		mockSaga.AssertExpectations(t)
 	})
+
+	t.Run("Prices Changed", func(t *testing.T) {
+		mockCarter := new(MockCarter)
+		mockSaga := new(MockSagaClient)
+		service := NewSagaService(logger, mockCarter, mockSaga)
+
+		cart := &entity.Cart{
+			Items: []entity.CartItem{
+				{ProductID: 1, Quantity: 1, Price: 100},
+			},
+		}
+		quote := &entity.Cart{
+			Items: []entity.CartItem{
+				{UserID: 1, ProductID: 1, Quantity: 1, Price: 120},
+			},
+		}
+
+		mockCarter.On("GetCartProducts", mock.Anything, int64(1)).Return(cart, nil)
+		mockSaga.On("StartCheckout", mock.Anything, int64(1), "", cart).Return(nil, &apperrors.PricesChangedError{Quote: quote})
+		mockCarter.On("SaveCart", mock.Anything, int64(1), quote).Return(nil)
+
+		result, err := service.Checkout(context.Background(), 1, "")
+
+		assert.ErrorIs(t, err, apperrors.ErrPricesChanged)
+		assert.Nil(t, result)
+		mockCarter.AssertExpectations(t)
+		mockSaga.AssertExpectations(t)
+	})
+
+	t.Run("Save Quote Error", func(t *testing.T) {
+		mockCarter := new(MockCarter)
+		mockSaga := new(MockSagaClient)
+		service := NewSagaService(logger, mockCarter, mockSaga)
+
+		cart := &entity.Cart{Items: []entity.CartItem{{ProductID: 1, Quantity: 1, Price: 100}}}
+		quote := &entity.Cart{Items: []entity.CartItem{{UserID: 1, ProductID: 1, Quantity: 1, Price: 120}}}
+
+		mockCarter.On("GetCartProducts", mock.Anything, int64(1)).Return(cart, nil)
+		mockSaga.On("StartCheckout", mock.Anything, int64(1), "", cart).Return(nil, &apperrors.PricesChangedError{Quote: quote})
+		mockCarter.On("SaveCart", mock.Anything, int64(1), quote).Return(errors.New("redis error"))
+
+		_, err := service.Checkout(context.Background(), 1, "")
+
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, apperrors.ErrPricesChanged)
+		mockCarter.AssertExpectations(t)
+	})
+}
+
+func TestService_CheckoutStatus(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	t.Run("Success", func(t *testing.T) {
+		mockSaga := new(MockSagaClient)
+		service := NewSagaService(logger, new(MockCarter), mockSaga)
+
+		expected := &orderEntity.CheckoutStatus{OrderID: "order-123", Status: "COMPLETED"}
+		mockSaga.On("GetCheckoutStatus", mock.Anything, int64(1), "order-123").Return(expected, nil)
+
+		checkoutStatus, err := service.CheckoutStatus(context.Background(), 1, "order-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, expected, checkoutStatus)
+		mockSaga.AssertExpectations(t)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockSaga := new(MockSagaClient)
+		service := NewSagaService(logger, new(MockCarter), mockSaga)
+
+		mockSaga.On("GetCheckoutStatus", mock.Anything, int64(1), "order-123").Return(nil, errors.New("saga error"))
+
+		checkoutStatus, err := service.CheckoutStatus(context.Background(), 1, "order-123")
+
+		assert.Error(t, err)
+		assert.Nil(t, checkoutStatus)
+		mockSaga.AssertExpectations(t)
+	})
 }
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/vsespontanno/eCommerce/pkg/pubsub"
 )
 
 type Config struct {
@@ -32,6 +34,10 @@ type Config struct {
 	KafkaSSLCAPath         string
 	KafkaSecurityProtocol  string
 	KafkaSASLMechanism     string
+	// Транспорт событий заказов: kafka, postgres или memory. По умолчанию kafka, если задан KAFKA_BROKER,
+	// иначе корзина событий не читает.
+	EventTransport    string
+	EventPollInterval time.Duration
 }
 
 func MustLoad() (*Config, error) {
@@ -58,6 +64,16 @@ func MustLoad() (*Config, error) {
 		MaxProductQuantity = 100 // default max 100 items per product
 	}
 
+	EventTransport := os.Getenv("EVENT_TRANSPORT")
+	if EventTransport == "" && os.Getenv("KAFKA_BROKER") != "" {
+		EventTransport = pubsub.TransportKafka
+	}
+
+	EventPollInterval := 500 * time.Millisecond
+	if ms, err := strconv.Atoi(os.Getenv("EVENT_POLL_INTERVAL_MS")); err == nil && ms > 0 {
+		EventPollInterval = time.Duration(ms) * time.Millisecond
+	}
+
 	return &Config{
 		PGUser:                 os.Getenv("PG_USER"),
 		PGPassword:             os.Getenv("PG_PASSWORD"),
@@ -82,5 +98,23 @@ func MustLoad() (*Config, error) {
 		KafkaSSLCAPath:         os.Getenv("KAFKA_SSL_CA_PATH"),
 		KafkaSecurityProtocol:  os.Getenv("KAFKA_SECURITY_PROTOCOL"),
 		KafkaSASLMechanism:     os.Getenv("KAFKA_SASL_MECHANISM"),
+		EventTransport:         EventTransport,
+		EventPollInterval:      EventPollInterval,
 	}, nil
 }
+
+// PubSub - настройки транспорта событий
+func (c *Config) PubSub() pubsub.Config {
+	return pubsub.Config{
+		Transport: c.EventTransport,
+		Kafka: pubsub.KafkaConfig{
+			Brokers:          c.KafkaBroker,
+			SASLUsername:     c.KafkaSASLUsername,
+			SASLPassword:     c.KafkaSASLPassword,
+			SSLCAPath:        c.KafkaSSLCAPath,
+			SecurityProtocol: c.KafkaSecurityProtocol,
+			SASLMechanism:    c.KafkaSASLMechanism,
+		},
+		PollInterval: c.EventPollInterval,
+	}
+}
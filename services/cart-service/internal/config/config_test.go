@@ -3,8 +3,10 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vsespontanno/eCommerce/pkg/pubsub"
 )
 
 func TestMustLoad(t *testing.T) {
@@ -64,4 +66,28 @@ func TestMustLoad(t *testing.T) {
 		assert.Equal(t, 60, cfg.RateLimitRPS)
 		assert.Equal(t, 100, cfg.MaxProductQuantity)
 	})
+
+	t.Run("Event Transport", func(t *testing.T) {
+		os.Setenv("HTTP_PORT", "8080")
+		os.Unsetenv("EVENT_TRANSPORT")
+		os.Unsetenv("KAFKA_BROKER")
+		cfg, err := MustLoad()
+		assert.NoError(t, err)
+		assert.Empty(t, cfg.EventTransport)
+
+		os.Setenv("KAFKA_BROKER", "broker:9092")
+		defer os.Unsetenv("KAFKA_BROKER")
+		cfg, err = MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, pubsub.TransportKafka, cfg.EventTransport)
+
+		os.Setenv("EVENT_TRANSPORT", pubsub.TransportPostgres)
+		os.Setenv("EVENT_POLL_INTERVAL_MS", "200")
+		defer os.Unsetenv("EVENT_TRANSPORT")
+		defer os.Unsetenv("EVENT_POLL_INTERVAL_MS")
+		cfg, err = MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, pubsub.TransportPostgres, cfg.PubSub().Transport)
+		assert.Equal(t, 200*time.Millisecond, cfg.PubSub().PollInterval)
+	})
 }
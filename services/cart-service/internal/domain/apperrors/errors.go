@@ -1,8 +1,27 @@
 package apperrors
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/cart/entity"
+)
 
 var ErrProductIsNotInCart = errors.New("product is not in cart")
 var ErrTooManyProductsOfOneType = errors.New("you cannot order more than 100 products of 1 type")
 var ErrNoCartFound = errors.New("no cart found")
 var ErrProductIsNotInStock = errors.New("product is not in stock")
+var ErrOrderNotFound = errors.New("order not found")
+var ErrPricesChanged = errors.New("cart prices are out of date")
+
+// PricesChangedError - checkout отклонён из-за изменившихся цен; Quote - корзина по текущим ценам
+type PricesChangedError struct {
+	Quote *entity.Cart
+}
+
+func (e *PricesChangedError) Error() string {
+	return ErrPricesChanged.Error()
+}
+
+func (e *PricesChangedError) Unwrap() error {
+	return ErrPricesChanged
+}
@@ -1,5 +1,27 @@
 package entity
 
+import "time"
+
+// Типы событий saga-orchestrator, которые обрабатывает корзина
+const (
+	EventTypeOrderCompleted = "OrderCompleted"
+	EventTypeOrderFailed    = "OrderFailed"
+)
+
+// OrderEventVersion - версия схемы OrderEvent в конверте, которую понимает корзина.
+// События без конверта (LegacyVersion) имеют ту же схему.
+const OrderEventVersion = 1
+
+// StatusCompleted - статус OrderCompleted; по нему же узнаются старые события без event_type
+const StatusCompleted = "Completed"
+
+// Причины OrderFailed
+const (
+	FailureInsufficientFunds = "insufficient_funds"
+	FailureOutOfStock        = "out_of_stock"
+	FailureInternal          = "internal_error"
+)
+
 type OrderEvent struct {
 	OrderID   string            `json:"order_id"`
 	UserID    int64             `json:"user_id"`
@@ -7,9 +29,35 @@ type OrderEvent struct {
 	Total     int64             `json:"total"`
 	Status    string            `json:"status"`
 	EventType string            `json:"event_type,omitempty"` // Тип события для routing
+	// Только для OrderFailed
+	Reason  string `json:"reason,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// CheckoutFailure - неудачное оформление заказа; корзина при этом остаётся нетронутой
+type CheckoutFailure struct {
+	OrderID   string    `json:"orderId"`
+	Reason    string    `json:"reason"`
+	Message   string    `json:"message,omitempty"`
+	Total     int64     `json:"total"`
+	CreatedAt time.Time `json:"createdAt"`
 }
 
 type ProductForOrder struct {
 	ID       int64 `json:"product_id"`
 	Quantity int64 `json:"quantity"`
 }
+
+// CheckoutStatus - состояние оформления заказа, которое отдаёт saga-orchestrator
+type CheckoutStatus struct {
+	OrderID string `json:"orderId"`
+	Status  string `json:"status"`           // PENDING, COMPLETED или FAILED
+	Reason  string `json:"reason,omitempty"` // код причины отказа для FAILED, как в /cart/order/failures
+}
+
+// CheckoutResult - итог запуска checkout. Replayed - заказ уже был создан раньше
+// с тем же Idempotency-Key, и Status показывает его текущее состояние
+type CheckoutResult struct {
+	CheckoutStatus
+	Replayed bool `json:"replayed"`
+}
@@ -2,13 +2,18 @@ package saga
 
 import (
 	"context"
+	"errors"
 	"log"
 
 	"github.com/vsespontanno/eCommerce/proto/saga"
+	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/cart/entity"
+	orderEntity "github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/order/entity"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 type Client struct {
@@ -32,7 +37,8 @@ func NewSagaClient(addr string, logger *zap.SugaredLogger) *Client {
 	}
 }
 
-func (s *Client) StartCheckout(ctx context.Context, userID int64, cart *entity.Cart) (string, error) {
+// StartCheckout запускает оформление заказа. idempotencyKey может быть пустым - тогда повтор создаст новый заказ
+func (s *Client) StartCheckout(ctx context.Context, userID int64, idempotencyKey string, cart *entity.Cart) (*orderEntity.CheckoutResult, error) {
 	// конвертируем []entity.Product → []*saga.Cart
 	items := make([]*saga.Cart, 0, len(cart.Items))
 	for _, p := range cart.Items {
@@ -44,13 +50,58 @@ func (s *Client) StartCheckout(ctx context.Context, userID int64, cart *entity.C
 	}
 
 	resp, err := s.client.StartCheckout(ctx, &saga.StartCheckoutRequest{
-		UserID: userID,
-		Cart:   items,
+		UserID:         userID,
+		Cart:           items,
+		IdempotencyKey: idempotencyKey,
 	})
 	if err != nil {
 		s.logger.Errorw("Error while starting checkout", "error", err, "stage", "StartCheckout")
-		return "", err
+		return nil, err
+	}
+	if len(resp.Quote) > 0 {
+		s.logger.Infow("Checkout rejected, cart prices changed", "userID", userID, "stage", "StartCheckout")
+		quote := &entity.Cart{Items: make([]entity.CartItem, 0, len(resp.Quote))}
+		for _, p := range resp.Quote {
+			quote.Items = append(quote.Items, entity.CartItem{
+				UserID:    userID,
+				ProductID: p.ProductID,
+				Quantity:  p.Quantity,
+				Price:     p.Price,
+			})
+		}
+		return nil, &apperrors.PricesChangedError{Quote: quote}
+	}
+	if resp.Error != "" {
+		s.logger.Errorw("Checkout rejected", "error", resp.Error, "stage", "StartCheckout")
+		return nil, errors.New(resp.Error)
+	}
+
+	return &orderEntity.CheckoutResult{
+		CheckoutStatus: orderEntity.CheckoutStatus{
+			OrderID: resp.OrderID,
+			Status:  resp.Status,
+			Reason:  resp.Reason,
+		},
+		Replayed: resp.Replayed,
+	}, nil
+}
+
+func (s *Client) GetCheckoutStatus(ctx context.Context, userID int64, orderID string) (*orderEntity.CheckoutStatus, error) {
+	resp, err := s.client.GetCheckoutStatus(ctx, &saga.GetCheckoutStatusRequest{
+		OrderID: orderID,
+		UserID:  userID,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, apperrors.ErrOrderNotFound
+		}
+		s.logger.Errorw("Error while getting checkout status", "error", err, "orderID", orderID, "stage", "GetCheckoutStatus")
+		return nil, err
 	}
 
-	return resp.OrderID, nil
+	return &orderEntity.CheckoutStatus{
+		OrderID: resp.OrderID,
+		Status:  resp.Status,
+		Reason:  resp.Reason,
+	}, nil
 }
@@ -0,0 +1,175 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/vsespontanno/eCommerce/pkg/events"
+	"github.com/vsespontanno/eCommerce/pkg/pubsub"
+	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/order/entity"
+	"github.com/vsespontanno/eCommerce/services/cart-service/internal/infrastructure/metrics"
+	"go.uber.org/zap"
+)
+
+type OrderCompleter interface {
+	CompleteOrder(ctx context.Context, order *entity.OrderEvent) error
+}
+
+type OrderFailer interface {
+	FailOrder(ctx context.Context, order *entity.OrderEvent) error
+}
+
+// Consumer читает события заказов из транспорта и закрывает или помечает неудавшимися заказы корзины
+type Consumer struct {
+	subscriber     pubsub.Subscriber
+	topic          string
+	logger         *zap.SugaredLogger
+	orderCompleter OrderCompleter
+	orderFailer    OrderFailer
+	// done закрывается, когда чтение, запущенное Poll, остановилось
+	done chan struct{}
+}
+
+func NewConsumer(subscriber pubsub.Subscriber, topic string, logger *zap.SugaredLogger, orderCompleter OrderCompleter, orderFailer OrderFailer) *Consumer {
+	return &Consumer{
+		subscriber:     subscriber,
+		topic:          topic,
+		logger:         logger,
+		orderCompleter: orderCompleter,
+		orderFailer:    orderFailer,
+	}
+}
+
+// Close ждёт остановки чтения и закрывает подписку; ctx, переданный в Poll, должен быть уже отменён
+func (c *Consumer) Close() {
+	if c.done != nil {
+		<-c.done
+	}
+	if err := c.subscriber.Close(); err != nil {
+		c.logger.Errorw("Error closing event subscriber", "error", err)
+	}
+}
+
+// Poll читает топик в фоне, пока не отменён ctx
+func (c *Consumer) Poll(ctx context.Context) {
+	c.done = make(chan struct{})
+	go func() {
+		defer close(c.done)
+		if err := c.subscriber.Subscribe(ctx, c.topic, c.handle); err != nil {
+			c.logger.Errorw("Failed to subscribe to order events", "error", err, "topic", c.topic)
+		}
+	}()
+}
+
+// handle - обработчик сообщения транспорта. Ошибка обработки заказа оставляет сообщение
+// неподтверждённым, и оно придёт повторно.
+func (c *Consumer) handle(ctx context.Context, msg pubsub.Message) error {
+	// Тело события - данные пользователя и заказа, в Info попадают только его идентификаторы
+	c.logger.Infow("Received message",
+		"topic", c.topic,
+		"key", msg.Key,
+		"id", msg.Headers[events.HeaderID],
+		"type", msg.Headers[events.HeaderType],
+	)
+	c.logger.Debugw("Message payload", "key", msg.Key, "value", string(msg.Value))
+
+	order, err := c.processMessage(msg)
+	if err != nil {
+		// Ошибка парсинга или неизвестная версия схемы - подтверждаем, чтобы не застрять
+		// на сообщении, которое эта версия корзины никогда не разберёт
+		c.logger.Errorw("Failed to parse message, acknowledging to skip", "error", err, "key", msg.Key)
+		return nil
+	}
+	return c.handleOrder(ctx, order)
+}
+
+// handleOrder направляет событие обработчику по его типу
+func (c *Consumer) handleOrder(ctx context.Context, order *entity.OrderEvent) error {
+	switch {
+	case order.EventType == entity.EventTypeOrderFailed:
+		if err := c.orderFailer.FailOrder(ctx, order); err != nil {
+			c.logger.Errorw("Error recording failed order",
+				"order_id", order.OrderID,
+				"user_id", order.UserID,
+				"reason", order.Reason,
+				"error", err,
+			)
+			return err
+		}
+		c.logger.Infow("Order failure recorded",
+			"order_id", order.OrderID,
+			"user_id", order.UserID,
+			"reason", order.Reason,
+		)
+	// События без event_type от старых версий оркестратора узнаём по статусу
+	case order.EventType == entity.EventTypeOrderCompleted,
+		order.EventType == "" && order.Status == entity.StatusCompleted:
+		if err := c.orderCompleter.CompleteOrder(ctx, order); err != nil {
+			c.logger.Errorw("Error completing order",
+				"order_id", order.OrderID,
+				"user_id", order.UserID,
+				"eventType", order.EventType,
+				"error", err,
+			)
+			return err
+		}
+		c.logger.Infow("Order completed successfully",
+			"order_id", order.OrderID,
+			"user_id", order.UserID,
+			"eventType", order.EventType,
+			"total", order.Total,
+		)
+	default:
+		c.logger.Warnw("Received order with unexpected event type",
+			"order_id", order.OrderID,
+			"status", order.Status,
+			"eventType", order.EventType,
+		)
+	}
+	return nil
+}
+
+func (c *Consumer) processMessage(msg pubsub.Message) (*entity.OrderEvent, error) {
+	envelope, err := events.Decode(msg.Value)
+	if err != nil {
+		c.logger.Errorw("Error decoding message envelope", "error", err, "stage: ", "processMessage")
+		return &entity.OrderEvent{}, err
+	}
+	data, err := upcast(envelope)
+	if err != nil {
+		metrics.EventsRejectedTotal.WithLabelValues(envelope.Type, strconv.Itoa(envelope.SchemaVersion)).Inc()
+		c.logger.Errorw("Rejected event with unknown schema version",
+			"error", err,
+			"id", envelope.ID,
+			"type", envelope.Type,
+			"version", envelope.SchemaVersion,
+		)
+		return &entity.OrderEvent{}, err
+	}
+
+	var cartOrder entity.OrderEvent
+	err = json.Unmarshal(data, &cartOrder)
+	if err != nil {
+		c.logger.Errorw("Error unmarshalling message", "error", err, "stage: ", "processMessage")
+		return &entity.OrderEvent{}, err
+	}
+	c.logger.Debugw("Decoded order event", "order", cartOrder)
+	if cartOrder.EventType == "" {
+		cartOrder.EventType = envelope.Type
+	}
+	return &cartOrder, nil
+}
+
+// upcast приводит данные события к схеме, которую понимает корзина. Новую версию схемы сюда
+// добавляют вместе с переходом от старой; версии новее корзина не знает и отклоняет,
+// чтобы не разобрать их молча с пустыми полями.
+func upcast(envelope events.Envelope) (json.RawMessage, error) {
+	switch envelope.SchemaVersion {
+	case events.LegacyVersion, entity.OrderEventVersion:
+		return envelope.Data, nil
+	default:
+		return nil, fmt.Errorf("%w: %s v%d", events.ErrUnsupportedVersion, envelope.Type, envelope.SchemaVersion)
+	}
+}
@@ -0,0 +1,166 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/vsespontanno/eCommerce/pkg/events"
+	"github.com/vsespontanno/eCommerce/pkg/pubsub"
+	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/order/entity"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestConsumer_ProcessMessage(t *testing.T) {
+	consumer := &Consumer{logger: zap.NewNop().Sugar()}
+	data := map[string]any{
+		"order_id":   "order-1",
+		"user_id":    7,
+		"products":   []map[string]any{{"product_id": 3, "quantity": 2}},
+		"total":      500,
+		"status":     "Completed",
+		"event_type": entity.EventTypeOrderCompleted,
+	}
+	message := func(t *testing.T, version int) pubsub.Message {
+		envelope, err := events.New("order-1:OrderCompleted", entity.EventTypeOrderCompleted, "saga-orchestrator", version, time.Now(), data)
+		require.NoError(t, err)
+		value, err := json.Marshal(envelope)
+		require.NoError(t, err)
+		return pubsub.Message{Key: "order-1", Value: value}
+	}
+	expected := &entity.OrderEvent{
+		OrderID:   "order-1",
+		UserID:    7,
+		Products:  []entity.ProductForOrder{{ID: 3, Quantity: 2}},
+		Total:     500,
+		Status:    "Completed",
+		EventType: entity.EventTypeOrderCompleted,
+	}
+
+	t.Run("Current Version", func(t *testing.T) {
+		order, err := consumer.processMessage(message(t, entity.OrderEventVersion))
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, order)
+	})
+
+	t.Run("Legacy Event Without Envelope Is Upcast", func(t *testing.T) {
+		value, err := json.Marshal(data)
+		require.NoError(t, err)
+
+		order, err := consumer.processMessage(pubsub.Message{Value: value})
+
+		require.NoError(t, err)
+		assert.Equal(t, expected, order)
+	})
+
+	t.Run("Unknown Version Is Rejected", func(t *testing.T) {
+		_, err := consumer.processMessage(message(t, entity.OrderEventVersion+1))
+
+		assert.ErrorIs(t, err, events.ErrUnsupportedVersion)
+	})
+}
+
+// TestConsumer_Logging - на уровне Info в логи попадают только идентификаторы события, без тела
+func TestConsumer_Logging(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	completer := new(MockOrderCompleter)
+	consumer := NewConsumer(nil, "orders", zap.New(core).Sugar(), completer, new(MockOrderFailer))
+	completer.On("CompleteOrder", mock.Anything, mock.Anything).Return(nil)
+
+	envelope, err := events.New("order-1:OrderCompleted", entity.EventTypeOrderCompleted, "saga-orchestrator", entity.OrderEventVersion, time.Now(),
+		map[string]any{"order_id": "order-1", "user_id": 7, "products": []map[string]any{{"id": 1, "quantity": 2}}, "total": 1000})
+	require.NoError(t, err)
+	value, err := json.Marshal(envelope)
+	require.NoError(t, err)
+	headers := map[string]string{}
+	for _, h := range envelope.Headers() {
+		headers[h.Key] = h.Value
+	}
+
+	require.NoError(t, consumer.handle(context.Background(), pubsub.Message{Key: "order-1", Value: value, Headers: headers}))
+
+	received := logs.FilterMessage("Received message").All()
+	require.Len(t, received, 1)
+	assert.Equal(t, map[string]any{
+		"topic": "orders",
+		"key":   "order-1",
+		"id":    "order-1:OrderCompleted",
+		"type":  entity.EventTypeOrderCompleted,
+	}, received[0].ContextMap())
+	for _, entry := range logs.All() {
+		assert.NotContains(t, entry.ContextMap(), "value", entry.Message)
+		assert.NotContains(t, entry.ContextMap(), "order", entry.Message)
+	}
+}
+
+type MockOrderCompleter struct {
+	mock.Mock
+}
+
+func (m *MockOrderCompleter) CompleteOrder(ctx context.Context, order *entity.OrderEvent) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+type MockOrderFailer struct {
+	mock.Mock
+}
+
+func (m *MockOrderFailer) FailOrder(ctx context.Context, order *entity.OrderEvent) error {
+	args := m.Called(ctx, order)
+	return args.Error(0)
+}
+
+// TestConsumer_MemoryTransport прогоняет события через брокер в памяти так же, как они идут через Kafka
+func TestConsumer_MemoryTransport(t *testing.T) {
+	broker := pubsub.NewBroker(time.Millisecond, zap.NewNop().Sugar())
+	completer := new(MockOrderCompleter)
+	failer := new(MockOrderFailer)
+	consumer := NewConsumer(broker.Subscriber("cart-service"), "orders", zap.NewNop().Sugar(), completer, failer)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	consumer.Poll(ctx)
+
+	publish := func(t *testing.T, eventType string, data map[string]any) {
+		envelope, err := events.New(data["order_id"].(string)+":"+eventType, eventType, "saga-orchestrator", entity.OrderEventVersion, time.Now(), data)
+		require.NoError(t, err)
+		value, err := json.Marshal(envelope)
+		require.NoError(t, err)
+		for _, err := range broker.Publish(ctx, "orders", []pubsub.Message{{Key: data["order_id"].(string), Value: value}}) {
+			require.NoError(t, err)
+		}
+	}
+	isOrder := func(orderID string) any {
+		return mock.MatchedBy(func(order *entity.OrderEvent) bool { return order.OrderID == orderID })
+	}
+
+	// Первая попытка закрыть заказ падает - событие приходит повторно; битое сообщение пропускается
+	completed := make(chan struct{})
+	completer.On("CompleteOrder", mock.Anything, isOrder("order-1")).Return(errors.New("db is down")).Once()
+	completer.On("CompleteOrder", mock.Anything, isOrder("order-1")).Return(nil).Once()
+	failer.On("FailOrder", mock.Anything, isOrder("order-2")).Return(nil).Once().Run(func(mock.Arguments) { close(completed) })
+
+	publish(t, entity.EventTypeOrderCompleted, map[string]any{"order_id": "order-1", "user_id": 7, "status": "Completed", "event_type": entity.EventTypeOrderCompleted})
+	broker.Publish(ctx, "orders", []pubsub.Message{{Key: "broken", Value: []byte("not json")}})
+	publish(t, entity.EventTypeOrderFailed, map[string]any{"order_id": "order-2", "user_id": 7, "event_type": entity.EventTypeOrderFailed, "reason": "insufficient funds"})
+
+	select {
+	case <-completed:
+	case <-time.After(time.Second):
+		t.Fatal("order events were not consumed")
+	}
+	completer.AssertExpectations(t)
+	failer.AssertExpectations(t)
+
+	cancel()
+	consumer.Close()
+}
@@ -66,6 +66,24 @@ var (
 		[]string{"status"},
 	)
 
+	// CheckoutFailuresTotal - OrderFailed, полученные от saga-orchestrator, по причине
+	CheckoutFailuresTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cart_checkout_failures_total",
+			Help: "Total number of failed checkouts reported by the saga orchestrator",
+		},
+		[]string{"reason"},
+	)
+
+	// EventsRejectedTotal - события saga-orchestrator, версию схемы которых корзина не знает
+	EventsRejectedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cart_events_rejected_total",
+			Help: "Total number of events skipped because of an unknown schema version",
+		},
+		[]string{"type", "version"},
+	)
+
 	ProductAddedToCartTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "cart_product_added_total",
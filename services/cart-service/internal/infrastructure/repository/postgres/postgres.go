@@ -143,3 +143,53 @@ func (s *CartStore) CleanCart(ctx context.Context, order *orderEntity.OrderEvent
 	s.logger.Infow("Cart cleaned in Postgres", "userID", order.UserID)
 	return nil
 }
+
+// SaveCheckoutFailure сохраняет неудачное оформление заказа; повторное событие по тому же заказу игнорируется
+func (s *CartStore) SaveCheckoutFailure(ctx context.Context, order *orderEntity.OrderEvent) error {
+	query, args, err := s.builder.
+		Insert("checkout_failures").
+		Columns("order_id", "user_id", "reason", "message", "total").
+		Values(order.OrderID, order.UserID, order.Reason, order.Message, order.Total).
+		Suffix("ON CONFLICT (order_id) DO NOTHING").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("failed to build SQL: %w", err)
+	}
+
+	if _, err := s.db.ExecContext(ctx, query, args...); err != nil {
+		s.logger.Errorw("Failed to save checkout failure",
+			"orderID", order.OrderID,
+			"userID", order.UserID,
+			"error", err)
+		return err
+	}
+	return nil
+}
+
+// CheckoutFailures возвращает последние неудачные оформления заказов пользователя, новые первыми
+func (s *CartStore) CheckoutFailures(ctx context.Context, userID int64, limit int) ([]orderEntity.CheckoutFailure, error) {
+	rows, err := s.builder.
+		Select("order_id, reason, message, total, created_at").
+		From("checkout_failures").
+		Where(sq.Eq{"user_id": userID}).
+		OrderBy("created_at DESC").
+		Limit(uint64(limit)).
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		s.logger.Errorw("Failed to query checkout failures", "userID", userID, "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	failures := []orderEntity.CheckoutFailure{}
+	for rows.Next() {
+		var f orderEntity.CheckoutFailure
+		if err := rows.Scan(&f.OrderID, &f.Reason, &f.Message, &f.Total, &f.CreatedAt); err != nil {
+			return nil, err
+		}
+		failures = append(failures, f)
+	}
+
+	return failures, rows.Err()
+}
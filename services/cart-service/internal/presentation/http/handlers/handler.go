@@ -11,12 +11,19 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/cart/entity"
+	orderEntity "github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/order/entity"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/infrastructure/client/grpc/jwt/dto"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/infrastructure/metrics"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/presentation/http/handlers/middleware"
 	"go.uber.org/zap"
 )
 
+// Заголовки идемпотентного checkout
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
 type CartServiceInterface interface {
 	Cart(ctx context.Context, userID int64) (*entity.Cart, error)
 	AddProductToCart(ctx context.Context, userID int64, productID int64) error
@@ -35,7 +42,13 @@ type ValidatorInterface interface {
 }
 
 type Checkouter interface {
-	Checkout(ctx context.Context, userID int64) (string, error)
+	Checkout(ctx context.Context, userID int64, idempotencyKey string) (*orderEntity.CheckoutResult, error)
+	CheckoutStatus(ctx context.Context, userID int64, orderID string) (*orderEntity.CheckoutStatus, error)
+}
+
+// FailureLister - неудачные оформления заказов, о которых сообщил saga-orchestrator
+type FailureLister interface {
+	CheckoutFailures(ctx context.Context, userID int64) ([]orderEntity.CheckoutFailure, error)
 }
 
 type Handler struct {
@@ -44,17 +57,19 @@ type Handler struct {
 	grpcAuthClient ValidatorInterface
 	rateLimiter    RateLimiterInterface
 	checkouter     Checkouter
+	failures       FailureLister
 }
 
 func New(cartService CartServiceInterface, sugarLogger *zap.SugaredLogger,
 	grpcAuthClient ValidatorInterface,
-	rateLimiter RateLimiterInterface, checkouter Checkouter) *Handler {
+	rateLimiter RateLimiterInterface, checkouter Checkouter, failures FailureLister) *Handler {
 	return &Handler{
 		cartService:    cartService,
 		sugarLogger:    sugarLogger,
 		grpcAuthClient: grpcAuthClient,
 		rateLimiter:    rateLimiter,
 		checkouter:     checkouter,
+		failures:       failures,
 	}
 }
 
@@ -99,6 +114,18 @@ func (h *Handler) RegisterRoutes(router *mux.Router) {
 		),
 	).Methods(http.MethodPost)
 
+	router.Handle("/cart/order/failures",
+		h.rateLimiter.RateLimitMiddleware(
+			middleware.AuthMiddleware(http.HandlerFunc(h.CheckoutFailures), h.grpcAuthClient),
+		),
+	).Methods(http.MethodGet)
+
+	router.Handle("/cart/order/{id}/status",
+		h.rateLimiter.RateLimitMiddleware(
+			middleware.AuthMiddleware(http.HandlerFunc(h.CheckoutStatus), h.grpcAuthClient),
+		),
+	).Methods(http.MethodGet)
+
 	router.HandleFunc("/health", h.HealthCheck).Methods(http.MethodGet)
 
 	// Prometheus metrics endpoint
@@ -300,19 +327,44 @@ func (h *Handler) Checkout(w http.ResponseWriter, r *http.Request) {
 		metrics.CheckoutTotal.WithLabelValues("error").Inc()
 		return
 	}
-	orderID, err := h.checkouter.Checkout(ctx, userID)
+	// Повторный запрос с тем же Idempotency-Key (двойной клик, ретрай клиента) не создаёт второй заказ
+	result, err := h.checkouter.Checkout(ctx, userID, r.Header.Get(idempotencyKeyHeader))
+	var pricesChanged *apperrors.PricesChangedError
+	if errors.As(err, &pricesChanged) {
+		// Цены изменились: отдаём корзину по актуальным ценам, клиент подтверждает и повторяет checkout
+		metrics.CheckoutTotal.WithLabelValues("prices_changed").Inc()
+		if err := writeJSON(w, http.StatusConflict, map[string]interface{}{
+			"message": "Cart prices have changed, please review the cart and check out again",
+			"cart":    pricesChanged.Quote,
+		}); err != nil {
+			h.sugarLogger.Errorf("Failed to checkout: %v", err)
+			http.Error(w, "Failed to checkout", http.StatusInternalServerError)
+		}
+		return
+	}
 	if err != nil {
 		http.Error(w, "Error while checking out", http.StatusBadRequest)
 		metrics.CheckoutTotal.WithLabelValues("error").Inc()
 		return
 	}
 
+	if result.Replayed {
+		metrics.CheckoutTotal.WithLabelValues("replayed").Inc()
+		w.Header().Set(idempotentReplayedHeader, "true")
+		err = writeJSON(w, http.StatusOK, result)
+		if err != nil {
+			h.sugarLogger.Errorf("Failed to checkout: %v", err)
+			http.Error(w, "Failed to checkout", http.StatusInternalServerError)
+		}
+		return
+	}
+
 	// Записываем успешный checkout
 	metrics.CheckoutTotal.WithLabelValues("success").Inc()
 
 	err = writeJSON(w, http.StatusAccepted, map[string]interface{}{
 		"message": "Order accepted and is being processed",
-		"orderId": orderID,
+		"orderId": result.OrderID,
 	})
 	if err != nil {
 		h.sugarLogger.Errorf("Failed to checkout: %v", err)
@@ -321,6 +373,64 @@ func (h *Handler) Checkout(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *Handler) CheckoutStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int64)
+	if !ok {
+		http.Error(w, "Failed to get user ID from context", http.StatusInternalServerError)
+		metrics.CartOperationsTotal.WithLabelValues("checkout_status", "error").Inc()
+		return
+	}
+	orderID := mux.Vars(r)["id"]
+	if orderID == "" || len(orderID) > 64 {
+		http.Error(w, "Invalid order ID", http.StatusBadRequest)
+		metrics.CartOperationsTotal.WithLabelValues("checkout_status", "invalid_id").Inc()
+		return
+	}
+
+	checkoutStatus, err := h.checkouter.CheckoutStatus(ctx, userID, orderID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrOrderNotFound) {
+			http.Error(w, "Order not found", http.StatusNotFound)
+			metrics.CartOperationsTotal.WithLabelValues("checkout_status", "not_found").Inc()
+			return
+		}
+		http.Error(w, "Error while getting checkout status", http.StatusInternalServerError)
+		metrics.CartOperationsTotal.WithLabelValues("checkout_status", "error").Inc()
+		return
+	}
+
+	metrics.CartOperationsTotal.WithLabelValues("checkout_status", "success").Inc()
+
+	if writeErr := writeJSON(w, http.StatusOK, checkoutStatus); writeErr != nil {
+		h.sugarLogger.Errorw("failed to write checkout status response", "error", writeErr)
+	}
+}
+
+func (h *Handler) CheckoutFailures(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID, ok := r.Context().Value(middleware.UserIDKey).(int64)
+	if !ok {
+		http.Error(w, "Failed to get user ID from context", http.StatusInternalServerError)
+		metrics.CartOperationsTotal.WithLabelValues("checkout_failures", "error").Inc()
+		return
+	}
+
+	failures, err := h.failures.CheckoutFailures(ctx, userID)
+	if err != nil {
+		h.sugarLogger.Errorw("failed to get checkout failures", "userID", userID, "error", err)
+		http.Error(w, "Error while getting checkout failures", http.StatusInternalServerError)
+		metrics.CartOperationsTotal.WithLabelValues("checkout_failures", "error").Inc()
+		return
+	}
+
+	metrics.CartOperationsTotal.WithLabelValues("checkout_failures", "success").Inc()
+
+	if writeErr := writeJSON(w, http.StatusOK, failures); writeErr != nil {
+		h.sugarLogger.Errorw("failed to write checkout failures response", "error", writeErr)
+	}
+}
+
 func (h *Handler) HealthCheck(w http.ResponseWriter, r *http.Request) {
 	if err := writeJSON(w, http.StatusOK, map[string]interface{}{
 		"status":  "healthy",
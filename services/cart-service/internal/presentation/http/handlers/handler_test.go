@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/cart/entity"
+	orderEntity "github.com/vsespontanno/eCommerce/services/cart-service/internal/domain/order/entity"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/infrastructure/client/grpc/jwt/dto"
 	"github.com/vsespontanno/eCommerce/services/cart-service/internal/presentation/http/handlers/middleware"
 	"go.uber.org/zap"
@@ -80,9 +81,28 @@ type MockCheckouter struct {
 	mock.Mock
 }
 
-func (m *MockCheckouter) Checkout(ctx context.Context, userID int64) (string, error) {
+func (m *MockCheckouter) Checkout(ctx context.Context, userID int64, idempotencyKey string) (*orderEntity.CheckoutResult, error) {
+	args := m.Called(ctx, userID, idempotencyKey)
+	result, _ := args.Get(0).(*orderEntity.CheckoutResult)
+	return result, args.Error(1)
+}
+
+func (m *MockCheckouter) CheckoutStatus(ctx context.Context, userID int64, orderID string) (*orderEntity.CheckoutStatus, error) {
+	args := m.Called(ctx, userID, orderID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*orderEntity.CheckoutStatus), args.Error(1)
+}
+
+type MockFailureLister struct {
+	mock.Mock
+}
+
+func (m *MockFailureLister) CheckoutFailures(ctx context.Context, userID int64) ([]orderEntity.CheckoutFailure, error) {
 	args := m.Called(ctx, userID)
-	return args.String(0), args.Error(1)
+	failures, _ := args.Get(0).([]orderEntity.CheckoutFailure)
+	return failures, args.Error(1)
 }
 
 func TestHandler_GetCart(t *testing.T) {
@@ -90,7 +110,7 @@ func TestHandler_GetCart(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		cart := &entity.Cart{
 			Items: []entity.CartItem{
@@ -112,7 +132,7 @@ func TestHandler_GetCart(t *testing.T) {
 
 	t.Run("Empty Cart", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		mockService.On("Cart", mock.Anything, int64(1)).Return(nil, apperrors.ErrNoCartFound)
 
@@ -131,7 +151,7 @@ func TestHandler_GetCart(t *testing.T) {
 
 	t.Run("Internal Error", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		mockService.On("Cart", mock.Anything, int64(1)).Return(nil, errors.New("db error"))
 
@@ -151,7 +171,7 @@ func TestHandler_ClearCart(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		mockService.On("ClearCart", mock.Anything, int64(1)).Return(nil)
 
@@ -167,7 +187,7 @@ func TestHandler_ClearCart(t *testing.T) {
 
 	t.Run("Error", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		mockService.On("ClearCart", mock.Anything, int64(1)).Return(errors.New("db error"))
 
@@ -187,7 +207,7 @@ func TestHandler_RemoveProduct(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		mockService.On("DeleteProductFromCart", mock.Anything, int64(1), int64(100)).Return(nil)
 
@@ -204,7 +224,7 @@ func TestHandler_RemoveProduct(t *testing.T) {
 
 	t.Run("Invalid ID", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		req := httptest.NewRequest(http.MethodDelete, "/cart/invalid", nil)
 		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
@@ -218,7 +238,7 @@ func TestHandler_RemoveProduct(t *testing.T) {
 
 	t.Run("Error", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		mockService.On("DeleteProductFromCart", mock.Anything, int64(1), int64(100)).Return(errors.New("db error"))
 
@@ -239,7 +259,7 @@ func TestHandler_IncrementProduct(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		mockService.On("AddProductToCart", mock.Anything, int64(1), int64(100)).Return(nil)
 
@@ -256,7 +276,7 @@ func TestHandler_IncrementProduct(t *testing.T) {
 
 	t.Run("Limit Exceeded", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		mockService.On("AddProductToCart", mock.Anything, int64(1), int64(100)).Return(apperrors.ErrTooManyProductsOfOneType)
 
@@ -277,7 +297,7 @@ func TestHandler_DecrementProduct(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockService := new(MockCartService)
-		handler := New(mockService, logger, nil, nil, nil)
+		handler := New(mockService, logger, nil, nil, nil, nil)
 
 		mockService.On("Decrement", mock.Anything, int64(1), int64(100)).Return(nil)
 
@@ -298,9 +318,11 @@ func TestHandler_Checkout(t *testing.T) {
 
 	t.Run("Success", func(t *testing.T) {
 		mockCheckouter := new(MockCheckouter)
-		handler := New(nil, logger, nil, nil, mockCheckouter)
+		handler := New(nil, logger, nil, nil, mockCheckouter, nil)
 
-		mockCheckouter.On("Checkout", mock.Anything, int64(1)).Return("order-123", nil)
+		mockCheckouter.On("Checkout", mock.Anything, int64(1), "").Return(&orderEntity.CheckoutResult{
+			CheckoutStatus: orderEntity.CheckoutStatus{OrderID: "order-123", Status: "PENDING"},
+		}, nil)
 
 		req := httptest.NewRequest(http.MethodPost, "/cart/order/checkout", nil)
 		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
@@ -317,9 +339,9 @@ func TestHandler_Checkout(t *testing.T) {
 
 	t.Run("Error", func(t *testing.T) {
 		mockCheckouter := new(MockCheckouter)
-		handler := New(nil, logger, nil, nil, mockCheckouter)
+		handler := New(nil, logger, nil, nil, mockCheckouter, nil)
 
-		mockCheckouter.On("Checkout", mock.Anything, int64(1)).Return("", errors.New("checkout error"))
+		mockCheckouter.On("Checkout", mock.Anything, int64(1), "").Return(nil, errors.New("checkout error"))
 
 		req := httptest.NewRequest(http.MethodPost, "/cart/order/checkout", nil)
 		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
@@ -330,11 +352,164 @@ func TestHandler_Checkout(t *testing.T) {
 		assert.Equal(t, http.StatusBadRequest, w.Code)
 		mockCheckouter.AssertExpectations(t)
 	})
+
+	t.Run("Prices Changed", func(t *testing.T) {
+		mockCheckouter := new(MockCheckouter)
+		handler := New(nil, logger, nil, nil, mockCheckouter, nil)
+
+		quote := &entity.Cart{Items: []entity.CartItem{{UserID: 1, ProductID: 1, Quantity: 2, Price: 120}}}
+		mockCheckouter.On("Checkout", mock.Anything, int64(1), "").Return(nil, &apperrors.PricesChangedError{Quote: quote})
+
+		req := httptest.NewRequest(http.MethodPost, "/cart/order/checkout", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.Checkout(w, req)
+
+		assert.Equal(t, http.StatusConflict, w.Code)
+		var response struct {
+			Cart entity.Cart `json:"cart"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, *quote, response.Cart)
+		mockCheckouter.AssertExpectations(t)
+	})
+
+	t.Run("Replayed With Idempotency Key", func(t *testing.T) {
+		mockCheckouter := new(MockCheckouter)
+		handler := New(nil, logger, nil, nil, mockCheckouter, nil)
+
+		mockCheckouter.On("Checkout", mock.Anything, int64(1), "key-1").Return(&orderEntity.CheckoutResult{
+			CheckoutStatus: orderEntity.CheckoutStatus{OrderID: "order-123", Status: "COMPLETED"},
+			Replayed:       true,
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodPost, "/cart/order/checkout", nil)
+		req.Header.Set("Idempotency-Key", "key-1")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.Checkout(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "true", w.Header().Get("Idempotent-Replayed"))
+		var response orderEntity.CheckoutResult
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "order-123", response.OrderID)
+		assert.Equal(t, "COMPLETED", response.Status)
+		assert.True(t, response.Replayed)
+		mockCheckouter.AssertExpectations(t)
+	})
+}
+
+func TestHandler_CheckoutStatus(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	t.Run("Success", func(t *testing.T) {
+		mockCheckouter := new(MockCheckouter)
+		handler := New(nil, logger, nil, nil, mockCheckouter, nil)
+
+		mockCheckouter.On("CheckoutStatus", mock.Anything, int64(1), "order-123").Return(&orderEntity.CheckoutStatus{
+			OrderID: "order-123",
+			Status:  "FAILED",
+			Reason:  "insufficient funds",
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/cart/order/order-123/status", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+		req = mux.SetURLVars(req, map[string]string{"id": "order-123"})
+		w := httptest.NewRecorder()
+
+		handler.CheckoutStatus(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Equal(t, "order-123", response["orderId"])
+		assert.Equal(t, "FAILED", response["status"])
+		assert.Equal(t, "insufficient funds", response["reason"])
+		mockCheckouter.AssertExpectations(t)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockCheckouter := new(MockCheckouter)
+		handler := New(nil, logger, nil, nil, mockCheckouter, nil)
+
+		mockCheckouter.On("CheckoutStatus", mock.Anything, int64(1), "order-123").Return(nil, apperrors.ErrOrderNotFound)
+
+		req := httptest.NewRequest(http.MethodGet, "/cart/order/order-123/status", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+		req = mux.SetURLVars(req, map[string]string{"id": "order-123"})
+		w := httptest.NewRecorder()
+
+		handler.CheckoutStatus(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockCheckouter.AssertExpectations(t)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockCheckouter := new(MockCheckouter)
+		handler := New(nil, logger, nil, nil, mockCheckouter, nil)
+
+		mockCheckouter.On("CheckoutStatus", mock.Anything, int64(1), "order-123").Return(nil, errors.New("saga error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/cart/order/order-123/status", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+		req = mux.SetURLVars(req, map[string]string{"id": "order-123"})
+		w := httptest.NewRecorder()
+
+		handler.CheckoutStatus(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockCheckouter.AssertExpectations(t)
+	})
+}
+
+func TestHandler_CheckoutFailures(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	t.Run("Success", func(t *testing.T) {
+		mockFailures := new(MockFailureLister)
+		handler := New(nil, logger, nil, nil, nil, mockFailures)
+
+		mockFailures.On("CheckoutFailures", mock.Anything, int64(1)).Return([]orderEntity.CheckoutFailure{
+			{OrderID: "order-123", Reason: orderEntity.FailureInsufficientFunds, Message: "insufficient funds", Total: 1000},
+		}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/cart/order/failures", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.CheckoutFailures(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response []orderEntity.CheckoutFailure
+		json.Unmarshal(w.Body.Bytes(), &response)
+		assert.Len(t, response, 1)
+		assert.Equal(t, orderEntity.FailureInsufficientFunds, response[0].Reason)
+		mockFailures.AssertExpectations(t)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		mockFailures := new(MockFailureLister)
+		handler := New(nil, logger, nil, nil, nil, mockFailures)
+
+		mockFailures.On("CheckoutFailures", mock.Anything, int64(1)).Return(nil, errors.New("db error"))
+
+		req := httptest.NewRequest(http.MethodGet, "/cart/order/failures", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.UserIDKey, int64(1)))
+		w := httptest.NewRecorder()
+
+		handler.CheckoutFailures(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
 }
 
 func TestHandler_HealthCheck(t *testing.T) {
 	logger := zap.NewNop().Sugar()
-	handler := New(nil, logger, nil, nil, nil)
+	handler := New(nil, logger, nil, nil, nil, nil)
 
 	req := httptest.NewRequest(http.MethodGet, "/health", nil)
 	w := httptest.NewRecorder()
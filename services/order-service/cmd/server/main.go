@@ -17,6 +17,7 @@ import (
 	proto "github.com/vsespontanno/eCommerce/proto/orders"
 	"github.com/vsespontanno/eCommerce/services/order-service/internal/application/order"
 	"github.com/vsespontanno/eCommerce/services/order-service/internal/config"
+	"github.com/vsespontanno/eCommerce/services/order-service/internal/infrastructure/client/grpc/saga"
 	"github.com/vsespontanno/eCommerce/services/order-service/internal/infrastructure/db"
 	"github.com/vsespontanno/eCommerce/services/order-service/internal/infrastructure/repository"
 	orderServ "github.com/vsespontanno/eCommerce/services/order-service/internal/presentation/order"
@@ -38,7 +39,8 @@ func main() {
 		logger.Log.Fatalf("Failed to connect to database: %v", err)
 	}
 	orderRepo := repository.NewOrderStore(db, logger.Log)
-	orderSvc := order.NewOrderService(orderRepo, logger.Log)
+	sagaClient := saga.NewSagaClient(cfg.GRPCSagaClientPort, logger.Log)
+	orderSvc := order.NewOrderService(orderRepo, sagaClient, logger.Log)
 	orderServer := orderServ.NewGRPCServer(orderSvc, logger.Log)
 	grpcServer := initializeGRPC(logger.Log)
 
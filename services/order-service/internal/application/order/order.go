@@ -2,7 +2,11 @@ package order
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/google/uuid"
+	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/order/entity"
 	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/order/interfaces"
 	"go.uber.org/zap"
@@ -10,11 +14,15 @@ import (
 
 type Service struct {
 	repo   interfaces.OrderRepo
+	saga   interfaces.SagaStarter
 	logger *zap.SugaredLogger
 }
 
-func NewOrderService(repo interfaces.OrderRepo, logger *zap.SugaredLogger) *Service {
-	return &Service{repo: repo, logger: logger}
+// returnableStatuses are the order statuses in which items can be returned
+var returnableStatuses = []string{entity.StatusCompleted, entity.StatusPartiallyReturned}
+
+func NewOrderService(repo interfaces.OrderRepo, saga interfaces.SagaStarter, logger *zap.SugaredLogger) *Service {
+	return &Service{repo: repo, saga: saga, logger: logger}
 }
 
 // Called by Cart Service when order is confirmed by Saga
@@ -51,3 +59,227 @@ func (s *Service) GetOrder(ctx context.Context, orderID string) (*entity.Order,
 func (s *Service) ListOrdersByUser(ctx context.Context, userID int64, limit, offset uint64) ([]entity.Order, error) {
 	return s.repo.ListOrdersByUser(ctx, userID, limit, offset)
 }
+
+// CancelOrder marks a completed order of the user as Cancelling and starts the cancellation saga,
+// which refunds the payment, restocks the products and confirms the cancellation.
+// Calling it again for an order that is already being cancelled restarts nothing and is safe to retry.
+func (s *Service) CancelOrder(ctx context.Context, orderID string, userID int64) (string, error) {
+	order, err := s.repo.GetOrder(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+	if order == nil || order.UserID != userID {
+		return "", apperrors.ErrOrderNotFound
+	}
+
+	switch order.Status {
+	case entity.StatusCancelled:
+		return entity.StatusCancelled, nil
+	case entity.StatusCompleted, entity.StatusCancelling:
+	default:
+		s.logger.Infow("Order cannot be cancelled", "order_id", orderID, "status", order.Status)
+		return "", apperrors.ErrOrderNotCancellable
+	}
+
+	// The conditional update loses to a concurrent status change, e.g. a finished cancellation,
+	// and to a return requested in the meantime
+	ok, err := s.repo.MarkCancelling(ctx, orderID)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", apperrors.ErrOrderNotCancellable
+	}
+
+	if err := s.saga.CancelOrder(ctx, orderID, userID); err != nil {
+		if errors.Is(err, apperrors.ErrOrderNotCancellable) {
+			s.revertCancelling(ctx, orderID)
+			return "", err
+		}
+		// The order stays Cancelling, so the client can simply retry
+		s.logger.Errorw("Failed to start cancellation saga", "order_id", orderID, "user_id", userID, "error", err)
+		return "", fmt.Errorf("start cancellation saga: %w", err)
+	}
+
+	s.logger.Infow("Order cancellation started", "order_id", orderID, "user_id", userID)
+	return entity.StatusCancelling, nil
+}
+
+// ConfirmCancellation is called by the saga once the payment is refunded and the products are restocked
+func (s *Service) ConfirmCancellation(ctx context.Context, orderID string) error {
+	ok, err := s.repo.UpdateStatus(ctx, orderID, []string{entity.StatusCancelling, entity.StatusCompleted}, entity.StatusCancelled)
+	if err != nil {
+		return err
+	}
+	if ok {
+		s.logger.Infow("Order cancelled", "order_id", orderID)
+		return nil
+	}
+
+	order, err := s.repo.GetOrder(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case order == nil:
+		return apperrors.ErrOrderNotFound
+	case order.Status == entity.StatusCancelled:
+		return nil
+	default:
+		return apperrors.ErrOrderNotCancellable
+	}
+}
+
+// revertCancelling returns the order to COMPLETED when the saga refused to cancel it
+func (s *Service) revertCancelling(ctx context.Context, orderID string) {
+	if _, err := s.repo.UpdateStatus(ctx, orderID, []string{entity.StatusCancelling}, entity.StatusCompleted); err != nil {
+		s.logger.Errorw("Failed to revert order status", "order_id", orderID, "error", err)
+	}
+}
+
+// RequestReturn opens a return of some items of the user's order. Items already in other returns
+// cannot be returned again unless those returns were rejected.
+func (s *Service) RequestReturn(ctx context.Context, orderID string, userID int64, items []entity.OrderItem, reason string) (*entity.Return, error) {
+	merged, err := mergeReturnItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &entity.Return{
+		ReturnID: uuid.NewString(),
+		OrderID:  orderID,
+		UserID:   userID,
+		Items:    merged,
+		Status:   entity.ReturnRequested,
+		Reason:   reason,
+	}
+	if err := s.repo.CreateReturn(ctx, ret, returnableStatuses); err != nil {
+		s.logger.Infow("Return request rejected", "order_id", orderID, "user_id", userID, "error", err)
+		return nil, err
+	}
+
+	s.logger.Infow("Return requested", "return_id", ret.ReturnID, "order_id", orderID, "user_id", userID)
+	return ret, nil
+}
+
+// ApproveReturn starts the return saga, which refunds the returned items at the price they were bought for
+// and restocks them. Approving an approved return again restarts nothing and is safe to retry.
+func (s *Service) ApproveReturn(ctx context.Context, returnID string) (*entity.Return, error) {
+	ret, err := s.repo.GetReturn(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	if ret == nil {
+		return nil, apperrors.ErrReturnNotFound
+	}
+
+	switch ret.Status {
+	case entity.ReturnCompleted:
+		return ret, nil
+	case entity.ReturnRequested, entity.ReturnApproved:
+	default:
+		return nil, apperrors.ErrInvalidReturnState
+	}
+
+	ok, err := s.repo.UpdateReturnStatus(ctx, returnID, []string{entity.ReturnRequested, entity.ReturnApproved}, entity.ReturnApproved)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, apperrors.ErrInvalidReturnState
+	}
+
+	amount, err := s.saga.StartReturn(ctx, returnID, ret.OrderID, ret.UserID, ret.Items)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrOrderNotReturnable) || errors.Is(err, apperrors.ErrInvalidReturn) {
+			s.revertApproval(ctx, returnID)
+			return nil, err
+		}
+		// The return stays APPROVED, so the approval can simply be retried
+		s.logger.Errorw("Failed to start return saga", "return_id", returnID, "order_id", ret.OrderID, "error", err)
+		return nil, fmt.Errorf("start return saga: %w", err)
+	}
+
+	s.logger.Infow("Return approved", "return_id", returnID, "order_id", ret.OrderID, "amount", amount)
+	ret.Status = entity.ReturnApproved
+	ret.Amount = amount
+	return ret, nil
+}
+
+// RejectReturn closes a requested return without a refund
+func (s *Service) RejectReturn(ctx context.Context, returnID string) (*entity.Return, error) {
+	if _, err := s.repo.UpdateReturnStatus(ctx, returnID, []string{entity.ReturnRequested}, entity.ReturnRejected); err != nil {
+		return nil, err
+	}
+
+	ret, err := s.repo.GetReturn(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case ret == nil:
+		return nil, apperrors.ErrReturnNotFound
+	case ret.Status != entity.ReturnRejected:
+		return nil, apperrors.ErrInvalidReturnState
+	}
+
+	s.logger.Infow("Return rejected", "return_id", returnID, "order_id", ret.OrderID)
+	return ret, nil
+}
+
+func (s *Service) ListReturns(ctx context.Context, orderID string) ([]entity.Return, error) {
+	return s.repo.ListReturns(ctx, orderID)
+}
+
+// CompleteReturn is called by the saga once the returned items are refunded and restocked
+func (s *Service) CompleteReturn(ctx context.Context, returnID string, amount int64) error {
+	ok, err := s.repo.CompleteReturn(ctx, returnID, amount)
+	if err != nil {
+		return err
+	}
+	if ok {
+		s.logger.Infow("Return completed", "return_id", returnID, "amount", amount)
+		return nil
+	}
+
+	ret, err := s.repo.GetReturn(ctx, returnID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case ret == nil:
+		return apperrors.ErrReturnNotFound
+	case ret.Status == entity.ReturnCompleted:
+		return nil
+	default:
+		return apperrors.ErrInvalidReturnState
+	}
+}
+
+// revertApproval returns the return to REQUESTED when the saga refused to start it
+func (s *Service) revertApproval(ctx context.Context, returnID string) {
+	if _, err := s.repo.UpdateReturnStatus(ctx, returnID, []string{entity.ReturnApproved}, entity.ReturnRequested); err != nil {
+		s.logger.Errorw("Failed to revert return status", "return_id", returnID, "error", err)
+	}
+}
+
+// mergeReturnItems validates the returned items and sums repeated products
+func mergeReturnItems(items []entity.OrderItem) ([]entity.OrderItem, error) {
+	if len(items) == 0 {
+		return nil, apperrors.ErrInvalidReturn
+	}
+	merged := make([]entity.OrderItem, 0, len(items))
+	index := make(map[int64]int, len(items))
+	for _, it := range items {
+		if it.ProductID <= 0 || it.Quantity <= 0 {
+			return nil, apperrors.ErrInvalidReturn
+		}
+		if i, ok := index[it.ProductID]; ok {
+			merged[i].Quantity += it.Quantity
+			continue
+		}
+		index[it.ProductID] = len(merged)
+		merged = append(merged, it)
+	}
+	return merged, nil
+}
@@ -3,9 +3,11 @@ package order
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 
 	"github.com/vsespontanno/eCommerce/pkg/logger"
+	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/order/entity"
 )
 
@@ -18,6 +20,14 @@ type MockOrderRepo struct {
 	CreateOrderFunc      func(ctx context.Context, order *entity.Order) error
 	GetOrderFunc         func(ctx context.Context, orderID string) (*entity.Order, error)
 	ListOrdersByUserFunc func(ctx context.Context, userID int64, limit, offset uint64) ([]entity.Order, error)
+	UpdateStatusFunc     func(ctx context.Context, orderID string, from []string, status string) (bool, error)
+	MarkCancellingFunc   func(ctx context.Context, orderID string) (bool, error)
+
+	CreateReturnFunc       func(ctx context.Context, ret *entity.Return, returnable []string) error
+	GetReturnFunc          func(ctx context.Context, returnID string) (*entity.Return, error)
+	ListReturnsFunc        func(ctx context.Context, orderID string) ([]entity.Return, error)
+	UpdateReturnStatusFunc func(ctx context.Context, returnID string, from []string, status string) (bool, error)
+	CompleteReturnFunc     func(ctx context.Context, returnID string, amount int64) (bool, error)
 }
 
 func (m *MockOrderRepo) CreateOrder(ctx context.Context, order *entity.Order) error {
@@ -32,6 +42,48 @@ func (m *MockOrderRepo) ListOrdersByUser(ctx context.Context, userID int64, limi
 	return m.ListOrdersByUserFunc(ctx, userID, limit, offset)
 }
 
+func (m *MockOrderRepo) UpdateStatus(ctx context.Context, orderID string, from []string, status string) (bool, error) {
+	return m.UpdateStatusFunc(ctx, orderID, from, status)
+}
+
+func (m *MockOrderRepo) MarkCancelling(ctx context.Context, orderID string) (bool, error) {
+	return m.MarkCancellingFunc(ctx, orderID)
+}
+
+func (m *MockOrderRepo) CreateReturn(ctx context.Context, ret *entity.Return, returnable []string) error {
+	return m.CreateReturnFunc(ctx, ret, returnable)
+}
+
+func (m *MockOrderRepo) GetReturn(ctx context.Context, returnID string) (*entity.Return, error) {
+	return m.GetReturnFunc(ctx, returnID)
+}
+
+func (m *MockOrderRepo) ListReturns(ctx context.Context, orderID string) ([]entity.Return, error) {
+	return m.ListReturnsFunc(ctx, orderID)
+}
+
+func (m *MockOrderRepo) UpdateReturnStatus(ctx context.Context, returnID string, from []string, status string) (bool, error) {
+	return m.UpdateReturnStatusFunc(ctx, returnID, from, status)
+}
+
+func (m *MockOrderRepo) CompleteReturn(ctx context.Context, returnID string, amount int64) (bool, error) {
+	return m.CompleteReturnFunc(ctx, returnID, amount)
+}
+
+// MockSagaStarter is a mock implementation of interfaces.SagaStarter
+type MockSagaStarter struct {
+	CancelOrderFunc func(ctx context.Context, orderID string, userID int64) error
+	StartReturnFunc func(ctx context.Context, returnID, orderID string, userID int64, items []entity.OrderItem) (int64, error)
+}
+
+func (m *MockSagaStarter) CancelOrder(ctx context.Context, orderID string, userID int64) error {
+	return m.CancelOrderFunc(ctx, orderID, userID)
+}
+
+func (m *MockSagaStarter) StartReturn(ctx context.Context, returnID, orderID string, userID int64, items []entity.OrderItem) (int64, error) {
+	return m.StartReturnFunc(ctx, returnID, orderID, userID, items)
+}
+
 func TestService_CreateOrder(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -77,7 +129,7 @@ func TestService_CreateOrder(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := NewOrderService(tt.mockRepo(), logger.Log)
+			service := NewOrderService(tt.mockRepo(), &MockSagaStarter{}, logger.Log)
 
 			id, err := service.CreateOrder(context.Background(), tt.order)
 
@@ -135,7 +187,7 @@ func TestService_GetOrder(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := NewOrderService(tt.mockRepo(), logger.Log)
+			service := NewOrderService(tt.mockRepo(), &MockSagaStarter{}, logger.Log)
 
 			order, err := service.GetOrder(context.Background(), tt.orderID)
 
@@ -199,7 +251,7 @@ func TestService_ListOrdersByUser(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			service := NewOrderService(tt.mockRepo(), logger.Log)
+			service := NewOrderService(tt.mockRepo(), &MockSagaStarter{}, logger.Log)
 
 			orders, err := service.ListOrdersByUser(context.Background(), tt.userID, tt.limit, tt.offset)
 
@@ -218,3 +270,460 @@ func TestService_ListOrdersByUser(t *testing.T) {
 		})
 	}
 }
+
+func TestService_CancelOrder(t *testing.T) {
+	completed := func(ctx context.Context, orderID string) (*entity.Order, error) {
+		return &entity.Order{OrderID: orderID, UserID: 1, Status: entity.StatusCompleted}, nil
+	}
+	marked := func(ctx context.Context, orderID string) (bool, error) {
+		return true, nil
+	}
+	sagaStarted := func(ctx context.Context, orderID string, userID int64) error { return nil }
+
+	tests := []struct {
+		name           string
+		userID         int64
+		mockRepo       func() *MockOrderRepo
+		mockSaga       func() *MockSagaStarter
+		expectedStatus string
+		expectedError  error
+	}{
+		{
+			name:   "Success",
+			userID: 1,
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{GetOrderFunc: completed, MarkCancellingFunc: marked}
+			},
+			mockSaga:       func() *MockSagaStarter { return &MockSagaStarter{CancelOrderFunc: sagaStarted} },
+			expectedStatus: entity.StatusCancelling,
+		},
+		{
+			name:   "Already Cancelled",
+			userID: 1,
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{
+					GetOrderFunc: func(ctx context.Context, orderID string) (*entity.Order, error) {
+						return &entity.Order{OrderID: orderID, UserID: 1, Status: entity.StatusCancelled}, nil
+					},
+				}
+			},
+			mockSaga:       func() *MockSagaStarter { return &MockSagaStarter{} },
+			expectedStatus: entity.StatusCancelled,
+		},
+		{
+			name:   "Other User",
+			userID: 2,
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{GetOrderFunc: completed}
+			},
+			mockSaga:      func() *MockSagaStarter { return &MockSagaStarter{} },
+			expectedError: apperrors.ErrOrderNotFound,
+		},
+		{
+			name:   "Partially Returned",
+			userID: 1,
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{
+					GetOrderFunc: func(ctx context.Context, orderID string) (*entity.Order, error) {
+						return &entity.Order{OrderID: orderID, UserID: 1, Status: entity.StatusPartiallyReturned}, nil
+					},
+				}
+			},
+			mockSaga:      func() *MockSagaStarter { return &MockSagaStarter{} },
+			expectedError: apperrors.ErrOrderNotCancellable,
+		},
+		{
+			name:   "Return Requested Concurrently",
+			userID: 1,
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{
+					GetOrderFunc: completed,
+					MarkCancellingFunc: func(ctx context.Context, orderID string) (bool, error) {
+						return false, nil
+					},
+				}
+			},
+			mockSaga:      func() *MockSagaStarter { return &MockSagaStarter{} },
+			expectedError: apperrors.ErrOrderNotCancellable,
+		},
+		{
+			name:   "Saga Refused",
+			userID: 1,
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{
+					GetOrderFunc:       completed,
+					MarkCancellingFunc: marked,
+					UpdateStatusFunc: func(ctx context.Context, orderID string, from []string, status string) (bool, error) {
+						// The order goes back to COMPLETED after the saga refuses to cancel it
+						if status != entity.StatusCompleted {
+							t.Errorf("Unexpected status %s", status)
+						}
+						return true, nil
+					},
+				}
+			},
+			mockSaga: func() *MockSagaStarter {
+				return &MockSagaStarter{CancelOrderFunc: func(ctx context.Context, orderID string, userID int64) error {
+					return apperrors.ErrOrderNotCancellable
+				}}
+			},
+			expectedError: apperrors.ErrOrderNotCancellable,
+		},
+		{
+			name:   "Saga Unavailable",
+			userID: 1,
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{GetOrderFunc: completed, MarkCancellingFunc: marked}
+			},
+			mockSaga: func() *MockSagaStarter {
+				return &MockSagaStarter{CancelOrderFunc: func(ctx context.Context, orderID string, userID int64) error {
+					return errors.New("unavailable")
+				}}
+			},
+			expectedError: errors.New("start cancellation saga: unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewOrderService(tt.mockRepo(), tt.mockSaga(), logger.Log)
+
+			orderStatus, err := service.CancelOrder(context.Background(), "order-123", tt.userID)
+
+			if tt.expectedError != nil {
+				if err == nil || err.Error() != tt.expectedError.Error() {
+					t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if orderStatus != tt.expectedStatus {
+					t.Errorf("Expected status %s, got %s", tt.expectedStatus, orderStatus)
+				}
+			}
+		})
+	}
+}
+
+func TestService_ConfirmCancellation(t *testing.T) {
+	notUpdated := func(ctx context.Context, orderID string, from []string, status string) (bool, error) {
+		return false, nil
+	}
+
+	tests := []struct {
+		name          string
+		mockRepo      func() *MockOrderRepo
+		expectedError error
+	}{
+		{
+			name: "Success",
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{
+					UpdateStatusFunc: func(ctx context.Context, orderID string, from []string, status string) (bool, error) {
+						if status != entity.StatusCancelled {
+							t.Errorf("Expected status %s, got %s", entity.StatusCancelled, status)
+						}
+						return true, nil
+					},
+				}
+			},
+		},
+		{
+			name: "Already Cancelled",
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{
+					UpdateStatusFunc: notUpdated,
+					GetOrderFunc: func(ctx context.Context, orderID string) (*entity.Order, error) {
+						return &entity.Order{OrderID: orderID, Status: entity.StatusCancelled}, nil
+					},
+				}
+			},
+		},
+		{
+			name: "Not Found",
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{
+					UpdateStatusFunc: notUpdated,
+					GetOrderFunc: func(ctx context.Context, orderID string) (*entity.Order, error) {
+						return nil, nil
+					},
+				}
+			},
+			expectedError: apperrors.ErrOrderNotFound,
+		},
+		{
+			name: "Returned",
+			mockRepo: func() *MockOrderRepo {
+				return &MockOrderRepo{
+					UpdateStatusFunc: notUpdated,
+					GetOrderFunc: func(ctx context.Context, orderID string) (*entity.Order, error) {
+						return &entity.Order{OrderID: orderID, Status: entity.StatusReturned}, nil
+					},
+				}
+			},
+			expectedError: apperrors.ErrOrderNotCancellable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewOrderService(tt.mockRepo(), &MockSagaStarter{}, logger.Log)
+
+			err := service.ConfirmCancellation(context.Background(), "order-123")
+
+			if tt.expectedError != nil {
+				if err == nil || err.Error() != tt.expectedError.Error() {
+					t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+				}
+			} else if err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestService_RequestReturn(t *testing.T) {
+	tests := []struct {
+		name          string
+		items         []entity.OrderItem
+		repoErr       error
+		expectedItems []entity.OrderItem
+		expectedError error
+	}{
+		{
+			name:          "Success",
+			items:         []entity.OrderItem{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 1}, {ProductID: 1, Quantity: 1}},
+			expectedItems: []entity.OrderItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}},
+		},
+		{
+			name:          "No Items",
+			expectedError: apperrors.ErrInvalidReturn,
+		},
+		{
+			name:          "Invalid Quantity",
+			items:         []entity.OrderItem{{ProductID: 1, Quantity: 0}},
+			expectedError: apperrors.ErrInvalidReturn,
+		},
+		{
+			name:          "More Than Left",
+			items:         []entity.OrderItem{{ProductID: 1, Quantity: 5}},
+			repoErr:       apperrors.ErrInvalidReturn,
+			expectedError: apperrors.ErrInvalidReturn,
+		},
+		{
+			name:          "Cancelled Order",
+			items:         []entity.OrderItem{{ProductID: 1, Quantity: 1}},
+			repoErr:       apperrors.ErrOrderNotReturnable,
+			expectedError: apperrors.ErrOrderNotReturnable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockOrderRepo{
+				CreateReturnFunc: func(ctx context.Context, ret *entity.Return, returnable []string) error {
+					if ret.Status != entity.ReturnRequested || ret.ReturnID == "" {
+						t.Errorf("Unexpected return %+v", ret)
+					}
+					if !slices.Contains(returnable, entity.StatusPartiallyReturned) || slices.Contains(returnable, entity.StatusCancelled) {
+						t.Errorf("Unexpected returnable statuses %v", returnable)
+					}
+					if tt.repoErr == nil && !slices.Equal(ret.Items, tt.expectedItems) {
+						t.Errorf("Expected items %v, got %v", tt.expectedItems, ret.Items)
+					}
+					return tt.repoErr
+				},
+			}
+			service := NewOrderService(repo, &MockSagaStarter{}, logger.Log)
+
+			ret, err := service.RequestReturn(context.Background(), "order-123", 1, tt.items, "damaged")
+
+			if tt.expectedError != nil {
+				if !errors.Is(err, tt.expectedError) {
+					t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ret.OrderID != "order-123" || ret.Reason != "damaged" {
+				t.Errorf("Unexpected return %+v", ret)
+			}
+		})
+	}
+}
+
+func TestService_ApproveReturn(t *testing.T) {
+	requested := func(ctx context.Context, returnID string) (*entity.Return, error) {
+		return &entity.Return{ReturnID: returnID, OrderID: "order-123", UserID: 1, Status: entity.ReturnRequested,
+			Items: []entity.OrderItem{{ProductID: 1, Quantity: 1}}}, nil
+	}
+
+	tests := []struct {
+		name           string
+		getReturn      func(ctx context.Context, returnID string) (*entity.Return, error)
+		sagaErr        error
+		expectedStatus string
+		expectedAmount int64
+		expectedError  error
+		expectRevert   bool
+	}{
+		{
+			name:           "Success",
+			getReturn:      requested,
+			expectedStatus: entity.ReturnApproved,
+			expectedAmount: 100,
+		},
+		{
+			name: "Already Completed",
+			getReturn: func(ctx context.Context, returnID string) (*entity.Return, error) {
+				return &entity.Return{ReturnID: returnID, Status: entity.ReturnCompleted, Amount: 100}, nil
+			},
+			expectedStatus: entity.ReturnCompleted,
+			expectedAmount: 100,
+		},
+		{
+			name: "Rejected",
+			getReturn: func(ctx context.Context, returnID string) (*entity.Return, error) {
+				return &entity.Return{ReturnID: returnID, Status: entity.ReturnRejected}, nil
+			},
+			expectedError: apperrors.ErrInvalidReturnState,
+		},
+		{
+			name: "Not Found",
+			getReturn: func(ctx context.Context, returnID string) (*entity.Return, error) {
+				return nil, nil
+			},
+			expectedError: apperrors.ErrReturnNotFound,
+		},
+		{
+			name:          "Saga Refused",
+			getReturn:     requested,
+			sagaErr:       apperrors.ErrOrderNotReturnable,
+			expectedError: apperrors.ErrOrderNotReturnable,
+			expectRevert:  true,
+		},
+		{
+			name:          "Saga Unavailable",
+			getReturn:     requested,
+			sagaErr:       errors.New("unavailable"),
+			expectedError: errors.New("start return saga: unavailable"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reverted := false
+			repo := &MockOrderRepo{
+				GetReturnFunc: tt.getReturn,
+				UpdateReturnStatusFunc: func(ctx context.Context, returnID string, from []string, status string) (bool, error) {
+					if status == entity.ReturnRequested {
+						reverted = true
+					}
+					return true, nil
+				},
+			}
+			saga := &MockSagaStarter{
+				StartReturnFunc: func(ctx context.Context, returnID, orderID string, userID int64, items []entity.OrderItem) (int64, error) {
+					if returnID != "return-1" || orderID != "order-123" || userID != 1 {
+						t.Errorf("Unexpected saga call %s %s %d", returnID, orderID, userID)
+					}
+					return 100, tt.sagaErr
+				},
+			}
+			service := NewOrderService(repo, saga, logger.Log)
+
+			ret, err := service.ApproveReturn(context.Background(), "return-1")
+
+			if reverted != tt.expectRevert {
+				t.Errorf("Expected revert %v, got %v", tt.expectRevert, reverted)
+			}
+			if tt.expectedError != nil {
+				if err == nil || err.Error() != tt.expectedError.Error() {
+					t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if ret.Status != tt.expectedStatus || ret.Amount != tt.expectedAmount {
+				t.Errorf("Expected %s/%d, got %s/%d", tt.expectedStatus, tt.expectedAmount, ret.Status, ret.Amount)
+			}
+		})
+	}
+}
+
+func TestService_RejectReturn(t *testing.T) {
+	tests := []struct {
+		name          string
+		current       *entity.Return
+		expectedError error
+	}{
+		{name: "Success", current: &entity.Return{ReturnID: "return-1", Status: entity.ReturnRejected}},
+		{name: "Already Approved", current: &entity.Return{ReturnID: "return-1", Status: entity.ReturnApproved}, expectedError: apperrors.ErrInvalidReturnState},
+		{name: "Not Found", expectedError: apperrors.ErrReturnNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockOrderRepo{
+				UpdateReturnStatusFunc: func(ctx context.Context, returnID string, from []string, status string) (bool, error) {
+					if status != entity.ReturnRejected || !slices.Equal(from, []string{entity.ReturnRequested}) {
+						t.Errorf("Unexpected transition %v -> %s", from, status)
+					}
+					return tt.current != nil && tt.current.Status == entity.ReturnRejected, nil
+				},
+				GetReturnFunc: func(ctx context.Context, returnID string) (*entity.Return, error) {
+					return tt.current, nil
+				},
+			}
+			service := NewOrderService(repo, &MockSagaStarter{}, logger.Log)
+
+			_, err := service.RejectReturn(context.Background(), "return-1")
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestService_CompleteReturn(t *testing.T) {
+	tests := []struct {
+		name          string
+		completed     bool
+		current       *entity.Return
+		expectedError error
+	}{
+		{name: "Success", completed: true},
+		{name: "Already Completed", current: &entity.Return{ReturnID: "return-1", Status: entity.ReturnCompleted}},
+		{name: "Not Approved", current: &entity.Return{ReturnID: "return-1", Status: entity.ReturnRequested}, expectedError: apperrors.ErrInvalidReturnState},
+		{name: "Not Found", expectedError: apperrors.ErrReturnNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MockOrderRepo{
+				CompleteReturnFunc: func(ctx context.Context, returnID string, amount int64) (bool, error) {
+					if amount != 100 {
+						t.Errorf("Expected amount 100, got %d", amount)
+					}
+					return tt.completed, nil
+				},
+				GetReturnFunc: func(ctx context.Context, returnID string) (*entity.Return, error) {
+					return tt.current, nil
+				},
+			}
+			service := NewOrderService(repo, &MockSagaStarter{}, logger.Log)
+
+			err := service.CompleteReturn(context.Background(), "return-1", 100)
+
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("Expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
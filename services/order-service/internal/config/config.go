@@ -16,6 +16,8 @@ type Config struct {
 	PGPort         string
 	GRPCServerPort int
 	HTTPHealthPort int
+	// saga orchestrator that runs the order cancellation saga
+	GRPCSagaClientPort string
 }
 
 func MustLoad() (*Config, error) {
@@ -35,12 +37,13 @@ func MustLoad() (*Config, error) {
 	}
 
 	return &Config{
-		PGUser:         os.Getenv("PG_USER"),
-		PGPassword:     os.Getenv("PG_PASSWORD"),
-		PGName:         os.Getenv("PG_NAME"),
-		PGHost:         os.Getenv("PG_HOST"),
-		PGPort:         os.Getenv("PG_PORT"),
-		GRPCServerPort: GRPCServerPort,
-		HTTPHealthPort: HTTPHealthPort,
+		PGUser:             os.Getenv("PG_USER"),
+		PGPassword:         os.Getenv("PG_PASSWORD"),
+		PGName:             os.Getenv("PG_NAME"),
+		PGHost:             os.Getenv("PG_HOST"),
+		PGPort:             os.Getenv("PG_PORT"),
+		GRPCServerPort:     GRPCServerPort,
+		HTTPHealthPort:     HTTPHealthPort,
+		GRPCSagaClientPort: os.Getenv("GRPC_SAGA_CLIENT_PORT"),
 	}, nil
 }
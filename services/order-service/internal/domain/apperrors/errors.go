@@ -0,0 +1,20 @@
+package apperrors
+
+import "errors"
+
+// ErrOrderNotFound - the order does not exist or belongs to another user
+var ErrOrderNotFound = errors.New("order not found")
+
+// ErrOrderNotCancellable - only a completed order without open returns can be cancelled
+var ErrOrderNotCancellable = errors.New("order cannot be cancelled")
+
+var (
+	// ErrReturnNotFound - the return does not exist
+	ErrReturnNotFound = errors.New("return not found")
+	// ErrOrderNotReturnable - only items of a completed or delivered order can be returned
+	ErrOrderNotReturnable = errors.New("order cannot be returned")
+	// ErrInvalidReturn - the return has no items, an item is not in the order or more is returned than was bought
+	ErrInvalidReturn = errors.New("invalid return items")
+	// ErrInvalidReturnState - the operation is not allowed in the current status of the return
+	ErrInvalidReturnState = errors.New("operation not allowed in current return state")
+)
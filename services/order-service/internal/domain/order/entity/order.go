@@ -2,6 +2,17 @@ package entity
 
 import "time"
 
+// Order statuses. StatusCompleted is the status the saga reports for a paid order;
+// only Completed orders can be cancelled, and only Completed or PartiallyReturned ones can be returned.
+const (
+	StatusCompleted  = "Completed"
+	StatusCancelling = "Cancelling"
+	StatusCancelled  = "Cancelled"
+	// Some or all items of the order were returned and refunded
+	StatusPartiallyReturned = "PartiallyReturned"
+	StatusReturned          = "Returned"
+)
+
 type OrderItem struct {
 	ProductID int64 `db:"product_id" json:"product_id"`
 	Quantity  int64 `db:"quantity" json:"quantity"`
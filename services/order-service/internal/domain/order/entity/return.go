@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// Return statuses. A requested return is refunded and restocked by the saga once approved.
+const (
+	ReturnRequested = "REQUESTED"
+	ReturnApproved  = "APPROVED"
+	ReturnCompleted = "COMPLETED"
+	ReturnRejected  = "REJECTED"
+)
+
+// Return is a request to give back some items of an order
+type Return struct {
+	ReturnID string      `db:"return_id" json:"return_id"`
+	OrderID  string      `db:"order_id" json:"order_id"`
+	UserID   int64       `db:"user_id" json:"user_id"`
+	Items    []OrderItem `json:"items"`
+	Status   string      `db:"status" json:"status"`
+	Reason   string      `db:"reason" json:"reason,omitempty"`
+	// Amount is the refunded sum, known once the saga has refunded the return
+	Amount    int64     `db:"amount" json:"amount"`
+	CreatedAt time.Time `db:"created_at" json:"created_at,omitempty"`
+}
@@ -10,4 +10,28 @@ type OrderRepo interface {
 	CreateOrder(ctx context.Context, order *entity.Order) error
 	GetOrder(ctx context.Context, orderID string) (*entity.Order, error)
 	ListOrdersByUser(ctx context.Context, userID int64, limit, offset uint64) ([]entity.Order, error)
+	// UpdateStatus moves the order to status only if its current status is one of from.
+	// It returns false if the order does not exist or is in another status.
+	UpdateStatus(ctx context.Context, orderID string, from []string, status string) (bool, error)
+	// MarkCancelling moves a completed order to Cancelling unless it has open returns.
+	// It returns false if the order is in another status or a return is in progress.
+	MarkCancelling(ctx context.Context, orderID string) (bool, error)
+
+	// CreateReturn saves a requested return if the order is in one of the returnable statuses
+	// and every item is still available for return.
+	CreateReturn(ctx context.Context, ret *entity.Return, returnable []string) error
+	GetReturn(ctx context.Context, returnID string) (*entity.Return, error)
+	ListReturns(ctx context.Context, orderID string) ([]entity.Return, error)
+	// UpdateReturnStatus moves the return to status only if its current status is one of from
+	UpdateReturnStatus(ctx context.Context, returnID string, from []string, status string) (bool, error)
+	// CompleteReturn marks an approved return as completed with the refunded amount and
+	// moves the order to PartiallyReturned or Returned. It returns false if the return is not approved.
+	CompleteReturn(ctx context.Context, returnID string, amount int64) (bool, error)
+}
+
+// SagaStarter starts the cancellation and return sagas in the saga orchestrator
+type SagaStarter interface {
+	CancelOrder(ctx context.Context, orderID string, userID int64) error
+	// StartReturn returns the amount that will be refunded for the returned items
+	StartReturn(ctx context.Context, returnID, orderID string, userID int64, items []entity.OrderItem) (int64, error)
 }
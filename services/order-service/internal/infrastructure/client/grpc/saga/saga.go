@@ -0,0 +1,72 @@
+package saga
+
+import (
+	"context"
+
+	"github.com/vsespontanno/eCommerce/proto/saga"
+	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/apperrors"
+	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/order/entity"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+type Client struct {
+	client saga.SagaClient
+	logger *zap.SugaredLogger
+	addr   string
+}
+
+func NewSagaClient(addr string, logger *zap.SugaredLogger) *Client {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logger.Fatalf("Failed to dial gRPC server %s: %v", addr, err)
+	}
+	logger.Infow("Connected to Saga service as a client", "addr", addr)
+	return &Client{
+		client: saga.NewSagaClient(conn),
+		addr:   addr,
+		logger: logger,
+	}
+}
+
+// CancelOrder starts the cancellation saga; calling it again while the saga runs is a no-op
+func (c *Client) CancelOrder(ctx context.Context, orderID string, userID int64) error {
+	_, err := c.client.CancelOrder(ctx, &saga.CancelOrderRequest{OrderID: orderID, UserID: userID})
+	switch status.Code(err) {
+	case codes.OK:
+		return nil
+	case codes.NotFound:
+		return apperrors.ErrOrderNotFound
+	case codes.FailedPrecondition:
+		return apperrors.ErrOrderNotCancellable
+	default:
+		c.logger.Errorw("Error while cancelling order", "error", err, "order_id", orderID)
+		return err
+	}
+}
+
+// StartReturn starts the return saga and returns the amount to be refunded; calling it again is a no-op
+func (c *Client) StartReturn(ctx context.Context, returnID, orderID string, userID int64, items []entity.OrderItem) (int64, error) {
+	req := &saga.StartReturnRequest{ReturnID: returnID, OrderID: orderID, UserID: userID}
+	for _, it := range items {
+		req.Items = append(req.Items, &saga.ReturnItem{ProductID: it.ProductID, Quantity: it.Quantity})
+	}
+
+	resp, err := c.client.StartReturn(ctx, req)
+	switch status.Code(err) {
+	case codes.OK:
+		return resp.Amount, nil
+	case codes.NotFound:
+		return 0, apperrors.ErrOrderNotFound
+	case codes.FailedPrecondition:
+		return 0, apperrors.ErrOrderNotReturnable
+	case codes.InvalidArgument:
+		return 0, apperrors.ErrInvalidReturn
+	default:
+		c.logger.Errorw("Error while starting order return", "error", err, "return_id", returnID, "order_id", orderID)
+		return 0, err
+	}
+}
@@ -155,6 +155,45 @@ func (s *OrderStore) ListOrdersByUser(ctx context.Context, userID int64, limit,
 	return orders, nil
 }
 
+func (s *OrderStore) UpdateStatus(ctx context.Context, orderID string, from []string, status string) (bool, error) {
+	res, err := s.builder.
+		Update("orders").
+		Set("status", status).
+		Where(sq.Eq{"id": orderID, "status": from}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("update order status: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return n == 1, nil
+}
+
+func (s *OrderStore) MarkCancelling(ctx context.Context, orderID string) (bool, error) {
+	// The open returns check and the update are one statement, so a return requested
+	// concurrently either sees Cancelling or blocks cancellation
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE orders SET status = $2
+         WHERE id = $1 AND status IN ($3, $2)
+           AND NOT EXISTS (
+               SELECT 1 FROM order_returns
+               WHERE order_id = $1 AND status IN ($4, $5)
+           )`,
+		orderID, entity.StatusCancelling, entity.StatusCompleted, entity.ReturnRequested, entity.ReturnApproved,
+	)
+	if err != nil {
+		return false, fmt.Errorf("mark order cancelling: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return n == 1, nil
+}
+
 // loadOrderItems loads items for a specific order
 func (s *OrderStore) loadOrderItems(ctx context.Context, orderID string) ([]entity.OrderItem, error) {
 	itemsRows, err := s.db.QueryxContext(ctx,
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/apperrors"
+	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/order/entity"
+)
+
+func (s *OrderStore) CreateReturn(ctx context.Context, ret *entity.Return, returnable []string) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("tx begin: %w", err)
+	}
+	defer s.rollback(tx)
+
+	// Locking the order serializes returns with each other and with cancellation
+	var (
+		userID int64
+		status string
+	)
+	err = tx.QueryRowContext(ctx,
+		`SELECT user_id, status FROM orders WHERE id = $1 FOR UPDATE`, ret.OrderID,
+	).Scan(&userID, &status)
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.ErrOrderNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("lock order: %w", err)
+	}
+	if userID != ret.UserID {
+		return apperrors.ErrOrderNotFound
+	}
+	if !slices.Contains(returnable, status) {
+		return apperrors.ErrOrderNotReturnable
+	}
+
+	// Items in rejected returns can be returned again
+	available, err := s.itemsLeft(ctx, tx, ret.OrderID,
+		[]string{entity.ReturnRequested, entity.ReturnApproved, entity.ReturnCompleted},
+	)
+	if err != nil {
+		return err
+	}
+	for _, it := range ret.Items {
+		if it.Quantity > available[it.ProductID] {
+			return apperrors.ErrInvalidReturn
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO order_returns (id, order_id, user_id, status, reason)
+         VALUES ($1, $2, $3, $4, $5)`,
+		ret.ReturnID, ret.OrderID, ret.UserID, ret.Status, ret.Reason,
+	)
+	if err != nil {
+		return fmt.Errorf("insert return: %w", err)
+	}
+	for _, it := range ret.Items {
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO order_return_items (return_id, product_id, quantity)
+             VALUES ($1, $2, $3)`,
+			ret.ReturnID, it.ProductID, it.Quantity,
+		)
+		if err != nil {
+			return fmt.Errorf("insert return item: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+
+	s.logger.Infow("return saved", "return_id", ret.ReturnID, "order_id", ret.OrderID)
+	return nil
+}
+
+func (s *OrderStore) GetReturn(ctx context.Context, returnID string) (*entity.Return, error) {
+	var ret entity.Return
+	err := s.db.GetContext(ctx, &ret,
+		`SELECT id AS return_id, order_id, user_id, status, reason, amount, created_at
+         FROM order_returns WHERE id = $1`, returnID,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("select return: %w", err)
+	}
+
+	items, err := s.loadReturnItems(ctx, returnID)
+	if err != nil {
+		return nil, err
+	}
+	ret.Items = items
+	return &ret, nil
+}
+
+func (s *OrderStore) ListReturns(ctx context.Context, orderID string) ([]entity.Return, error) {
+	returns := make([]entity.Return, 0)
+	err := s.db.SelectContext(ctx, &returns,
+		`SELECT id AS return_id, order_id, user_id, status, reason, amount, created_at
+         FROM order_returns WHERE order_id = $1
+         ORDER BY created_at ASC`, orderID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select returns: %w", err)
+	}
+
+	for i := range returns {
+		items, err := s.loadReturnItems(ctx, returns[i].ReturnID)
+		if err != nil {
+			return nil, fmt.Errorf("load items for return %s: %w", returns[i].ReturnID, err)
+		}
+		returns[i].Items = items
+	}
+	return returns, nil
+}
+
+func (s *OrderStore) UpdateReturnStatus(ctx context.Context, returnID string, from []string, status string) (bool, error) {
+	res, err := s.builder.
+		Update("order_returns").
+		Set("status", status).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"id": returnID, "status": from}).
+		RunWith(s.db).
+		ExecContext(ctx)
+	if err != nil {
+		return false, fmt.Errorf("update return status: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	return n == 1, nil
+}
+
+func (s *OrderStore) CompleteReturn(ctx context.Context, returnID string, amount int64) (bool, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("tx begin: %w", err)
+	}
+	defer s.rollback(tx)
+
+	var orderID string
+	err = tx.QueryRowContext(ctx,
+		`SELECT order_id FROM order_returns WHERE id = $1`, returnID,
+	).Scan(&orderID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("select return: %w", err)
+	}
+
+	// Concurrent returns of the same order must not both see the other one as not yet completed
+	if _, err := tx.ExecContext(ctx, `SELECT 1 FROM orders WHERE id = $1 FOR UPDATE`, orderID); err != nil {
+		return false, fmt.Errorf("lock order: %w", err)
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE order_returns SET status = $2, amount = $3, updated_at = NOW()
+         WHERE id = $1 AND status = $4`,
+		returnID, entity.ReturnCompleted, amount, entity.ReturnApproved,
+	)
+	if err != nil {
+		return false, fmt.Errorf("complete return: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("rows affected: %w", err)
+	}
+	if n == 0 {
+		return false, nil
+	}
+
+	remaining, err := s.itemsLeft(ctx, tx, orderID, []string{entity.ReturnCompleted})
+	if err != nil {
+		return false, err
+	}
+	status := entity.StatusReturned
+	for _, qty := range remaining {
+		if qty > 0 {
+			status = entity.StatusPartiallyReturned
+			break
+		}
+	}
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE orders SET status = $2, updated_at = NOW() WHERE id = $1`, orderID, status,
+	); err != nil {
+		return false, fmt.Errorf("update order status: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit: %w", err)
+	}
+
+	s.logger.Infow("return completed", "return_id", returnID, "order_id", orderID, "order_status", status)
+	return true, nil
+}
+
+// itemsLeft returns the quantity of every order item not covered by returns in the given statuses
+func (s *OrderStore) itemsLeft(ctx context.Context, tx *sqlx.Tx, orderID string, statuses []string) (map[int64]int64, error) {
+	rows, err := tx.QueryContext(ctx,
+		`SELECT oi.product_id, oi.quantity - COALESCE((
+             SELECT SUM(ri.quantity)
+             FROM order_return_items ri
+             JOIN order_returns r ON r.id = ri.return_id
+             WHERE r.order_id = oi.order_id AND ri.product_id = oi.product_id AND r.status = ANY($2)
+         ), 0)
+         FROM order_items oi WHERE oi.order_id = $1`,
+		orderID, pq.Array(statuses),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query item quantities: %w", err)
+	}
+	defer rows.Close()
+
+	quantities := make(map[int64]int64)
+	for rows.Next() {
+		var productID, qty int64
+		if err := rows.Scan(&productID, &qty); err != nil {
+			return nil, fmt.Errorf("scan item quantity: %w", err)
+		}
+		quantities[productID] = qty
+	}
+	return quantities, rows.Err()
+}
+
+// loadReturnItems loads items for a specific return
+func (s *OrderStore) loadReturnItems(ctx context.Context, returnID string) ([]entity.OrderItem, error) {
+	var items []entity.OrderItem
+	err := s.db.SelectContext(ctx, &items,
+		`SELECT product_id, quantity FROM order_return_items WHERE return_id = $1`, returnID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select return items: %w", err)
+	}
+	return items, nil
+}
+
+func (s *OrderStore) rollback(tx *sqlx.Tx) {
+	if rbErr := tx.Rollback(); rbErr != nil && !errors.Is(rbErr, sql.ErrTxDone) {
+		s.logger.Errorw("failed to rollback transaction", "error", rbErr)
+	}
+}
@@ -2,9 +2,11 @@ package ordergrpc
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 	proto "github.com/vsespontanno/eCommerce/proto/orders"
+	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/order/entity"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
@@ -15,6 +17,13 @@ type OrderSvc interface {
 	CreateOrder(ctx context.Context, order *entity.Order) (string, error)
 	GetOrder(ctx context.Context, orderID string) (*entity.Order, error)
 	ListOrdersByUser(ctx context.Context, userID int64, limit, offset uint64) ([]entity.Order, error)
+	CancelOrder(ctx context.Context, orderID string, userID int64) (string, error)
+	ConfirmCancellation(ctx context.Context, orderID string) error
+	RequestReturn(ctx context.Context, orderID string, userID int64, items []entity.OrderItem, reason string) (*entity.Return, error)
+	ApproveReturn(ctx context.Context, returnID string) (*entity.Return, error)
+	RejectReturn(ctx context.Context, returnID string) (*entity.Return, error)
+	ListReturns(ctx context.Context, orderID string) ([]entity.Return, error)
+	CompleteReturn(ctx context.Context, returnID string, amount int64) error
 }
 
 type Server struct {
@@ -165,3 +174,162 @@ func (s *Server) ListOrders(ctx context.Context, req *proto.ListOrdersRequest) (
 	s.logger.Infow("orders listed", "user_id", req.UserId, "count", len(orders))
 	return resp, nil
 }
+
+func (s *Server) CancelOrder(ctx context.Context, req *proto.CancelOrderRequest) (*proto.CancelOrderResponse, error) {
+	if _, err := uuid.Parse(req.OrderId); err != nil {
+		s.logger.Warnw("Invalid order_id format in CancelOrder", "order_id", req.OrderId)
+		return nil, status.Error(codes.InvalidArgument, "order_id must be a valid UUID")
+	}
+	if req.UserId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "valid user_id is required")
+	}
+
+	orderStatus, err := s.svc.CancelOrder(ctx, req.OrderId, req.UserId)
+	if err != nil {
+		return nil, cancellationError(err)
+	}
+
+	return &proto.CancelOrderResponse{Status: orderStatus}, nil
+}
+
+func (s *Server) ConfirmCancellation(ctx context.Context, req *proto.ConfirmCancellationRequest) (*proto.ConfirmCancellationResponse, error) {
+	if _, err := uuid.Parse(req.OrderId); err != nil {
+		s.logger.Warnw("Invalid order_id format in ConfirmCancellation", "order_id", req.OrderId)
+		return nil, status.Error(codes.InvalidArgument, "order_id must be a valid UUID")
+	}
+
+	if err := s.svc.ConfirmCancellation(ctx, req.OrderId); err != nil {
+		s.logger.Errorw("confirm cancellation failed", "order_id", req.OrderId, "err", err)
+		return nil, cancellationError(err)
+	}
+
+	return &proto.ConfirmCancellationResponse{}, nil
+}
+
+func (s *Server) RequestReturn(ctx context.Context, req *proto.RequestReturnRequest) (*proto.ReturnResponse, error) {
+	if _, err := uuid.Parse(req.OrderId); err != nil {
+		s.logger.Warnw("Invalid order_id format in RequestReturn", "order_id", req.OrderId)
+		return nil, status.Error(codes.InvalidArgument, "order_id must be a valid UUID")
+	}
+	if req.UserId <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "valid user_id is required")
+	}
+
+	items := make([]entity.OrderItem, 0, len(req.Items))
+	for _, it := range req.Items {
+		items = append(items, entity.OrderItem{ProductID: it.ProductId, Quantity: it.Quantity})
+	}
+
+	ret, err := s.svc.RequestReturn(ctx, req.OrderId, req.UserId, items, req.Reason)
+	if err != nil {
+		return nil, returnError(err)
+	}
+
+	return &proto.ReturnResponse{Return: toProtoReturn(ret)}, nil
+}
+
+func (s *Server) ApproveReturn(ctx context.Context, req *proto.ApproveReturnRequest) (*proto.ReturnResponse, error) {
+	if _, err := uuid.Parse(req.ReturnId); err != nil {
+		s.logger.Warnw("Invalid return_id format in ApproveReturn", "return_id", req.ReturnId)
+		return nil, status.Error(codes.InvalidArgument, "return_id must be a valid UUID")
+	}
+
+	ret, err := s.svc.ApproveReturn(ctx, req.ReturnId)
+	if err != nil {
+		return nil, returnError(err)
+	}
+
+	return &proto.ReturnResponse{Return: toProtoReturn(ret)}, nil
+}
+
+func (s *Server) RejectReturn(ctx context.Context, req *proto.RejectReturnRequest) (*proto.ReturnResponse, error) {
+	if _, err := uuid.Parse(req.ReturnId); err != nil {
+		s.logger.Warnw("Invalid return_id format in RejectReturn", "return_id", req.ReturnId)
+		return nil, status.Error(codes.InvalidArgument, "return_id must be a valid UUID")
+	}
+
+	ret, err := s.svc.RejectReturn(ctx, req.ReturnId)
+	if err != nil {
+		return nil, returnError(err)
+	}
+
+	return &proto.ReturnResponse{Return: toProtoReturn(ret)}, nil
+}
+
+func (s *Server) ListReturns(ctx context.Context, req *proto.ListReturnsRequest) (*proto.ListReturnsResponse, error) {
+	if _, err := uuid.Parse(req.OrderId); err != nil {
+		s.logger.Warnw("Invalid order_id format in ListReturns", "order_id", req.OrderId)
+		return nil, status.Error(codes.InvalidArgument, "order_id must be a valid UUID")
+	}
+
+	returns, err := s.svc.ListReturns(ctx, req.OrderId)
+	if err != nil {
+		s.logger.Errorw("list returns failed", "order_id", req.OrderId, "err", err)
+		return nil, status.Error(codes.Internal, "failed to list returns")
+	}
+
+	resp := &proto.ListReturnsResponse{Returns: make([]*proto.OrderReturn, 0, len(returns))}
+	for i := range returns {
+		resp.Returns = append(resp.Returns, toProtoReturn(&returns[i]))
+	}
+	return resp, nil
+}
+
+func (s *Server) CompleteReturn(ctx context.Context, req *proto.CompleteReturnRequest) (*proto.CompleteReturnResponse, error) {
+	if _, err := uuid.Parse(req.ReturnId); err != nil {
+		s.logger.Warnw("Invalid return_id format in CompleteReturn", "return_id", req.ReturnId)
+		return nil, status.Error(codes.InvalidArgument, "return_id must be a valid UUID")
+	}
+	if req.Amount < 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must not be negative")
+	}
+
+	if err := s.svc.CompleteReturn(ctx, req.ReturnId, req.Amount); err != nil {
+		s.logger.Errorw("complete return failed", "return_id", req.ReturnId, "err", err)
+		return nil, returnError(err)
+	}
+
+	return &proto.CompleteReturnResponse{}, nil
+}
+
+func toProtoReturn(ret *entity.Return) *proto.OrderReturn {
+	resp := &proto.OrderReturn{
+		ReturnId: ret.ReturnID,
+		OrderId:  ret.OrderID,
+		UserId:   ret.UserID,
+		Status:   ret.Status,
+		Reason:   ret.Reason,
+		Amount:   ret.Amount,
+	}
+	for _, it := range ret.Items {
+		resp.Items = append(resp.Items, &proto.OrderItem{
+			ProductId: it.ProductID,
+			Quantity:  it.Quantity,
+		})
+	}
+	return resp
+}
+
+func returnError(err error) error {
+	switch {
+	case errors.Is(err, apperrors.ErrOrderNotFound), errors.Is(err, apperrors.ErrReturnNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, apperrors.ErrInvalidReturn):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, apperrors.ErrOrderNotReturnable), errors.Is(err, apperrors.ErrInvalidReturnState):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, "failed to process return")
+	}
+}
+
+func cancellationError(err error) error {
+	switch {
+	case errors.Is(err, apperrors.ErrOrderNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, apperrors.ErrOrderNotCancellable):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, "failed to cancel order")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/vsespontanno/eCommerce/pkg/logger"
 	proto "github.com/vsespontanno/eCommerce/proto/orders"
+	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/order-service/internal/domain/order/entity"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -19,9 +20,16 @@ func init() {
 
 // MockOrderSvc is a mock implementation of OrderSvc
 type MockOrderSvc struct {
-	CreateOrderFunc      func(ctx context.Context, order *entity.Order) (string, error)
-	GetOrderFunc         func(ctx context.Context, orderID string) (*entity.Order, error)
-	ListOrdersByUserFunc func(ctx context.Context, userID int64, limit, offset uint64) ([]entity.Order, error)
+	CreateOrderFunc         func(ctx context.Context, order *entity.Order) (string, error)
+	GetOrderFunc            func(ctx context.Context, orderID string) (*entity.Order, error)
+	ListOrdersByUserFunc    func(ctx context.Context, userID int64, limit, offset uint64) ([]entity.Order, error)
+	CancelOrderFunc         func(ctx context.Context, orderID string, userID int64) (string, error)
+	ConfirmCancellationFunc func(ctx context.Context, orderID string) error
+	RequestReturnFunc       func(ctx context.Context, orderID string, userID int64, items []entity.OrderItem, reason string) (*entity.Return, error)
+	ApproveReturnFunc       func(ctx context.Context, returnID string) (*entity.Return, error)
+	RejectReturnFunc        func(ctx context.Context, returnID string) (*entity.Return, error)
+	ListReturnsFunc         func(ctx context.Context, orderID string) ([]entity.Return, error)
+	CompleteReturnFunc      func(ctx context.Context, returnID string, amount int64) error
 }
 
 func (m *MockOrderSvc) CreateOrder(ctx context.Context, order *entity.Order) (string, error) {
@@ -36,6 +44,34 @@ func (m *MockOrderSvc) ListOrdersByUser(ctx context.Context, userID int64, limit
 	return m.ListOrdersByUserFunc(ctx, userID, limit, offset)
 }
 
+func (m *MockOrderSvc) CancelOrder(ctx context.Context, orderID string, userID int64) (string, error) {
+	return m.CancelOrderFunc(ctx, orderID, userID)
+}
+
+func (m *MockOrderSvc) ConfirmCancellation(ctx context.Context, orderID string) error {
+	return m.ConfirmCancellationFunc(ctx, orderID)
+}
+
+func (m *MockOrderSvc) RequestReturn(ctx context.Context, orderID string, userID int64, items []entity.OrderItem, reason string) (*entity.Return, error) {
+	return m.RequestReturnFunc(ctx, orderID, userID, items, reason)
+}
+
+func (m *MockOrderSvc) ApproveReturn(ctx context.Context, returnID string) (*entity.Return, error) {
+	return m.ApproveReturnFunc(ctx, returnID)
+}
+
+func (m *MockOrderSvc) RejectReturn(ctx context.Context, returnID string) (*entity.Return, error) {
+	return m.RejectReturnFunc(ctx, returnID)
+}
+
+func (m *MockOrderSvc) ListReturns(ctx context.Context, orderID string) ([]entity.Return, error) {
+	return m.ListReturnsFunc(ctx, orderID)
+}
+
+func (m *MockOrderSvc) CompleteReturn(ctx context.Context, returnID string, amount int64) error {
+	return m.CompleteReturnFunc(ctx, returnID, amount)
+}
+
 func TestServer_CreateOrder(t *testing.T) {
 	validUUID := uuid.New().String()
 
@@ -310,3 +346,234 @@ func TestServer_ListOrders(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_CancelOrder(t *testing.T) {
+	validUUID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		req            *proto.CancelOrderRequest
+		mockSvc        func() *MockOrderSvc
+		expectedStatus string
+		expectedCode   codes.Code
+	}{
+		{
+			name: "Success",
+			req:  &proto.CancelOrderRequest{OrderId: validUUID, UserId: 1},
+			mockSvc: func() *MockOrderSvc {
+				return &MockOrderSvc{
+					CancelOrderFunc: func(ctx context.Context, orderID string, userID int64) (string, error) {
+						return entity.StatusCancelling, nil
+					},
+				}
+			},
+			expectedStatus: entity.StatusCancelling,
+			expectedCode:   codes.OK,
+		},
+		{
+			name:         "Invalid UUID",
+			req:          &proto.CancelOrderRequest{OrderId: "invalid-uuid", UserId: 1},
+			mockSvc:      func() *MockOrderSvc { return &MockOrderSvc{} },
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name:         "Invalid UserID",
+			req:          &proto.CancelOrderRequest{OrderId: validUUID},
+			mockSvc:      func() *MockOrderSvc { return &MockOrderSvc{} },
+			expectedCode: codes.InvalidArgument,
+		},
+		{
+			name: "Not Found",
+			req:  &proto.CancelOrderRequest{OrderId: validUUID, UserId: 1},
+			mockSvc: func() *MockOrderSvc {
+				return &MockOrderSvc{
+					CancelOrderFunc: func(ctx context.Context, orderID string, userID int64) (string, error) {
+						return "", apperrors.ErrOrderNotFound
+					},
+				}
+			},
+			expectedCode: codes.NotFound,
+		},
+		{
+			name: "Not Cancellable",
+			req:  &proto.CancelOrderRequest{OrderId: validUUID, UserId: 1},
+			mockSvc: func() *MockOrderSvc {
+				return &MockOrderSvc{
+					CancelOrderFunc: func(ctx context.Context, orderID string, userID int64) (string, error) {
+						return "", apperrors.ErrOrderNotCancellable
+					},
+				}
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name: "Internal Error",
+			req:  &proto.CancelOrderRequest{OrderId: validUUID, UserId: 1},
+			mockSvc: func() *MockOrderSvc {
+				return &MockOrderSvc{
+					CancelOrderFunc: func(ctx context.Context, orderID string, userID int64) (string, error) {
+						return "", errors.New("saga unavailable")
+					},
+				}
+			},
+			expectedCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewGRPCServer(tt.mockSvc(), logger.Log)
+
+			resp, err := server.CancelOrder(context.Background(), tt.req)
+
+			if status.Code(err) != tt.expectedCode {
+				t.Errorf("Expected code %v, got %v", tt.expectedCode, status.Code(err))
+			}
+			if tt.expectedCode == codes.OK && resp.Status != tt.expectedStatus {
+				t.Errorf("Expected status %s, got %s", tt.expectedStatus, resp.Status)
+			}
+		})
+	}
+}
+
+func TestServer_ConfirmCancellation(t *testing.T) {
+	validUUID := uuid.New().String()
+
+	tests := []struct {
+		name         string
+		req          *proto.ConfirmCancellationRequest
+		confirmErr   error
+		expectedCode codes.Code
+	}{
+		{name: "Success", req: &proto.ConfirmCancellationRequest{OrderId: validUUID}, expectedCode: codes.OK},
+		{name: "Invalid UUID", req: &proto.ConfirmCancellationRequest{OrderId: "invalid-uuid"}, expectedCode: codes.InvalidArgument},
+		{name: "Not Found", req: &proto.ConfirmCancellationRequest{OrderId: validUUID}, confirmErr: apperrors.ErrOrderNotFound, expectedCode: codes.NotFound},
+		{name: "Not Cancellable", req: &proto.ConfirmCancellationRequest{OrderId: validUUID}, confirmErr: apperrors.ErrOrderNotCancellable, expectedCode: codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewGRPCServer(&MockOrderSvc{
+				ConfirmCancellationFunc: func(ctx context.Context, orderID string) error { return tt.confirmErr },
+			}, logger.Log)
+
+			_, err := server.ConfirmCancellation(context.Background(), tt.req)
+
+			if status.Code(err) != tt.expectedCode {
+				t.Errorf("Expected code %v, got %v", tt.expectedCode, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestServer_RequestReturn(t *testing.T) {
+	validUUID := uuid.New().String()
+	items := []*proto.OrderItem{{ProductId: 1, Quantity: 2}}
+
+	tests := []struct {
+		name         string
+		req          *proto.RequestReturnRequest
+		svcErr       error
+		expectedCode codes.Code
+	}{
+		{name: "Success", req: &proto.RequestReturnRequest{OrderId: validUUID, UserId: 1, Items: items}, expectedCode: codes.OK},
+		{name: "Invalid UUID", req: &proto.RequestReturnRequest{OrderId: "invalid-uuid", UserId: 1, Items: items}, expectedCode: codes.InvalidArgument},
+		{name: "Invalid UserID", req: &proto.RequestReturnRequest{OrderId: validUUID, Items: items}, expectedCode: codes.InvalidArgument},
+		{name: "Order Not Found", req: &proto.RequestReturnRequest{OrderId: validUUID, UserId: 1, Items: items}, svcErr: apperrors.ErrOrderNotFound, expectedCode: codes.NotFound},
+		{name: "Too Many Items", req: &proto.RequestReturnRequest{OrderId: validUUID, UserId: 1, Items: items}, svcErr: apperrors.ErrInvalidReturn, expectedCode: codes.InvalidArgument},
+		{name: "Not Returnable", req: &proto.RequestReturnRequest{OrderId: validUUID, UserId: 1, Items: items}, svcErr: apperrors.ErrOrderNotReturnable, expectedCode: codes.FailedPrecondition},
+		{name: "Internal Error", req: &proto.RequestReturnRequest{OrderId: validUUID, UserId: 1, Items: items}, svcErr: errors.New("db error"), expectedCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewGRPCServer(&MockOrderSvc{
+				RequestReturnFunc: func(ctx context.Context, orderID string, userID int64, items []entity.OrderItem, reason string) (*entity.Return, error) {
+					if tt.svcErr != nil {
+						return nil, tt.svcErr
+					}
+					return &entity.Return{ReturnID: "return-1", OrderID: orderID, UserID: userID, Items: items, Status: entity.ReturnRequested}, nil
+				},
+			}, logger.Log)
+
+			resp, err := server.RequestReturn(context.Background(), tt.req)
+
+			if status.Code(err) != tt.expectedCode {
+				t.Errorf("Expected code %v, got %v", tt.expectedCode, status.Code(err))
+			}
+			if tt.expectedCode == codes.OK {
+				if resp.Return.Status != entity.ReturnRequested || len(resp.Return.Items) != 1 || resp.Return.Items[0].Quantity != 2 {
+					t.Errorf("Unexpected return %v", resp.Return)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_ApproveReturn(t *testing.T) {
+	validUUID := uuid.New().String()
+
+	tests := []struct {
+		name         string
+		req          *proto.ApproveReturnRequest
+		svcErr       error
+		expectedCode codes.Code
+	}{
+		{name: "Success", req: &proto.ApproveReturnRequest{ReturnId: validUUID}, expectedCode: codes.OK},
+		{name: "Invalid UUID", req: &proto.ApproveReturnRequest{ReturnId: "invalid-uuid"}, expectedCode: codes.InvalidArgument},
+		{name: "Not Found", req: &proto.ApproveReturnRequest{ReturnId: validUUID}, svcErr: apperrors.ErrReturnNotFound, expectedCode: codes.NotFound},
+		{name: "Rejected", req: &proto.ApproveReturnRequest{ReturnId: validUUID}, svcErr: apperrors.ErrInvalidReturnState, expectedCode: codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewGRPCServer(&MockOrderSvc{
+				ApproveReturnFunc: func(ctx context.Context, returnID string) (*entity.Return, error) {
+					if tt.svcErr != nil {
+						return nil, tt.svcErr
+					}
+					return &entity.Return{ReturnID: returnID, Status: entity.ReturnApproved, Amount: 100}, nil
+				},
+			}, logger.Log)
+
+			resp, err := server.ApproveReturn(context.Background(), tt.req)
+
+			if status.Code(err) != tt.expectedCode {
+				t.Errorf("Expected code %v, got %v", tt.expectedCode, status.Code(err))
+			}
+			if tt.expectedCode == codes.OK && resp.Return.Amount != 100 {
+				t.Errorf("Expected amount 100, got %d", resp.Return.Amount)
+			}
+		})
+	}
+}
+
+func TestServer_CompleteReturn(t *testing.T) {
+	validUUID := uuid.New().String()
+
+	tests := []struct {
+		name         string
+		req          *proto.CompleteReturnRequest
+		completeErr  error
+		expectedCode codes.Code
+	}{
+		{name: "Success", req: &proto.CompleteReturnRequest{ReturnId: validUUID, Amount: 100}, expectedCode: codes.OK},
+		{name: "Invalid UUID", req: &proto.CompleteReturnRequest{ReturnId: "invalid-uuid", Amount: 100}, expectedCode: codes.InvalidArgument},
+		{name: "Negative Amount", req: &proto.CompleteReturnRequest{ReturnId: validUUID, Amount: -1}, expectedCode: codes.InvalidArgument},
+		{name: "Not Approved", req: &proto.CompleteReturnRequest{ReturnId: validUUID, Amount: 100}, completeErr: apperrors.ErrInvalidReturnState, expectedCode: codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewGRPCServer(&MockOrderSvc{
+				CompleteReturnFunc: func(ctx context.Context, returnID string, amount int64) error { return tt.completeErr },
+			}, logger.Log)
+
+			_, err := server.CompleteReturn(context.Background(), tt.req)
+
+			if status.Code(err) != tt.expectedCode {
+				t.Errorf("Expected code %v, got %v", tt.expectedCode, status.Code(err))
+			}
+		})
+	}
+}
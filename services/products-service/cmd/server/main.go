@@ -15,6 +15,7 @@ import (
 	"github.com/vsespontanno/eCommerce/services/products-service/internal/domain/products/entity"
 	client "github.com/vsespontanno/eCommerce/services/products-service/internal/infrastructure/client/grpc"
 	"github.com/vsespontanno/eCommerce/services/products-service/internal/infrastructure/db"
+	"github.com/vsespontanno/eCommerce/services/products-service/internal/infrastructure/jobs"
 	postgres "github.com/vsespontanno/eCommerce/services/products-service/internal/infrastructure/repository"
 	"github.com/vsespontanno/eCommerce/services/products-service/internal/presentation/http/handler"
 )
@@ -43,7 +44,7 @@ func main() {
 
 	store := postgres.NewProductStore(dataBase)
 	cartStore := postgres.NewCartStore(dataBase)
-	sagaStore := postgres.NewSagaStore(dataBase, logger.Log)
+	sagaStore := postgres.NewSagaStore(dataBase, logger.Log, cfg.ReservationTTL)
 	sagaService := saga.NewSagaService(sagaStore, logger.Log)
 	// Initialize application
 	app := app.New(logger.Log, cfg.HTTPPort, cfg.GRPCProductsServerPort, cfg.GRPCSagaServerPort, store, sagaService)
@@ -54,6 +55,12 @@ func main() {
 	handler := handler.New(cartStore, store, logger.Log, jwtClient)
 	handler.RegisterRoutes(app.HTTPApp.Router())
 
+	// Фоновое снятие просроченных резервов
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	sweeper := jobs.NewReservationSweeper(sagaStore, logger.Log, cfg.ReservationSweep, cfg.ReservationSweepBatch)
+	go sweeper.Start(jobsCtx)
+
 	// Start server in a goroutine
 	go func() {
 		if err := app.HTTPApp.Run(); err != nil {
@@ -73,6 +80,7 @@ func main() {
 
 	<-stop
 	logger.Log.Info("Shutting down server...")
+	stopJobs()
 
 	// Shutdown HTTP server with timeout
 	httpCtx, httpCancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -108,6 +116,8 @@ func seedSomeValues(store *postgres.ProductStore) {
 		Name:         "Red Bull",
 		Description:  "Good energy drink for gym",
 		Price:        141.0,
+		Category:     "drinks",
+		Brand:        "Red Bull",
 		ID:           1,
 		CountInStock: 100,
 	}
@@ -116,6 +126,8 @@ func seedSomeValues(store *postgres.ProductStore) {
 		Name:         "Chapman Red",
 		Description:  "Very tasty cigarettes for your depression",
 		Price:        253.0,
+		Category:     "tobacco",
+		Brand:        "Chapman",
 		ID:           2,
 		CountInStock: 100,
 	}
@@ -10,10 +10,12 @@ import (
 	"go.uber.org/zap"
 )
 
+// ProductStorage - резервы товаров, привязанные к заказу; все операции идемпотентны по orderID
 type ProductStorage interface {
-	ReserveTxn(ctx context.Context, products []*dto.ItemRequest) error
-	ReleaseTxn(ctx context.Context, products []*dto.ItemRequest) error
-	CommitTxn(ctx context.Context, products []*dto.ItemRequest) error
+	ReserveTxn(ctx context.Context, orderID string, products []*dto.ItemRequest) error
+	ReleaseTxn(ctx context.Context, orderID string, products []*dto.ItemRequest) error
+	CommitTxn(ctx context.Context, orderID string) error
+	RestockTxn(ctx context.Context, orderID, restockID string, products []*dto.ItemRequest) error
 }
 
 type Service struct {
@@ -25,24 +27,33 @@ func NewSagaService(storage ProductStorage, logger *zap.SugaredLogger) *Service
 	return &Service{storage: storage, logger: logger}
 }
 
-func (s *Service) Reserve(ctx context.Context, products []*dto.ItemRequest) error {
-	s.logger.Infow("Reserving products in saga", "products ", products)
+func (s *Service) Reserve(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
+	s.logger.Infow("Reserving products in saga", "orderID", orderID, "products ", products)
 	return s.execWithRetry("reserve", func() error {
-		return s.storage.ReserveTxn(ctx, products)
+		return s.storage.ReserveTxn(ctx, orderID, products)
 	})
 }
 
-func (s *Service) Release(ctx context.Context, products []*dto.ItemRequest) error {
-	s.logger.Infow("Releasing products in saga", "products ", products)
+func (s *Service) Release(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
+	s.logger.Infow("Releasing products in saga", "orderID", orderID, "products ", products)
 	return s.execWithRetry("release", func() error {
-		return s.storage.ReleaseTxn(ctx, products)
+		return s.storage.ReleaseTxn(ctx, orderID, products)
 	})
 }
 
-func (s *Service) Commit(ctx context.Context, products []*dto.ItemRequest) error {
-	s.logger.Infow("Committing products in saga", "products ", products)
+// Commit списывает ровно то, что было зарезервировано под заказ; products нужны только для лога
+func (s *Service) Commit(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
+	s.logger.Infow("Committing products in saga", "orderID", orderID, "products ", products)
 	return s.execWithRetry("commit", func() error {
-		return s.storage.CommitTxn(ctx, products)
+		return s.storage.CommitTxn(ctx, orderID)
+	})
+}
+
+// Restock возвращает на склад списанный по заказу товар; повтор с тем же restockID ничего не меняет
+func (s *Service) Restock(ctx context.Context, orderID, restockID string, products []*dto.ItemRequest) error {
+	s.logger.Infow("Restocking products in saga", "orderID", orderID, "restockID", restockID, "products ", products)
+	return s.execWithRetry("restock", func() error {
+		return s.storage.RestockTxn(ctx, orderID, restockID, products)
 	})
 }
 
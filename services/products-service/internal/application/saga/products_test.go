@@ -15,19 +15,24 @@ func init() {
 
 // MockProductStorage is a mock implementation of ProductStorage
 type MockProductStorage struct {
-	ReserveTxnFunc func(ctx context.Context, products []*dto.ItemRequest) error
-	ReleaseTxnFunc func(ctx context.Context, products []*dto.ItemRequest) error
-	CommitTxnFunc  func(ctx context.Context, products []*dto.ItemRequest) error
+	ReserveTxnFunc func(ctx context.Context, orderID string, products []*dto.ItemRequest) error
+	ReleaseTxnFunc func(ctx context.Context, orderID string, products []*dto.ItemRequest) error
+	CommitTxnFunc  func(ctx context.Context, orderID string) error
+	RestockTxnFunc func(ctx context.Context, orderID, restockID string, products []*dto.ItemRequest) error
 }
 
-func (m *MockProductStorage) ReserveTxn(ctx context.Context, products []*dto.ItemRequest) error {
-	return m.ReserveTxnFunc(ctx, products)
+func (m *MockProductStorage) ReserveTxn(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
+	return m.ReserveTxnFunc(ctx, orderID, products)
 }
-func (m *MockProductStorage) ReleaseTxn(ctx context.Context, products []*dto.ItemRequest) error {
-	return m.ReleaseTxnFunc(ctx, products)
+func (m *MockProductStorage) ReleaseTxn(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
+	return m.ReleaseTxnFunc(ctx, orderID, products)
 }
-func (m *MockProductStorage) CommitTxn(ctx context.Context, products []*dto.ItemRequest) error {
-	return m.CommitTxnFunc(ctx, products)
+func (m *MockProductStorage) CommitTxn(ctx context.Context, orderID string) error {
+	return m.CommitTxnFunc(ctx, orderID)
+}
+
+func (m *MockProductStorage) RestockTxn(ctx context.Context, orderID, restockID string, products []*dto.ItemRequest) error {
+	return m.RestockTxnFunc(ctx, orderID, restockID, products)
 }
 
 func TestService_Reserve(t *testing.T) {
@@ -40,7 +45,7 @@ func TestService_Reserve(t *testing.T) {
 			name: "Success",
 			mockStorage: func() *MockProductStorage {
 				return &MockProductStorage{
-					ReserveTxnFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					ReserveTxnFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return nil
 					},
 				}
@@ -52,7 +57,7 @@ func TestService_Reserve(t *testing.T) {
 			mockStorage: func() *MockProductStorage {
 				attempts := 0
 				return &MockProductStorage{
-					ReserveTxnFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					ReserveTxnFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						attempts++
 						if attempts < 3 {
 							return errors.New("deadlock detected")
@@ -67,7 +72,7 @@ func TestService_Reserve(t *testing.T) {
 			name: "Transient Error - Max Attempts Reached",
 			mockStorage: func() *MockProductStorage {
 				return &MockProductStorage{
-					ReserveTxnFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					ReserveTxnFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return errors.New("deadlock detected")
 					},
 				}
@@ -80,7 +85,7 @@ func TestService_Reserve(t *testing.T) {
 			name: "Non-Transient Error",
 			mockStorage: func() *MockProductStorage {
 				return &MockProductStorage{
-					ReserveTxnFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					ReserveTxnFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return errors.New("not enough stock")
 					},
 				}
@@ -93,7 +98,7 @@ func TestService_Reserve(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			service := NewSagaService(tt.mockStorage(), logger.Log)
 
-			err := service.Reserve(context.Background(), nil)
+			err := service.Reserve(context.Background(), "order-123", nil)
 
 			if tt.expectedErr != nil {
 				if err == nil || err.Error() != tt.expectedErr.Error() {
@@ -118,7 +123,7 @@ func TestService_Release(t *testing.T) {
 			name: "Success",
 			mockStorage: func() *MockProductStorage {
 				return &MockProductStorage{
-					ReleaseTxnFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					ReleaseTxnFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return nil
 					},
 				}
@@ -129,7 +134,7 @@ func TestService_Release(t *testing.T) {
 			name: "Error",
 			mockStorage: func() *MockProductStorage {
 				return &MockProductStorage{
-					ReleaseTxnFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					ReleaseTxnFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return errors.New("db error")
 					},
 				}
@@ -142,7 +147,7 @@ func TestService_Release(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			service := NewSagaService(tt.mockStorage(), logger.Log)
 
-			err := service.Release(context.Background(), nil)
+			err := service.Release(context.Background(), "order-123", nil)
 
 			if tt.expectedErr != nil {
 				if err == nil || err.Error() != tt.expectedErr.Error() {
@@ -167,7 +172,7 @@ func TestService_Commit(t *testing.T) {
 			name: "Success",
 			mockStorage: func() *MockProductStorage {
 				return &MockProductStorage{
-					CommitTxnFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					CommitTxnFunc: func(ctx context.Context, orderID string) error {
 						return nil
 					},
 				}
@@ -178,7 +183,7 @@ func TestService_Commit(t *testing.T) {
 			name: "Error",
 			mockStorage: func() *MockProductStorage {
 				return &MockProductStorage{
-					CommitTxnFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					CommitTxnFunc: func(ctx context.Context, orderID string) error {
 						return errors.New("db error")
 					},
 				}
@@ -191,7 +196,59 @@ func TestService_Commit(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			service := NewSagaService(tt.mockStorage(), logger.Log)
 
-			err := service.Commit(context.Background(), nil)
+			err := service.Commit(context.Background(), "order-123", nil)
+
+			if tt.expectedErr != nil {
+				if err == nil || err.Error() != tt.expectedErr.Error() {
+					t.Errorf("Expected error %v, got %v", tt.expectedErr, err)
+				}
+			} else {
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestService_Restock(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockStorage func() *MockProductStorage
+		expectedErr error
+	}{
+		{
+			name: "Success",
+			mockStorage: func() *MockProductStorage {
+				return &MockProductStorage{
+					RestockTxnFunc: func(ctx context.Context, orderID, restockID string, products []*dto.ItemRequest) error {
+						if orderID != "order-123" || restockID != "return-1" {
+							return errors.New("unexpected ids")
+						}
+						return nil
+					},
+				}
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "Error",
+			mockStorage: func() *MockProductStorage {
+				return &MockProductStorage{
+					RestockTxnFunc: func(ctx context.Context, orderID, restockID string, products []*dto.ItemRequest) error {
+						return errors.New("db error")
+					},
+				}
+			},
+			expectedErr: errors.New("restock failed: db error"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewSagaService(tt.mockStorage(), logger.Log)
+
+			err := service.Restock(context.Background(), "order-123", "return-1", []*dto.ItemRequest{{ProductID: 1, Qty: 1}})
 
 			if tt.expectedErr != nil {
 				if err == nil || err.Error() != tt.expectedErr.Error() {
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -18,6 +19,9 @@ type Config struct {
 	GRPCProductsServerPort int
 	GRPCSagaServerPort     int
 	GRPCJwtPort            string
+	ReservationTTL         time.Duration
+	ReservationSweep       time.Duration
+	ReservationSweepBatch  int
 }
 
 func MustLoad() (*Config, error) {
@@ -36,6 +40,18 @@ func MustLoad() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	reservationTTL, err := getEnvAsInt("RESERVATION_TTL_SECONDS", 900)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	reservationSweep, err := getEnvAsInt("RESERVATION_SWEEP_INTERVAL_SECONDS", 60)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	reservationSweepBatch, err := getEnvAsInt("RESERVATION_SWEEP_BATCH", 100)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 	return &Config{
 		PGUser:                 os.Getenv("PG_USER"),
 		PGPassword:             os.Getenv("PG_PASSWORD"),
@@ -46,5 +62,24 @@ func MustLoad() (*Config, error) {
 		GRPCProductsServerPort: GRPCProductsServerPort,
 		GRPCSagaServerPort:     GRPCSagaServerPort,
 		GRPCJwtPort:            os.Getenv("GRPC_JWT_CLIENT_PORT"),
+		ReservationTTL:         time.Duration(reservationTTL) * time.Second,
+		ReservationSweep:       time.Duration(reservationSweep) * time.Second,
+		ReservationSweepBatch:  reservationSweepBatch,
 	}, nil
 }
+
+// getEnvAsInt - необязательная положительная числовая настройка со значением по умолчанию
+func getEnvAsInt(name string, defaultVal int) (int, error) {
+	val := os.Getenv(name)
+	if val == "" {
+		return defaultVal, nil
+	}
+	intVal, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	if intVal <= 0 {
+		return 0, fmt.Errorf("invalid %s: must be positive, got %d", name, intVal)
+	}
+	return intVal, nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 )
 
 func TestMustLoad(t *testing.T) {
@@ -30,6 +31,9 @@ func TestMustLoad(t *testing.T) {
 		"PG_HOST",
 		"PG_PORT",
 		"GRPC_JWT_CLIENT_PORT",
+		"RESERVATION_TTL_SECONDS",
+		"RESERVATION_SWEEP_INTERVAL_SECONDS",
+		"RESERVATION_SWEEP_BATCH",
 	}
 
 	t.Run("Success", func(t *testing.T) {
@@ -63,6 +67,58 @@ func TestMustLoad(t *testing.T) {
 		if cfg.PGUser != "user" {
 			t.Errorf("Expected PGUser user, got %s", cfg.PGUser)
 		}
+		if cfg.ReservationTTL != 15*time.Minute {
+			t.Errorf("Expected default ReservationTTL 15m, got %v", cfg.ReservationTTL)
+		}
+		if cfg.ReservationSweep != time.Minute {
+			t.Errorf("Expected default ReservationSweep 1m, got %v", cfg.ReservationSweep)
+		}
+		if cfg.ReservationSweepBatch != 100 {
+			t.Errorf("Expected default ReservationSweepBatch 100, got %d", cfg.ReservationSweepBatch)
+		}
+	})
+
+	t.Run("Reservation Settings", func(t *testing.T) {
+		unsetEnv(envVars)
+		setEnv(map[string]string{
+			"HTTP_PORT":                          "8080",
+			"GRPC_PRODUCTS_SERVER_PORT":          "50051",
+			"GRPC_SAGA_SERVER_PORT":              "50052",
+			"RESERVATION_TTL_SECONDS":            "120",
+			"RESERVATION_SWEEP_INTERVAL_SECONDS": "10",
+			"RESERVATION_SWEEP_BATCH":            "20",
+		})
+		defer unsetEnv(envVars)
+
+		cfg, err := MustLoad()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if cfg.ReservationTTL != 2*time.Minute {
+			t.Errorf("Expected ReservationTTL 2m, got %v", cfg.ReservationTTL)
+		}
+		if cfg.ReservationSweep != 10*time.Second {
+			t.Errorf("Expected ReservationSweep 10s, got %v", cfg.ReservationSweep)
+		}
+		if cfg.ReservationSweepBatch != 20 {
+			t.Errorf("Expected ReservationSweepBatch 20, got %d", cfg.ReservationSweepBatch)
+		}
+	})
+
+	t.Run("Invalid RESERVATION_TTL_SECONDS", func(t *testing.T) {
+		unsetEnv(envVars)
+		setEnv(map[string]string{
+			"HTTP_PORT":                 "8080",
+			"GRPC_PRODUCTS_SERVER_PORT": "50051",
+			"GRPC_SAGA_SERVER_PORT":     "50052",
+			"RESERVATION_TTL_SECONDS":   "0",
+		})
+		defer unsetEnv(envVars)
+
+		_, err := MustLoad()
+		if err == nil {
+			t.Error("Expected error for non-positive RESERVATION_TTL_SECONDS")
+		}
 	})
 
 	t.Run("Missing HTTP_PORT", func(t *testing.T) {
@@ -8,4 +8,16 @@ var (
 	ErrNotEnoughStock = errors.New("not enough stock")
 	// ErrTransient - transient ошибка, можно ретраить
 	ErrTransient = errors.New("transient db error")
+	// ErrReservationNotFound - у заказа нет резерва
+	ErrReservationNotFound = errors.New("reservation not found")
+	// ErrInvalidReservationState - операция невозможна в текущем состоянии резерва
+	ErrInvalidReservationState = errors.New("operation not allowed in current reservation state")
+	// ErrReservationMismatch - повторный резерв заказа с другим набором товаров
+	ErrReservationMismatch = errors.New("order already reserved with different items")
+	// ErrRestockExceedsOrder - на склад возвращают больше, чем было списано по заказу
+	ErrRestockExceedsOrder = errors.New("restock exceeds committed quantity")
+	// ErrRestockMismatch - повтор возврата на склад с другим набором товаров
+	ErrRestockMismatch = errors.New("restock already applied with different items")
+	// ErrInvalidCursor - курсор страницы повреждён или выдан для другой сортировки
+	ErrInvalidCursor = errors.New("invalid page cursor")
 )
@@ -0,0 +1,49 @@
+package entity
+
+// ProductSort - порядок выдачи каталога
+type ProductSort string
+
+const (
+	// SortID - по id товара; порядок по умолчанию без поиска
+	SortID ProductSort = "id"
+	// SortRelevance - по релевантности поисковому запросу; порядок по умолчанию при поиске
+	SortRelevance ProductSort = "relevance"
+	SortPriceAsc  ProductSort = "price_asc"
+	SortPriceDesc ProductSort = "price_desc"
+	SortNewest    ProductSort = "newest"
+)
+
+// Valid возвращает true для известных порядков сортировки
+func (s ProductSort) Valid() bool {
+	switch s {
+	case SortID, SortRelevance, SortPriceAsc, SortPriceDesc, SortNewest:
+		return true
+	}
+	return false
+}
+
+// ProductFilter - параметры выборки каталога. Пустые поля не ограничивают выборку.
+type ProductFilter struct {
+	// Search - полнотекстовый запрос по названию и описанию
+	Search   string
+	Category string
+	Brand    string
+	// MinPrice и MaxPrice - границы цены включительно; 0 - граница не задана
+	MinPrice int64
+	MaxPrice int64
+	// InStock - только товары, которые можно заказать прямо сейчас
+	InStock bool
+	Sort    ProductSort
+	// Cursor - NextCursor предыдущей страницы; пустой - первая страница
+	Cursor string
+	Limit  int
+}
+
+// ProductPage - страница каталога
+type ProductPage struct {
+	Products []*Product `json:"products"`
+	// Total - сколько всего товаров подходит под фильтр, без учёта страниц
+	Total int64 `json:"total"`
+	// NextCursor - курсор следующей страницы; пустой на последней
+	NextCursor string `json:"next_cursor,omitempty"`
+}
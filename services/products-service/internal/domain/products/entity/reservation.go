@@ -0,0 +1,45 @@
+package entity
+
+import "time"
+
+// ReservationStatus - состояние резерва товара под заказ
+type ReservationStatus string
+
+const (
+	ReservationReserved  ReservationStatus = "RESERVED"
+	ReservationCommitted ReservationStatus = "COMMITTED"
+	ReservationReleased  ReservationStatus = "RELEASED"
+	// ReservationExpired - резерв пережил TTL и был снят фоновым sweeper'ом
+	ReservationExpired ReservationStatus = "EXPIRED"
+)
+
+// EventTypeReservationExpired - тип события в outbox для каждого истёкшего резерва
+const EventTypeReservationExpired = "ProductReservationExpired"
+
+// Outbox общий с оркестратором саг, поэтому событие об истёкшем резерве пишется в том же конверте
+// CloudEvents и под своим aggregate_type - publisher отправляет такие события в топик резервов,
+// а не в топик заказов.
+const (
+	EventSource                = "products-service"
+	EventSchemaVersion         = 1
+	OutboxAggregateReservation = "ProductReservation"
+)
+
+// Reservation - резерв одного товара под конкретный заказ
+type Reservation struct {
+	OrderID   string
+	ProductID int64
+	Qty       int
+	Status    ReservationStatus
+	ExpiresAt time.Time
+}
+
+// ReservationExpiredEvent - payload события EventTypeReservationExpired
+type ReservationExpiredEvent struct {
+	OrderID   string    `json:"order_id"`
+	ProductID int64     `json:"product_id"`
+	Qty       int       `json:"qty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	ExpiredAt time.Time `json:"expired_at"`
+	EventType string    `json:"event_type"`
+}
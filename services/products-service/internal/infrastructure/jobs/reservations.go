@@ -0,0 +1,85 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/vsespontanno/eCommerce/services/products-service/internal/domain/products/entity"
+	"github.com/vsespontanno/eCommerce/services/products-service/internal/infrastructure/metrics"
+	"go.uber.org/zap"
+)
+
+// ReservationExpirer снимает просроченные резервы и возвращает снятые
+type ReservationExpirer interface {
+	ExpireReservations(ctx context.Context, limit int) ([]entity.Reservation, error)
+}
+
+// ReservationSweeper периодически освобождает резервы, которые пережили TTL:
+// если оркестратор упал или компенсация не прошла, products.reserved сам вернётся в норму
+type ReservationSweeper struct {
+	store     ReservationExpirer
+	logger    *zap.SugaredLogger
+	interval  time.Duration
+	batchSize int
+}
+
+func NewReservationSweeper(store ReservationExpirer, logger *zap.SugaredLogger, interval time.Duration, batchSize int) *ReservationSweeper {
+	return &ReservationSweeper{
+		store:     store,
+		logger:    logger,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+func (j *ReservationSweeper) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.logger.Infof("ReservationSweeper started (interval: %v)", j.interval)
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := j.Sweep(ctx); err != nil {
+				j.logger.Errorw("reservation sweep failed", "error", err)
+			}
+		case <-ctx.Done():
+			j.logger.Info("ReservationSweeper stopped")
+			return
+		}
+	}
+}
+
+// Sweep снимает просроченные резервы пачками, пока они не закончатся, и возвращает их количество
+func (j *ReservationSweeper) Sweep(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		expired, err := j.store.ExpireReservations(ctx, j.batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		for _, r := range expired {
+			metrics.ReservationsExpiredTotal.Inc()
+			metrics.ReservedUnitsExpiredTotal.Add(float64(r.Qty))
+			j.logger.Warnw("reservation expired, stock released",
+				"orderID", r.OrderID,
+				"productID", r.ProductID,
+				"qty", r.Qty,
+				"expiresAt", r.ExpiresAt,
+			)
+		}
+		total += len(expired)
+
+		// Неполная пачка - больше просроченных нет (или они заняты сагой до следующего прохода)
+		if len(expired) < j.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		j.logger.Infow("ReservationSweeper completed", "expired", total)
+	}
+	return total, nil
+}
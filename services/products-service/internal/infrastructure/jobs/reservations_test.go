@@ -0,0 +1,117 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vsespontanno/eCommerce/pkg/logger"
+	"github.com/vsespontanno/eCommerce/services/products-service/internal/domain/products/entity"
+	"github.com/vsespontanno/eCommerce/services/products-service/internal/infrastructure/metrics"
+)
+
+func init() {
+	logger.InitLogger()
+}
+
+// MockReservationExpirer is a mock implementation of ReservationExpirer
+type MockReservationExpirer struct {
+	ExpireReservationsFunc func(ctx context.Context, limit int) ([]entity.Reservation, error)
+}
+
+func (m *MockReservationExpirer) ExpireReservations(ctx context.Context, limit int) ([]entity.Reservation, error) {
+	return m.ExpireReservationsFunc(ctx, limit)
+}
+
+func reservations(n int) []entity.Reservation {
+	res := make([]entity.Reservation, n)
+	for i := range res {
+		res[i] = entity.Reservation{OrderID: "order-1", ProductID: int64(i + 1), Qty: 2, Status: entity.ReservationExpired}
+	}
+	return res
+}
+
+func TestReservationSweeper_Sweep(t *testing.T) {
+	tests := []struct {
+		name          string
+		batches       [][]entity.Reservation
+		failOn        int
+		expectedCount int
+		expectedCalls int
+		expectErr     bool
+	}{
+		{
+			name:          "Nothing Expired",
+			batches:       [][]entity.Reservation{nil},
+			failOn:        -1,
+			expectedCount: 0,
+			expectedCalls: 1,
+		},
+		{
+			name:          "Partial Batch Stops",
+			batches:       [][]entity.Reservation{reservations(2)},
+			failOn:        -1,
+			expectedCount: 2,
+			expectedCalls: 1,
+		},
+		{
+			name:          "Full Batches Continue",
+			batches:       [][]entity.Reservation{reservations(3), reservations(3), reservations(1)},
+			failOn:        -1,
+			expectedCount: 7,
+			expectedCalls: 3,
+		},
+		{
+			name:          "Store Error",
+			batches:       [][]entity.Reservation{reservations(3), nil},
+			failOn:        1,
+			expectedCount: 3,
+			expectedCalls: 2,
+			expectErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			store := &MockReservationExpirer{
+				ExpireReservationsFunc: func(ctx context.Context, limit int) ([]entity.Reservation, error) {
+					if limit != 3 {
+						t.Errorf("Expected limit 3, got %d", limit)
+					}
+					defer func() { calls++ }()
+					if calls == tt.failOn {
+						return nil, errors.New("db error")
+					}
+					return tt.batches[calls], nil
+				},
+			}
+			expiredBefore := testutil.ToFloat64(metrics.ReservationsExpiredTotal)
+			unitsBefore := testutil.ToFloat64(metrics.ReservedUnitsExpiredTotal)
+
+			sweeper := NewReservationSweeper(store, logger.Log, time.Minute, 3)
+			count, err := sweeper.Sweep(context.Background())
+
+			if tt.expectErr && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if count != tt.expectedCount {
+				t.Errorf("Expected %d expired, got %d", tt.expectedCount, count)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("Expected %d store calls, got %d", tt.expectedCalls, calls)
+			}
+			if got := testutil.ToFloat64(metrics.ReservationsExpiredTotal) - expiredBefore; got != float64(tt.expectedCount) {
+				t.Errorf("Expected expired metric +%d, got +%v", tt.expectedCount, got)
+			}
+			if got := testutil.ToFloat64(metrics.ReservedUnitsExpiredTotal) - unitsBefore; got != float64(2*tt.expectedCount) {
+				t.Errorf("Expected units metric +%d, got +%v", 2*tt.expectedCount, got)
+			}
+		})
+	}
+}
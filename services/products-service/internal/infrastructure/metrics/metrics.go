@@ -38,3 +38,19 @@ var (
 		[]string{"product_id"},
 	)
 )
+
+var (
+	ReservationsExpiredTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "products_reservations_expired_total",
+			Help: "Total number of product reservations released after their TTL expired",
+		},
+	)
+
+	ReservedUnitsExpiredTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "products_reserved_units_expired_total",
+			Help: "Total number of reserved product units returned to stock by the expiry sweeper",
+		},
+	)
+)
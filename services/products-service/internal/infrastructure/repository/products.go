@@ -3,6 +3,8 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -26,8 +28,8 @@ func NewProductStore(db *sqlx.DB) *ProductStore {
 
 func (s *ProductStore) SaveProduct(ctx context.Context, product *entity.Product) error {
 	query := s.builder.Insert("products").
-		Columns("productID", "productName", "productDescription", "productPrice", "productQuantity", "created_at").
-		Values(product.ID, product.Name, product.Description, product.Price, product.CountInStock, time.Now().Format(time.RFC1123Z))
+		Columns("productID", "productName", "productDescription", "productPrice", "category", "brand", "productQuantity", "created_at").
+		Values(product.ID, product.Name, product.Description, product.Price, product.Category, product.Brand, product.CountInStock, time.Now().Format(time.RFC1123Z))
 
 	sqlStr, args, err := query.ToSql()
 	if err != nil {
@@ -38,41 +40,188 @@ func (s *ProductStore) SaveProduct(ctx context.Context, product *entity.Product)
 	return err
 }
 
-func (s *ProductStore) GetProducts(ctx context.Context) ([]*entity.Product, error) {
-	query := s.builder.Select("productID", "productName", "productDescription", "productPrice", "created_at").
+// searchConfig - конфигурация полнотекстового поиска, та же, что у колонки search_vector
+const searchConfig = "simple"
+
+// rankExpr - релевантность товара запросу; float8, чтобы значение в курсоре совпадало с вычисленным в базе
+const rankExpr = "ts_rank(search_vector, websearch_to_tsquery('" + searchConfig + "', ?))::float8"
+
+// GetProducts возвращает страницу каталога по фильтру. Страницы листаются курсором (keyset):
+// следующая начинается строго после последнего товара предыдущей в выбранной сортировке,
+// поэтому глубокие страницы не дороже первой, а товары не задваиваются при вставках между запросами.
+func (s *ProductStore) GetProducts(ctx context.Context, filter entity.ProductFilter) (*entity.ProductPage, error) {
+	sort := resolveSort(filter)
+	where := productConditions(filter)
+	var cursor *pageCursor
+	if filter.Cursor != "" {
+		c, err := decodeCursor(filter.Cursor, sort)
+		if err != nil {
+			return nil, err
+		}
+		cursor = &c
+	}
+
+	var total int64
+	countSQL, countArgs, err := s.builder.Select("COUNT(*)").From("products").Where(where).ToSql()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.GetContext(ctx, &total, countSQL, countArgs...); err != nil {
+		return nil, err
+	}
+
+	limit := max(filter.Limit, 1)
+	query := s.builder.Select(
+		"productID", "productName", "productDescription", "productPrice",
+		"category", "brand", "productQuantity - reserved", "created_at",
+	).
 		From("products").
-		RunWith(s.db)
+		Where(where).
+		OrderBy(sortOrder[sort]).
+		// Лишний товар показывает, есть ли следующая страница
+		Limit(uint64(limit + 1))
+	if sort == entity.SortRelevance {
+		query = query.Column(sq.Expr(rankExpr+" AS rank", filter.Search))
+	}
+	if cursor != nil {
+		query = query.Where(cursor.after(filter.Search))
+	}
 
-	rows, err := query.QueryContext(ctx)
+	rows, err := query.RunWith(s.db).QueryContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var products []*entity.Product
+	page := &entity.ProductPage{Products: make([]*entity.Product, 0, limit), Total: total}
+	var last pageCursor
 	for rows.Next() {
-		var p entity.Product
-		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.CreatedAt); err != nil {
+		var (
+			p         entity.Product
+			createdAt time.Time
+			rank      float64
+		)
+		dest := []any{&p.ID, &p.Name, &p.Description, &p.Price, &p.Category, &p.Brand, &p.CountInStock, &createdAt}
+		if sort == entity.SortRelevance {
+			dest = append(dest, &rank)
+		}
+		if err := rows.Scan(dest...); err != nil {
 			return nil, err
 		}
-		products = append(products, &p)
+		if len(page.Products) == limit {
+			page.NextCursor = last.encode()
+			break
+		}
+		p.CreatedAt = createdAt.Format(time.RFC3339Nano)
+		page.Products = append(page.Products, &p)
+		last = pageCursor{Sort: sort, ID: p.ID, Price: p.Price, CreatedAt: createdAt, Rank: rank}
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return products, nil
+	return page, nil
+}
+
+// resolveSort - сортировка по умолчанию: по релевантности при поиске, иначе по id.
+// Без поискового запроса релевантность у всех товаров одинаковая, и сортировка по ней - это сортировка по id.
+func resolveSort(filter entity.ProductFilter) entity.ProductSort {
+	switch {
+	case filter.Sort == "" && filter.Search != "":
+		return entity.SortRelevance
+	case filter.Sort == "", filter.Sort == entity.SortRelevance && filter.Search == "":
+		return entity.SortID
+	default:
+		return filter.Sort
+	}
+}
+
+// sortOrder - ORDER BY для каждой сортировки; productID в конце делает порядок полным, без него курсор неоднозначен
+var sortOrder = map[entity.ProductSort]string{
+	entity.SortID:        "productID",
+	entity.SortRelevance: "rank DESC, productID DESC",
+	entity.SortPriceAsc:  "productPrice, productID",
+	entity.SortPriceDesc: "productPrice DESC, productID DESC",
+	entity.SortNewest:    "created_at DESC, productID DESC",
+}
+
+// productConditions - условия фильтра без курсора; по ним же считается Total
+func productConditions(filter entity.ProductFilter) sq.And {
+	where := sq.And{}
+	if filter.Search != "" {
+		where = append(where, sq.Expr("search_vector @@ websearch_to_tsquery('"+searchConfig+"', ?)", filter.Search))
+	}
+	if filter.Category != "" {
+		where = append(where, sq.Eq{"category": filter.Category})
+	}
+	if filter.Brand != "" {
+		where = append(where, sq.Eq{"brand": filter.Brand})
+	}
+	if filter.MinPrice > 0 {
+		where = append(where, sq.GtOrEq{"productPrice": filter.MinPrice})
+	}
+	if filter.MaxPrice > 0 {
+		where = append(where, sq.LtOrEq{"productPrice": filter.MaxPrice})
+	}
+	if filter.InStock {
+		where = append(where, sq.Expr("productQuantity - reserved > 0"))
+	}
+	return where
+}
+
+// pageCursor - значения сортировки последнего товара страницы
+type pageCursor struct {
+	Sort      entity.ProductSort `json:"s"`
+	ID        int64              `json:"id"`
+	Price     int64              `json:"p,omitzero"`
+	CreatedAt time.Time          `json:"c,omitzero"`
+	Rank      float64            `json:"r,omitzero"`
+}
+
+func (c pageCursor) encode() string {
+	//nolint:errcheck // структура из простых полей всегда сериализуется
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeCursor разбирает курсор и проверяет, что он выдан для той же сортировки
+func decodeCursor(token string, sort entity.ProductSort) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return pageCursor{}, apperrors.ErrInvalidCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.Sort != sort {
+		return pageCursor{}, apperrors.ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// after - условие "строго после курсора" в порядке sortOrder
+func (c pageCursor) after(search string) sq.Sqlizer {
+	switch c.Sort {
+	case entity.SortRelevance:
+		return sq.Expr("("+rankExpr+", productID) < (?, ?)", search, c.Rank, c.ID)
+	case entity.SortPriceAsc:
+		return sq.Expr("(productPrice, productID) > (?, ?)", c.Price, c.ID)
+	case entity.SortPriceDesc:
+		return sq.Expr("(productPrice, productID) < (?, ?)", c.Price, c.ID)
+	case entity.SortNewest:
+		return sq.Expr("(created_at, productID) < (?, ?)", c.CreatedAt, c.ID)
+	default:
+		return sq.Gt{"productID": c.ID}
+	}
 }
 
 func (s *ProductStore) GetProductByID(ctx context.Context, id int64) (*entity.Product, error) {
-	query := s.builder.Select("productID", "productName", "productDescription", "productPrice", "created_at").
+	query := s.builder.Select("productID", "productName", "productDescription", "productPrice", "category", "brand", "created_at").
 		From("products").
 		Where(sq.Eq{"productID": id}).
 		RunWith(s.db)
 
 	var p entity.Product
-	err := query.QueryRowContext(ctx).Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.CreatedAt)
+	err := query.QueryRowContext(ctx).Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.Category, &p.Brand, &p.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, apperrors.ErrNoProductFound // No product found
@@ -88,7 +237,7 @@ func (s *ProductStore) GetProductsByID(ctx context.Context, ids []int64) ([]*ent
 		return []*entity.Product{}, nil
 	}
 
-	query := s.builder.Select("productID", "productName", "productDescription", "productPrice", "created_at").
+	query := s.builder.Select("productID", "productName", "productDescription", "productPrice", "category", "brand", "created_at").
 		From("products").
 		Where(sq.Eq{"productID": ids}).
 		RunWith(s.db)
@@ -102,7 +251,7 @@ func (s *ProductStore) GetProductsByID(ctx context.Context, ids []int64) ([]*ent
 	products := make([]*entity.Product, 0, len(ids))
 	for rows.Next() {
 		var p entity.Product
-		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.CreatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.Name, &p.Description, &p.Price, &p.Category, &p.Brand, &p.CreatedAt); err != nil {
 			return nil, err
 		}
 		products = append(products, &p)
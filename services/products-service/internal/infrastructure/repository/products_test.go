@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vsespontanno/eCommerce/services/products-service/internal/domain/apperrors"
+	"github.com/vsespontanno/eCommerce/services/products-service/internal/domain/products/entity"
+)
+
+func TestPageCursor(t *testing.T) {
+	t.Run("Round Trip Keeps Exact Sort Values", func(t *testing.T) {
+		cursor := pageCursor{
+			Sort:      entity.SortRelevance,
+			ID:        42,
+			CreatedAt: time.Date(2025, 12, 25, 10, 0, 0, 123456000, time.UTC),
+			Rank:      0.060792699456214905,
+		}
+
+		decoded, err := decodeCursor(cursor.encode(), entity.SortRelevance)
+
+		require.NoError(t, err)
+		assert.Equal(t, cursor.ID, decoded.ID)
+		assert.Equal(t, cursor.Rank, decoded.Rank)
+		assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	})
+
+	t.Run("Cursor Of Another Sort Is Rejected", func(t *testing.T) {
+		token := pageCursor{Sort: entity.SortPriceAsc, ID: 1, Price: 100}.encode()
+
+		_, err := decodeCursor(token, entity.SortPriceDesc)
+
+		assert.ErrorIs(t, err, apperrors.ErrInvalidCursor)
+	})
+
+	t.Run("Garbage Is Rejected", func(t *testing.T) {
+		_, err := decodeCursor("not a cursor!", entity.SortID)
+
+		assert.ErrorIs(t, err, apperrors.ErrInvalidCursor)
+	})
+}
+
+func TestResolveSort(t *testing.T) {
+	assert.Equal(t, entity.SortID, resolveSort(entity.ProductFilter{}))
+	assert.Equal(t, entity.SortRelevance, resolveSort(entity.ProductFilter{Search: "phone"}))
+	assert.Equal(t, entity.SortID, resolveSort(entity.ProductFilter{Sort: entity.SortRelevance}))
+	assert.Equal(t, entity.SortNewest, resolveSort(entity.ProductFilter{Search: "phone", Sort: entity.SortNewest}))
+}
+
+func TestProductConditions(t *testing.T) {
+	where := productConditions(entity.ProductFilter{
+		Search:   "red shoes",
+		Category: "shoes",
+		Brand:    "acme",
+		MinPrice: 100,
+		MaxPrice: 500,
+		InStock:  true,
+	})
+	where = append(where, pageCursor{Sort: entity.SortPriceDesc, ID: 7, Price: 300}.after("red shoes"))
+
+	sql, args, err := sq.StatementBuilder.PlaceholderFormat(sq.Dollar).Select("productID").From("products").Where(where).ToSql()
+
+	require.NoError(t, err)
+	assert.Equal(t, "SELECT productID FROM products WHERE (search_vector @@ websearch_to_tsquery('simple', $1) "+
+		"AND category = $2 AND brand = $3 AND productPrice >= $4 AND productPrice <= $5 "+
+		"AND productQuantity - reserved > 0 AND (productPrice, productID) < ($6, $7))", sql)
+	assert.Equal(t, []any{"red shoes", "shoes", "acme", int64(100), int64(500), int64(300), int64(7)}, args)
+}
@@ -1,35 +1,51 @@
 package postgres
 
 import (
+	"cmp"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 	"go.uber.org/zap"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/vsespontanno/eCommerce/pkg/events"
 	"github.com/vsespontanno/eCommerce/services/products-service/internal/domain/apperrors"
+	"github.com/vsespontanno/eCommerce/services/products-service/internal/domain/products/entity"
 	"github.com/vsespontanno/eCommerce/services/products-service/internal/presentation/grpc/dto"
 )
 
-// TODO: убрать дублирование кода и сделать сет более правильным, чтобы вдруг не было отрицательных значений
+// DefaultReservationTTL - сколько живёт резерв, если заказ так и не закоммитили
+const DefaultReservationTTL = 15 * time.Minute
+
+// SagaStore хранит резервы товаров по заказам в product_reservations.
+// Агрегат products.reserved меняется только вместе с переходом резерва,
+// поэтому повторные вызовы для того же заказа ничего не меняют.
 type SagaStore struct {
-	db      *sqlx.DB
-	builder sq.StatementBuilderType
-	logger  *zap.SugaredLogger
+	db             *sqlx.DB
+	builder        sq.StatementBuilderType
+	logger         *zap.SugaredLogger
+	reservationTTL time.Duration
 }
 
-func NewSagaStore(db *sqlx.DB, logger *zap.SugaredLogger) *SagaStore {
+func NewSagaStore(db *sqlx.DB, logger *zap.SugaredLogger, reservationTTL time.Duration) *SagaStore {
+	if reservationTTL <= 0 {
+		reservationTTL = DefaultReservationTTL
+	}
 	return &SagaStore{
-		db:      db,
-		builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
-		logger:  logger,
+		db:             db,
+		builder:        sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
+		logger:         logger,
+		reservationTTL: reservationTTL,
 	}
 }
 
-func (s *SagaStore) ReserveTxn(ctx context.Context, items []*dto.ItemRequest) error {
+func (s *SagaStore) ReserveTxn(ctx context.Context, orderID string, items []*dto.ItemRequest) error {
 	if len(items) == 0 {
 		return nil
 	}
@@ -38,36 +54,35 @@ func (s *SagaStore) ReserveTxn(ctx context.Context, items []*dto.ItemRequest) er
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if rbErr := tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
-			s.logger.Errorw("failed to rollback reserve transaction", "error", rbErr)
+	defer s.rollback(tx, "reserve")
+
+	existing, err := s.lockReservations(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		// Заказ уже резервировался (или был отменён раньше, чем дошёл резерв) - повтор ничего не меняет
+		if err := checkSameItems(existing, items); err != nil {
+			return err
 		}
-	}()
+		s.logger.Infow("Reservation already exists, skipping", "orderID", orderID, "status", existing[0].Status)
+		return nil
+	}
 
-	for _, it := range items {
+	expiresAt := time.Now().Add(s.reservationTTL)
+	// Порядок товаров в запросе произвольный (корзина хранится в Redis-хэше), поэтому
+	// блокируем их по возрастанию ID - так же, как коммит, отмена, возврат и sweeper
+	sorted := slices.Clone(items)
+	slices.SortFunc(sorted, func(a, b *dto.ItemRequest) int { return cmp.Compare(a.ProductID, b.ProductID) })
+	for _, it := range sorted {
 		if it.Qty <= 0 {
 			return fmt.Errorf("invalid quantity: productID=%d qty=%d", it.ProductID, it.Qty)
 		}
-		qb := s.builder.
-			Select("productquantity", "reserved").
-			From("products").
-			Where(sq.Eq{"productID": it.ProductID}).
-			Suffix("FOR UPDATE")
-
-		sqlStr, args, err := qb.ToSql()
+		quantity, reserved, err := s.lockProduct(ctx, tx, it.ProductID)
 		if err != nil {
 			return err
 		}
 
-		var quantity, reserved int
-		row := tx.QueryRowContext(ctx, sqlStr, args...)
-		if scanErr := row.Scan(&quantity, &reserved); scanErr != nil {
-			if errors.Is(scanErr, sql.ErrNoRows) {
-				return fmt.Errorf("product %d not found", it.ProductID)
-			}
-			return scanErr
-		}
-
 		available := quantity - reserved
 		if available < it.Qty {
 			// явная бизнес-ошибка -> вернуть, транзакция откатится
@@ -75,69 +90,108 @@ func (s *SagaStore) ReserveTxn(ctx context.Context, items []*dto.ItemRequest) er
 		}
 
 		// Обновляем reserved
-		ub := s.builder.
+		if err := s.exec(ctx, tx, s.builder.
 			Update("products").
 			Set("reserved", sq.Expr("reserved + ?", it.Qty)).
-			Where(sq.Eq{"productID": it.ProductID})
-
-		updateSQL, updateArgs, err := ub.ToSql()
-		if err != nil {
+			Where(sq.Eq{"productID": it.ProductID})); err != nil {
 			return err
 		}
-		if _, err := tx.ExecContext(ctx, updateSQL, updateArgs...); err != nil {
+
+		if err := s.exec(ctx, tx, s.builder.
+			Insert("product_reservations").
+			Columns("order_id", "product_id", "qty", "status", "expires_at").
+			Values(orderID, it.ProductID, it.Qty, entity.ReservationReserved, expiresAt)); err != nil {
 			return err
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	return tx.Commit()
+}
+
+func (s *SagaStore) ReleaseTxn(ctx context.Context, orderID string, items []*dto.ItemRequest) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
 		return err
 	}
-	return nil
-}
+	defer s.rollback(tx, "release")
 
-func (s *SagaStore) ReleaseTxn(ctx context.Context, items []*dto.ItemRequest) error {
-	if len(items) == 0 {
-		return nil
+	existing, err := s.lockReservations(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	if len(existing) == 0 {
+		// Release пришёл раньше резерва: оставляем отметку, чтобы запоздавший резерв стал no-op
+		for _, it := range items {
+			if err := s.exec(ctx, tx, s.builder.
+				Insert("product_reservations").
+				Columns("order_id", "product_id", "qty", "status", "expires_at").
+				Values(orderID, it.ProductID, it.Qty, entity.ReservationReleased, time.Now())); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}
+
+	for _, r := range existing {
+		switch r.Status {
+		case entity.ReservationReleased, entity.ReservationExpired:
+			continue
+		case entity.ReservationCommitted:
+			return fmt.Errorf("%w: order %s is already committed", apperrors.ErrInvalidReservationState, orderID)
+		}
+		if err := s.releaseReservation(ctx, tx, r, entity.ReservationReleased); err != nil {
+			return err
+		}
 	}
 
+	return tx.Commit()
+}
+
+func (s *SagaStore) CommitTxn(ctx context.Context, orderID string) error {
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if rbErr := tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
-			s.logger.Errorw("failed to rollback release transaction", "error", rbErr)
-		}
-	}()
+	defer s.rollback(tx, "commit")
 
-	for _, it := range items {
-		if it.Qty <= 0 {
-			return fmt.Errorf("invalid quantity: productID=%d qty=%d", it.ProductID, it.Qty)
+	existing, err := s.lockReservations(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if len(existing) == 0 {
+		return fmt.Errorf("%w: order %s", apperrors.ErrReservationNotFound, orderID)
+	}
+
+	for _, r := range existing {
+		switch r.Status {
+		case entity.ReservationCommitted:
+			continue
+		case entity.ReservationReleased:
+			return fmt.Errorf("%w: order %s is already released", apperrors.ErrInvalidReservationState, orderID)
+		case entity.ReservationExpired:
+			return fmt.Errorf("%w: order %s reservation has expired", apperrors.ErrInvalidReservationState, orderID)
 		}
-		qb := s.builder.
-			Select("reserved").
-			From("products").
-			Where(sq.Eq{"productID": it.ProductID}).
-			Suffix("FOR UPDATE")
 
-		sqlStr, args, err := qb.ToSql()
+		quantity, reserved, err := s.lockProduct(ctx, tx, int(r.ProductID))
 		if err != nil {
 			return err
 		}
-		var reserved int
-		if scanErr := tx.QueryRowContext(ctx, sqlStr, args...).Scan(&reserved); scanErr != nil {
-			return scanErr
+		if quantity < r.Qty {
+			return fmt.Errorf("%w: productID=%d requested=%d available=%d", apperrors.ErrNotEnoughStock, r.ProductID, r.Qty, quantity)
+		}
+		if reserved < r.Qty {
+			return fmt.Errorf("insufficient reserved quantity: productID=%d reserved=%d requested=%d", r.ProductID, reserved, r.Qty)
 		}
 
-		ub := s.builder.
+		if err := s.exec(ctx, tx, s.builder.
 			Update("products").
-			Set("reserved", sq.Expr("GREATEST(reserved - ?, 0)", it.Qty)).
-			Where(sq.Eq{"productID": it.ProductID})
-		updateSQL, updateArgs, err := ub.ToSql()
-		if err != nil {
+			Set("productquantity", sq.Expr("productquantity - ?", r.Qty)).
+			Set("reserved", sq.Expr("reserved - ?", r.Qty)).
+			Where(sq.Eq{"productID": r.ProductID})); err != nil {
 			return err
 		}
-		if _, err := tx.ExecContext(ctx, updateSQL, updateArgs...); err != nil {
+		if err := s.setStatus(ctx, tx, r, entity.ReservationCommitted); err != nil {
 			return err
 		}
 	}
@@ -145,7 +199,9 @@ func (s *SagaStore) ReleaseTxn(ctx context.Context, items []*dto.ItemRequest) er
 	return tx.Commit()
 }
 
-func (s *SagaStore) CommitTxn(ctx context.Context, items []*dto.ItemRequest) error {
+// RestockTxn возвращает на склад часть или всё списанное по заказу. Возвраты одного заказа
+// суммируются: вернуть больше, чем списал CommitTxn, нельзя.
+func (s *SagaStore) RestockTxn(ctx context.Context, orderID, restockID string, items []*dto.ItemRequest) error {
 	if len(items) == 0 {
 		return nil
 	}
@@ -154,51 +210,293 @@ func (s *SagaStore) CommitTxn(ctx context.Context, items []*dto.ItemRequest) err
 	if err != nil {
 		return err
 	}
-	defer func() {
-		if rbErr := tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
-			s.logger.Errorw("failed to rollback commit transaction", "error", rbErr)
+	defer s.rollback(tx, "restock")
+
+	// Блокировка резервов заказа сериализует возвраты по одному заказу
+	reservations, err := s.lockReservations(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if len(reservations) == 0 {
+		return fmt.Errorf("%w: order %s", apperrors.ErrReservationNotFound, orderID)
+	}
+	committed := make(map[int64]int, len(reservations))
+	for _, r := range reservations {
+		if r.Status != entity.ReservationCommitted {
+			return fmt.Errorf("%w: order %s is not committed", apperrors.ErrInvalidReservationState, orderID)
 		}
-	}()
+		committed[r.ProductID] = r.Qty
+	}
 
-	for _, it := range items {
+	restocked, err := s.queryRestocks(ctx, tx, orderID)
+	if err != nil {
+		return err
+	}
+	if applied := restocked[restockID]; len(applied) > 0 {
+		if !sameRestock(applied, items) {
+			return apperrors.ErrRestockMismatch
+		}
+		s.logger.Infow("Restock already applied, skipping", "orderID", orderID, "restockID", restockID)
+		return nil
+	}
+	total := make(map[int64]int)
+	for _, byProduct := range restocked {
+		for productID, qty := range byProduct {
+			total[productID] += qty
+		}
+	}
+
+	// Товары блокируем по возрастанию ID, как и ReserveTxn, чтобы не ловить deadlock
+	sorted := slices.Clone(items)
+	slices.SortFunc(sorted, func(a, b *dto.ItemRequest) int { return cmp.Compare(a.ProductID, b.ProductID) })
+	for _, it := range sorted {
+		productID := int64(it.ProductID)
 		if it.Qty <= 0 {
 			return fmt.Errorf("invalid quantity: productID=%d qty=%d", it.ProductID, it.Qty)
 		}
-		var quantity, reserved int
-		qb := s.builder.
-			Select("productquantity", "reserved").
-			From("products").
-			Where(sq.Eq{"productID": it.ProductID}).
-			Suffix("FOR UPDATE")
-		sqlStr, args, err := qb.ToSql()
-		if err != nil {
+		if total[productID]+it.Qty > committed[productID] {
+			return fmt.Errorf("%w: productID=%d committed=%d restocked=%d requested=%d",
+				apperrors.ErrRestockExceedsOrder, it.ProductID, committed[productID], total[productID], it.Qty)
+		}
+		if _, _, err := s.lockProduct(ctx, tx, it.ProductID); err != nil {
 			return err
 		}
+		if err := s.exec(ctx, tx, s.builder.
+			Update("products").
+			Set("productquantity", sq.Expr("productquantity + ?", it.Qty)).
+			Where(sq.Eq{"productID": it.ProductID})); err != nil {
+			return err
+		}
+		if err := s.exec(ctx, tx, s.builder.
+			Insert("product_restocks").
+			Columns("restock_id", "order_id", "product_id", "qty").
+			Values(restockID, orderID, it.ProductID, it.Qty)); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
 
-		if scanErr := tx.QueryRowContext(ctx, sqlStr, args...).Scan(&quantity, &reserved); scanErr != nil {
-			return scanErr
+// queryRestocks возвращает уже выполненные возвраты заказа: restock_id -> product_id -> qty
+func (s *SagaStore) queryRestocks(ctx context.Context, tx *sql.Tx, orderID string) (map[string]map[int64]int, error) {
+	sqlStr, args, err := s.builder.
+		Select("restock_id", "product_id", "qty").
+		From("product_restocks").
+		Where(sq.Eq{"order_id": orderID}).
+		ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	restocks := make(map[string]map[int64]int)
+	for rows.Next() {
+		var (
+			restockID string
+			productID int64
+			qty       int
+		)
+		if err := rows.Scan(&restockID, &productID, &qty); err != nil {
+			return nil, err
+		}
+		if restocks[restockID] == nil {
+			restocks[restockID] = make(map[int64]int)
 		}
+		restocks[restockID][productID] = qty
+	}
+	return restocks, rows.Err()
+}
+
+// ExpireReservations снимает до limit резервов, у которых истёк TTL, и пишет событие
+// в outbox на каждый. Строки, заблокированные сагой прямо сейчас, пропускаются до следующего прохода.
+func (s *SagaStore) ExpireReservations(ctx context.Context, limit int) ([]entity.Reservation, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer s.rollback(tx, "expire")
 
-		if quantity < it.Qty {
-			return fmt.Errorf("%w: productID=%d requested=%d available=%d", apperrors.ErrNotEnoughStock, it.ProductID, it.Qty, quantity)
+	// Порядок по product_id - тот же, что у ReserveTxn/CommitTxn/RestockTxn, чтобы блокировки товаров не встречались крест-накрест
+	expired, err := s.queryReservations(ctx, tx, s.builder.
+		Select("order_id", "product_id", "qty", "status", "expires_at").
+		From("product_reservations").
+		Where(sq.Eq{"status": entity.ReservationReserved}).
+		Where(sq.Expr("expires_at < NOW()")).
+		OrderBy("product_id", "order_id").
+		Limit(uint64(limit)). // #nosec G115 - limit is checked to be positive above
+		Suffix("FOR UPDATE SKIP LOCKED"))
+	if err != nil {
+		return nil, err
+	}
+
+	expiredAt := time.Now()
+	for _, r := range expired {
+		if err := s.releaseReservation(ctx, tx, r, entity.ReservationExpired); err != nil {
+			return nil, err
 		}
-		if reserved < it.Qty {
-			return fmt.Errorf("insufficient reserved quantity: productID=%d reserved=%d requested=%d", it.ProductID, reserved, it.Qty)
+		if err := s.saveExpiredEvent(ctx, tx, r, expiredAt); err != nil {
+			return nil, err
 		}
+	}
 
-		ub := s.builder.
-			Update("products").
-			Set("productquantity", sq.Expr("productquantity - ?", it.Qty)).
-			Set("reserved", sq.Expr("reserved - ?", it.Qty)).
-			Where(sq.Eq{"productID": it.ProductID})
-		updateSQL, updateArgs, err := ub.ToSql()
-		if err != nil {
-			return err
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// saveExpiredEvent пишет событие об истёкшем резерве в outbox в той же транзакции
+func (s *SagaStore) saveExpiredEvent(ctx context.Context, tx *sql.Tx, r entity.Reservation, expiredAt time.Time) error {
+	// Резерв товара под заказ истекает один раз, поэтому заказ и товар однозначно задают событие
+	id := fmt.Sprintf("%s:%d:%s", r.OrderID, r.ProductID, entity.EventTypeReservationExpired)
+	envelope, err := events.New(id, entity.EventTypeReservationExpired, entity.EventSource, entity.EventSchemaVersion, expiredAt,
+		entity.ReservationExpiredEvent{
+			OrderID:   r.OrderID,
+			ProductID: r.ProductID,
+			Qty:       r.Qty,
+			ExpiresAt: r.ExpiresAt,
+			ExpiredAt: expiredAt,
+			EventType: entity.EventTypeReservationExpired,
+		})
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+	return s.exec(ctx, tx, s.builder.
+		Insert("outbox").
+		Columns("aggregate_id", "aggregate_type", "event_type", "payload", "status").
+		Values(r.OrderID, entity.OutboxAggregateReservation, entity.EventTypeReservationExpired, payload, "pending"))
+}
+
+// releaseReservation возвращает зарезервированное количество товара и переводит резерв в status
+func (s *SagaStore) releaseReservation(ctx context.Context, tx *sql.Tx, r entity.Reservation, status entity.ReservationStatus) error {
+	if _, _, err := s.lockProduct(ctx, tx, int(r.ProductID)); err != nil {
+		return err
+	}
+	if err := s.exec(ctx, tx, s.builder.
+		Update("products").
+		Set("reserved", sq.Expr("GREATEST(reserved - ?, 0)", r.Qty)).
+		Where(sq.Eq{"productID": r.ProductID})); err != nil {
+		return err
+	}
+	return s.setStatus(ctx, tx, r, status)
+}
+
+// lockReservations блокирует резервы заказа; порядок по product_id совпадает с порядком блокировки товаров
+func (s *SagaStore) lockReservations(ctx context.Context, tx *sql.Tx, orderID string) ([]entity.Reservation, error) {
+	return s.queryReservations(ctx, tx, s.builder.
+		Select("order_id", "product_id", "qty", "status", "expires_at").
+		From("product_reservations").
+		Where(sq.Eq{"order_id": orderID}).
+		OrderBy("product_id").
+		Suffix("FOR UPDATE"))
+}
+
+func (s *SagaStore) queryReservations(ctx context.Context, tx *sql.Tx, query sq.SelectBuilder) ([]entity.Reservation, error) {
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, sqlStr, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reservations []entity.Reservation
+	for rows.Next() {
+		var r entity.Reservation
+		if err := rows.Scan(&r.OrderID, &r.ProductID, &r.Qty, &r.Status, &r.ExpiresAt); err != nil {
+			return nil, err
 		}
-		if _, err := tx.ExecContext(ctx, updateSQL, updateArgs...); err != nil {
-			return err
+		reservations = append(reservations, r)
+	}
+	return reservations, rows.Err()
+}
+
+func (s *SagaStore) lockProduct(ctx context.Context, tx *sql.Tx, productID int) (quantity int, reserved int, err error) {
+	sqlStr, args, err := s.builder.
+		Select("productquantity", "reserved").
+		From("products").
+		Where(sq.Eq{"productID": productID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if scanErr := tx.QueryRowContext(ctx, sqlStr, args...).Scan(&quantity, &reserved); scanErr != nil {
+		if errors.Is(scanErr, sql.ErrNoRows) {
+			return 0, 0, fmt.Errorf("product %d not found", productID)
 		}
+		return 0, 0, scanErr
 	}
+	return quantity, reserved, nil
+}
 
-	return tx.Commit()
+func (s *SagaStore) setStatus(ctx context.Context, tx *sql.Tx, r entity.Reservation, status entity.ReservationStatus) error {
+	return s.exec(ctx, tx, s.builder.
+		Update("product_reservations").
+		Set("status", status).
+		Set("updated_at", sq.Expr("NOW()")).
+		Where(sq.Eq{"order_id": r.OrderID, "product_id": r.ProductID}))
+}
+
+func (s *SagaStore) exec(ctx context.Context, tx *sql.Tx, query sq.Sqlizer) error {
+	sqlStr, args, err := query.ToSql()
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, sqlStr, args...)
+	return err
+}
+
+func (s *SagaStore) rollback(tx *sql.Tx, op string) {
+	if rbErr := tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
+		s.logger.Errorw("failed to rollback "+op+" transaction", "error", rbErr)
+	}
+}
+
+// sameRestock сверяет повтор возврата на склад с уже выполненным
+func sameRestock(applied map[int64]int, items []*dto.ItemRequest) bool {
+	if len(applied) != len(items) {
+		return false
+	}
+	for _, it := range items {
+		if q, ok := applied[int64(it.ProductID)]; !ok || q != it.Qty {
+			return false
+		}
+	}
+	return true
+}
+
+// checkSameItems сверяет повторный резерв с уже сохранённым
+func checkSameItems(existing []entity.Reservation, items []*dto.ItemRequest) error {
+	if len(existing) != len(items) {
+		return apperrors.ErrReservationMismatch
+	}
+	qty := make(map[int64]int, len(existing))
+	for _, r := range existing {
+		qty[r.ProductID] = r.Qty
+	}
+	for _, it := range items {
+		if q, ok := qty[int64(it.ProductID)]; !ok || q != it.Qty {
+			return apperrors.ErrReservationMismatch
+		}
+	}
+	return nil
 }
@@ -2,8 +2,10 @@ package saga
 
 import (
 	"context"
+	"errors"
 
 	proto "github.com/vsespontanno/eCommerce/proto/products"
+	"github.com/vsespontanno/eCommerce/services/products-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/products-service/internal/presentation/grpc/dto"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
@@ -11,9 +13,10 @@ import (
 )
 
 type Reserver interface {
-	Reserve(ctx context.Context, products []*dto.ItemRequest) error
-	Release(ctx context.Context, products []*dto.ItemRequest) error
-	Commit(ctx context.Context, products []*dto.ItemRequest) error
+	Reserve(ctx context.Context, orderID string, products []*dto.ItemRequest) error
+	Release(ctx context.Context, orderID string, products []*dto.ItemRequest) error
+	Commit(ctx context.Context, orderID string, products []*dto.ItemRequest) error
+	Restock(ctx context.Context, orderID, restockID string, products []*dto.ItemRequest) error
 }
 
 type Server struct {
@@ -30,47 +33,78 @@ func NewSagaServer(reserver Reserver, logger *zap.SugaredLogger) *Server {
 }
 
 func (s *Server) ReserveProducts(ctx context.Context, req *proto.ReserveProductsRequest) (*proto.ReserveProductsResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
 	products := mapProtoToDTO(req.Products)
-	s.logger.Infow("Reserving products", "count", len(products))
+	s.logger.Infow("Reserving products", "orderID", req.OrderId, "count", len(products))
 
-	err := s.reserver.Reserve(ctx, products)
+	err := s.reserver.Reserve(ctx, req.OrderId, products)
 	if err != nil {
-		s.logger.Errorw("Failed to reserve products", "error", err, "count", len(products))
-		return nil, status.Errorf(codes.Internal, "failed to reserve products: %v", err)
+		s.logger.Errorw("Failed to reserve products", "error", err, "orderID", req.OrderId, "count", len(products))
+		return nil, sagaError(err, "failed to reserve products")
 	}
 
-	s.logger.Infow("Products reserved successfully", "count", len(products))
+	s.logger.Infow("Products reserved successfully", "orderID", req.OrderId, "count", len(products))
 	return &proto.ReserveProductsResponse{Success: true}, nil
 }
 
 func (s *Server) ReleaseProducts(ctx context.Context, req *proto.ReleaseProductsRequest) (*proto.ReleaseProductsResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
 	products := mapProtoToDTO(req.Products)
-	s.logger.Infow("Releasing products", "count", len(products))
+	s.logger.Infow("Releasing products", "orderID", req.OrderId, "count", len(products))
 
-	err := s.reserver.Release(ctx, products)
+	err := s.reserver.Release(ctx, req.OrderId, products)
 	if err != nil {
-		s.logger.Errorw("Failed to release products", "error", err, "count", len(products))
-		return nil, status.Errorf(codes.Internal, "failed to release products: %v", err)
+		s.logger.Errorw("Failed to release products", "error", err, "orderID", req.OrderId, "count", len(products))
+		return nil, sagaError(err, "failed to release products")
 	}
 
-	s.logger.Infow("Products released successfully", "count", len(products))
+	s.logger.Infow("Products released successfully", "orderID", req.OrderId, "count", len(products))
 	return &proto.ReleaseProductsResponse{Success: true}, nil
 }
 
 func (s *Server) CommitProducts(ctx context.Context, req *proto.CommitProductsRequest) (*proto.CommitProductsResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
 	products := mapProtoToDTO(req.Products)
-	s.logger.Infow("Committing products", "count", len(products))
+	s.logger.Infow("Committing products", "orderID", req.OrderId, "count", len(products))
 
-	err := s.reserver.Commit(ctx, products)
+	err := s.reserver.Commit(ctx, req.OrderId, products)
 	if err != nil {
-		s.logger.Errorw("Failed to commit products", "error", err, "count", len(products))
-		return nil, status.Errorf(codes.Internal, "failed to commit products: %v", err)
+		s.logger.Errorw("Failed to commit products", "error", err, "orderID", req.OrderId, "count", len(products))
+		return nil, sagaError(err, "failed to commit products")
 	}
 
-	s.logger.Infow("Products committed successfully", "count", len(products))
+	s.logger.Infow("Products committed successfully", "orderID", req.OrderId, "count", len(products))
 	return &proto.CommitProductsResponse{Success: true}, nil
 }
 
+func (s *Server) RestockProducts(ctx context.Context, req *proto.RestockProductsRequest) (*proto.RestockProductsResponse, error) {
+	if req.OrderId == "" {
+		return nil, status.Error(codes.InvalidArgument, "order_id is required")
+	}
+	// Без restock_id операция - возврат заказа целиком, повторы с тем же order_id идемпотентны
+	restockID := req.RestockId
+	if restockID == "" {
+		restockID = req.OrderId
+	}
+	products := mapProtoToDTO(req.Products)
+	s.logger.Infow("Restocking products", "orderID", req.OrderId, "restockID", restockID, "count", len(products))
+
+	err := s.reserver.Restock(ctx, req.OrderId, restockID, products)
+	if err != nil {
+		s.logger.Errorw("Failed to restock products", "error", err, "orderID", req.OrderId, "restockID", restockID, "count", len(products))
+		return nil, sagaError(err, "failed to restock products")
+	}
+
+	s.logger.Infow("Products restocked successfully", "orderID", req.OrderId, "restockID", restockID, "count", len(products))
+	return &proto.RestockProductsResponse{Success: true}, nil
+}
+
 func mapProtoToDTO(products []*proto.ProductSaga) []*dto.ItemRequest {
 	items := make([]*dto.ItemRequest, 0, len(products))
 	for _, p := range products {
@@ -81,3 +115,19 @@ func mapProtoToDTO(products []*proto.ProductSaga) []*dto.ItemRequest {
 	}
 	return items
 }
+
+// sagaError переводит доменные ошибки в gRPC коды, чтобы оркестратор мог отличить бизнес-отказ от сбоя
+func sagaError(err error, msg string) error {
+	switch {
+	case errors.Is(err, apperrors.ErrNotEnoughStock),
+		errors.Is(err, apperrors.ErrInvalidReservationState),
+		errors.Is(err, apperrors.ErrReservationNotFound),
+		errors.Is(err, apperrors.ErrRestockExceedsOrder):
+		return status.Errorf(codes.FailedPrecondition, "%s: %v", msg, err)
+	case errors.Is(err, apperrors.ErrReservationMismatch),
+		errors.Is(err, apperrors.ErrRestockMismatch):
+		return status.Errorf(codes.InvalidArgument, "%s: %v", msg, err)
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
@@ -3,10 +3,12 @@ package saga
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/vsespontanno/eCommerce/pkg/logger"
 	proto "github.com/vsespontanno/eCommerce/proto/products"
+	"github.com/vsespontanno/eCommerce/services/products-service/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/products-service/internal/presentation/grpc/dto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -18,19 +20,24 @@ func init() {
 
 // MockReserver is a mock implementation of Reserver
 type MockReserver struct {
-	ReserveFunc func(ctx context.Context, products []*dto.ItemRequest) error
-	ReleaseFunc func(ctx context.Context, products []*dto.ItemRequest) error
-	CommitFunc  func(ctx context.Context, products []*dto.ItemRequest) error
+	ReserveFunc func(ctx context.Context, orderID string, products []*dto.ItemRequest) error
+	ReleaseFunc func(ctx context.Context, orderID string, products []*dto.ItemRequest) error
+	CommitFunc  func(ctx context.Context, orderID string, products []*dto.ItemRequest) error
+	RestockFunc func(ctx context.Context, orderID, restockID string, products []*dto.ItemRequest) error
 }
 
-func (m *MockReserver) Reserve(ctx context.Context, products []*dto.ItemRequest) error {
-	return m.ReserveFunc(ctx, products)
+func (m *MockReserver) Reserve(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
+	return m.ReserveFunc(ctx, orderID, products)
 }
-func (m *MockReserver) Release(ctx context.Context, products []*dto.ItemRequest) error {
-	return m.ReleaseFunc(ctx, products)
+func (m *MockReserver) Release(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
+	return m.ReleaseFunc(ctx, orderID, products)
 }
-func (m *MockReserver) Commit(ctx context.Context, products []*dto.ItemRequest) error {
-	return m.CommitFunc(ctx, products)
+func (m *MockReserver) Commit(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
+	return m.CommitFunc(ctx, orderID, products)
+}
+
+func (m *MockReserver) Restock(ctx context.Context, orderID, restockID string, products []*dto.ItemRequest) error {
+	return m.RestockFunc(ctx, orderID, restockID, products)
 }
 
 func TestServer_ReserveProducts(t *testing.T) {
@@ -44,10 +51,11 @@ func TestServer_ReserveProducts(t *testing.T) {
 			name: "Success",
 			req: &proto.ReserveProductsRequest{
 				Products: []*proto.ProductSaga{{Id: 1, Quantity: 1}},
+				OrderId:  "order-123",
 			},
 			mockReserver: func() *MockReserver {
 				return &MockReserver{
-					ReserveFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					ReserveFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return nil
 					},
 				}
@@ -58,16 +66,42 @@ func TestServer_ReserveProducts(t *testing.T) {
 			name: "Internal Error",
 			req: &proto.ReserveProductsRequest{
 				Products: []*proto.ProductSaga{{Id: 1, Quantity: 1}},
+				OrderId:  "order-123",
 			},
 			mockReserver: func() *MockReserver {
 				return &MockReserver{
-					ReserveFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					ReserveFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return errors.New("db error")
 					},
 				}
 			},
 			expectedCode: codes.Internal,
 		},
+		{
+			name: "Not Enough Stock",
+			req: &proto.ReserveProductsRequest{
+				Products: []*proto.ProductSaga{{Id: 1, Quantity: 1}},
+				OrderId:  "order-123",
+			},
+			mockReserver: func() *MockReserver {
+				return &MockReserver{
+					ReserveFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
+						return fmt.Errorf("reserve failed: %w", apperrors.ErrNotEnoughStock)
+					},
+				}
+			},
+			expectedCode: codes.FailedPrecondition,
+		},
+		{
+			name: "Missing Order ID",
+			req: &proto.ReserveProductsRequest{
+				Products: []*proto.ProductSaga{{Id: 1, Quantity: 1}},
+			},
+			mockReserver: func() *MockReserver {
+				return &MockReserver{}
+			},
+			expectedCode: codes.InvalidArgument,
+		},
 	}
 
 	for _, tt := range tests {
@@ -100,10 +134,11 @@ func TestServer_ReleaseProducts(t *testing.T) {
 			name: "Success",
 			req: &proto.ReleaseProductsRequest{
 				Products: []*proto.ProductSaga{{Id: 1, Quantity: 1}},
+				OrderId:  "order-123",
 			},
 			mockReserver: func() *MockReserver {
 				return &MockReserver{
-					ReleaseFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					ReleaseFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return nil
 					},
 				}
@@ -114,10 +149,11 @@ func TestServer_ReleaseProducts(t *testing.T) {
 			name: "Internal Error",
 			req: &proto.ReleaseProductsRequest{
 				Products: []*proto.ProductSaga{{Id: 1, Quantity: 1}},
+				OrderId:  "order-123",
 			},
 			mockReserver: func() *MockReserver {
 				return &MockReserver{
-					ReleaseFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					ReleaseFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return errors.New("db error")
 					},
 				}
@@ -156,10 +192,11 @@ func TestServer_CommitProducts(t *testing.T) {
 			name: "Success",
 			req: &proto.CommitProductsRequest{
 				Products: []*proto.ProductSaga{{Id: 1, Quantity: 1}},
+				OrderId:  "order-123",
 			},
 			mockReserver: func() *MockReserver {
 				return &MockReserver{
-					CommitFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					CommitFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return nil
 					},
 				}
@@ -170,10 +207,11 @@ func TestServer_CommitProducts(t *testing.T) {
 			name: "Internal Error",
 			req: &proto.CommitProductsRequest{
 				Products: []*proto.ProductSaga{{Id: 1, Quantity: 1}},
+				OrderId:  "order-123",
 			},
 			mockReserver: func() *MockReserver {
 				return &MockReserver{
-					CommitFunc: func(ctx context.Context, products []*dto.ItemRequest) error {
+					CommitFunc: func(ctx context.Context, orderID string, products []*dto.ItemRequest) error {
 						return errors.New("db error")
 					},
 				}
@@ -200,3 +238,79 @@ func TestServer_CommitProducts(t *testing.T) {
 		})
 	}
 }
+
+func TestServer_RestockProducts(t *testing.T) {
+	tests := []struct {
+		name              string
+		req               *proto.RestockProductsRequest
+		expectedRestockID string
+		restockErr        error
+		expectedCode      codes.Code
+	}{
+		{
+			name: "Success",
+			req: &proto.RestockProductsRequest{
+				Products:  []*proto.ProductSaga{{Id: 1, Quantity: 1}},
+				OrderId:   "order-123",
+				RestockId: "return-1",
+			},
+			expectedRestockID: "return-1",
+			expectedCode:      codes.OK,
+		},
+		{
+			name: "Defaults Restock ID To Order ID",
+			req: &proto.RestockProductsRequest{
+				Products: []*proto.ProductSaga{{Id: 1, Quantity: 1}},
+				OrderId:  "order-123",
+			},
+			expectedRestockID: "order-123",
+			expectedCode:      codes.OK,
+		},
+		{
+			name: "Exceeds Committed Quantity",
+			req: &proto.RestockProductsRequest{
+				Products: []*proto.ProductSaga{{Id: 1, Quantity: 5}},
+				OrderId:  "order-123",
+			},
+			expectedRestockID: "order-123",
+			restockErr:        fmt.Errorf("restock failed: %w", apperrors.ErrRestockExceedsOrder),
+			expectedCode:      codes.FailedPrecondition,
+		},
+		{
+			name: "Mismatched Repeat",
+			req: &proto.RestockProductsRequest{
+				Products: []*proto.ProductSaga{{Id: 1, Quantity: 2}},
+				OrderId:  "order-123",
+			},
+			expectedRestockID: "order-123",
+			restockErr:        fmt.Errorf("restock failed: %w", apperrors.ErrRestockMismatch),
+			expectedCode:      codes.InvalidArgument,
+		},
+		{
+			name:         "Missing Order ID",
+			req:          &proto.RestockProductsRequest{Products: []*proto.ProductSaga{{Id: 1, Quantity: 1}}},
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRestockID string
+			server := NewSagaServer(&MockReserver{
+				RestockFunc: func(ctx context.Context, orderID, restockID string, products []*dto.ItemRequest) error {
+					gotRestockID = restockID
+					return tt.restockErr
+				},
+			}, logger.Log)
+
+			_, err := server.RestockProducts(context.Background(), tt.req)
+
+			if status.Code(err) != tt.expectedCode {
+				t.Errorf("Expected code %v, got %v", tt.expectedCode, status.Code(err))
+			}
+			if gotRestockID != tt.expectedRestockID {
+				t.Errorf("Expected restock ID %q, got %q", tt.expectedRestockID, gotRestockID)
+			}
+		})
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -18,13 +21,19 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	// defaultPageSize и maxPageSize - размер страницы каталога по умолчанию и наибольший
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 type CartStorer interface {
 	UpsertProductToCart(ctx context.Context, userID int64, productID int64, amountForProduct int64) (int, error)
 }
 
 type ProductStorer interface {
 	SaveProduct(ctx context.Context, product *entity.Product) error
-	GetProducts(ctx context.Context) ([]*entity.Product, error)
+	GetProducts(ctx context.Context, filter entity.ProductFilter) (*entity.ProductPage, error)
 	GetProductByID(ctx context.Context, id int64) (*entity.Product, error)
 	GetProductsByID(ctx context.Context, ids []int64) ([]*entity.Product, error)
 }
@@ -70,23 +79,91 @@ func writeJSON(w http.ResponseWriter, status int, payload any) error {
 
 // ---------- Handlers ----------
 
+// GetProducts - страница каталога. Параметры запроса:
+// q - полнотекстовый поиск, category, brand, min_price, max_price, in_stock,
+// sort (id, relevance, price_asc, price_desc, newest), cursor и limit.
 func (h *Handler) GetProducts(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	products, err := h.productStore.GetProducts(ctx)
+	filter, err := parseProductFilter(r.URL.Query())
+	if err != nil {
+		if writeErr := writeJSON(w, http.StatusBadRequest, map[string]any{"error": err.Error()}); writeErr != nil {
+			h.sugarLogger.Errorw("failed to write error response", "error", writeErr)
+		}
+		return
+	}
+
+	page, err := h.productStore.GetProducts(ctx, filter)
 	if err != nil {
+		if errors.Is(err, apperrors.ErrInvalidCursor) {
+			if writeErr := writeJSON(w, http.StatusBadRequest, map[string]any{"error": "invalid cursor"}); writeErr != nil {
+				h.sugarLogger.Errorw("failed to write error response", "error", writeErr)
+			}
+			return
+		}
 		h.sugarLogger.Errorw("failed to get products", "error", err)
 		http.Error(w, "Failed to get products", http.StatusInternalServerError)
 		return
 	}
 
-	h.sugarLogger.Infow("products retrieved", "count", len(products))
+	h.sugarLogger.Infow("products retrieved", "count", len(page.Products), "total", page.Total)
 
-	if err := writeJSON(w, http.StatusOK, products); err != nil {
+	if err := writeJSON(w, http.StatusOK, page); err != nil {
 		h.sugarLogger.Errorw("failed to write products response", "error", err)
 	}
 }
 
+// parseProductFilter разбирает параметры GET /products; ошибка описывает неверный параметр для клиента
+func parseProductFilter(query url.Values) (entity.ProductFilter, error) {
+	filter := entity.ProductFilter{
+		Search:   strings.TrimSpace(query.Get("q")),
+		Category: query.Get("category"),
+		Brand:    query.Get("brand"),
+		Sort:     entity.ProductSort(query.Get("sort")),
+		Cursor:   query.Get("cursor"),
+		Limit:    defaultPageSize,
+	}
+
+	var err error
+	if filter.MinPrice, err = parsePrice(query, "min_price"); err != nil {
+		return filter, err
+	}
+	if filter.MaxPrice, err = parsePrice(query, "max_price"); err != nil {
+		return filter, err
+	}
+	if filter.MinPrice > 0 && filter.MaxPrice > 0 && filter.MinPrice > filter.MaxPrice {
+		return filter, errors.New("min_price must not exceed max_price")
+	}
+	if v := query.Get("in_stock"); v != "" {
+		if filter.InStock, err = strconv.ParseBool(v); err != nil {
+			return filter, errors.New("in_stock must be true or false")
+		}
+	}
+	if filter.Sort != "" && !filter.Sort.Valid() {
+		return filter, fmt.Errorf("unknown sort %q", filter.Sort)
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return filter, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+func parsePrice(query url.Values, name string) (int64, error) {
+	v := query.Get(name)
+	if v == "" {
+		return 0, nil
+	}
+	price, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || price < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", name)
+	}
+	return price, nil
+}
+
 func (h *Handler) GetProduct(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	vars := mux.Vars(r)
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"testing"
 
 	"github.com/gorilla/mux"
@@ -21,15 +22,15 @@ func init() {
 
 // MockProductStorer is a mock implementation of ProductStorer
 type MockProductStorer struct {
-	GetProductsFunc    func(ctx context.Context) ([]*entity.Product, error)
+	GetProductsFunc    func(ctx context.Context, filter entity.ProductFilter) (*entity.ProductPage, error)
 	GetProductByIDFunc func(ctx context.Context, id int64) (*entity.Product, error)
 }
 
 func (m *MockProductStorer) SaveProduct(ctx context.Context, product *entity.Product) error {
 	return nil
 }
-func (m *MockProductStorer) GetProducts(ctx context.Context) ([]*entity.Product, error) {
-	return m.GetProductsFunc(ctx)
+func (m *MockProductStorer) GetProducts(ctx context.Context, filter entity.ProductFilter) (*entity.ProductPage, error) {
+	return m.GetProductsFunc(ctx, filter)
 }
 func (m *MockProductStorer) GetProductByID(ctx context.Context, id int64) (*entity.Product, error) {
 	return m.GetProductByIDFunc(ctx, id)
@@ -50,30 +51,99 @@ func (m *MockCartStorer) UpsertProductToCart(ctx context.Context, userID int64,
 func TestHandler_GetProducts(t *testing.T) {
 	tests := []struct {
 		name           string
+		url            string
 		mockStore      func() *MockProductStorer
 		expectedStatus int
 		expectedCount  int
+		expectedFilter *entity.ProductFilter
 	}{
 		{
 			name: "Success",
+			url:  "/products",
 			mockStore: func() *MockProductStorer {
 				return &MockProductStorer{
-					GetProductsFunc: func(ctx context.Context) ([]*entity.Product, error) {
-						return []*entity.Product{
-							{ID: 1, Name: "Product 1"},
-							{ID: 2, Name: "Product 2"},
+					GetProductsFunc: func(ctx context.Context, filter entity.ProductFilter) (*entity.ProductPage, error) {
+						return &entity.ProductPage{
+							Products: []*entity.Product{
+								{ID: 1, Name: "Product 1"},
+								{ID: 2, Name: "Product 2"},
+							},
+							Total:      5,
+							NextCursor: "next",
 						}, nil
 					},
 				}
 			},
 			expectedStatus: http.StatusOK,
 			expectedCount:  2,
+			expectedFilter: &entity.ProductFilter{Limit: defaultPageSize},
+		},
+		{
+			name: "Filters Passed To Store",
+			url:  "/products?q=+red+shoes+&category=shoes&brand=acme&min_price=100&max_price=500&in_stock=true&sort=price_desc&cursor=abc&limit=50",
+			mockStore: func() *MockProductStorer {
+				return &MockProductStorer{
+					GetProductsFunc: func(ctx context.Context, filter entity.ProductFilter) (*entity.ProductPage, error) {
+						return &entity.ProductPage{Products: []*entity.Product{}}, nil
+					},
+				}
+			},
+			expectedStatus: http.StatusOK,
+			expectedCount:  0,
+			expectedFilter: &entity.ProductFilter{
+				Search:   "red shoes",
+				Category: "shoes",
+				Brand:    "acme",
+				MinPrice: 100,
+				MaxPrice: 500,
+				InStock:  true,
+				Sort:     entity.SortPriceDesc,
+				Cursor:   "abc",
+				Limit:    50,
+			},
+		},
+		{
+			name:           "Unknown Sort",
+			url:            "/products?sort=popular",
+			mockStore:      func() *MockProductStorer { return &MockProductStorer{} },
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Limit Too Large",
+			url:            "/products?limit=1000",
+			mockStore:      func() *MockProductStorer { return &MockProductStorer{} },
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Inverted Price Range",
+			url:            "/products?min_price=500&max_price=100",
+			mockStore:      func() *MockProductStorer { return &MockProductStorer{} },
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid In Stock",
+			url:            "/products?in_stock=maybe",
+			mockStore:      func() *MockProductStorer { return &MockProductStorer{} },
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Invalid Cursor",
+			url:  "/products?cursor=garbage",
+			mockStore: func() *MockProductStorer {
+				return &MockProductStorer{
+					GetProductsFunc: func(ctx context.Context, filter entity.ProductFilter) (*entity.ProductPage, error) {
+						return nil, apperrors.ErrInvalidCursor
+					},
+				}
+			},
+			expectedStatus: http.StatusBadRequest,
 		},
 		{
 			name: "Internal Error",
+			url:  "/products",
 			mockStore: func() *MockProductStorer {
 				return &MockProductStorer{
-					GetProductsFunc: func(ctx context.Context) ([]*entity.Product, error) {
+					GetProductsFunc: func(ctx context.Context, filter entity.ProductFilter) (*entity.ProductPage, error) {
 						return nil, errors.New("db error")
 					},
 				}
@@ -85,9 +155,17 @@ func TestHandler_GetProducts(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			h := New(nil, tt.mockStore(), logger.Log, nil)
+			store := tt.mockStore()
+			var gotFilter *entity.ProductFilter
+			if getProducts := store.GetProductsFunc; getProducts != nil {
+				store.GetProductsFunc = func(ctx context.Context, filter entity.ProductFilter) (*entity.ProductPage, error) {
+					gotFilter = &filter
+					return getProducts(ctx, filter)
+				}
+			}
+			h := New(nil, store, logger.Log, nil)
 
-			req, _ := http.NewRequestWithContext(context.Background(), "GET", "/products", nil)
+			req, _ := http.NewRequestWithContext(context.Background(), "GET", tt.url, nil)
 			rr := httptest.NewRecorder()
 
 			h.GetProducts(rr, req)
@@ -96,11 +174,15 @@ func TestHandler_GetProducts(t *testing.T) {
 				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
 			}
 
+			if tt.expectedFilter != nil && !reflect.DeepEqual(tt.expectedFilter, gotFilter) {
+				t.Errorf("Expected filter %+v, got %+v", tt.expectedFilter, gotFilter)
+			}
+
 			if tt.expectedStatus == http.StatusOK {
-				var products []*entity.Product
-				json.NewDecoder(rr.Body).Decode(&products)
-				if len(products) != tt.expectedCount {
-					t.Errorf("Expected %d products, got %d", tt.expectedCount, len(products))
+				var page entity.ProductPage
+				json.NewDecoder(rr.Body).Decode(&page)
+				if len(page.Products) != tt.expectedCount {
+					t.Errorf("Expected %d products, got %d", tt.expectedCount, len(page.Products))
 				}
 			}
 		})
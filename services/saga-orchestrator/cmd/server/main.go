@@ -11,18 +11,25 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/vsespontanno/eCommerce/pkg/logger"
+	"github.com/vsespontanno/eCommerce/pkg/pubsub"
 	proto "github.com/vsespontanno/eCommerce/proto/saga"
+	applicationAdmin "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/application/admin"
 	applicationSaga "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/application/saga"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	eventEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/db"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/grpcClient/orders"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/grpcClient/products"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/grpcClient/wallet"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/outbox"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/repository"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/resilience"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/presentation/server/admin"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/presentation/server/saga"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -48,78 +55,104 @@ func main() {
 	// Outbox repository
 	outboxRepo := repository.NewOutboxRepository(postgresDB, logger.Log)
 
-	// Kafka is optional - service can work without it
-	var kafkaProducer *kafka.Producer
+	// Saga state repository
+	sagaStateRepo := repository.NewSagaStateRepository(postgresDB, logger.Log)
+
+	// Журнал действий операторов
+	auditRepo := repository.NewAuditRepository(postgresDB, logger.Log)
+
+	// Транспорт событий необязателен - без него события копятся в outbox
 	var outboxPublisher *outbox.Publisher
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	if cfg.KafkaBroker != "" {
-		var kafkaErr error
-		config := &kafka.ConfigMap{
-			"bootstrap.servers":  cfg.KafkaBroker,
-			"acks":               "all",
-			"retries":            10,
-			"enable.idempotence": true,
-		}
-
-		// Add SASL/SSL configuration if credentials are provided (for Yandex Cloud Kafka)
-		if cfg.KafkaSASLUsername != "" && cfg.KafkaSASLPassword != "" {
-			//nolint:errcheck // SetKey errors are non-critical for Kafka config
-			_ = config.SetKey("security.protocol", cfg.KafkaSecurityProtocol)
-			//nolint:errcheck
-			_ = config.SetKey("sasl.mechanism", cfg.KafkaSASLMechanism)
-			//nolint:errcheck
-			_ = config.SetKey("sasl.username", cfg.KafkaSASLUsername)
-			//nolint:errcheck
-			_ = config.SetKey("sasl.password", cfg.KafkaSASLPassword)
-
-			if cfg.KafkaSSLCAPath != "" {
-				//nolint:errcheck
-				_ = config.SetKey("ssl.ca.location", cfg.KafkaSSLCAPath)
-			}
-
-			logger.Log.Infow("Kafka producer configured with SASL/SSL",
-				"security.protocol", cfg.KafkaSecurityProtocol,
-				"sasl.mechanism", cfg.KafkaSASLMechanism,
-				"ssl.ca.location", cfg.KafkaSSLCAPath,
-			)
+	if cfg.EventTransport != "" {
+		transport, transportErr := pubsub.NewPublisher(cfg.PubSub(), postgresDB.DB, logger.Log)
+		if transportErr != nil {
+			logger.Log.Warnw("Failed to create event publisher, continuing without it", "transport", cfg.EventTransport, "error", transportErr)
 		} else {
-			logger.Log.Info("Kafka producer configured without SASL/SSL (local mode)")
-		}
-
-		kafkaProducer, kafkaErr = kafka.NewProducer(config)
-		if kafkaErr != nil {
-			logger.Log.Warnw("Failed to create Kafka producer, continuing without it", "error", kafkaErr)
-		} else {
-			logger.Log.Info("Kafka producer initialized successfully")
-			defer kafkaProducer.Close()
+			logger.Log.Infow("Event publisher initialized successfully", "transport", cfg.EventTransport)
+			if cfg.EventTransport == pubsub.TransportMemory {
+				logger.Log.Warn("In-memory event transport delivers events only within this process")
+			}
+			//nolint:errcheck // ошибка закрытия при остановке не важна
+			defer transport.Close()
 
 			// Outbox publisher (фоновый worker)
 			outboxPublisher = outbox.NewOutboxPublisher(
 				postgresDB,
-				kafkaProducer,
+				transport,
 				logger.Log,
 				cfg.KafkaTopic,
-				5*time.Second,
+				// Истёкшие резервы wallet и products - не события заказа, consumer'ам заказов они не нужны
+				map[string]string{
+					eventEntity.AggregateWalletTransaction:  cfg.KafkaReservationsTopic,
+					eventEntity.AggregateProductReservation: cfg.KafkaReservationsTopic,
+				},
+				cfg.OutboxPollInterval,
+				cfg.OutboxRetry,
+				cfg.OutboxWorkerID,
+				cfg.OutboxLease,
 			)
 
+			// LISTEN будит publisher сразу после SaveEvent; без него остаётся опрос по интервалу
+			var wake <-chan struct{}
+			outboxListener, listenErr := outbox.NewListener(db.DSN(cfg), logger.Log)
+			if listenErr != nil {
+				logger.Log.Warnw("Failed to listen for outbox notifications, polling only", "error", listenErr)
+			} else {
+				defer outboxListener.Close()
+				wake = outboxListener.Wake()
+				go outboxListener.Run(ctx)
+			}
+
 			// Запускаем outbox publisher
 			go func() {
-				outboxPublisher.Start(ctx)
+				outboxPublisher.Start(ctx, wake)
 			}()
 		}
 	} else {
-		logger.Log.Info("Kafka broker not configured, running without Kafka producer")
+		logger.Log.Info("Event transport not configured, running without outbox publisher")
 	}
 
-	// gRPC clients
-	walletClient := wallet.NewWalletClient(cfg.GRPCWalletClientPort, logger.Log)
-	productsClient := products.NewProductsClient(cfg.GRPCProductsClientPort, logger.Log)
+	// Архивация outbox нужна и без транспорта: события копятся в любом случае
+	if cfg.OutboxRetention > 0 {
+		archiver := outbox.NewArchiver(postgresDB, logger.Log, cfg.OutboxArchiveInterval, cfg.OutboxRetention, cfg.OutboxArchiveRetention)
+		go archiver.Start(ctx)
+	}
+
+	// gRPC clients: у каждого downstream свой circuit breaker, повторы - по политике шага
+	walletCaller := resilience.NewCaller(
+		resilience.NewBreaker("wallet", cfg.CircuitFailureThreshold, cfg.CircuitOpenTimeout, logger.Log),
+		cfg.Policy,
+		logger.Log,
+	)
+	productsCaller := resilience.NewCaller(
+		resilience.NewBreaker("products", cfg.CircuitFailureThreshold, cfg.CircuitOpenTimeout, logger.Log),
+		cfg.Policy,
+		logger.Log,
+	)
+	ordersCaller := resilience.NewCaller(
+		resilience.NewBreaker("orders", cfg.CircuitFailureThreshold, cfg.CircuitOpenTimeout, logger.Log),
+		cfg.Policy,
+		logger.Log,
+	)
+	// Неудачные попытки вызовов внутри шагов саги попадают в хронологию заказа
+	attemptRecorder := applicationSaga.NewAttemptRecorder(sagaStateRepo, logger.Log)
+	for _, caller := range []*resilience.Caller{walletCaller, productsCaller, ordersCaller} {
+		caller.SetObserver(attemptRecorder)
+	}
+	walletClient := wallet.NewWalletClient(cfg.GRPCWalletClientPort, walletCaller, logger.Log)
+	productsClient := products.NewProductsClient(cfg.GRPCProductsClientPort, cfg.GRPCCatalogClientPort, productsCaller, logger.Log)
+	ordersClient := orders.NewOrdersClient(cfg.GRPCOrderClientPort, ordersCaller, logger.Log)
 
 	// Saga service (использует outbox)
-	sagaService := applicationSaga.New(cfg, walletClient, productsClient, outboxRepo, logger.Log)
-	sagaServer := saga.NewSagaServer(logger.Log, sagaService)
+	sagaService := applicationSaga.New(cfg, walletClient, productsClient, ordersClient, outboxRepo, sagaStateRepo, logger.Log)
+
+	// Саги, оборванные упавшими репликами, доводятся в фоне: запуск сервера их не ждёт
+	sagaService.StartRecovery(ctx, cfg.SagaRecoveryInterval)
+
+	sagaServer := saga.NewSagaServer(logger.Log, sagaService, productsClient)
 
 	grpcServer := initializeGRPC(logger.Log)
 	proto.RegisterSagaServer(grpcServer, sagaServer)
@@ -145,6 +178,9 @@ func main() {
 		}
 	})
 
+	// Метрики шагов саги для Prometheus
+	healthMux.Handle("/metrics", promhttp.Handler())
+
 	healthServer := &http.Server{
 		Addr:              fmt.Sprintf(":%d", cfg.HTTPHealthPort),
 		Handler:           healthMux,
@@ -167,6 +203,10 @@ func main() {
 		}
 	}()
 
+	// Операторский API для зависших и упавших саг: отдельные порты, только с токеном оператора
+	adminService := applicationAdmin.New(sagaService, sagaStateRepo, auditRepo, outboxRepo, cfg.SagaStuckAfter, logger.Log)
+	adminGRPCServer, adminHTTPServer := startAdmin(ctx, cfg, adminService, logger.Log)
+
 	// Graceful shutdown
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
@@ -174,9 +214,9 @@ func main() {
 
 	logger.Log.Info("Shutting down Saga orchestrator...")
 
-	// Останавливаем outbox publisher если он был инициализирован
+	// Останавливаем recovery, архивацию и outbox publisher, если он был инициализирован
+	cancel()
 	if outboxPublisher != nil {
-		cancel()
 		time.Sleep(1 * time.Second)
 	}
 
@@ -186,13 +226,71 @@ func main() {
 	if err := healthServer.Shutdown(healthCtx); err != nil {
 		logger.Log.Errorw("Health server shutdown failed", "error", err)
 	}
+	if adminHTTPServer != nil {
+		if err := adminHTTPServer.Shutdown(healthCtx); err != nil {
+			logger.Log.Errorw("Admin HTTP server shutdown failed", "error", err)
+		}
+	}
 
 	// Останавливаем gRPC
 	grpcServer.GracefulStop()
+	if adminGRPCServer != nil {
+		adminGRPCServer.GracefulStop()
+	}
+
+	// Даём фоновым сагам доработать; не успевшие останавливаются и достаются recovery
+	sagaCtx, sagaCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer sagaCancel()
+	sagaService.Shutdown(sagaCtx)
 
 	logger.Log.Info("Saga orchestrator stopped gracefully")
 }
 
+// startAdmin запускает gRPC и HTTP версии операторского API на отдельных портах.
+// Без токенов операторов API не запускается: действия в журнале должны быть подписаны.
+func startAdmin(ctx context.Context, cfg *config.Config, adminService *applicationAdmin.Service, log *zap.SugaredLogger) (*grpc.Server, *http.Server) {
+	if len(cfg.AdminTokens) == 0 {
+		log.Warn("SAGA_ADMIN_TOKENS is not set, saga admin API is disabled")
+		return nil, nil
+	}
+	auth := admin.NewAuthenticator(cfg.AdminTokens)
+	adminServer := admin.NewAdminServer(log, adminService)
+
+	grpcServer := initializeGRPC(log, auth.UnaryInterceptor())
+	proto.RegisterSagaAdminServer(grpcServer, adminServer)
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.AdminGRPCPort))
+	if err != nil {
+		log.Fatalf("failed to listen for admin API: %v", err)
+	}
+	go func() {
+		log.Infof("Saga admin gRPC server started on port %d", cfg.AdminGRPCPort)
+		if err := grpcServer.Serve(lis); err != nil {
+			log.Errorw("Admin gRPC server stopped", "error", err)
+		}
+	}()
+
+	adminMux := runtime.NewServeMux()
+	if err := proto.RegisterSagaAdminHandlerServer(ctx, adminMux, adminServer); err != nil {
+		log.Fatalw("failed to register saga admin HTTP handlers", "error", err)
+	}
+	httpServer := &http.Server{
+		Addr:              fmt.Sprintf(":%d", cfg.AdminHTTPPort),
+		Handler:           auth.Middleware(adminMux),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       10 * time.Second,
+		// Retry и compensate ждут, пока сага доработает
+		WriteTimeout: 2 * time.Minute,
+		IdleTimeout:  30 * time.Second,
+	}
+	go func() {
+		log.Infof("Saga admin HTTP server started on port %d", cfg.AdminHTTPPort)
+		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Errorw("Admin HTTP server stopped", "error", err)
+		}
+	}()
+	return grpcServer, httpServer
+}
+
 func interceptorLogger(l *zap.SugaredLogger) logging.Logger {
 	return logging.LoggerFunc(func(ctx context.Context, lvl logging.Level, msg string, fields ...any) {
 		// #nosec G115 - logging.Level и zapcore.Level имеют одинаковые значения
@@ -201,7 +299,7 @@ func interceptorLogger(l *zap.SugaredLogger) logging.Logger {
 	})
 }
 
-func initializeGRPC(log *zap.SugaredLogger) *grpc.Server {
+func initializeGRPC(log *zap.SugaredLogger, interceptors ...grpc.UnaryServerInterceptor) *grpc.Server {
 	recoveryOpts := []recovery.Option{
 		recovery.WithRecoveryHandler(func(p interface{}) (err error) {
 			log.Errorw("Recovered from panic", "panic", p)
@@ -213,8 +311,8 @@ func initializeGRPC(log *zap.SugaredLogger) *grpc.Server {
 	}
 	interceptor := interceptorLogger(log)
 
-	return grpc.NewServer(grpc.ChainUnaryInterceptor(
+	return grpc.NewServer(grpc.ChainUnaryInterceptor(append([]grpc.UnaryServerInterceptor{
 		recovery.UnaryServerInterceptor(recoveryOpts...),
 		logging.UnaryServerInterceptor(interceptor, loggingOpts...),
-	))
+	}, interceptors...)...))
 }
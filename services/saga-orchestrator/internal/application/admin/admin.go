@@ -0,0 +1,327 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
+	eventEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 500
+)
+
+// ErrCompensatedByOperator - причина отката, если у саги не было своей ошибки
+var ErrCompensatedByOperator = errors.New("compensated by operator")
+
+// SagaRunner выполняет сохранённые саги по командам оператора
+type SagaRunner interface {
+	Retry(ctx context.Context, instance sagaEntity.Instance) error
+	Compensate(ctx context.Context, instance sagaEntity.Instance, cause error) error
+}
+
+type StateRepo interface {
+	GetSaga(ctx context.Context, orderID string) (*sagaEntity.Instance, error)
+	ListSagas(ctx context.Context, filter sagaEntity.SagaFilter) ([]sagaEntity.Instance, error)
+	ListSteps(ctx context.Context, orderID string) ([]sagaEntity.StepRecord, error)
+	TransitionStatus(ctx context.Context, instance sagaEntity.Instance, to sagaEntity.Status, errMsg string) (bool, error)
+	ListTimeline(ctx context.Context, orderID string) ([]sagaEntity.TimelineEvent, error)
+}
+
+// AuditRepo - журнал действий операторов
+type AuditRepo interface {
+	RecordAction(ctx context.Context, action sagaEntity.AdminAction) error
+	ListActions(ctx context.Context, filter sagaEntity.AdminActionFilter) ([]sagaEntity.AdminAction, error)
+}
+
+// OutboxRepo - события outbox, которые не удалось отправить
+type OutboxRepo interface {
+	GetEvent(ctx context.Context, id int64) (*eventEntity.OutboxEvent, error)
+	ListDeadEvents(ctx context.Context, limit int) ([]eventEntity.OutboxEvent, error)
+	RequeueEvent(ctx context.Context, id int64) (bool, error)
+}
+
+// SagaDetails - сага вместе с историей шагов и действиями операторов над ней
+type SagaDetails struct {
+	Instance sagaEntity.Instance
+	Steps    []sagaEntity.StepRecord
+	Actions  []sagaEntity.AdminAction
+}
+
+// Service - операции операторов над зависшими и упавшими сагами.
+// Каждое действие, включая отклонённые, пишется в журнал.
+type Service struct {
+	runner SagaRunner
+	state  StateRepo
+	audit  AuditRepo
+	outbox OutboxRepo
+	// stuckAfter - сколько RUNNING/COMPENSATING сага должна не обновляться,
+	// чтобы считаться зависшей, а не выполняющейся прямо сейчас
+	stuckAfter time.Duration
+	logger     *zap.SugaredLogger
+}
+
+func New(runner SagaRunner, state StateRepo, audit AuditRepo, outbox OutboxRepo, stuckAfter time.Duration, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		runner:     runner,
+		state:      state,
+		audit:      audit,
+		outbox:     outbox,
+		stuckAfter: stuckAfter,
+		logger:     logger,
+	}
+}
+
+// ListSagas возвращает саги по статусу и давности последнего обновления
+func (s *Service) ListSagas(ctx context.Context, filter sagaEntity.SagaFilter) ([]sagaEntity.Instance, error) {
+	filter.Limit = normalizeLimit(filter.Limit)
+	return s.state.ListSagas(ctx, filter)
+}
+
+// GetSaga возвращает сагу с историей шагов и журналом действий над ней
+func (s *Service) GetSaga(ctx context.Context, orderID string) (*SagaDetails, error) {
+	instance, err := s.state.GetSaga(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := s.state.ListSteps(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	actions, err := s.audit.ListActions(ctx, sagaEntity.AdminActionFilter{OrderID: orderID, Limit: MaxListLimit})
+	if err != nil {
+		return nil, err
+	}
+	return &SagaDetails{Instance: *instance, Steps: steps, Actions: actions}, nil
+}
+
+// OrderTimeline возвращает хронологию заказа: переходы всех саг по нему, неудачные попытки
+// вызовов и действия операторов. Пустая хронология - по заказу не запускалась ни одна сага.
+func (s *Service) OrderTimeline(ctx context.Context, orderID string) ([]sagaEntity.TimelineEvent, error) {
+	events, err := s.state.ListTimeline(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, apperrors.ErrSagaNotFound
+	}
+	return events, nil
+}
+
+// ListActions возвращает журнал действий операторов
+func (s *Service) ListActions(ctx context.Context, filter sagaEntity.AdminActionFilter) ([]sagaEntity.AdminAction, error) {
+	filter.Limit = normalizeLimit(filter.Limit)
+	return s.audit.ListActions(ctx, filter)
+}
+
+// Retry повторяет сагу с шага, на котором она остановилась.
+// Сагу, у которой уже откатился хотя бы один шаг, повторить нельзя - её резервы уже отпущены.
+func (s *Service) Retry(ctx context.Context, orderID, operator, reason string) (*sagaEntity.Instance, error) {
+	prepare := func(instance *sagaEntity.Instance) error {
+		steps, err := s.state.ListSteps(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		for _, step := range steps {
+			if step.Status == sagaEntity.StepCompensated {
+				return apperrors.ErrSagaCompensated
+			}
+		}
+		return s.claim(ctx, instance, sagaEntity.StatusRunning, "")
+	}
+	execute := func(instance sagaEntity.Instance) error {
+		return s.runner.Retry(ctx, instance)
+	}
+	return s.act(ctx, sagaEntity.ActionRetry, orderID, operator, reason, prepare, execute)
+}
+
+// Compensate откатывает сагу, в том числе повторяет компенсации, которые раньше не прошли
+func (s *Service) Compensate(ctx context.Context, orderID, operator, reason string) (*sagaEntity.Instance, error) {
+	prepare := func(instance *sagaEntity.Instance) error {
+		return s.claim(ctx, instance, sagaEntity.StatusCompensating, instance.Error)
+	}
+	execute := func(instance sagaEntity.Instance) error {
+		cause := ErrCompensatedByOperator
+		if instance.Error != "" {
+			// Клиент должен видеть исходную причину, а не факт ручного отката
+			cause = errors.New(instance.Error)
+		}
+		return s.runner.Compensate(ctx, instance, cause)
+	}
+	return s.act(ctx, sagaEntity.ActionCompensate, orderID, operator, reason, prepare, execute)
+}
+
+// Resolve помечает сагу разобранной вручную: после этого система её не трогает
+func (s *Service) Resolve(ctx context.Context, orderID, operator, reason string) (*sagaEntity.Instance, error) {
+	prepare := func(instance *sagaEntity.Instance) error {
+		if reason == "" {
+			return apperrors.ErrReasonRequired
+		}
+		return s.claim(ctx, instance, sagaEntity.StatusResolved, instance.Error)
+	}
+	return s.act(ctx, sagaEntity.ActionResolve, orderID, operator, reason, prepare, nil)
+}
+
+// ListDeadEvents возвращает события outbox, исчерпавшие попытки отправки
+func (s *Service) ListDeadEvents(ctx context.Context, limit int) ([]eventEntity.OutboxEvent, error) {
+	return s.outbox.ListDeadEvents(ctx, normalizeLimit(limit))
+}
+
+// RequeueEvent возвращает dead событие в очередь: publisher отправит его заново
+// с полным запасом попыток
+func (s *Service) RequeueEvent(ctx context.Context, id int64, operator, reason string) (*eventEntity.OutboxEvent, error) {
+	if operator == "" {
+		return nil, apperrors.ErrOperatorRequired
+	}
+
+	var orderID string
+	event, err := s.outbox.GetEvent(ctx, id)
+	if err == nil {
+		orderID = event.AggregateID
+		if event.Status != eventEntity.OutboxDead {
+			err = fmt.Errorf("%w: status %s", apperrors.ErrEventNotDead, event.Status)
+		}
+	}
+	if err == nil {
+		var ok bool
+		if ok, err = s.outbox.RequeueEvent(ctx, id); err == nil && !ok {
+			err = apperrors.ErrEventNotDead
+		}
+	}
+
+	entry := sagaEntity.AdminAction{
+		OrderID:  orderID,
+		Action:   sagaEntity.ActionRequeueEvent,
+		Operator: operator,
+		Reason:   reason,
+		Result:   sagaEntity.ActionSucceeded,
+		EventID:  id,
+	}
+	if err != nil {
+		entry.Result = sagaEntity.ActionRejected
+		entry.Error = err.Error()
+	}
+	s.recordEntry(ctx, entry)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infow("Outbox event requeued", "id", id, "orderID", orderID, "operator", operator)
+	return s.outbox.GetEvent(ctx, id)
+}
+
+// act проверяет, что над сагой можно выполнить действие, и захватывает её через prepare,
+// затем выполняет execute и пишет результат в журнал.
+// Ошибка prepare - действие отклонено; ошибка execute - действие выполнено, но сага снова упала.
+func (s *Service) act(
+	ctx context.Context,
+	action sagaEntity.AdminActionType,
+	orderID, operator, reason string,
+	prepare func(instance *sagaEntity.Instance) error,
+	execute func(instance sagaEntity.Instance) error,
+) (*sagaEntity.Instance, error) {
+	if operator == "" {
+		return nil, apperrors.ErrOperatorRequired
+	}
+
+	instance, err := s.state.GetSaga(ctx, orderID)
+	if err == nil {
+		err = s.checkActionable(*instance)
+	}
+	if err == nil {
+		err = prepare(instance)
+	}
+	if err != nil {
+		s.record(ctx, action, orderID, operator, reason, sagaEntity.ActionRejected, err)
+		return nil, err
+	}
+
+	result := sagaEntity.ActionSucceeded
+	var sagaErr error
+	if execute != nil {
+		if sagaErr = execute(*instance); sagaErr != nil {
+			result = sagaEntity.ActionFailed
+		}
+	}
+	s.record(ctx, action, orderID, operator, reason, result, sagaErr)
+	s.logger.Infow("Admin action performed", "orderID", orderID, "action", action, "operator", operator, "result", result, "error", sagaErr)
+
+	return s.state.GetSaga(ctx, orderID)
+}
+
+// checkActionable отсекает завершённые саги и те, что ещё выполняются
+func (s *Service) checkActionable(instance sagaEntity.Instance) error {
+	switch instance.Status {
+	case sagaEntity.StatusCompleted, sagaEntity.StatusResolved:
+		return fmt.Errorf("%w: status %s", apperrors.ErrSagaFinished, instance.Status)
+	case sagaEntity.StatusRunning, sagaEntity.StatusCompensating:
+		if time.Since(instance.UpdatedAt) < s.stuckAfter {
+			return fmt.Errorf("%w: updated %s ago", apperrors.ErrSagaInProgress, time.Since(instance.UpdatedAt).Round(time.Second))
+		}
+	}
+	return nil
+}
+
+// claim переводит сагу в новый статус, только если её никто не изменил с момента чтения -
+// так два оператора или оператор и recovery не выполнят одну сагу дважды
+func (s *Service) claim(ctx context.Context, instance *sagaEntity.Instance, to sagaEntity.Status, errMsg string) error {
+	ok, err := s.state.TransitionStatus(ctx, *instance, to, errMsg)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperrors.ErrSagaModified
+	}
+	instance.Status = to
+	return nil
+}
+
+// record пишет действие в журнал; сбой журнала не отменяет уже выполненное действие
+func (s *Service) record(
+	ctx context.Context,
+	action sagaEntity.AdminActionType,
+	orderID, operator, reason string,
+	result sagaEntity.AdminActionResult,
+	actionErr error,
+) {
+	entry := sagaEntity.AdminAction{
+		OrderID:  orderID,
+		Action:   action,
+		Operator: operator,
+		Reason:   reason,
+		Result:   result,
+	}
+	if actionErr != nil {
+		entry.Error = actionErr.Error()
+	}
+	s.recordEntry(ctx, entry)
+}
+
+func (s *Service) recordEntry(ctx context.Context, entry sagaEntity.AdminAction) {
+	if err := s.audit.RecordAction(context.WithoutCancel(ctx), entry); err != nil {
+		s.logger.Errorw("Failed to record admin action",
+			"error", err,
+			"orderID", entry.OrderID,
+			"eventID", entry.EventID,
+			"action", entry.Action,
+			"operator", entry.Operator,
+			"result", entry.Result,
+		)
+	}
+}
+
+func normalizeLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultListLimit
+	}
+	if limit > MaxListLimit {
+		return MaxListLimit
+	}
+	return limit
+}
@@ -0,0 +1,508 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
+	eventEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"go.uber.org/zap"
+)
+
+// Mocks
+type MockSagaRunner struct {
+	mock.Mock
+}
+
+func (m *MockSagaRunner) Retry(ctx context.Context, instance sagaEntity.Instance) error {
+	args := m.Called(ctx, instance)
+	return args.Error(0)
+}
+
+func (m *MockSagaRunner) Compensate(ctx context.Context, instance sagaEntity.Instance, cause error) error {
+	args := m.Called(ctx, instance, cause)
+	return args.Error(0)
+}
+
+type MockStateRepo struct {
+	mock.Mock
+}
+
+func (m *MockStateRepo) GetSaga(ctx context.Context, orderID string) (*sagaEntity.Instance, error) {
+	args := m.Called(ctx, orderID)
+	instance, _ := args.Get(0).(*sagaEntity.Instance)
+	return instance, args.Error(1)
+}
+
+func (m *MockStateRepo) ListSagas(ctx context.Context, filter sagaEntity.SagaFilter) ([]sagaEntity.Instance, error) {
+	args := m.Called(ctx, filter)
+	instances, _ := args.Get(0).([]sagaEntity.Instance)
+	return instances, args.Error(1)
+}
+
+func (m *MockStateRepo) ListSteps(ctx context.Context, orderID string) ([]sagaEntity.StepRecord, error) {
+	args := m.Called(ctx, orderID)
+	steps, _ := args.Get(0).([]sagaEntity.StepRecord)
+	return steps, args.Error(1)
+}
+
+func (m *MockStateRepo) TransitionStatus(ctx context.Context, instance sagaEntity.Instance, to sagaEntity.Status, errMsg string) (bool, error) {
+	args := m.Called(ctx, instance, to, errMsg)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockStateRepo) ListTimeline(ctx context.Context, orderID string) ([]sagaEntity.TimelineEvent, error) {
+	args := m.Called(ctx, orderID)
+	events, _ := args.Get(0).([]sagaEntity.TimelineEvent)
+	return events, args.Error(1)
+}
+
+type MockAuditRepo struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepo) RecordAction(ctx context.Context, action sagaEntity.AdminAction) error {
+	args := m.Called(ctx, action)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepo) ListActions(ctx context.Context, filter sagaEntity.AdminActionFilter) ([]sagaEntity.AdminAction, error) {
+	args := m.Called(ctx, filter)
+	actions, _ := args.Get(0).([]sagaEntity.AdminAction)
+	return actions, args.Error(1)
+}
+
+type MockOutboxRepo struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepo) GetEvent(ctx context.Context, id int64) (*eventEntity.OutboxEvent, error) {
+	args := m.Called(ctx, id)
+	event, _ := args.Get(0).(*eventEntity.OutboxEvent)
+	return event, args.Error(1)
+}
+
+func (m *MockOutboxRepo) ListDeadEvents(ctx context.Context, limit int) ([]eventEntity.OutboxEvent, error) {
+	args := m.Called(ctx, limit)
+	events, _ := args.Get(0).([]eventEntity.OutboxEvent)
+	return events, args.Error(1)
+}
+
+func (m *MockOutboxRepo) RequeueEvent(ctx context.Context, id int64) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+const stuckAfter = 5 * time.Minute
+
+type mocks struct {
+	runner *MockSagaRunner
+	state  *MockStateRepo
+	audit  *MockAuditRepo
+	outbox *MockOutboxRepo
+}
+
+func newService() (*Service, mocks) {
+	m := mocks{runner: new(MockSagaRunner), state: new(MockStateRepo), audit: new(MockAuditRepo), outbox: new(MockOutboxRepo)}
+	return New(m.runner, m.state, m.audit, m.outbox, stuckAfter, zap.NewNop().Sugar()), m
+}
+
+func failedSaga() *sagaEntity.Instance {
+	return &sagaEntity.Instance{
+		OrderID:     "order-1",
+		UserID:      1,
+		Total:       100,
+		Status:      sagaEntity.StatusFailed,
+		CurrentStep: sagaEntity.StepProductsCommit,
+		StepStatus:  sagaEntity.StepFailed,
+		Error:       "products commit failed",
+		UpdatedAt:   time.Now(),
+	}
+}
+
+func auditEntry(action sagaEntity.AdminActionType, result sagaEntity.AdminActionResult) interface{} {
+	return mock.MatchedBy(func(a sagaEntity.AdminAction) bool {
+		return a.OrderID == "order-1" && a.Action == action && a.Result == result && a.Operator == "alice"
+	})
+}
+
+func TestService_Retry(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		service, m := newService()
+		saga := failedSaga()
+		completed := *saga
+		completed.Status = sagaEntity.StatusCompleted
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(saga, nil).Once()
+		m.state.On("ListSteps", mock.Anything, "order-1").Return([]sagaEntity.StepRecord{
+			{Step: sagaEntity.StepProductsCommit, Status: sagaEntity.StepFailed},
+			{Step: sagaEntity.StepWalletCommit, Status: sagaEntity.StepCompensationFailed},
+		}, nil)
+		m.state.On("TransitionStatus", mock.Anything, *saga, sagaEntity.StatusRunning, "").Return(true, nil)
+		m.runner.On("Retry", mock.Anything, mock.MatchedBy(func(i sagaEntity.Instance) bool {
+			return i.OrderID == "order-1" && i.Status == sagaEntity.StatusRunning
+		})).Return(nil)
+		m.audit.On("RecordAction", mock.Anything, auditEntry(sagaEntity.ActionRetry, sagaEntity.ActionSucceeded)).Return(nil)
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(&completed, nil).Once()
+
+		instance, err := service.Retry(context.Background(), "order-1", "alice", "stock replenished")
+
+		assert.NoError(t, err)
+		assert.Equal(t, sagaEntity.StatusCompleted, instance.Status)
+		m.state.AssertExpectations(t)
+		m.runner.AssertExpectations(t)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("Saga Failed Again", func(t *testing.T) {
+		service, m := newService()
+		saga := failedSaga()
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(saga, nil)
+		m.state.On("ListSteps", mock.Anything, "order-1").Return([]sagaEntity.StepRecord(nil), nil)
+		m.state.On("TransitionStatus", mock.Anything, mock.Anything, sagaEntity.StatusRunning, "").Return(true, nil)
+		m.runner.On("Retry", mock.Anything, mock.Anything).Return(errors.New("products commit failed"))
+		m.audit.On("RecordAction", mock.Anything, mock.MatchedBy(func(a sagaEntity.AdminAction) bool {
+			return a.Result == sagaEntity.ActionFailed && a.Error == "products commit failed"
+		})).Return(nil)
+
+		instance, err := service.Retry(context.Background(), "order-1", "alice", "")
+
+		// Действие выполнено, итог виден по статусу саги
+		assert.NoError(t, err)
+		assert.NotNil(t, instance)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("Compensated Saga Rejected", func(t *testing.T) {
+		service, m := newService()
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(failedSaga(), nil)
+		m.state.On("ListSteps", mock.Anything, "order-1").Return([]sagaEntity.StepRecord{
+			{Step: sagaEntity.StepWalletReserve, Status: sagaEntity.StepCompensated},
+		}, nil)
+		m.audit.On("RecordAction", mock.Anything, auditEntry(sagaEntity.ActionRetry, sagaEntity.ActionRejected)).Return(nil)
+
+		_, err := service.Retry(context.Background(), "order-1", "alice", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrSagaCompensated)
+		m.state.AssertNotCalled(t, "TransitionStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		m.runner.AssertNotCalled(t, "Retry", mock.Anything, mock.Anything)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("Running Saga Not Stuck Yet", func(t *testing.T) {
+		service, m := newService()
+		saga := failedSaga()
+		saga.Status = sagaEntity.StatusRunning
+		saga.UpdatedAt = time.Now().Add(-time.Minute)
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(saga, nil)
+		m.audit.On("RecordAction", mock.Anything, auditEntry(sagaEntity.ActionRetry, sagaEntity.ActionRejected)).Return(nil)
+
+		_, err := service.Retry(context.Background(), "order-1", "alice", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrSagaInProgress)
+		m.runner.AssertNotCalled(t, "Retry", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Concurrent Modification", func(t *testing.T) {
+		service, m := newService()
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(failedSaga(), nil)
+		m.state.On("ListSteps", mock.Anything, "order-1").Return([]sagaEntity.StepRecord(nil), nil)
+		m.state.On("TransitionStatus", mock.Anything, mock.Anything, sagaEntity.StatusRunning, "").Return(false, nil)
+		m.audit.On("RecordAction", mock.Anything, auditEntry(sagaEntity.ActionRetry, sagaEntity.ActionRejected)).Return(nil)
+
+		_, err := service.Retry(context.Background(), "order-1", "alice", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrSagaModified)
+		m.runner.AssertNotCalled(t, "Retry", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		service, m := newService()
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(nil, apperrors.ErrSagaNotFound)
+		m.audit.On("RecordAction", mock.Anything, auditEntry(sagaEntity.ActionRetry, sagaEntity.ActionRejected)).Return(nil)
+
+		_, err := service.Retry(context.Background(), "order-1", "alice", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrSagaNotFound)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("Operator Required", func(t *testing.T) {
+		service, m := newService()
+
+		_, err := service.Retry(context.Background(), "order-1", "", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrOperatorRequired)
+		m.state.AssertNotCalled(t, "GetSaga", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Compensate(t *testing.T) {
+	t.Run("Keeps Original Reason", func(t *testing.T) {
+		service, m := newService()
+		saga := failedSaga()
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(saga, nil)
+		m.state.On("TransitionStatus", mock.Anything, *saga, sagaEntity.StatusCompensating, "products commit failed").Return(true, nil)
+		m.runner.On("Compensate", mock.Anything, mock.Anything, mock.MatchedBy(func(cause error) bool {
+			return cause.Error() == "products commit failed"
+		})).Return(nil)
+		m.audit.On("RecordAction", mock.Anything, auditEntry(sagaEntity.ActionCompensate, sagaEntity.ActionSucceeded)).Return(nil)
+
+		_, err := service.Compensate(context.Background(), "order-1", "alice", "refund by hand")
+
+		assert.NoError(t, err)
+		m.runner.AssertExpectations(t)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("Stuck Saga Without Error", func(t *testing.T) {
+		service, m := newService()
+		saga := failedSaga()
+		saga.Status = sagaEntity.StatusCompensating
+		saga.Error = ""
+		saga.UpdatedAt = time.Now().Add(-time.Hour)
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(saga, nil)
+		m.state.On("TransitionStatus", mock.Anything, mock.Anything, sagaEntity.StatusCompensating, "").Return(true, nil)
+		m.runner.On("Compensate", mock.Anything, mock.Anything, ErrCompensatedByOperator).Return(errors.New("wallet_commit: unavailable"))
+		m.audit.On("RecordAction", mock.Anything, auditEntry(sagaEntity.ActionCompensate, sagaEntity.ActionFailed)).Return(nil)
+
+		_, err := service.Compensate(context.Background(), "order-1", "alice", "")
+
+		assert.NoError(t, err)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("Completed Saga Rejected", func(t *testing.T) {
+		service, m := newService()
+		saga := failedSaga()
+		saga.Status = sagaEntity.StatusCompleted
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(saga, nil)
+		m.audit.On("RecordAction", mock.Anything, auditEntry(sagaEntity.ActionCompensate, sagaEntity.ActionRejected)).Return(nil)
+
+		_, err := service.Compensate(context.Background(), "order-1", "alice", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrSagaFinished)
+		m.runner.AssertNotCalled(t, "Compensate", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Resolve(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		service, m := newService()
+		saga := failedSaga()
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(saga, nil)
+		m.state.On("TransitionStatus", mock.Anything, *saga, sagaEntity.StatusResolved, "products commit failed").Return(true, nil)
+		m.audit.On("RecordAction", mock.Anything, mock.MatchedBy(func(a sagaEntity.AdminAction) bool {
+			return a.Action == sagaEntity.ActionResolve && a.Result == sagaEntity.ActionSucceeded && a.Reason == "refunded manually"
+		})).Return(nil)
+
+		_, err := service.Resolve(context.Background(), "order-1", "alice", "refunded manually")
+
+		assert.NoError(t, err)
+		m.state.AssertExpectations(t)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("Reason Required", func(t *testing.T) {
+		service, m := newService()
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(failedSaga(), nil)
+		m.audit.On("RecordAction", mock.Anything, auditEntry(sagaEntity.ActionResolve, sagaEntity.ActionRejected)).Return(nil)
+
+		_, err := service.Resolve(context.Background(), "order-1", "alice", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrReasonRequired)
+		m.state.AssertNotCalled(t, "TransitionStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Audit Failure Does Not Fail Action", func(t *testing.T) {
+		service, m := newService()
+
+		m.state.On("GetSaga", mock.Anything, "order-1").Return(failedSaga(), nil)
+		m.state.On("TransitionStatus", mock.Anything, mock.Anything, sagaEntity.StatusResolved, mock.Anything).Return(true, nil)
+		m.audit.On("RecordAction", mock.Anything, mock.Anything).Return(errors.New("db down"))
+
+		_, err := service.Resolve(context.Background(), "order-1", "alice", "duplicate order")
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestService_ListSagas(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{"Default Limit", 0, DefaultListLimit},
+		{"Custom Limit", 10, 10},
+		{"Capped Limit", 10000, MaxListLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newService()
+			filter := sagaEntity.SagaFilter{Status: sagaEntity.StatusFailed, OlderThan: time.Hour, Limit: tt.limit}
+
+			m.state.On("ListSagas", mock.Anything, sagaEntity.SagaFilter{
+				Status:    sagaEntity.StatusFailed,
+				OlderThan: time.Hour,
+				Limit:     tt.want,
+			}).Return([]sagaEntity.Instance{*failedSaga()}, nil)
+
+			instances, err := service.ListSagas(context.Background(), filter)
+
+			assert.NoError(t, err)
+			assert.Len(t, instances, 1)
+			m.state.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_OrderTimeline(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		service, m := newService()
+		events := []sagaEntity.TimelineEvent{
+			{ID: 1, OrderID: "order-1", SagaID: "order-1", Kind: sagaEntity.KindCheckout, Type: sagaEntity.TimelineSagaStatus, Status: string(sagaEntity.StatusRunning)},
+			{ID: 2, OrderID: "order-1", SagaID: "order-1", Kind: sagaEntity.KindCheckout, Type: sagaEntity.TimelineStep, Step: sagaEntity.StepWalletReserve, Status: string(sagaEntity.StepStarted)},
+		}
+		m.state.On("ListTimeline", mock.Anything, "order-1").Return(events, nil)
+
+		timeline, err := service.OrderTimeline(context.Background(), "order-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, events, timeline)
+	})
+
+	t.Run("No Sagas For Order", func(t *testing.T) {
+		service, m := newService()
+		m.state.On("ListTimeline", mock.Anything, "order-1").Return(nil, nil)
+
+		_, err := service.OrderTimeline(context.Background(), "order-1")
+
+		assert.ErrorIs(t, err, apperrors.ErrSagaNotFound)
+	})
+
+	t.Run("Repository Error", func(t *testing.T) {
+		service, m := newService()
+		m.state.On("ListTimeline", mock.Anything, "order-1").Return(nil, errors.New("db down"))
+
+		_, err := service.OrderTimeline(context.Background(), "order-1")
+
+		assert.EqualError(t, err, "db down")
+	})
+}
+
+func deadEvent() *eventEntity.OutboxEvent {
+	return &eventEntity.OutboxEvent{
+		ID:          42,
+		AggregateID: "order-1",
+		EventType:   eventEntity.EventTypeOrderCompleted,
+		Status:      eventEntity.OutboxDead,
+		Attempts:    10,
+		LastError:   "kafka flush timeout",
+	}
+}
+
+func TestService_RequeueEvent(t *testing.T) {
+	requeueEntry := func(result sagaEntity.AdminActionResult) interface{} {
+		return mock.MatchedBy(func(a sagaEntity.AdminAction) bool {
+			return a.Action == sagaEntity.ActionRequeueEvent && a.EventID == 42 && a.Result == result && a.Operator == "alice"
+		})
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		service, m := newService()
+		requeued := deadEvent()
+		requeued.Status = eventEntity.OutboxPending
+		requeued.Attempts = 0
+
+		m.outbox.On("GetEvent", mock.Anything, int64(42)).Return(deadEvent(), nil).Once()
+		m.outbox.On("RequeueEvent", mock.Anything, int64(42)).Return(true, nil)
+		m.audit.On("RecordAction", mock.Anything, mock.MatchedBy(func(a sagaEntity.AdminAction) bool {
+			return a.OrderID == "order-1" && a.EventID == 42 && a.Result == sagaEntity.ActionSucceeded
+		})).Return(nil)
+		m.outbox.On("GetEvent", mock.Anything, int64(42)).Return(requeued, nil).Once()
+
+		event, err := service.RequeueEvent(context.Background(), 42, "alice", "kafka is back")
+
+		assert.NoError(t, err)
+		assert.Equal(t, eventEntity.OutboxPending, event.Status)
+		m.outbox.AssertExpectations(t)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("Event Not Dead", func(t *testing.T) {
+		service, m := newService()
+		event := deadEvent()
+		event.Status = eventEntity.OutboxFailed
+
+		m.outbox.On("GetEvent", mock.Anything, int64(42)).Return(event, nil)
+		m.audit.On("RecordAction", mock.Anything, requeueEntry(sagaEntity.ActionRejected)).Return(nil)
+
+		_, err := service.RequeueEvent(context.Background(), 42, "alice", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrEventNotDead)
+		m.outbox.AssertNotCalled(t, "RequeueEvent", mock.Anything, mock.Anything)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("Requeued Concurrently", func(t *testing.T) {
+		service, m := newService()
+
+		m.outbox.On("GetEvent", mock.Anything, int64(42)).Return(deadEvent(), nil)
+		m.outbox.On("RequeueEvent", mock.Anything, int64(42)).Return(false, nil)
+		m.audit.On("RecordAction", mock.Anything, requeueEntry(sagaEntity.ActionRejected)).Return(nil)
+
+		_, err := service.RequeueEvent(context.Background(), 42, "alice", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrEventNotDead)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		service, m := newService()
+
+		m.outbox.On("GetEvent", mock.Anything, int64(42)).Return(nil, apperrors.ErrEventNotFound)
+		m.audit.On("RecordAction", mock.Anything, requeueEntry(sagaEntity.ActionRejected)).Return(nil)
+
+		_, err := service.RequeueEvent(context.Background(), 42, "alice", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrEventNotFound)
+		m.audit.AssertExpectations(t)
+	})
+
+	t.Run("Operator Required", func(t *testing.T) {
+		service, m := newService()
+
+		_, err := service.RequeueEvent(context.Background(), 42, "", "")
+
+		assert.ErrorIs(t, err, apperrors.ErrOperatorRequired)
+		m.outbox.AssertNotCalled(t, "GetEvent", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_ListDeadEvents(t *testing.T) {
+	service, m := newService()
+
+	m.outbox.On("ListDeadEvents", mock.Anything, DefaultListLimit).Return([]eventEntity.OutboxEvent{*deadEvent()}, nil)
+
+	events, err := service.ListDeadEvents(context.Background(), 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, events, 1)
+	m.outbox.AssertExpectations(t)
+}
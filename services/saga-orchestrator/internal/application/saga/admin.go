@@ -0,0 +1,28 @@
+package saga
+
+import (
+	"context"
+
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+)
+
+// Retry по команде оператора выполняет сохранённую сагу дальше с шага, на котором она остановилась.
+// Ошибка - сага снова упала и была откачена.
+func (o *Orchestrator) Retry(ctx context.Context, instance sagaEntity.Instance) error {
+	o.inflight.Add(1)
+	defer o.inflight.Done()
+	// Как и в StartSaga, отмена запроса оператора не должна обрывать сагу на середине
+	sagaCtx, cancel := o.detach(ctx)
+	defer cancel()
+	return o.engine.Continue(sagaCtx, o.definition(instance.Kind), instance)
+}
+
+// Compensate по команде оператора откатывает сохранённую сагу с шага, на котором она остановилась.
+// Ошибка - часть шагов откатить не удалось, подробности в истории шагов.
+func (o *Orchestrator) Compensate(ctx context.Context, instance sagaEntity.Instance, cause error) error {
+	o.inflight.Add(1)
+	defer o.inflight.Done()
+	sagaCtx, cancel := o.detach(ctx)
+	defer cancel()
+	return o.engine.Rollback(sagaCtx, o.definition(instance.Kind), instance, cause)
+}
@@ -0,0 +1,388 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/metrics"
+	"go.uber.org/zap"
+)
+
+// ErrNoCompensation - шагу нечего откатывать, движок просто переходит к предыдущему
+var ErrNoCompensation = errors.New("step has no compensation")
+
+// ErrSuspended - сага остановлена без отката: ctx отменили посреди шага или шаг после Pivot
+// не прошёл за RetryTimeout. Сага остаётся RUNNING, и её доводит recovery - вперёд,
+// если она дошла до Pivot, иначе откатом.
+var ErrSuspended = errors.New("saga suspended")
+
+// Step - шаг саги: прямое действие и компенсирующее его действие.
+// Оба должны быть идемпотентны: после рестарта движок может повторить любой из них.
+type Step interface {
+	Name() sagaEntity.StepName
+	Execute(ctx context.Context, order orderEntity.OrderEvent) error
+	Compensate(ctx context.Context, order orderEntity.OrderEvent) error
+}
+
+// StepFuncs описывает шаг набором функций
+type StepFuncs struct {
+	StepName sagaEntity.StepName
+	// ErrMsg - префикс ошибки, которую вернёт Run, если шаг упал
+	ErrMsg      string
+	ExecuteFunc func(ctx context.Context, order orderEntity.OrderEvent) error
+	// CompensateFunc == nil - шагу нечего откатывать
+	CompensateFunc func(ctx context.Context, order orderEntity.OrderEvent) error
+	// CompensateOnFailure - откатывать и сам упавший шаг: эффект мог примениться,
+	// хотя вызов вернул ошибку (например, ответ потерялся по таймауту)
+	CompensateOnFailure bool
+}
+
+func (s StepFuncs) Name() sagaEntity.StepName {
+	return s.StepName
+}
+
+func (s StepFuncs) Execute(ctx context.Context, order orderEntity.OrderEvent) error {
+	return s.ExecuteFunc(ctx, order)
+}
+
+func (s StepFuncs) Compensate(ctx context.Context, order orderEntity.OrderEvent) error {
+	if s.CompensateFunc == nil {
+		return ErrNoCompensation
+	}
+	return s.CompensateFunc(ctx, order)
+}
+
+func (s StepFuncs) FailureMessage() string {
+	return s.ErrMsg
+}
+
+func (s StepFuncs) CompensatesOwnFailure() bool {
+	return s.CompensateOnFailure
+}
+
+// Definition - сага как упорядоченный список шагов
+type Definition struct {
+	Steps []Step
+	// Pivot - точка невозврата: если этот шаг уже начался, после рестарта
+	// сагу доводят вперёд, а не откатывают. Пусто - откатывать всегда.
+	Pivot sagaEntity.StepName
+	// OnFailure вызывается после отката, до того как сага станет FAILED,
+	// поэтому после рестарта может быть вызван повторно
+	OnFailure func(ctx context.Context, order orderEntity.OrderEvent, cause error) error
+	// Transient отличает временный сбой шага от отказа. Шаг с Pivot и после него при временном
+	// сбое не откатывается, а повторяется раз в RetryInterval: ответ мог потеряться уже после
+	// того, как деньги или товары списались. nil - любая ошибка шага ведёт к откату.
+	Transient     func(err error) bool
+	RetryInterval time.Duration
+	// RetryTimeout - сколько повторять такой шаг, прежде чем приостановить сагу до recovery;
+	// 0 - повторять, пока не отменят ctx
+	RetryTimeout time.Duration
+}
+
+// Index возвращает позицию шага или -1. Для ветки параллельного шага возвращается позиция группы:
+// так находятся и саги, записанные до того, как шаги объединили в группу.
+func (d Definition) Index(name sagaEntity.StepName) int {
+	for i, step := range d.Steps {
+		if step.Name() == name {
+			return i
+		}
+		if group, ok := step.(Parallel); ok && group.branch(name) >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// StateRecorder - куда движок пишет переходы шагов и статус саги
+type StateRecorder interface {
+	RecordStep(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error
+	UpdateStatus(ctx context.Context, orderID string, status sagaEntity.Status, errMsg string) error
+	// RecordBranch пишет переход ветки параллельного шага только в историю, current_step не меняется
+	RecordBranch(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error
+}
+
+// StepInfo - шаг саги, внутри которого сделан вызов
+type StepInfo struct {
+	SagaID string
+	Step   sagaEntity.StepName
+}
+
+type stepKey struct{}
+
+// StepFromContext возвращает шаг, который движок выполняет или откатывает в ctx.
+// false - вызов сделан вне шага саги.
+func StepFromContext(ctx context.Context) (StepInfo, bool) {
+	info, ok := ctx.Value(stepKey{}).(StepInfo)
+	return info, ok
+}
+
+func withStep(ctx context.Context, sagaID string, step sagaEntity.StepName) context.Context {
+	return context.WithValue(ctx, stepKey{}, StepInfo{SagaID: sagaID, Step: step})
+}
+
+// Engine выполняет саги по их Definition и сам откатывает их при ошибке
+type Engine struct {
+	state  StateRecorder
+	logger *zap.SugaredLogger
+}
+
+func New(state StateRecorder, logger *zap.SugaredLogger) *Engine {
+	return &Engine{state: state, logger: logger}
+}
+
+// Run выполняет шаги начиная с from. Если шаг упал, уже выполненные шаги
+// компенсируются в обратном порядке, сага становится FAILED, а ошибка шага возвращается.
+// Приостановленная сага (ErrSuspended) не откатывается и остаётся RUNNING.
+func (e *Engine) Run(ctx context.Context, def Definition, order orderEntity.OrderEvent, from int) error {
+	for i := from; i < len(def.Steps); i++ {
+		step := def.Steps[i]
+		e.recordStep(ctx, order.SagaID(), step.Name(), sagaEntity.StepStarted, nil)
+		if err := e.executeForward(ctx, def, i, step, order); err != nil {
+			// Отменённый ctx - остановка сервиса, а не отказ шага: откат с ним тоже не пройдёт
+			if ctx.Err() != nil && !errors.Is(err, ErrSuspended) {
+				err = fmt.Errorf("%w: %w", ErrSuspended, err)
+			}
+			if errors.Is(err, ErrSuspended) {
+				e.logger.Warnw("Saga suspended, recovery will finish it", "step", step.Name(), "orderID", order.OrderID, "error", err)
+				return fmt.Errorf("%s: %w", failureMessage(step), err)
+			}
+			e.logger.Errorw("Saga step failed", "error", err, "step", step.Name(), "orderID", order.OrderID, "userID", order.UserID)
+			e.recordStep(ctx, order.SagaID(), step.Name(), sagaEntity.StepFailed, err)
+			last := lastToCompensate(def, i, sagaEntity.StepFailed)
+			if _, ok := step.(Parallel); ok {
+				// Свои ветки упавшая группа уже откатила сама
+				last = i - 1
+			}
+			//nolint:errcheck // ошибки компенсации уже записаны в историю шагов
+			_ = e.Compensate(ctx, def, order, last, err)
+			return fmt.Errorf("%s: %w", failureMessage(step), err)
+		}
+		e.recordStep(ctx, order.SagaID(), step.Name(), sagaEntity.StepSucceeded, nil)
+	}
+
+	e.updateStatus(ctx, order.SagaID(), sagaEntity.StatusCompleted, nil)
+	return nil
+}
+
+// Compensate переводит сагу в COMPENSATING, откатывает шаги с last по первый и фиксирует FAILED.
+// Ошибка компенсации не останавливает откат остальных шагов - она записывается в историю
+// и возвращается вместе с остальными.
+func (e *Engine) Compensate(ctx context.Context, def Definition, order orderEntity.OrderEvent, last int, cause error) error {
+	e.updateStatus(ctx, order.SagaID(), sagaEntity.StatusCompensating, cause)
+	e.logger.Infow("Starting rollback", "orderID", order.OrderID, "fromStep", stepName(def, last))
+
+	var failures []error
+	for i := last; i >= 0; i-- {
+		step := def.Steps[i]
+		err := e.compensate(ctx, step, order)
+		switch {
+		case errors.Is(err, ErrNoCompensation):
+			continue
+		case err != nil:
+			e.logger.Errorw("rollback: step compensation failed", "orderID", order.OrderID, "step", step.Name(), "error", err)
+			e.recordStep(ctx, order.SagaID(), step.Name(), sagaEntity.StepCompensationFailed, err)
+			failures = append(failures, fmt.Errorf("%s: %w", step.Name(), err))
+		default:
+			e.logger.Infow("rollback: step compensated", "orderID", order.OrderID, "step", step.Name())
+			e.recordStep(ctx, order.SagaID(), step.Name(), sagaEntity.StepCompensated, nil)
+		}
+	}
+
+	e.logger.Infow("Rollback completed", "orderID", order.OrderID)
+
+	if def.OnFailure != nil {
+		if err := def.OnFailure(ctx, order, cause); err != nil {
+			e.logger.Errorw("Saga failure handler failed", "orderID", order.OrderID, "error", err)
+			failures = append(failures, fmt.Errorf("on failure: %w", err))
+		}
+	}
+
+	e.updateStatus(ctx, order.SagaID(), sagaEntity.StatusFailed, cause)
+	return errors.Join(failures...)
+}
+
+// Resume доводит до конца сохранённую сагу, прерванную рестартом.
+// RUNNING сага, дошедшая до Pivot, выполняется дальше; остальные откатываются с причиной cause.
+// Ошибка - сага упала при выполнении или откатилась не полностью.
+func (e *Engine) Resume(ctx context.Context, def Definition, instance sagaEntity.Instance, cause error) error {
+	// Сагу с Pivot на первом шаге доводят вперёд, даже если ни один шаг ещё не начался
+	pivot := def.Index(def.Pivot)
+	if instance.Status == sagaEntity.StatusRunning && pivot >= 0 && nextStep(def, instance) >= pivot {
+		e.logger.Infow("Recovery: resuming saga", "orderID", instance.OrderID, "fromStep", instance.CurrentStep)
+		return e.Continue(ctx, def, instance)
+	}
+
+	if def.Index(instance.CurrentStep) < 0 {
+		// Ни один шаг не начинался - откатывать нечего
+		e.logger.Warnw("Recovery: saga has no steps, marking as failed", "orderID", instance.OrderID)
+		e.updateStatus(ctx, instance.OrderID, sagaEntity.StatusFailed, cause)
+		return nil
+	}
+
+	e.logger.Infow("Recovery: compensating saga", "orderID", instance.OrderID, "step", instance.CurrentStep, "status", instance.Status)
+	return e.Rollback(ctx, def, instance, cause)
+}
+
+// Continue выполняет сохранённую сагу дальше с шага, на котором она остановилась:
+// недовыполненный или упавший шаг повторяется, завершённый - нет.
+func (e *Engine) Continue(ctx context.Context, def Definition, instance sagaEntity.Instance) error {
+	return e.Run(ctx, def, orderFromInstance(instance), nextStep(def, instance))
+}
+
+// Rollback откатывает сохранённую сагу начиная с шага, на котором она остановилась.
+// Уже откаченные шаги откатываются повторно - компенсации идемпотентны.
+func (e *Engine) Rollback(ctx context.Context, def Definition, instance sagaEntity.Instance, cause error) error {
+	last := -1
+	if current := def.Index(instance.CurrentStep); current >= 0 {
+		last = lastToCompensate(def, current, instance.StepStatus)
+	}
+	return e.Compensate(ctx, def, orderFromInstance(instance), last, cause)
+}
+
+func orderFromInstance(instance sagaEntity.Instance) orderEntity.OrderEvent {
+	order := orderEntity.OrderEvent{
+		OrderID:  instance.OrderID,
+		UserID:   instance.UserID,
+		Products: instance.Products,
+		Total:    instance.Total,
+	}
+	// Сага возврата хранится под ID возврата, а её шаги работают с исходным заказом
+	if instance.ParentOrderID != "" {
+		order.OrderID = instance.ParentOrderID
+		order.ReturnID = instance.OrderID
+	}
+	return order
+}
+
+// nextStep - шаг, с которого сохранённую сагу нужно выполнять дальше
+func nextStep(def Definition, instance sagaEntity.Instance) int {
+	current := def.Index(instance.CurrentStep)
+	if current < 0 {
+		return 0
+	}
+	// Завершённая ветка ещё не значит, что завершилась вся группа
+	if instance.StepStatus == sagaEntity.StepSucceeded && def.Steps[current].Name() == instance.CurrentStep {
+		return current + 1
+	}
+	return current
+}
+
+// lastToCompensate - последний шаг, который нужно откатить, если шаг current остановился в status.
+// Завершённый шаг откатывается всегда; упавший или недовыполненный - только если он это допускает.
+func lastToCompensate(def Definition, current int, status sagaEntity.StepStatus) int {
+	if status == sagaEntity.StepSucceeded || compensatesOwnFailure(def.Steps[current]) {
+		return current
+	}
+	return current - 1
+}
+
+// executeForward выполняет i-й шаг саги; шаг с Pivot и после него повторяется, пока падает временно,
+// но не дольше RetryTimeout
+func (e *Engine) executeForward(ctx context.Context, def Definition, i int, step Step, order orderEntity.OrderEvent) error {
+	pivot := def.Index(def.Pivot)
+	var deadline <-chan time.Time
+	if def.RetryTimeout > 0 {
+		timer := time.NewTimer(def.RetryTimeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	for {
+		err := e.execute(ctx, step, order)
+		if err == nil || def.Transient == nil || pivot < 0 || i < pivot || !def.Transient(err) {
+			return err
+		}
+		e.logger.Warnw("Saga step after pivot failed transiently, retrying", "step", step.Name(), "orderID", order.OrderID, "error", err)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%w: %w", ErrSuspended, err)
+		case <-deadline:
+			return fmt.Errorf("%w: retries exhausted after %s: %w", ErrSuspended, def.RetryTimeout, err)
+		case <-time.After(def.RetryInterval):
+		}
+	}
+}
+
+// execute выполняет шаг и замеряет его длительность; параллельный шаг движок выполняет по веткам
+func (e *Engine) execute(ctx context.Context, step Step, order orderEntity.OrderEvent) error {
+	start := time.Now()
+	var err error
+	if group, ok := step.(Parallel); ok {
+		err = e.executeParallel(ctx, group, order)
+	} else {
+		err = step.Execute(withStep(ctx, order.SagaID(), step.Name()), order)
+	}
+	observeStep(step.Name(), metrics.PhaseExecute, start, err)
+	return err
+}
+
+// compensate откатывает шаг и замеряет длительность отката; у параллельного шага откатываются все ветки
+func (e *Engine) compensate(ctx context.Context, step Step, order orderEntity.OrderEvent) error {
+	start := time.Now()
+	var err error
+	if group, ok := step.(Parallel); ok {
+		err = e.compensateBranches(ctx, group.Branches, order)
+	} else {
+		err = step.Compensate(withStep(ctx, order.SagaID(), step.Name()), order)
+	}
+	if !errors.Is(err, ErrNoCompensation) {
+		observeStep(step.Name(), metrics.PhaseCompensate, start, err)
+	}
+	return err
+}
+
+func observeStep(step sagaEntity.StepName, phase string, start time.Time, err error) {
+	outcome := metrics.OutcomeSucceeded
+	if err != nil {
+		outcome = metrics.OutcomeFailed
+	}
+	metrics.SagaStepDuration.WithLabelValues(string(step), phase, outcome).Observe(time.Since(start).Seconds())
+}
+
+func failureMessage(step Step) string {
+	if s, ok := step.(interface{ FailureMessage() string }); ok && s.FailureMessage() != "" {
+		return s.FailureMessage()
+	}
+	return string(step.Name()) + " failed"
+}
+
+func compensatesOwnFailure(step Step) bool {
+	s, ok := step.(interface{ CompensatesOwnFailure() bool })
+	return ok && s.CompensatesOwnFailure()
+}
+
+func stepName(def Definition, i int) sagaEntity.StepName {
+	if i < 0 || i >= len(def.Steps) {
+		return ""
+	}
+	return def.Steps[i].Name()
+}
+
+// recordStep пишет переход шага; ошибка записи не должна ломать уже идущую сагу
+func (e *Engine) recordStep(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, stepErr error) {
+	if err := e.state.RecordStep(ctx, orderID, step, status, errorMessage(stepErr)); err != nil {
+		e.logger.Errorw("Failed to record saga step", "error", err, "orderID", orderID, "step", step, "status", status)
+	}
+}
+
+func (e *Engine) recordBranch(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, stepErr error) {
+	if err := e.state.RecordBranch(ctx, orderID, step, status, errorMessage(stepErr)); err != nil {
+		e.logger.Errorw("Failed to record saga branch", "error", err, "orderID", orderID, "step", step, "status", status)
+	}
+}
+
+func (e *Engine) updateStatus(ctx context.Context, orderID string, status sagaEntity.Status, cause error) {
+	if err := e.state.UpdateStatus(ctx, orderID, status, errorMessage(cause)); err != nil {
+		e.logger.Errorw("Failed to update saga status", "error", err, "orderID", orderID, "status", status)
+	}
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,513 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"go.uber.org/zap"
+)
+
+type MockStateRecorder struct {
+	mock.Mock
+}
+
+func (m *MockStateRecorder) RecordStep(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error {
+	args := m.Called(ctx, orderID, step, status, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockStateRecorder) RecordBranch(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error {
+	args := m.Called(ctx, orderID, step, status, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockStateRecorder) UpdateStatus(ctx context.Context, orderID string, status sagaEntity.Status, errMsg string) error {
+	args := m.Called(ctx, orderID, status, errMsg)
+	return args.Error(0)
+}
+
+func newMockState() *MockStateRecorder {
+	state := new(MockStateRecorder)
+	state.On("RecordStep", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	state.On("RecordBranch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	state.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return state
+}
+
+// journal записывает порядок вызовов шагов; ветки параллельного шага пишут в него одновременно
+type journal struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (j *journal) add(call string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.calls = append(j.calls, call)
+}
+
+func (j *journal) step(name string, fail error, compensate bool, onFailure bool) StepFuncs {
+	s := StepFuncs{
+		StepName: sagaEntity.StepName(name),
+		ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+			j.add("execute:" + name)
+			return fail
+		},
+		CompensateOnFailure: onFailure,
+	}
+	if compensate {
+		s.CompensateFunc = func(ctx context.Context, order orderEntity.OrderEvent) error {
+			j.add("compensate:" + name)
+			return nil
+		}
+	}
+	return s
+}
+
+// customStep - шаг, реализующий Step без StepFuncs
+type customStep struct {
+	j *journal
+}
+
+func (s customStep) Name() sagaEntity.StepName { return "fraud_check" }
+
+func (s customStep) Execute(ctx context.Context, order orderEntity.OrderEvent) error {
+	s.j.calls = append(s.j.calls, "execute:fraud_check")
+	return errors.New("fraud suspected")
+}
+
+func (s customStep) Compensate(ctx context.Context, order orderEntity.OrderEvent) error {
+	s.j.calls = append(s.j.calls, "compensate:fraud_check")
+	return nil
+}
+
+func TestEngine_Run(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	order := orderEntity.OrderEvent{OrderID: "order-1", UserID: 1}
+
+	t.Run("Success", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		def := Definition{Steps: []Step{j.step("a", nil, true, false), j.step("b", nil, true, false)}}
+
+		err := New(state, logger).Run(context.Background(), def, order, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"execute:a", "execute:b"}, j.calls)
+		state.AssertCalled(t, "UpdateStatus", mock.Anything, "order-1", sagaEntity.StatusCompleted, "")
+	})
+
+	t.Run("Compensates Completed Steps In Reverse Order", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		def := Definition{Steps: []Step{
+			j.step("a", nil, true, false),
+			j.step("b", nil, false, false), // нечего откатывать
+			j.step("c", nil, true, false),
+			j.step("d", errors.New("boom"), true, false),
+		}}
+
+		err := New(state, logger).Run(context.Background(), def, order, 0)
+
+		assert.EqualError(t, err, "d failed: boom")
+		assert.Equal(t, []string{"execute:a", "execute:b", "execute:c", "execute:d", "compensate:c", "compensate:a"}, j.calls)
+		state.AssertCalled(t, "RecordStep", mock.Anything, "order-1", sagaEntity.StepName("c"), sagaEntity.StepCompensated, "")
+		state.AssertNotCalled(t, "RecordStep", mock.Anything, "order-1", sagaEntity.StepName("b"), sagaEntity.StepCompensated, "")
+		state.AssertCalled(t, "UpdateStatus", mock.Anything, "order-1", sagaEntity.StatusFailed, "boom")
+	})
+
+	t.Run("Compensates Failed Step When Allowed", func(t *testing.T) {
+		j := &journal{}
+		def := Definition{Steps: []Step{
+			j.step("a", nil, true, false),
+			StepFuncs{
+				StepName:    "b",
+				ErrMsg:      "b reserve failed",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error { return errors.New("timeout") },
+				CompensateFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					j.calls = append(j.calls, "compensate:b")
+					return nil
+				},
+				CompensateOnFailure: true,
+			},
+		}}
+
+		err := New(newMockState(), logger).Run(context.Background(), def, order, 0)
+
+		assert.EqualError(t, err, "b reserve failed: timeout")
+		assert.Equal(t, []string{"execute:a", "compensate:b", "compensate:a"}, j.calls)
+	})
+
+	t.Run("Compensation Failure Does Not Stop Rollback", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		def := Definition{Steps: []Step{
+			j.step("a", nil, true, false),
+			StepFuncs{
+				StepName:       "b",
+				ExecuteFunc:    func(ctx context.Context, order orderEntity.OrderEvent) error { return nil },
+				CompensateFunc: func(ctx context.Context, order orderEntity.OrderEvent) error { return errors.New("unavailable") },
+			},
+			j.step("c", errors.New("boom"), true, false),
+		}}
+
+		err := New(state, logger).Run(context.Background(), def, order, 0)
+
+		assert.Error(t, err)
+		assert.Equal(t, []string{"execute:a", "execute:c", "compensate:a"}, j.calls)
+		state.AssertCalled(t, "RecordStep", mock.Anything, "order-1", sagaEntity.StepName("b"), sagaEntity.StepCompensationFailed, "unavailable")
+	})
+
+	t.Run("Failure Handler Runs After Rollback", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		def := Definition{
+			Steps: []Step{j.step("a", nil, true, false), j.step("b", errors.New("boom"), false, false)},
+			OnFailure: func(ctx context.Context, order orderEntity.OrderEvent, cause error) error {
+				j.calls = append(j.calls, "on_failure:"+cause.Error())
+				return nil
+			},
+		}
+
+		err := New(state, logger).Run(context.Background(), def, order, 0)
+
+		assert.Error(t, err)
+		assert.Equal(t, []string{"execute:a", "execute:b", "compensate:a", "on_failure:boom"}, j.calls)
+	})
+
+	t.Run("Failure Handler Error Is Reported By Compensate", func(t *testing.T) {
+		j := &journal{}
+		def := Definition{
+			Steps: []Step{j.step("a", nil, true, false)},
+			OnFailure: func(ctx context.Context, order orderEntity.OrderEvent, cause error) error {
+				return errors.New("outbox unavailable")
+			},
+		}
+
+		err := New(newMockState(), logger).Compensate(context.Background(), def, order, 0, errors.New("boom"))
+
+		assert.ErrorContains(t, err, "outbox unavailable")
+	})
+
+	t.Run("Custom Step Implementation", func(t *testing.T) {
+		j := &journal{}
+		def := Definition{Steps: []Step{j.step("a", nil, true, false), customStep{j: j}}}
+
+		err := New(newMockState(), logger).Run(context.Background(), def, order, 0)
+
+		assert.EqualError(t, err, "fraud_check failed: fraud suspected")
+		assert.Equal(t, []string{"execute:a", "execute:fraud_check", "compensate:a"}, j.calls)
+	})
+}
+
+func TestEngine_Resume(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	instance := func(status sagaEntity.Status, step string, stepStatus sagaEntity.StepStatus) sagaEntity.Instance {
+		return sagaEntity.Instance{OrderID: "order-1", Status: status, CurrentStep: sagaEntity.StepName(step), StepStatus: stepStatus}
+	}
+	definition := func(j *journal) Definition {
+		return Definition{
+			Pivot: "c",
+			Steps: []Step{
+				j.step("a", nil, true, true),
+				j.step("b", nil, true, true),
+				j.step("c", nil, true, false),
+				j.step("d", nil, false, false),
+			},
+		}
+	}
+	cause := errors.New("restart")
+
+	tests := []struct {
+		name     string
+		instance sagaEntity.Instance
+		expected []string
+	}{
+		{"Before Pivot Compensates Started Step", instance(sagaEntity.StatusRunning, "b", sagaEntity.StepStarted), []string{"compensate:b", "compensate:a"}},
+		{"Before Pivot Compensates Completed Steps", instance(sagaEntity.StatusRunning, "a", sagaEntity.StepSucceeded), []string{"compensate:a"}},
+		{"Pivot Started Runs Forward", instance(sagaEntity.StatusRunning, "c", sagaEntity.StepStarted), []string{"execute:c", "execute:d"}},
+		{"After Pivot Runs Forward", instance(sagaEntity.StatusRunning, "c", sagaEntity.StepSucceeded), []string{"execute:d"}},
+		{"Compensating Saga Skips Failed Step", instance(sagaEntity.StatusCompensating, "d", sagaEntity.StepFailed), []string{"compensate:c", "compensate:b", "compensate:a"}},
+		{"No Steps Started", instance(sagaEntity.StatusRunning, "", ""), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := &journal{}
+			state := newMockState()
+
+			err := New(state, logger).Resume(context.Background(), definition(j), tt.instance, cause)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, j.calls)
+		})
+	}
+
+	t.Run("Pivot On First Step Runs Forward Before Any Step", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		def := Definition{
+			Pivot: "a",
+			Steps: []Step{j.step("a", nil, false, false), j.step("b", nil, false, false)},
+		}
+
+		err := New(state, logger).Resume(context.Background(), def, instance(sagaEntity.StatusRunning, "", ""), cause)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"execute:a", "execute:b"}, j.calls)
+		state.AssertCalled(t, "UpdateStatus", mock.Anything, "order-1", sagaEntity.StatusCompleted, "")
+	})
+}
+
+func TestEngine_PivotRetry(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	order := orderEntity.OrderEvent{OrderID: "order-1", UserID: 1}
+	errTransient := errors.New("unavailable")
+	transient := func(err error) bool { return errors.Is(err, errTransient) }
+
+	// flaky - шаг, который первые fails раз падает временно
+	flaky := func(j *journal, name string, fails int) StepFuncs {
+		return StepFuncs{
+			StepName: sagaEntity.StepName(name),
+			ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+				j.add("execute:" + name)
+				if fails > 0 {
+					fails--
+					return errTransient
+				}
+				return nil
+			},
+		}
+	}
+
+	t.Run("Retries Transient Failure After Pivot", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		def := Definition{
+			Pivot:     "b",
+			Transient: transient,
+			Steps:     []Step{j.step("a", nil, true, false), flaky(j, "b", 2)},
+		}
+
+		err := New(state, logger).Run(context.Background(), def, order, 0)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"execute:a", "execute:b", "execute:b", "execute:b"}, j.calls)
+		state.AssertCalled(t, "UpdateStatus", mock.Anything, "order-1", sagaEntity.StatusCompleted, "")
+	})
+
+	t.Run("Transient Failure Before Pivot Compensates", func(t *testing.T) {
+		j := &journal{}
+		def := Definition{
+			Pivot:     "c",
+			Transient: transient,
+			Steps:     []Step{j.step("a", nil, true, false), flaky(j, "b", 1), j.step("c", nil, false, false)},
+		}
+
+		err := New(newMockState(), logger).Run(context.Background(), def, order, 0)
+
+		assert.ErrorIs(t, err, errTransient)
+		assert.Equal(t, []string{"execute:a", "execute:b", "compensate:a"}, j.calls)
+	})
+
+	t.Run("Terminal Failure After Pivot Compensates", func(t *testing.T) {
+		j := &journal{}
+		def := Definition{
+			Pivot:     "b",
+			Transient: transient,
+			Steps:     []Step{j.step("a", nil, true, false), j.step("b", errors.New("reservation expired"), false, false)},
+		}
+
+		err := New(newMockState(), logger).Run(context.Background(), def, order, 0)
+
+		assert.EqualError(t, err, "b failed: reservation expired")
+		assert.Equal(t, []string{"execute:a", "execute:b", "compensate:a"}, j.calls)
+	})
+
+	t.Run("Cancelled Retry Leaves Saga Running", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		ctx, cancel := context.WithCancel(context.Background())
+		step := flaky(j, "b", 1)
+		step.ExecuteFunc = func(ctx context.Context, order orderEntity.OrderEvent) error {
+			j.add("execute:b")
+			cancel()
+			return errTransient
+		}
+		def := Definition{
+			Pivot:         "b",
+			Transient:     transient,
+			RetryInterval: time.Hour,
+			Steps:         []Step{j.step("a", nil, true, false), step},
+		}
+
+		err := New(state, logger).Run(ctx, def, order, 0)
+
+		assert.ErrorIs(t, err, ErrSuspended)
+		assert.Equal(t, []string{"execute:a", "execute:b"}, j.calls)
+		state.AssertNotCalled(t, "RecordStep", mock.Anything, "order-1", sagaEntity.StepName("b"), sagaEntity.StepFailed, mock.Anything)
+		state.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Retries Stop After Retry Timeout", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		def := Definition{
+			Pivot:         "b",
+			Transient:     transient,
+			RetryInterval: time.Millisecond,
+			RetryTimeout:  20 * time.Millisecond,
+			Steps:         []Step{j.step("a", nil, true, false), flaky(j, "b", 1<<30)},
+		}
+
+		err := New(state, logger).Run(context.Background(), def, order, 0)
+
+		assert.ErrorIs(t, err, ErrSuspended)
+		assert.ErrorIs(t, err, errTransient)
+		assert.NotContains(t, j.calls, "compensate:a")
+		state.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Cancelled Step Before Pivot Leaves Saga Running", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		ctx, cancel := context.WithCancel(context.Background())
+		def := Definition{
+			Pivot:     "c",
+			Transient: transient,
+			Steps: []Step{
+				j.step("a", nil, true, false),
+				StepFuncs{
+					StepName: "b",
+					ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+						j.add("execute:b")
+						cancel()
+						return ctx.Err()
+					},
+				},
+				j.step("c", nil, false, false),
+			},
+		}
+
+		err := New(state, logger).Run(ctx, def, order, 0)
+
+		// Откат с отменённым ctx не прошёл бы - его сделает recovery
+		assert.ErrorIs(t, err, ErrSuspended)
+		assert.Equal(t, []string{"execute:a", "execute:b"}, j.calls)
+		state.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestEngine_StepContext(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	var seen []StepInfo
+	record := func(ctx context.Context, order orderEntity.OrderEvent) error {
+		info, ok := StepFromContext(ctx)
+		assert.True(t, ok)
+		seen = append(seen, info)
+		return nil
+	}
+	def := Definition{Steps: []Step{
+		StepFuncs{StepName: "a", ExecuteFunc: record, CompensateFunc: record},
+		StepFuncs{StepName: "b", ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+			return errors.New("boom")
+		}},
+	}}
+	order := orderEntity.OrderEvent{OrderID: "order-1", ReturnID: "return-1"}
+
+	err := New(newMockState(), logger).Run(context.Background(), def, order, 0)
+
+	assert.Error(t, err)
+	assert.Equal(t, []StepInfo{{SagaID: "return-1", Step: "a"}, {SagaID: "return-1", Step: "a"}}, seen)
+
+	_, ok := StepFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func TestEngine_Parallel(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	order := orderEntity.OrderEvent{OrderID: "order-1", UserID: 1}
+
+	t.Run("Runs Branches And Continues", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		def := Definition{Steps: []Step{
+			Parallel{StepName: "reserve", Branches: []Step{j.step("a", nil, true, false), j.step("b", nil, true, false)}},
+			j.step("c", nil, true, false),
+		}}
+
+		err := New(state, logger).Run(context.Background(), def, order, 0)
+
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"execute:a", "execute:b"}, j.calls[:2])
+		assert.Equal(t, "execute:c", j.calls[2])
+		state.AssertCalled(t, "RecordStep", mock.Anything, "order-1", sagaEntity.StepName("reserve"), sagaEntity.StepSucceeded, "")
+		state.AssertCalled(t, "RecordBranch", mock.Anything, "order-1", sagaEntity.StepName("a"), sagaEntity.StepSucceeded, "")
+		state.AssertNotCalled(t, "RecordStep", mock.Anything, "order-1", sagaEntity.StepName("a"), mock.Anything, mock.Anything)
+	})
+
+	t.Run("Compensates Only Applied Branches", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		def := Definition{Steps: []Step{
+			j.step("before", nil, true, false),
+			Parallel{StepName: "reserve", ErrMsg: "reserve failed", Branches: []Step{
+				j.step("a", nil, true, false),
+				j.step("b", errors.New("boom"), true, false), // упавшую ветку без CompensateOnFailure не откатываем
+				j.step("c", errors.New("timeout"), true, true),
+			}},
+		}}
+
+		err := New(state, logger).Run(context.Background(), def, order, 0)
+
+		assert.ErrorContains(t, err, "reserve failed: b failed: boom")
+		assert.ErrorContains(t, err, "c failed: timeout")
+		assert.ElementsMatch(t, []string{"execute:before", "execute:a", "execute:b", "execute:c", "compensate:a", "compensate:c", "compensate:before"}, j.calls)
+		assert.Equal(t, "compensate:before", j.calls[len(j.calls)-1])
+		state.AssertCalled(t, "RecordBranch", mock.Anything, "order-1", sagaEntity.StepName("a"), sagaEntity.StepCompensated, "")
+		state.AssertNotCalled(t, "RecordBranch", mock.Anything, "order-1", sagaEntity.StepName("b"), sagaEntity.StepCompensated, "")
+		state.AssertCalled(t, "RecordStep", mock.Anything, "order-1", sagaEntity.StepName("reserve"), sagaEntity.StepFailed, mock.Anything)
+		state.AssertNotCalled(t, "RecordStep", mock.Anything, "order-1", sagaEntity.StepName("reserve"), sagaEntity.StepCompensated, "")
+	})
+
+	t.Run("Later Failure Compensates All Branches", func(t *testing.T) {
+		j := &journal{}
+		state := newMockState()
+		def := Definition{Steps: []Step{
+			Parallel{StepName: "reserve", Branches: []Step{j.step("a", nil, true, false), j.step("b", nil, false, false)}},
+			j.step("commit", errors.New("boom"), false, false),
+		}}
+
+		err := New(state, logger).Run(context.Background(), def, order, 0)
+
+		assert.EqualError(t, err, "commit failed: boom")
+		assert.Equal(t, "compensate:a", j.calls[len(j.calls)-1])
+		state.AssertCalled(t, "RecordStep", mock.Anything, "order-1", sagaEntity.StepName("reserve"), sagaEntity.StepCompensated, "")
+		state.AssertNotCalled(t, "RecordBranch", mock.Anything, "order-1", sagaEntity.StepName("b"), sagaEntity.StepCompensated, "")
+	})
+
+	t.Run("Branch Name Resolves To Group", func(t *testing.T) {
+		j := &journal{}
+		def := Definition{
+			Pivot: "commit",
+			Steps: []Step{
+				Parallel{StepName: "reserve", Branches: []Step{j.step("a", nil, true, true), j.step("b", nil, true, true)}},
+				j.step("commit", nil, false, false),
+			},
+		}
+		instance := sagaEntity.Instance{OrderID: "order-1", Status: sagaEntity.StatusRunning, CurrentStep: "a", StepStatus: sagaEntity.StepSucceeded}
+
+		err := New(newMockState(), logger).Resume(context.Background(), def, instance, errors.New("restart"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, def.Index("b"))
+		assert.ElementsMatch(t, []string{"compensate:a", "compensate:b"}, j.calls)
+	})
+}
@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+)
+
+// Parallel - независимые шаги (ветки), которые выполняются одновременно как один шаг саги.
+// current_step саги указывает на саму группу, переходы веток пишутся только в историю.
+// Если упала хотя бы одна ветка, движок откатывает ветки, которые выполнились
+// (и упавшие, если они это допускают), и группа считается упавшей.
+type Parallel struct {
+	StepName sagaEntity.StepName
+	// ErrMsg - префикс ошибки, которую вернёт Run, если упала хотя бы одна ветка
+	ErrMsg   string
+	Branches []Step
+}
+
+func (p Parallel) Name() sagaEntity.StepName {
+	return p.StepName
+}
+
+// Execute выполняет ветки без записи их переходов; внутри саги группу выполняет движок
+func (p Parallel) Execute(ctx context.Context, order orderEntity.OrderEvent) error {
+	return joinBranchErrors(p.Branches, runBranches(p.Branches, func(branch Step) error {
+		return branch.Execute(ctx, order)
+	}))
+}
+
+// Compensate откатывает все ветки без записи их переходов; внутри саги группу откатывает движок
+func (p Parallel) Compensate(ctx context.Context, order orderEntity.OrderEvent) error {
+	return compensationResult(p.Branches, runBranches(p.Branches, func(branch Step) error {
+		return branch.Compensate(ctx, order)
+	}))
+}
+
+func (p Parallel) FailureMessage() string {
+	return p.ErrMsg
+}
+
+// CompensatesOwnFailure - после рестарта неизвестно, какие ветки успели выполниться,
+// поэтому недовыполненную группу можно откатить целиком, только если это допускает каждая ветка
+func (p Parallel) CompensatesOwnFailure() bool {
+	for _, branch := range p.Branches {
+		if !compensatesOwnFailure(branch) {
+			return false
+		}
+	}
+	return len(p.Branches) > 0
+}
+
+// branch возвращает позицию ветки с именем name или -1
+func (p Parallel) branch(name sagaEntity.StepName) int {
+	for i, branch := range p.Branches {
+		if branch.Name() == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// executeParallel выполняет ветки группы одновременно. Если какая-то упала, ветки,
+// которые могли примениться, откатываются до возврата ошибки - движку остаётся
+// откатить только шаги до группы.
+func (e *Engine) executeParallel(ctx context.Context, group Parallel, order orderEntity.OrderEvent) error {
+	errs := runBranches(group.Branches, func(branch Step) error {
+		return e.executeBranch(ctx, branch, order)
+	})
+	err := joinBranchErrors(group.Branches, errs)
+	if err == nil {
+		return nil
+	}
+	// При остановке сервиса откат с отменённым ctx не пройдёт - ветки откатит recovery
+	if ctx.Err() != nil {
+		return err
+	}
+
+	var applied []Step
+	for i, branch := range group.Branches {
+		if errs[i] == nil || compensatesOwnFailure(branch) {
+			applied = append(applied, branch)
+		}
+	}
+	e.logger.Infow("Parallel step failed, compensating applied branches", "orderID", order.OrderID, "step", group.Name(), "branches", len(applied))
+	//nolint:errcheck // ошибки компенсации уже записаны в историю веток
+	_ = e.compensateBranches(ctx, applied, order)
+
+	return err
+}
+
+func (e *Engine) executeBranch(ctx context.Context, branch Step, order orderEntity.OrderEvent) error {
+	e.recordBranch(ctx, order.SagaID(), branch.Name(), sagaEntity.StepStarted, nil)
+	err := e.execute(ctx, branch, order)
+	if err != nil {
+		e.logger.Errorw("Saga branch failed", "error", err, "step", branch.Name(), "orderID", order.OrderID)
+		e.recordBranch(ctx, order.SagaID(), branch.Name(), sagaEntity.StepFailed, err)
+		return err
+	}
+	e.recordBranch(ctx, order.SagaID(), branch.Name(), sagaEntity.StepSucceeded, nil)
+	return nil
+}
+
+func (e *Engine) compensateBranches(ctx context.Context, branches []Step, order orderEntity.OrderEvent) error {
+	errs := runBranches(branches, func(branch Step) error {
+		err := e.compensate(ctx, branch, order)
+		switch {
+		case errors.Is(err, ErrNoCompensation):
+		case err != nil:
+			e.logger.Errorw("rollback: branch compensation failed", "orderID", order.OrderID, "step", branch.Name(), "error", err)
+			e.recordBranch(ctx, order.SagaID(), branch.Name(), sagaEntity.StepCompensationFailed, err)
+		default:
+			e.logger.Infow("rollback: branch compensated", "orderID", order.OrderID, "step", branch.Name())
+			e.recordBranch(ctx, order.SagaID(), branch.Name(), sagaEntity.StepCompensated, nil)
+		}
+		return err
+	})
+	return compensationResult(branches, errs)
+}
+
+// runBranches вызывает fn для каждой ветки в своей горутине и ждёт все;
+// i-я ошибка относится к i-й ветке
+func runBranches(branches []Step, fn func(branch Step) error) []error {
+	errs := make([]error, len(branches))
+	var wg sync.WaitGroup
+	for i, branch := range branches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(branch)
+		}()
+	}
+	wg.Wait()
+	return errs
+}
+
+// joinBranchErrors собирает ошибки упавших веток с префиксами их сообщений
+func joinBranchErrors(branches []Step, errs []error) error {
+	var failures []error
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%s: %w", failureMessage(branches[i]), err))
+		}
+	}
+	return errors.Join(failures...)
+}
+
+// compensationResult - ErrNoCompensation, если ни одной ветке нечего откатывать,
+// иначе ошибки откативших с ошибкой веток
+func compensationResult(branches []Step, errs []error) error {
+	var failures []error
+	compensated := false
+	for i, err := range errs {
+		switch {
+		case errors.Is(err, ErrNoCompensation):
+		case err != nil:
+			failures = append(failures, fmt.Errorf("%s: %w", branches[i].Name(), err))
+		default:
+			compensated = true
+		}
+	}
+	if !compensated && len(failures) == 0 {
+		return ErrNoCompensation
+	}
+	return errors.Join(failures...)
+}
@@ -0,0 +1,669 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
+	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/product/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/resilience"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Фейки ниже повторяют семантику настоящих хранилищ wallet и products: идемпотентность по ID заказа,
+// отметки release до резерва и отказы на неверный статус. Без этого харнесс проверял бы сам себя.
+
+const (
+	txnReserved  = "RESERVED"
+	txnCommitted = "COMMITTED"
+	txnReleased  = "RELEASED"
+	txnRefunded  = "REFUNDED"
+)
+
+// errFailedPrecondition - отказ downstream: повтор вернёт то же самое
+func errFailedPrecondition(format string, args ...any) error {
+	return status.Errorf(codes.FailedPrecondition, format, args...)
+}
+
+type walletTxn struct {
+	userID int64
+	amount int64
+	status string
+}
+
+// fakeWallet - кошельки пользователей; balance включает зарезервированные деньги
+type fakeWallet struct {
+	mu       sync.Mutex
+	balance  map[int64]int64
+	reserved map[int64]int64
+	txns     map[string]*walletTxn
+}
+
+func newFakeWallet(balances map[int64]int64) *fakeWallet {
+	w := &fakeWallet{
+		balance:  make(map[int64]int64, len(balances)),
+		reserved: make(map[int64]int64, len(balances)),
+		txns:     make(map[string]*walletTxn),
+	}
+	for userID, balance := range balances {
+		w.balance[userID] = balance
+	}
+	return w
+}
+
+func (w *fakeWallet) replay(txn *walletTxn, userID, amount int64) error {
+	if txn.userID != userID || txn.amount != amount {
+		return errFailedPrecondition("transaction replayed with different parameters")
+	}
+	return nil
+}
+
+func (w *fakeWallet) ReserveFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	// Любая запись по транзакции, в том числе отметка release, делает резерв повтором
+	if txn, ok := w.txns[transactionID]; ok {
+		return "", w.replay(txn, userID, amount)
+	}
+	if w.balance[userID]-w.reserved[userID] < amount {
+		return "", errFailedPrecondition("insufficient funds: user %d", userID)
+	}
+	w.reserved[userID] += amount
+	w.txns[transactionID] = &walletTxn{userID: userID, amount: amount, status: txnReserved}
+	return "", nil
+}
+
+func (w *fakeWallet) CommitFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	txn, ok := w.txns[transactionID]
+	if !ok {
+		return "", errFailedPrecondition("transaction %s was never reserved", transactionID)
+	}
+	if err := w.replay(txn, userID, amount); err != nil {
+		return "", err
+	}
+	switch txn.status {
+	case txnCommitted, txnRefunded:
+		return "", nil
+	case txnReleased:
+		return "", errFailedPrecondition("transaction %s is released", transactionID)
+	}
+	w.balance[userID] -= amount
+	w.reserved[userID] -= amount
+	txn.status = txnCommitted
+	return "", nil
+}
+
+func (w *fakeWallet) ReleaseFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	txn, ok := w.txns[transactionID]
+	if !ok {
+		// Отметка: резерв, пришедший после release, ничего не зарезервирует
+		w.txns[transactionID] = &walletTxn{userID: userID, amount: amount, status: txnReleased}
+		return "", nil
+	}
+	switch txn.status {
+	case txnReleased, txnRefunded:
+		return "", nil
+	case txnCommitted:
+		return "", errFailedPrecondition("transaction %s is committed", transactionID)
+	}
+	w.reserved[userID] -= txn.amount
+	txn.status = txnReleased
+	return "", nil
+}
+
+func (w *fakeWallet) RefundFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	txn, ok := w.txns[transactionID]
+	if !ok {
+		return "", errFailedPrecondition("transaction %s was never committed", transactionID)
+	}
+	switch txn.status {
+	case txnRefunded:
+		return "", w.replay(txn, userID, amount)
+	case txnCommitted:
+	default:
+		return "", errFailedPrecondition("transaction %s is %s, not committed", transactionID, txn.status)
+	}
+	w.balance[userID] += txn.amount
+	txn.status = txnRefunded
+	return "", nil
+}
+
+func (w *fakeWallet) RefundPartial(ctx context.Context, userID int64, amount int64, transactionID, refundID string) (string, error) {
+	return "", errFailedPrecondition("partial refunds are not part of checkout")
+}
+
+type stockReservation struct {
+	productID int64
+	qty       int64
+	status    string
+}
+
+// fakeStock - склад; reserved - сколько из quantity занято резервами
+type fakeStock struct {
+	mu           sync.Mutex
+	quantity     map[int64]int64
+	reserved     map[int64]int64
+	reservations map[string][]*stockReservation
+}
+
+func newFakeStock(quantities map[int64]int64) *fakeStock {
+	s := &fakeStock{
+		quantity:     make(map[int64]int64, len(quantities)),
+		reserved:     make(map[int64]int64, len(quantities)),
+		reservations: make(map[string][]*stockReservation),
+	}
+	for productID, quantity := range quantities {
+		s.quantity[productID] = quantity
+	}
+	return s
+}
+
+func (s *fakeStock) ReserveProducts(ctx context.Context, products []entity.Product, orderID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.reservations[orderID]; ok {
+		if len(existing) != len(products) {
+			return false, errFailedPrecondition("reservation %s replayed with different items", orderID)
+		}
+		return true, nil
+	}
+	// Резерв всего заказа - одна транзакция: либо все товары, либо ни одного
+	for _, product := range products {
+		if s.quantity[product.ID]-s.reserved[product.ID] < int64(product.Quantity) {
+			return false, errFailedPrecondition("not enough stock: product %d", product.ID)
+		}
+	}
+	reservations := make([]*stockReservation, 0, len(products))
+	for _, product := range products {
+		s.reserved[product.ID] += int64(product.Quantity)
+		reservations = append(reservations, &stockReservation{productID: product.ID, qty: int64(product.Quantity), status: txnReserved})
+	}
+	s.reservations[orderID] = reservations
+	return true, nil
+}
+
+func (s *fakeStock) CommitProducts(ctx context.Context, products []entity.Product, orderID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, ok := s.reservations[orderID]
+	if !ok {
+		return false, errFailedPrecondition("reservation not found: order %s", orderID)
+	}
+	for _, r := range reservations {
+		if r.status == txnReleased {
+			return false, errFailedPrecondition("reservation %s is released", orderID)
+		}
+	}
+	for _, r := range reservations {
+		if r.status == txnCommitted {
+			continue
+		}
+		s.quantity[r.productID] -= r.qty
+		s.reserved[r.productID] -= r.qty
+		r.status = txnCommitted
+	}
+	return true, nil
+}
+
+func (s *fakeStock) ReleaseProducts(ctx context.Context, products []entity.Product, orderID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations, ok := s.reservations[orderID]
+	if !ok {
+		tombstones := make([]*stockReservation, 0, len(products))
+		for _, product := range products {
+			tombstones = append(tombstones, &stockReservation{productID: product.ID, qty: int64(product.Quantity), status: txnReleased})
+		}
+		s.reservations[orderID] = tombstones
+		return true, nil
+	}
+	for _, r := range reservations {
+		if r.status == txnCommitted {
+			return false, errFailedPrecondition("reservation %s is committed", orderID)
+		}
+	}
+	for _, r := range reservations {
+		if r.status == txnReleased {
+			continue
+		}
+		s.reserved[r.productID] -= r.qty
+		r.status = txnReleased
+	}
+	return true, nil
+}
+
+func (s *fakeStock) RestockProducts(ctx context.Context, products []entity.Product, orderID, restockID string) (bool, error) {
+	return false, errFailedPrecondition("restock is not part of checkout")
+}
+
+// fakeOutbox - outbox с тем же ключом дедупликации, что и у таблицы
+type fakeOutbox struct {
+	mu     sync.Mutex
+	events []orderEntity.OrderEvent
+	keys   map[string]bool
+}
+
+func newFakeOutbox() *fakeOutbox {
+	return &fakeOutbox{keys: make(map[string]bool)}
+}
+
+func (o *fakeOutbox) SaveEvent(ctx context.Context, event orderEntity.OrderEvent) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.keys[event.DedupeKey()] {
+		return nil
+	}
+	o.keys[event.DedupeKey()] = true
+	o.events = append(o.events, event)
+	return nil
+}
+
+// fakeSagaState - состояние саг в памяти; current_step двигают только переходы прямого хода
+type fakeSagaState struct {
+	mu    sync.Mutex
+	sagas map[string]*sagaEntity.Instance
+}
+
+func newFakeSagaState() *fakeSagaState {
+	return &fakeSagaState{sagas: make(map[string]*sagaEntity.Instance)}
+}
+
+func (s *fakeSagaState) CreateSaga(ctx context.Context, instance sagaEntity.Instance) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sagas[instance.OrderID]; ok {
+		return fmt.Errorf("saga %s already exists", instance.OrderID)
+	}
+	s.sagas[instance.OrderID] = &instance
+	return nil
+}
+
+func (s *fakeSagaState) RecordStep(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	instance, ok := s.sagas[orderID]
+	if !ok {
+		return apperrors.ErrSagaNotFound
+	}
+	if status.IsForward() {
+		instance.CurrentStep = step
+		instance.StepStatus = status
+	}
+	return nil
+}
+
+func (s *fakeSagaState) RecordBranch(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error {
+	return nil
+}
+
+func (s *fakeSagaState) UpdateStatus(ctx context.Context, orderID string, status sagaEntity.Status, errMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	instance, ok := s.sagas[orderID]
+	if !ok {
+		return apperrors.ErrSagaNotFound
+	}
+	instance.Status = status
+	instance.Error = errMsg
+	return nil
+}
+
+func (s *fakeSagaState) SetFailureReason(ctx context.Context, orderID string, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	instance, ok := s.sagas[orderID]
+	if !ok {
+		return apperrors.ErrSagaNotFound
+	}
+	instance.FailureReason = reason
+	return nil
+}
+
+func (s *fakeSagaState) GetSaga(ctx context.Context, orderID string) (*sagaEntity.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	instance, ok := s.sagas[orderID]
+	if !ok {
+		return nil, apperrors.ErrSagaNotFound
+	}
+	saga := *instance
+	return &saga, nil
+}
+
+func (s *fakeSagaState) GetSagaByIdempotencyKey(ctx context.Context, userID int64, key string) (*sagaEntity.Instance, error) {
+	return nil, apperrors.ErrSagaNotFound
+}
+
+func (s *fakeSagaState) BeginCancellation(ctx context.Context, orderID string, userID int64) (bool, error) {
+	return false, nil
+}
+
+// ListUnfinished не смотрит на давность обновления: рестарт в прогоне случается, когда прежний процесс уже упал
+func (s *fakeSagaState) ListUnfinished(ctx context.Context, olderThan time.Duration) ([]sagaEntity.Instance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var unfinished []sagaEntity.Instance
+	for _, instance := range s.sagas {
+		if instance.Status == sagaEntity.StatusRunning || instance.Status == sagaEntity.StatusCompensating {
+			unfinished = append(unfinished, *instance)
+		}
+	}
+	return unfinished, nil
+}
+
+func (s *fakeSagaState) ClaimSaga(ctx context.Context, instance sagaEntity.Instance) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saga, ok := s.sagas[instance.OrderID]
+	if !ok || saga.Status != instance.Status || !saga.UpdatedAt.Equal(instance.UpdatedAt) {
+		return false, nil
+	}
+	saga.UpdatedAt = time.Now()
+	return true, nil
+}
+
+// fault - что происходит с одним вызовом downstream
+type fault int
+
+const (
+	faultNone fault = iota
+	// faultLost - запрос не дошёл
+	faultLost
+	// faultTimeout - запрос применился, но ответ потерялся
+	faultTimeout
+	// faultDuplicate - запрос доставлен дважды
+	faultDuplicate
+	// faultCrashBefore и faultCrashAfter - оркестратор упал до отправки запроса или до получения ответа
+	faultCrashBefore
+	faultCrashAfter
+)
+
+// faults решает, какой сбой случится с очередным вызовом
+type faults struct {
+	mu         sync.Mutex
+	rnd        *rand.Rand
+	rate       float64
+	crashRate  float64
+	maxLatency time.Duration
+}
+
+func (f *faults) draw(lossy, crashes bool) (fault, time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	latency := time.Duration(f.rnd.Int64N(int64(f.maxLatency) + 1))
+	if crashes && f.rnd.Float64() < f.crashRate {
+		return faultCrashBefore + fault(f.rnd.IntN(2)), latency
+	}
+	if f.rnd.Float64() >= f.rate {
+		return faultNone, latency
+	}
+	if !lossy {
+		return faultDuplicate, latency
+	}
+	return faultLost + fault(f.rnd.IntN(3)), latency
+}
+
+var errCrashed = errors.New("orchestrator crashed")
+
+// process - один запуск оркестратора поверх общих фейков. После падения процесс не достучится
+// ни до downstream, ни до своей базы; запросы, отправленные до падения, при этом доходят.
+type process struct {
+	*harness
+	crashes bool
+	crashed atomic.Bool
+	caller  *resilience.Caller
+}
+
+// harness - общие для всех запусков оркестратора фейки и генератор сбоев
+type harness struct {
+	faults *faults
+	wallet *fakeWallet
+	stock  *fakeStock
+	outbox *fakeOutbox
+	state  *fakeSagaState
+	logger *zap.SugaredLogger
+}
+
+// start запускает новый процесс оркестратора; crashes = false - процесс не падает
+func (h *harness) start(crashes bool) *process {
+	breaker := resilience.NewBreaker("harness", math.MaxInt, time.Second, h.logger)
+	return &process{harness: h, crashes: crashes, caller: resilience.NewCaller(breaker, harnessPolicy, h.logger)}
+}
+
+// harnessPolicy - прямые вызовы почти не повторяются, чтобы сбои доходили до саги;
+// компенсации повторяются, пока не пройдут, как и должны в проде
+func harnessPolicy(call string) config.RetryPolicy {
+	switch call {
+	case config.CallWalletRelease, config.CallWalletRefund, config.CallProductsRelease:
+		return config.RetryPolicy{MaxAttempts: 50}
+	default:
+		return config.RetryPolicy{MaxAttempts: 2}
+	}
+}
+
+func (p *process) orchestrator() *Orchestrator {
+	return New(&config.Config{}, processWallet{p}, processStock{p}, nil, processOutbox{p}, processState{p}, p.logger)
+}
+
+// deliver прогоняет вызов downstream через сбой; lossy = false - только задержки, дубли и падения
+func (p *process) deliver(call string, lossy bool, apply func() error) error {
+	kind, latency := p.faults.draw(lossy, p.crashes)
+	time.Sleep(latency)
+	if p.crashed.Load() {
+		return errCrashed
+	}
+	switch kind {
+	case faultLost:
+		return status.Errorf(codes.Unavailable, "%s: injected network failure", call)
+	case faultCrashBefore:
+		p.crashed.Store(true)
+		return errCrashed
+	}
+
+	err := apply()
+	switch kind {
+	case faultTimeout:
+		return status.Errorf(codes.DeadlineExceeded, "%s: injected lost response", call)
+	case faultDuplicate:
+		return apply()
+	case faultCrashAfter:
+		p.crashed.Store(true)
+		return errCrashed
+	}
+	return err
+}
+
+// do - вызов downstream с повторами, как у gRPC клиентов оркестратора
+func (p *process) do(ctx context.Context, call string, apply func() error) error {
+	return p.caller.Do(ctx, call, func(ctx context.Context) error {
+		return p.deliver(call, true, apply)
+	})
+}
+
+// processWallet, processStock и processOutbox - фейки, как их видит один процесс
+type processWallet struct{ p *process }
+
+func (w processWallet) ReserveFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	return "", w.p.do(ctx, config.CallWalletReserve, func() error {
+		_, err := w.p.wallet.ReserveFunds(ctx, userID, amount, transactionID)
+		return err
+	})
+}
+
+func (w processWallet) CommitFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	return "", w.p.do(ctx, config.CallWalletCommit, func() error {
+		_, err := w.p.wallet.CommitFunds(ctx, userID, amount, transactionID)
+		return err
+	})
+}
+
+func (w processWallet) ReleaseFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	return "", w.p.do(ctx, config.CallWalletRelease, func() error {
+		_, err := w.p.wallet.ReleaseFunds(ctx, userID, amount, transactionID)
+		return err
+	})
+}
+
+func (w processWallet) RefundFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	return "", w.p.do(ctx, config.CallWalletRefund, func() error {
+		_, err := w.p.wallet.RefundFunds(ctx, userID, amount, transactionID)
+		return err
+	})
+}
+
+func (w processWallet) RefundPartial(ctx context.Context, userID int64, amount int64, transactionID, refundID string) (string, error) {
+	return w.p.wallet.RefundPartial(ctx, userID, amount, transactionID, refundID)
+}
+
+type processStock struct{ p *process }
+
+func (s processStock) ReserveProducts(ctx context.Context, products []entity.Product, orderID string) (bool, error) {
+	err := s.p.do(ctx, config.CallProductsReserve, func() error {
+		_, err := s.p.stock.ReserveProducts(ctx, products, orderID)
+		return err
+	})
+	return err == nil, err
+}
+
+func (s processStock) CommitProducts(ctx context.Context, products []entity.Product, orderID string) (bool, error) {
+	err := s.p.do(ctx, config.CallProductsCommit, func() error {
+		_, err := s.p.stock.CommitProducts(ctx, products, orderID)
+		return err
+	})
+	return err == nil, err
+}
+
+func (s processStock) ReleaseProducts(ctx context.Context, products []entity.Product, orderID string) (bool, error) {
+	err := s.p.do(ctx, config.CallProductsRelease, func() error {
+		_, err := s.p.stock.ReleaseProducts(ctx, products, orderID)
+		return err
+	})
+	return err == nil, err
+}
+
+func (s processStock) RestockProducts(ctx context.Context, products []entity.Product, orderID, restockID string) (bool, error) {
+	return s.p.stock.RestockProducts(ctx, products, orderID, restockID)
+}
+
+// processOutbox - outbox живёт в базе оркестратора рядом с состоянием саги и пишется локальной
+// транзакцией, поэтому сбои его записи - задержки, дубли и падения процесса, но не потерянный ответ
+type processOutbox struct{ p *process }
+
+func (o processOutbox) SaveEvent(ctx context.Context, event orderEntity.OrderEvent) error {
+	return o.p.deliver("outbox", false, func() error {
+		return o.p.outbox.SaveEvent(ctx, event)
+	})
+}
+
+// processState - упавший процесс больше ничего не пишет в состояние саг
+type processState struct{ p *process }
+
+func (s processState) alive() error {
+	if s.p.crashed.Load() {
+		return errCrashed
+	}
+	return nil
+}
+
+func (s processState) CreateSaga(ctx context.Context, instance sagaEntity.Instance) error {
+	if err := s.alive(); err != nil {
+		return err
+	}
+	return s.p.state.CreateSaga(ctx, instance)
+}
+
+func (s processState) RecordStep(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error {
+	if err := s.alive(); err != nil {
+		return err
+	}
+	return s.p.state.RecordStep(ctx, orderID, step, status, errMsg)
+}
+
+func (s processState) RecordBranch(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error {
+	if err := s.alive(); err != nil {
+		return err
+	}
+	return s.p.state.RecordBranch(ctx, orderID, step, status, errMsg)
+}
+
+func (s processState) UpdateStatus(ctx context.Context, orderID string, status sagaEntity.Status, errMsg string) error {
+	if err := s.alive(); err != nil {
+		return err
+	}
+	return s.p.state.UpdateStatus(ctx, orderID, status, errMsg)
+}
+
+func (s processState) SetFailureReason(ctx context.Context, orderID string, reason string) error {
+	if err := s.alive(); err != nil {
+		return err
+	}
+	return s.p.state.SetFailureReason(ctx, orderID, reason)
+}
+
+func (s processState) GetSaga(ctx context.Context, orderID string) (*sagaEntity.Instance, error) {
+	if err := s.alive(); err != nil {
+		return nil, err
+	}
+	return s.p.state.GetSaga(ctx, orderID)
+}
+
+func (s processState) GetSagaByIdempotencyKey(ctx context.Context, userID int64, key string) (*sagaEntity.Instance, error) {
+	if err := s.alive(); err != nil {
+		return nil, err
+	}
+	return s.p.state.GetSagaByIdempotencyKey(ctx, userID, key)
+}
+
+func (s processState) BeginCancellation(ctx context.Context, orderID string, userID int64) (bool, error) {
+	if err := s.alive(); err != nil {
+		return false, err
+	}
+	return s.p.state.BeginCancellation(ctx, orderID, userID)
+}
+
+func (s processState) ListUnfinished(ctx context.Context, olderThan time.Duration) ([]sagaEntity.Instance, error) {
+	if err := s.alive(); err != nil {
+		return nil, err
+	}
+	return s.p.state.ListUnfinished(ctx, olderThan)
+}
+
+func (s processState) ClaimSaga(ctx context.Context, instance sagaEntity.Instance) (bool, error) {
+	if err := s.alive(); err != nil {
+		return false, err
+	}
+	return s.p.state.ClaimSaga(ctx, instance)
+}
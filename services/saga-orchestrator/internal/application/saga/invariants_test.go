@@ -0,0 +1,177 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/product/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"go.uber.org/zap"
+)
+
+const (
+	harnessUsers     = 20
+	harnessProducts  = 15
+	harnessCheckouts = 3000
+	// harnessBatch - сколько оформлений идёт одновременно в одном процессе;
+	// падение процесса обрывает их все
+	harnessBatch = 50
+	// harnessRestarts - после стольких рестартов подряд с падениями recovery запускается без них
+	harnessRestarts = 3
+)
+
+// TestCheckout_Invariants гоняет тысячи случайных оформлений, пока фейки wallet, products и outbox
+// задерживают, теряют и дублируют вызовы, а процесс оркестратора падает на любом шаге.
+// После каждой пачки оркестратор перезапускается и recovery доводит оборванные саги.
+// Что бы ни случилось, деньги и товары не должны появляться и пропадать, резервы - зависать,
+// а каждая завершённая сага - иметь ровно одно событие OrderCompleted.
+func TestCheckout_Invariants(t *testing.T) {
+	seed := uint64(time.Now().UnixNano())
+	t.Logf("seed: %d", seed)
+	rnd := rand.New(rand.NewPCG(seed, seed))
+
+	balances := make(map[int64]int64, harnessUsers)
+	for userID := int64(1); userID <= harnessUsers; userID++ {
+		balances[userID] = 20000 + rnd.Int64N(40000)
+	}
+	stock := make(map[int64]int64, harnessProducts)
+	prices := make(map[int64]int64, harnessProducts)
+	for productID := int64(1); productID <= harnessProducts; productID++ {
+		stock[productID] = 200 + rnd.Int64N(400)
+		prices[productID] = 10 + rnd.Int64N(200)
+	}
+
+	h := &harness{
+		faults: &faults{
+			rnd:        rand.New(rand.NewPCG(seed, seed+1)),
+			rate:       0.15,
+			crashRate:  0.003,
+			maxLatency: 100 * time.Microsecond,
+		},
+		wallet: newFakeWallet(balances),
+		stock:  newFakeStock(stock),
+		outbox: newFakeOutbox(),
+		state:  newFakeSagaState(),
+		logger: zap.NewNop().Sugar(),
+	}
+	ctx := context.Background()
+
+	for n := 0; n < harnessCheckouts; n += harnessBatch {
+		orchestrator := h.start(true).orchestrator()
+		var wg sync.WaitGroup
+		for i := n; i < n+harnessBatch; i++ {
+			order := randomOrder(rnd, i, prices)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				//nolint:errcheck // исход саги проверяется по фейкам
+				_ = orchestrator.SagaTransaction(ctx, order)
+			}()
+		}
+		wg.Wait()
+		recoverAll(t, h)
+	}
+
+	assertInvariants(t, h, balances, stock)
+}
+
+// randomOrder - заказ из 1-3 разных товаров; часть заказов не пройдёт по деньгам или складу
+func randomOrder(rnd *rand.Rand, i int, prices map[int64]int64) orderEntity.OrderEvent {
+	order := orderEntity.OrderEvent{
+		OrderID: fmt.Sprintf("order-%d", i),
+		UserID:  1 + rnd.Int64N(harnessUsers),
+	}
+	for _, idx := range rnd.Perm(harnessProducts)[:1+rnd.IntN(3)] {
+		product := entity.Product{ID: int64(idx + 1), Quantity: 1 + rnd.IntN(3), Price: prices[int64(idx+1)]}
+		order.Products = append(order.Products, product)
+		order.Total += product.Price * int64(product.Quantity)
+	}
+	return order
+}
+
+// recoverAll перезапускает оркестратор, пока recovery не доведёт все саги; первые рестарты тоже могут упасть
+func recoverAll(t *testing.T, h *harness) {
+	t.Helper()
+	for restart := 0; ; restart++ {
+		unfinished, err := h.state.ListUnfinished(context.Background(), 0)
+		require.NoError(t, err)
+		if len(unfinished) == 0 {
+			return
+		}
+		require.Less(t, restart, 2*harnessRestarts, "recovery does not converge: %d unfinished sagas", len(unfinished))
+		//nolint:errcheck // упавший процесс не дочитает список саг - их подберёт следующий рестарт
+		_ = h.start(restart < harnessRestarts).orchestrator().Recover(context.Background())
+	}
+}
+
+func assertInvariants(t *testing.T, h *harness, balances, stock map[int64]int64) {
+	t.Helper()
+
+	spent := make(map[int64]int64)
+	sold := make(map[int64]int64)
+	completed := make(map[string]int)
+	statuses := make(map[sagaEntity.Status]int)
+	for orderID, saga := range h.state.sagas {
+		statuses[saga.Status]++
+		if !assert.Containsf(t, []sagaEntity.Status{sagaEntity.StatusCompleted, sagaEntity.StatusFailed}, saga.Status, "saga %s is not finished", orderID) {
+			continue
+		}
+		if saga.Status != sagaEntity.StatusCompleted {
+			continue
+		}
+		completed[orderID] = 0
+		spent[saga.UserID] += saga.Total
+		for _, product := range saga.Products {
+			sold[product.ID] += int64(product.Quantity)
+		}
+	}
+	t.Logf("sagas: %v, outbox events: %d", statuses, len(h.outbox.events))
+	// Без обоих исходов прогон ничего не проверил бы
+	require.NotZero(t, statuses[sagaEntity.StatusCompleted])
+	require.NotZero(t, statuses[sagaEntity.StatusFailed])
+
+	// Деньги: списано ровно столько, сколько стоят завершённые заказы, и ничего не осталось в резерве
+	for userID, initial := range balances {
+		assert.Equalf(t, initial-spent[userID], h.wallet.balance[userID], "balance of user %d", userID)
+		assert.Zerof(t, h.wallet.reserved[userID], "funds left reserved for user %d", userID)
+	}
+	for txnID, txn := range h.wallet.txns {
+		assert.NotEqualf(t, txnReserved, txn.status, "wallet reservation %s leaked", txnID)
+		_, ok := completed[txnID]
+		assert.Equalf(t, ok, txn.status == txnCommitted, "wallet transaction %s is %s", txnID, txn.status)
+	}
+
+	// Товары: со склада ушло ровно то, что продано завершёнными заказами
+	for productID, initial := range stock {
+		assert.Equalf(t, initial-sold[productID], h.stock.quantity[productID], "stock of product %d", productID)
+		assert.Zerof(t, h.stock.reserved[productID], "stock left reserved for product %d", productID)
+	}
+	for orderID, reservations := range h.stock.reservations {
+		_, ok := completed[orderID]
+		for _, r := range reservations {
+			assert.NotEqualf(t, txnReserved, r.status, "stock reservation of %s leaked", orderID)
+			assert.Equalf(t, ok, r.status == txnCommitted, "stock reservation of %s is %s", orderID, r.status)
+		}
+	}
+
+	// Outbox: ровно одно OrderCompleted на завершённую сагу и ни одного на упавшую
+	for _, event := range h.outbox.events {
+		if event.EventType != orderEntity.EventTypeOrderCompleted {
+			continue
+		}
+		count, ok := completed[event.OrderID]
+		if assert.Truef(t, ok, "OrderCompleted for unfinished order %s", event.OrderID) {
+			completed[event.OrderID] = count + 1
+		}
+	}
+	for orderID, count := range completed {
+		assert.Equalf(t, 1, count, "OrderCompleted events for %s", orderID)
+	}
+}
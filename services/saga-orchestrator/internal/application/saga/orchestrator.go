@@ -2,141 +2,351 @@ package saga
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
+	"sync"
+	"time"
 
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/application/saga/engine"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
 	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/product/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
 	"go.uber.org/zap"
 )
 
-type Step int
-
-const (
-	StepWallet Step = iota + 1
-	StepProducts
-)
-
 type Eventer interface {
 	ProccessEvent(ctx context.Context, event orderEntity.OrderEvent) error
 }
 
+// MoneyReserver - операции с кошельком; transactionID (ID заказа) делает повторные вызовы идемпотентными
 type MoneyReserver interface {
-	ReserveFunds(ctx context.Context, userID int64, amount int64) (string, error)
-	CommitFunds(ctx context.Context, userID int64, amount int64) (string, error)
-	ReleaseFunds(ctx context.Context, userID int64, amount int64) (string, error)
+	ReserveFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error)
+	CommitFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error)
+	ReleaseFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error)
+	RefundFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error)
+	// RefundPartial возвращает часть списанного по transactionID; refundID делает возврат идемпотентным
+	RefundPartial(ctx context.Context, userID int64, amount int64, transactionID, refundID string) (string, error)
 }
 
+// ProductsReserver - резервы товаров; orderID связывает резерв с заказом и делает вызовы идемпотентными
 type ProductsReserver interface {
-	ReserveProducts(ctx context.Context, productIDs []entity.Product) (bool, error)
-	CommitProducts(ctx context.Context, productIDs []entity.Product) (bool, error)
-	ReleaseProducts(ctx context.Context, productIDs []entity.Product) (bool, error)
+	ReserveProducts(ctx context.Context, productIDs []entity.Product, orderID string) (bool, error)
+	CommitProducts(ctx context.Context, productIDs []entity.Product, orderID string) (bool, error)
+	ReleaseProducts(ctx context.Context, productIDs []entity.Product, orderID string) (bool, error)
+	// RestockProducts возвращает на склад уже проданные товары; restockID делает возврат идемпотентным
+	RestockProducts(ctx context.Context, productIDs []entity.Product, orderID, restockID string) (bool, error)
+}
+
+// OrderUpdater - сервис заказов: переводит отменённый заказ в Cancelled и закрывает выполненные возвраты
+type OrderUpdater interface {
+	ConfirmCancellation(ctx context.Context, orderID string) error
+	CompleteReturn(ctx context.Context, returnID string, amount int64) error
 }
 
 type OutboxRepo interface {
 	SaveEvent(ctx context.Context, event orderEntity.OrderEvent) error
 }
 
+// SagaStateRepo хранит состояние саги, чтобы после рестарта её можно было довести до конца
+type SagaStateRepo interface {
+	CreateSaga(ctx context.Context, instance sagaEntity.Instance) error
+	RecordStep(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error
+	UpdateStatus(ctx context.Context, orderID string, status sagaEntity.Status, errMsg string) error
+	RecordBranch(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error
+	GetSaga(ctx context.Context, orderID string) (*sagaEntity.Instance, error)
+	GetSagaByIdempotencyKey(ctx context.Context, userID int64, key string) (*sagaEntity.Instance, error)
+	// BeginCancellation переводит завершённый заказ пользователя (или упавшую отмену) в RUNNING отмену;
+	// false - переводить нечего
+	BeginCancellation(ctx context.Context, orderID string, userID int64) (bool, error)
+	// ListUnfinished возвращает RUNNING и COMPENSATING саги, не обновлявшиеся дольше olderThan
+	ListUnfinished(ctx context.Context, olderThan time.Duration) ([]sagaEntity.Instance, error)
+	// ClaimSaga забирает сагу на восстановление, если её не изменили с момента чтения;
+	// false - её уже забрала другая реплика
+	ClaimSaga(ctx context.Context, instance sagaEntity.Instance) (bool, error)
+	// SetFailureReason сохраняет причину провала, которую увидит пользователь
+	SetFailureReason(ctx context.Context, orderID string, reason string) error
+}
+
 type Orchestrator struct {
 	config   *config.Config
 	logger   *zap.SugaredLogger
 	wallet   MoneyReserver
 	products ProductsReserver
+	orders   OrderUpdater
 	outboxer OutboxRepo
+	state    SagaStateRepo
+	engine   *engine.Engine
+	inflight sync.WaitGroup // саги, запущенные в фоне через StartSaga
+	// background отменяется при остановке сервиса и останавливает фоновые саги
+	background     context.Context
+	stopBackground context.CancelFunc
 }
 
-func New(config *config.Config, wallet MoneyReserver, products ProductsReserver, outboxer OutboxRepo, logger *zap.SugaredLogger) *Orchestrator {
-	return &Orchestrator{config: config, logger: logger, wallet: wallet, products: products, outboxer: outboxer}
+func New(config *config.Config, wallet MoneyReserver, products ProductsReserver, orders OrderUpdater, outboxer OutboxRepo, state SagaStateRepo, logger *zap.SugaredLogger) *Orchestrator {
+	background, stopBackground := context.WithCancel(context.Background())
+	return &Orchestrator{
+		config:         config,
+		logger:         logger,
+		wallet:         wallet,
+		products:       products,
+		orders:         orders,
+		outboxer:       outboxer,
+		state:          state,
+		engine:         engine.New(state, logger),
+		background:     background,
+		stopBackground: stopBackground,
+	}
 }
 
+// SagaTransaction сохраняет сагу и выполняет её синхронно
 func (o *Orchestrator) SagaTransaction(ctx context.Context, order orderEntity.OrderEvent) error {
-	// Шаг 1: Резервируем деньги
-	_, err := o.wallet.ReserveFunds(ctx, order.UserID, order.Total)
+	order, err := o.begin(ctx, order)
 	if err != nil {
-		o.logger.Errorw("Failed to reserve funds", "error", err, "userID", order.UserID, "amount", order.Total)
-		o.rollbackTransaction(ctx, order, StepWallet)
-		return fmt.Errorf("wallet reserve failed: %w", err)
+		return err
 	}
+	return o.execute(ctx, order, 0)
+}
 
-	// Сортируем товары по ID для предотвращения deadlock
-	sort.Slice(order.Products, func(i, j int) bool {
-		return order.Products[i].ID < order.Products[j].ID
-	})
-
-	// Шаг 2: Резервируем товары
-	_, err = o.products.ReserveProducts(ctx, order.Products)
+// StartSaga сохраняет сагу и запускает её в фоне, не дожидаясь результата.
+// Итог саги можно узнать через GetSaga.
+func (o *Orchestrator) StartSaga(ctx context.Context, order orderEntity.OrderEvent) error {
+	order, err := o.begin(ctx, order)
 	if err != nil {
-		o.logger.Errorw("Failed to reserve products", "error", err, "orderID", order.OrderID)
-		o.rollbackTransaction(ctx, order, StepProducts)
-		return fmt.Errorf("products reserve failed: %w", err)
+		return err
 	}
 
-	// Шаг 3: Коммитим деньги
-	_, err = o.wallet.CommitFunds(ctx, order.UserID, order.Total)
+	// Сага не должна обрываться вместе с gRPC запросом, который её запустил
+	sagaCtx, cancel := o.detach(ctx)
+	o.inflight.Add(1)
+	go func() {
+		defer o.inflight.Done()
+		defer cancel()
+		if err := o.execute(sagaCtx, order, 0); err != nil {
+			o.logger.Errorw("Background saga failed", "orderID", order.OrderID, "error", err)
+		}
+	}()
+	return nil
+}
+
+// StartCancellation запускает в фоне отмену завершённого заказа пользователя.
+// Повторный вызов во время отмены или после неё ничего не делает; упавшая отмена запускается заново
+// с шага, на котором остановилась.
+func (o *Orchestrator) StartCancellation(ctx context.Context, orderID string, userID int64) error {
+	started, err := o.state.BeginCancellation(ctx, orderID, userID)
 	if err != nil {
-		o.logger.Errorw("Failed to commit funds", "error", err, "orderID", order.OrderID)
-		o.rollbackTransaction(ctx, order, StepProducts)
-		return fmt.Errorf("wallet commit failed: %w", err)
+		return fmt.Errorf("failed to begin cancellation: %w", err)
 	}
 
-	// Шаг 4: Коммитим товары
-	_, err = o.products.CommitProducts(ctx, order.Products)
+	instance, err := o.state.GetSaga(ctx, orderID)
 	if err != nil {
-		o.logger.Errorw("Failed to commit products", "error", err, "orderID", order.OrderID)
-		// КРИТИЧНО: Если commit товаров упал, нужно откатить commit денег!
-		// Но это уже сложная ситуация - деньги уже списаны
-		o.logger.Errorw("CRITICAL: Funds committed but products commit failed - manual intervention required", "orderID", order.OrderID)
-		o.rollbackTransaction(ctx, order, StepProducts)
-		return fmt.Errorf("products commit failed: %w", err)
-	}
-
-	// Шаг 5: ТОЛЬКО ПОСЛЕ успешного commit отправляем событие в outbox
-	order.Status = "Completed"
-	order.EventType = orderEntity.EventTypeOrderCompleted
-	err = o.outboxer.SaveEvent(ctx, order)
-	if err != nil {
-		o.logger.Errorw("Failed to save event to outbox", "error", err, "orderID", order.OrderID)
-		o.rollbackTransaction(ctx, order, StepProducts)
-		return fmt.Errorf("failed to save event to outbox: %w", err)
+		return err
+	}
+	if instance.UserID != userID {
+		return apperrors.ErrSagaNotFound
+	}
+	if !started {
+		if instance.Kind == sagaEntity.KindCancellation {
+			o.logger.Infow("Order cancellation already in progress", "orderID", orderID, "status", instance.Status)
+			return nil
+		}
+		return apperrors.ErrOrderNotCancellable
 	}
 
-	o.logger.Infow("Saga transaction completed successfully",
-		"orderID", order.OrderID,
-		"userID", order.UserID,
-		"total", order.Total,
-		"eventType", order.EventType,
-	)
+	sagaCtx, cancel := o.detach(ctx)
+	o.inflight.Add(1)
+	go func() {
+		defer o.inflight.Done()
+		defer cancel()
+		if err := o.engine.Continue(sagaCtx, o.cancellation(), *instance); err != nil {
+			o.logger.Errorw("Background order cancellation failed", "orderID", orderID, "error", err)
+			return
+		}
+		o.logger.Infow("Order cancelled", "orderID", orderID, "userID", userID, "eventType", orderEntity.EventTypeOrderCancelled)
+	}()
 	return nil
 }
 
-func (o *Orchestrator) rollbackTransaction(ctx context.Context, order orderEntity.OrderEvent, step Step) {
-	o.logger.Infow("Starting rollback", "orderID", order.OrderID, "step", step)
+// StartReturn запускает в фоне возврат части товаров завершённого заказа пользователя и возвращает
+// сумму, которая вернётся на кошелёк. Сумма считается по ценам, по которым товары были куплены.
+// Повтор с тем же returnID второй возврат не запускает и возвращает ту же сумму.
+func (o *Orchestrator) StartReturn(ctx context.Context, returnID, orderID string, userID int64, items []entity.Product) (int64, error) {
+	existing, err := o.state.GetSaga(ctx, returnID)
+	switch {
+	case err == nil:
+		if existing.Kind != sagaEntity.KindReturn || existing.ParentOrderID != orderID || existing.UserID != userID {
+			return 0, apperrors.ErrInvalidReturn
+		}
+		o.logger.Infow("Return already started", "returnID", returnID, "orderID", orderID, "status", existing.Status)
+		return existing.Total, nil
+	case !errors.Is(err, apperrors.ErrSagaNotFound):
+		return 0, fmt.Errorf("failed to check return: %w", err)
+	}
+
+	parent, err := o.state.GetSaga(ctx, orderID)
+	if err != nil {
+		return 0, err
+	}
+	if parent.UserID != userID {
+		return 0, apperrors.ErrSagaNotFound
+	}
+	// Отменённый или не оформленный до конца заказ возвращать нечего
+	if parent.Kind != sagaEntity.KindCheckout || parent.Status != sagaEntity.StatusCompleted {
+		return 0, apperrors.ErrOrderNotReturnable
+	}
+
+	returned, total, err := priceReturn(parent.Products, items)
+	if err != nil {
+		return 0, err
+	}
 
-	switch step {
-	case StepWallet:
-		// Отменяем только резерв денег
-		if _, err := o.wallet.ReleaseFunds(ctx, order.UserID, order.Total); err != nil {
-			o.logger.Errorw("rollback: failed to release funds", "orderID", order.OrderID, "error", err)
-		} else {
-			o.logger.Infow("rollback: funds released successfully", "orderID", order.OrderID)
+	instance := sagaEntity.Instance{
+		OrderID:       returnID,
+		Kind:          sagaEntity.KindReturn,
+		ParentOrderID: orderID,
+		UserID:        userID,
+		Total:         total,
+		Products:      returned,
+		Status:        sagaEntity.StatusRunning,
+	}
+	if err := o.state.CreateSaga(ctx, instance); err != nil {
+		o.logger.Errorw("Failed to persist return saga", "error", err, "returnID", returnID, "orderID", orderID)
+		return 0, fmt.Errorf("failed to persist saga: %w", err)
+	}
+
+	sagaCtx, cancel := o.detach(ctx)
+	o.inflight.Add(1)
+	go func() {
+		defer o.inflight.Done()
+		defer cancel()
+		if err := o.engine.Continue(sagaCtx, o.orderReturn(), instance); err != nil {
+			o.logger.Errorw("Background order return failed", "returnID", returnID, "orderID", orderID, "error", err)
+			return
 		}
+		o.logger.Infow("Order items returned", "returnID", returnID, "orderID", orderID, "amount", total, "eventType", orderEntity.EventTypeOrderReturned)
+	}()
+	return total, nil
+}
+
+// priceReturn сверяет возвращаемые товары с заказом и считает сумму по ценам заказа.
+// Повторы одного товара складываются; вернуть товара больше, чем куплено, нельзя.
+func priceReturn(ordered, items []entity.Product) ([]entity.Product, int64, error) {
+	if len(items) == 0 {
+		return nil, 0, apperrors.ErrInvalidReturn
+	}
+	byID := make(map[int64]entity.Product, len(ordered))
+	for _, product := range ordered {
+		byID[product.ID] = product
+	}
 
-	case StepProducts:
-		// Отменяем резерв товаров
-		if _, err := o.products.ReleaseProducts(ctx, order.Products); err != nil {
-			o.logger.Errorw("rollback: failed to release products", "orderID", order.OrderID, "error", err)
-		} else {
-			o.logger.Infow("rollback: products released successfully", "orderID", order.OrderID)
+	quantities := make(map[int64]int, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			return nil, 0, apperrors.ErrInvalidReturn
 		}
+		quantities[item.ID] += item.Quantity
+	}
 
-		// Отменяем резерв денег
-		if _, err := o.wallet.ReleaseFunds(ctx, order.UserID, order.Total); err != nil {
-			o.logger.Errorw("rollback: failed to release funds", "orderID", order.OrderID, "error", err)
-		} else {
-			o.logger.Infow("rollback: funds released successfully", "orderID", order.OrderID)
+	returned := make([]entity.Product, 0, len(quantities))
+	var total int64
+	for id, quantity := range quantities {
+		product, ok := byID[id]
+		if !ok || quantity > product.Quantity {
+			return nil, 0, apperrors.ErrInvalidReturn
 		}
+		returned = append(returned, entity.Product{ID: id, Quantity: quantity, Price: product.Price})
+		total += product.Price * int64(quantity)
+	}
+	sort.Slice(returned, func(i, j int) bool {
+		return returned[i].ID < returned[j].ID
+	})
+	return returned, total, nil
+}
+
+// GetSaga возвращает сохранённое состояние саги
+func (o *Orchestrator) GetSaga(ctx context.Context, orderID string) (*sagaEntity.Instance, error) {
+	return o.state.GetSaga(ctx, orderID)
+}
+
+// FindCheckout возвращает сагу, которую пользователь уже запустил с этим ключом идемпотентности
+func (o *Orchestrator) FindCheckout(ctx context.Context, userID int64, key string) (*sagaEntity.Instance, error) {
+	return o.state.GetSagaByIdempotencyKey(ctx, userID, key)
+}
+
+// Wait дожидается завершения саг, запущенных через StartSaga
+func (o *Orchestrator) Wait() {
+	o.inflight.Wait()
+}
+
+// Shutdown даёт фоновым сагам доработать, пока не истечёт ctx, а затем останавливает оставшиеся.
+// Остановленные саги остаются RUNNING, и их доводит recovery.
+func (o *Orchestrator) Shutdown(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		o.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
 	}
+	o.logger.Warn("Background sagas did not finish in time, suspending them")
+	o.stopBackground()
+	<-done
+}
 
-	o.logger.Infow("Rollback completed", "orderID", order.OrderID)
+// detach отвязывает сагу от отмены запроса, который её запустил, но не от остановки сервиса
+func (o *Orchestrator) detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	sagaCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(o.background, cancel)
+	return sagaCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// begin сохраняет сагу до первого побочного эффекта, иначе после падения её не восстановить
+func (o *Orchestrator) begin(ctx context.Context, order orderEntity.OrderEvent) (orderEntity.OrderEvent, error) {
+	// Сортируем товары по ID для предотвращения deadlock
+	sort.Slice(order.Products, func(i, j int) bool {
+		return order.Products[i].ID < order.Products[j].ID
+	})
+
+	err := o.state.CreateSaga(ctx, sagaEntity.Instance{
+		OrderID:  order.OrderID,
+		Kind:     sagaEntity.KindCheckout,
+		UserID:   order.UserID,
+		Total:    order.Total,
+		Products: order.Products,
+		Status:   sagaEntity.StatusRunning,
+		// Ключ сохраняется вместе с сагой: повтор с ним не запустит её второй раз
+		IdempotencyKey: order.IdempotencyKey,
+	})
+	if errors.Is(err, apperrors.ErrDuplicateCheckout) {
+		o.logger.Infow("Checkout already started with this idempotency key", "userID", order.UserID, "orderID", order.OrderID)
+		return order, err
+	}
+	if err != nil {
+		o.logger.Errorw("Failed to persist saga", "error", err, "orderID", order.OrderID)
+		return order, fmt.Errorf("failed to persist saga: %w", err)
+	}
+	return order, nil
+}
+
+// execute выполняет сагу оформления заказа начиная с шага from
+func (o *Orchestrator) execute(ctx context.Context, order orderEntity.OrderEvent, from int) error {
+	if err := o.engine.Run(ctx, o.checkout(), order, from); err != nil {
+		return err
+	}
+	o.logger.Infow("Saga transaction completed successfully",
+		"orderID", order.OrderID,
+		"userID", order.UserID,
+		"total", order.Total,
+		"eventType", orderEntity.EventTypeOrderCompleted,
+	)
+	return nil
 }
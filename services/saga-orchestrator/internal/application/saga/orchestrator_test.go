@@ -3,14 +3,22 @@ package saga
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/application/saga/engine"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
 	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/product/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Mocks
@@ -18,18 +26,28 @@ type MockMoneyReserver struct {
 	mock.Mock
 }
 
-func (m *MockMoneyReserver) ReserveFunds(ctx context.Context, userID int64, amount int64) (string, error) {
-	args := m.Called(ctx, userID, amount)
+func (m *MockMoneyReserver) ReserveFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	args := m.Called(ctx, userID, amount, transactionID)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockMoneyReserver) CommitFunds(ctx context.Context, userID int64, amount int64) (string, error) {
-	args := m.Called(ctx, userID, amount)
+func (m *MockMoneyReserver) CommitFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	args := m.Called(ctx, userID, amount, transactionID)
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockMoneyReserver) ReleaseFunds(ctx context.Context, userID int64, amount int64) (string, error) {
-	args := m.Called(ctx, userID, amount)
+func (m *MockMoneyReserver) ReleaseFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	args := m.Called(ctx, userID, amount, transactionID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockMoneyReserver) RefundFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	args := m.Called(ctx, userID, amount, transactionID)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockMoneyReserver) RefundPartial(ctx context.Context, userID int64, amount int64, transactionID, refundID string) (string, error) {
+	args := m.Called(ctx, userID, amount, transactionID, refundID)
 	return args.String(0), args.Error(1)
 }
 
@@ -37,21 +55,40 @@ type MockProductsReserver struct {
 	mock.Mock
 }
 
-func (m *MockProductsReserver) ReserveProducts(ctx context.Context, productIDs []entity.Product) (bool, error) {
-	args := m.Called(ctx, productIDs)
+func (m *MockProductsReserver) ReserveProducts(ctx context.Context, productIDs []entity.Product, orderID string) (bool, error) {
+	args := m.Called(ctx, productIDs, orderID)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockProductsReserver) CommitProducts(ctx context.Context, productIDs []entity.Product) (bool, error) {
-	args := m.Called(ctx, productIDs)
+func (m *MockProductsReserver) CommitProducts(ctx context.Context, productIDs []entity.Product, orderID string) (bool, error) {
+	args := m.Called(ctx, productIDs, orderID)
 	return args.Bool(0), args.Error(1)
 }
 
-func (m *MockProductsReserver) ReleaseProducts(ctx context.Context, productIDs []entity.Product) (bool, error) {
-	args := m.Called(ctx, productIDs)
+func (m *MockProductsReserver) ReleaseProducts(ctx context.Context, productIDs []entity.Product, orderID string) (bool, error) {
+	args := m.Called(ctx, productIDs, orderID)
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockProductsReserver) RestockProducts(ctx context.Context, productIDs []entity.Product, orderID, restockID string) (bool, error) {
+	args := m.Called(ctx, productIDs, orderID, restockID)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockOrderUpdater struct {
+	mock.Mock
+}
+
+func (m *MockOrderUpdater) ConfirmCancellation(ctx context.Context, orderID string) error {
+	args := m.Called(ctx, orderID)
+	return args.Error(0)
+}
+
+func (m *MockOrderUpdater) CompleteReturn(ctx context.Context, returnID string, amount int64) error {
+	args := m.Called(ctx, returnID, amount)
+	return args.Error(0)
+}
+
 type MockOutboxRepo struct {
 	mock.Mock
 }
@@ -61,6 +98,82 @@ func (m *MockOutboxRepo) SaveEvent(ctx context.Context, event orderEntity.OrderE
 	return args.Error(0)
 }
 
+type MockSagaStateRepo struct {
+	mock.Mock
+}
+
+func (m *MockSagaStateRepo) CreateSaga(ctx context.Context, instance sagaEntity.Instance) error {
+	args := m.Called(ctx, instance)
+	return args.Error(0)
+}
+
+func (m *MockSagaStateRepo) RecordStep(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error {
+	args := m.Called(ctx, orderID, step, status, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockSagaStateRepo) RecordBranch(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error {
+	args := m.Called(ctx, orderID, step, status, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockSagaStateRepo) UpdateStatus(ctx context.Context, orderID string, status sagaEntity.Status, errMsg string) error {
+	args := m.Called(ctx, orderID, status, errMsg)
+	return args.Error(0)
+}
+
+func (m *MockSagaStateRepo) GetSaga(ctx context.Context, orderID string) (*sagaEntity.Instance, error) {
+	args := m.Called(ctx, orderID)
+	instance, _ := args.Get(0).(*sagaEntity.Instance)
+	return instance, args.Error(1)
+}
+
+func (m *MockSagaStateRepo) GetSagaByIdempotencyKey(ctx context.Context, userID int64, key string) (*sagaEntity.Instance, error) {
+	args := m.Called(ctx, userID, key)
+	instance, _ := args.Get(0).(*sagaEntity.Instance)
+	return instance, args.Error(1)
+}
+
+func (m *MockSagaStateRepo) BeginCancellation(ctx context.Context, orderID string, userID int64) (bool, error) {
+	args := m.Called(ctx, orderID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSagaStateRepo) ListUnfinished(ctx context.Context, olderThan time.Duration) ([]sagaEntity.Instance, error) {
+	args := m.Called(ctx, olderThan)
+	instances, _ := args.Get(0).([]sagaEntity.Instance)
+	return instances, args.Error(1)
+}
+
+func (m *MockSagaStateRepo) SetFailureReason(ctx context.Context, orderID string, reason string) error {
+	args := m.Called(ctx, orderID, reason)
+	return args.Error(0)
+}
+
+func (m *MockSagaStateRepo) ClaimSaga(ctx context.Context, instance sagaEntity.Instance) (bool, error) {
+	args := m.Called(ctx, instance)
+	return args.Bool(0), args.Error(1)
+}
+
+// newMockSagaState возвращает мок состояния, который принимает любые переходы
+func newMockSagaState() *MockSagaStateRepo {
+	m := new(MockSagaStateRepo)
+	m.On("CreateSaga", mock.Anything, mock.Anything).Return(nil)
+	m.On("RecordStep", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	m.On("RecordBranch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	m.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	m.On("ClaimSaga", mock.Anything, mock.Anything).Return(true, nil)
+	m.On("SetFailureReason", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return m
+}
+
+// failedEvent совпадает с OrderFailed, который сага пишет в outbox после отката
+func failedEvent(reason orderEntity.FailureReason) interface{} {
+	return mock.MatchedBy(func(e orderEntity.OrderEvent) bool {
+		return e.EventType == orderEntity.EventTypeOrderFailed && e.Status == "Failed" && e.Reason == reason && e.Message != ""
+	})
+}
+
 func TestOrchestrator_SagaTransaction(t *testing.T) {
 	logger := zap.NewNop().Sugar()
 	cfg := &config.Config{}
@@ -69,7 +182,7 @@ func TestOrchestrator_SagaTransaction(t *testing.T) {
 		mockWallet := new(MockMoneyReserver)
 		mockProducts := new(MockProductsReserver)
 		mockOutbox := new(MockOutboxRepo)
-		orchestrator := New(cfg, mockWallet, mockProducts, mockOutbox, logger)
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, newMockSagaState(), logger)
 
 		order := orderEntity.OrderEvent{
 			OrderID: "order-123",
@@ -80,10 +193,10 @@ func TestOrchestrator_SagaTransaction(t *testing.T) {
 			},
 		}
 
-		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000)).Return("reserved", nil)
-		mockProducts.On("ReserveProducts", mock.Anything, order.Products).Return(true, nil)
-		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000)).Return("committed", nil)
-		mockProducts.On("CommitProducts", mock.Anything, order.Products).Return(true, nil)
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("reserved", nil)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("committed", nil)
+		mockProducts.On("CommitProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
 		mockOutbox.On("SaveEvent", mock.Anything, mock.MatchedBy(func(e orderEntity.OrderEvent) bool {
 			return e.Status == "Completed" && e.EventType == orderEntity.EventTypeOrderCompleted
 		})).Return(nil)
@@ -100,30 +213,107 @@ func TestOrchestrator_SagaTransaction(t *testing.T) {
 		mockWallet := new(MockMoneyReserver)
 		mockProducts := new(MockProductsReserver)
 		mockOutbox := new(MockOutboxRepo)
-		orchestrator := New(cfg, mockWallet, mockProducts, mockOutbox, logger)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
 
 		order := orderEntity.OrderEvent{
 			OrderID: "order-123",
 			UserID:  1,
 			Total:   1000,
+			Products: []entity.Product{
+				{ID: 1, Quantity: 1},
+			},
 		}
 
-		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000)).Return("", errors.New("insufficient funds"))
-		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000)).Return("released", nil)
+		// Товары резервируются одновременно с деньгами, поэтому их резерв тоже откатывается
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("", apperrors.ErrInsufficientFunds)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockProducts.On("ReleaseProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockOutbox.On("SaveEvent", mock.Anything, failedEvent(orderEntity.FailureInsufficientFunds)).Return(nil)
 
 		err := orchestrator.SagaTransaction(context.Background(), order)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "wallet reserve failed")
+		// Пользователь увидит код причины, а не цепочку ошибок
+		mockState.AssertCalled(t, "SetFailureReason", mock.Anything, "order-123", string(orderEntity.FailureInsufficientFunds))
 		mockWallet.AssertExpectations(t)
-		mockProducts.AssertNotCalled(t, "ReserveProducts", mock.Anything, mock.Anything)
+		mockProducts.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+		mockWallet.AssertNotCalled(t, "CommitFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Reserve Legs Run Concurrently", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, newMockSagaState(), logger)
+
+		order := orderEntity.OrderEvent{
+			OrderID:  "order-123",
+			UserID:   1,
+			Total:    1000,
+			Products: []entity.Product{{ID: 1, Quantity: 1}},
+		}
+
+		// Каждый резерв ждёт, пока начнётся второй: последовательная сага здесь зависла бы
+		var started sync.WaitGroup
+		started.Add(2)
+		waitBoth := func(mock.Arguments) {
+			started.Done()
+			started.Wait()
+		}
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("reserved", nil).Run(waitBoth)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(true, nil).Run(waitBoth)
+		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("committed", nil)
+		mockProducts.On("CommitProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.Anything).Return(nil)
+
+		done := make(chan error, 1)
+		go func() { done <- orchestrator.SagaTransaction(context.Background(), order) }()
+
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("reserve legs did not run concurrently")
+		}
+	})
+
+	t.Run("Both Reserve Legs Failed", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, newMockSagaState(), logger)
+
+		order := orderEntity.OrderEvent{
+			OrderID:  "order-123",
+			UserID:   1,
+			Total:    1000,
+			Products: []entity.Product{{ID: 1, Quantity: 1}},
+		}
+
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("", apperrors.ErrInsufficientFunds)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(false, apperrors.ErrOutOfStock)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockProducts.On("ReleaseProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.Anything).Return(nil)
+
+		err := orchestrator.SagaTransaction(context.Background(), order)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "wallet reserve failed: insufficient funds")
+		assert.Contains(t, err.Error(), "products reserve failed: not enough stock")
+		mockWallet.AssertExpectations(t)
+		mockProducts.AssertExpectations(t)
 	})
 
 	t.Run("Products Reserve Failed", func(t *testing.T) {
 		mockWallet := new(MockMoneyReserver)
 		mockProducts := new(MockProductsReserver)
 		mockOutbox := new(MockOutboxRepo)
-		orchestrator := New(cfg, mockWallet, mockProducts, mockOutbox, logger)
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, newMockSagaState(), logger)
 
 		order := orderEntity.OrderEvent{
 			OrderID: "order-123",
@@ -134,12 +324,13 @@ func TestOrchestrator_SagaTransaction(t *testing.T) {
 			},
 		}
 
-		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000)).Return("reserved", nil)
-		mockProducts.On("ReserveProducts", mock.Anything, order.Products).Return(false, errors.New("out of stock"))
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("reserved", nil)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(false, fmt.Errorf("%w: %w", apperrors.ErrOutOfStock, status.Error(codes.FailedPrecondition, "failed to reserve products: not enough stock")))
 
 		// Rollback expectations
-		mockProducts.On("ReleaseProducts", mock.Anything, order.Products).Return(true, nil)
-		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000)).Return("released", nil)
+		mockProducts.On("ReleaseProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockOutbox.On("SaveEvent", mock.Anything, failedEvent(orderEntity.FailureOutOfStock)).Return(nil)
 
 		err := orchestrator.SagaTransaction(context.Background(), order)
 
@@ -147,13 +338,14 @@ func TestOrchestrator_SagaTransaction(t *testing.T) {
 		assert.Contains(t, err.Error(), "products reserve failed")
 		mockWallet.AssertExpectations(t)
 		mockProducts.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
 	})
 
 	t.Run("Wallet Commit Failed", func(t *testing.T) {
 		mockWallet := new(MockMoneyReserver)
 		mockProducts := new(MockProductsReserver)
 		mockOutbox := new(MockOutboxRepo)
-		orchestrator := New(cfg, mockWallet, mockProducts, mockOutbox, logger)
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, newMockSagaState(), logger)
 
 		order := orderEntity.OrderEvent{
 			OrderID: "order-123",
@@ -164,13 +356,14 @@ func TestOrchestrator_SagaTransaction(t *testing.T) {
 			},
 		}
 
-		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000)).Return("reserved", nil)
-		mockProducts.On("ReserveProducts", mock.Anything, order.Products).Return(true, nil)
-		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000)).Return("", errors.New("commit failed"))
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("reserved", nil)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("", errors.New("commit failed"))
 
 		// Rollback expectations
-		mockProducts.On("ReleaseProducts", mock.Anything, order.Products).Return(true, nil)
-		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000)).Return("released", nil)
+		mockProducts.On("ReleaseProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockOutbox.On("SaveEvent", mock.Anything, failedEvent(orderEntity.FailureInternal)).Return(nil)
 
 		err := orchestrator.SagaTransaction(context.Background(), order)
 
@@ -178,13 +371,14 @@ func TestOrchestrator_SagaTransaction(t *testing.T) {
 		assert.Contains(t, err.Error(), "wallet commit failed")
 		mockWallet.AssertExpectations(t)
 		mockProducts.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
 	})
 
 	t.Run("Products Commit Failed", func(t *testing.T) {
 		mockWallet := new(MockMoneyReserver)
 		mockProducts := new(MockProductsReserver)
 		mockOutbox := new(MockOutboxRepo)
-		orchestrator := New(cfg, mockWallet, mockProducts, mockOutbox, logger)
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, newMockSagaState(), logger)
 
 		order := orderEntity.OrderEvent{
 			OrderID: "order-123",
@@ -195,28 +389,84 @@ func TestOrchestrator_SagaTransaction(t *testing.T) {
 			},
 		}
 
-		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000)).Return("reserved", nil)
-		mockProducts.On("ReserveProducts", mock.Anything, order.Products).Return(true, nil)
-		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000)).Return("committed", nil)
-		mockProducts.On("CommitProducts", mock.Anything, order.Products).Return(false, errors.New("commit failed"))
-
-		// Rollback expectations
-		mockProducts.On("ReleaseProducts", mock.Anything, order.Products).Return(true, nil)
-		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000)).Return("released", nil)
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("reserved", nil)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("committed", nil)
+		mockProducts.On("CommitProducts", mock.Anything, order.Products, "order-123").Return(false, errors.New("commit failed"))
+
+		// Rollback expectations: completed steps are compensated in reverse order,
+		// committed funds are refunded first, the release after it is a no-op on the wallet side.
+		// Both reserves are released together, in any order.
+		var (
+			mu    sync.Mutex
+			calls []string
+		)
+		call := func(name string) func(mock.Arguments) {
+			return func(mock.Arguments) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls = append(calls, name)
+			}
+		}
+		mockWallet.On("RefundFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("refunded", nil).Run(call("refund"))
+		mockProducts.On("ReleaseProducts", mock.Anything, order.Products, "order-123").Return(true, nil).Run(call("release_products"))
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil).Run(call("release_funds"))
+		mockOutbox.On("SaveEvent", mock.Anything, failedEvent(orderEntity.FailureInternal)).Return(nil)
 
 		err := orchestrator.SagaTransaction(context.Background(), order)
 
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "products commit failed")
+		assert.Len(t, calls, 3)
+		assert.Equal(t, "refund", calls[0])
+		assert.ElementsMatch(t, []string{"release_products", "release_funds"}, calls[1:])
 		mockWallet.AssertExpectations(t)
 		mockProducts.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+	})
+
+	t.Run("Products Commit Failed Refund Failed", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		state := new(MockSagaStateRepo)
+		state.On("CreateSaga", mock.Anything, mock.Anything).Return(nil)
+		state.On("RecordStep", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		state.On("RecordBranch", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		state.On("UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		state.On("SetFailureReason", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, state, logger)
+
+		order := orderEntity.OrderEvent{
+			OrderID: "order-123",
+			UserID:  1,
+			Total:   1000,
+			Products: []entity.Product{
+				{ID: 1, Quantity: 1},
+			},
+		}
+
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("reserved", nil)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("committed", nil)
+		mockProducts.On("CommitProducts", mock.Anything, order.Products, "order-123").Return(false, errors.New("commit failed"))
+		mockProducts.On("ReleaseProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("RefundFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("", errors.New("wallet unavailable"))
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockOutbox.On("SaveEvent", mock.Anything, failedEvent(orderEntity.FailureInternal)).Return(nil)
+
+		err := orchestrator.SagaTransaction(context.Background(), order)
+
+		assert.Error(t, err)
+		state.AssertCalled(t, "RecordStep", mock.Anything, "order-123", sagaEntity.StepWalletCommit, sagaEntity.StepCompensationFailed, "wallet unavailable")
+		state.AssertCalled(t, "UpdateStatus", mock.Anything, "order-123", sagaEntity.StatusFailed, mock.Anything)
 	})
 
 	t.Run("Outbox Save Failed", func(t *testing.T) {
 		mockWallet := new(MockMoneyReserver)
 		mockProducts := new(MockProductsReserver)
 		mockOutbox := new(MockOutboxRepo)
-		orchestrator := New(cfg, mockWallet, mockProducts, mockOutbox, logger)
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, newMockSagaState(), logger)
 
 		order := orderEntity.OrderEvent{
 			OrderID: "order-123",
@@ -227,15 +477,16 @@ func TestOrchestrator_SagaTransaction(t *testing.T) {
 			},
 		}
 
-		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000)).Return("reserved", nil)
-		mockProducts.On("ReserveProducts", mock.Anything, order.Products).Return(true, nil)
-		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000)).Return("committed", nil)
-		mockProducts.On("CommitProducts", mock.Anything, order.Products).Return(true, nil)
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("reserved", nil)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("committed", nil)
+		mockProducts.On("CommitProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
 		mockOutbox.On("SaveEvent", mock.Anything, mock.Anything).Return(errors.New("db error"))
 
 		// Rollback expectations
-		mockProducts.On("ReleaseProducts", mock.Anything, order.Products).Return(true, nil)
-		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000)).Return("released", nil)
+		mockWallet.On("RefundFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("refunded", nil)
+		mockProducts.On("ReleaseProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
 
 		err := orchestrator.SagaTransaction(context.Background(), order)
 
@@ -245,4 +496,525 @@ func TestOrchestrator_SagaTransaction(t *testing.T) {
 		mockProducts.AssertExpectations(t)
 		mockOutbox.AssertExpectations(t)
 	})
+
+	t.Run("Persist Saga Failed", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := new(MockSagaStateRepo)
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
+
+		order := orderEntity.OrderEvent{
+			OrderID: "order-123",
+			UserID:  1,
+			Total:   1000,
+		}
+
+		mockState.On("CreateSaga", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		err := orchestrator.SagaTransaction(context.Background(), order)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to persist saga")
+		mockWallet.AssertNotCalled(t, "ReserveFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Records Step Transitions", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
+
+		order := orderEntity.OrderEvent{
+			OrderID: "order-123",
+			UserID:  1,
+			Total:   1000,
+			Products: []entity.Product{
+				{ID: 1, Quantity: 1},
+			},
+		}
+
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("reserved", nil)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(false, errors.New("out of stock"))
+		mockProducts.On("ReleaseProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.Anything).Return(nil)
+
+		err := orchestrator.SagaTransaction(context.Background(), order)
+
+		assert.Error(t, err)
+		mockState.AssertCalled(t, "RecordStep", mock.Anything, "order-123", sagaEntity.StepReserve, sagaEntity.StepStarted, "")
+		mockState.AssertCalled(t, "RecordBranch", mock.Anything, "order-123", sagaEntity.StepWalletReserve, sagaEntity.StepSucceeded, "")
+		mockState.AssertCalled(t, "RecordBranch", mock.Anything, "order-123", sagaEntity.StepProductsReserve, sagaEntity.StepFailed, "out of stock")
+		mockState.AssertCalled(t, "RecordBranch", mock.Anything, "order-123", sagaEntity.StepProductsReserve, sagaEntity.StepCompensated, "")
+		mockState.AssertCalled(t, "RecordBranch", mock.Anything, "order-123", sagaEntity.StepWalletReserve, sagaEntity.StepCompensated, "")
+		mockState.AssertCalled(t, "RecordStep", mock.Anything, "order-123", sagaEntity.StepReserve, sagaEntity.StepFailed, "products reserve failed: out of stock")
+		mockState.AssertCalled(t, "UpdateStatus", mock.Anything, "order-123", sagaEntity.StatusFailed, "products reserve failed: out of stock")
+		mockState.AssertNotCalled(t, "UpdateStatus", mock.Anything, "order-123", sagaEntity.StatusCompleted, mock.Anything)
+	})
+
+	t.Run("StartSaga Runs In Background", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
+
+		order := orderEntity.OrderEvent{
+			OrderID: "order-123",
+			UserID:  1,
+			Total:   1000,
+			Products: []entity.Product{
+				{ID: 1, Quantity: 1},
+			},
+		}
+
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("reserved", nil)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("committed", nil)
+		mockProducts.On("CommitProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.Anything).Return(nil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err := orchestrator.StartSaga(ctx, order)
+		// Отмена контекста запроса не должна обрывать сагу
+		cancel()
+		orchestrator.Wait()
+
+		assert.NoError(t, err)
+		mockWallet.AssertExpectations(t)
+		mockProducts.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+		mockState.AssertCalled(t, "UpdateStatus", mock.Anything, "order-123", sagaEntity.StatusCompleted, "")
+	})
+
+	t.Run("Shutdown Suspends Background Saga Retrying After Pivot", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockState := newMockSagaState()
+		// Без RetryTimeout шаг после точки невозврата повторялся бы, пока не остановят сервис
+		orchestrator := New(&config.Config{PivotRetryInterval: time.Millisecond}, mockWallet, mockProducts, new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		order := orderEntity.OrderEvent{OrderID: "order-123", UserID: 1, Total: 1000, Products: []entity.Product{{ID: 1, Quantity: 1}}}
+		mockWallet.On("ReserveFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("reserved", nil)
+		mockProducts.On("ReserveProducts", mock.Anything, order.Products, "order-123").Return(true, nil)
+		mockWallet.On("CommitFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("committed", nil)
+		mockProducts.On("CommitProducts", mock.Anything, order.Products, "order-123").Return(false, status.Error(codes.Unavailable, "products down"))
+
+		err := orchestrator.StartSaga(context.Background(), order)
+		assert.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		orchestrator.Shutdown(ctx)
+
+		// Сага приостановлена: не откачена и не завершена, её доведёт recovery
+		mockWallet.AssertNotCalled(t, "ReleaseFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockWallet.AssertNotCalled(t, "RefundFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockState.AssertNotCalled(t, "UpdateStatus", mock.Anything, "order-123", mock.Anything, mock.Anything)
+	})
+
+	t.Run("StartSaga Persist Failed", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockState := new(MockSagaStateRepo)
+		orchestrator := New(cfg, mockWallet, new(MockProductsReserver), new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("CreateSaga", mock.Anything, mock.Anything).Return(errors.New("db error"))
+
+		err := orchestrator.StartSaga(context.Background(), orderEntity.OrderEvent{OrderID: "order-123", UserID: 1, Total: 1000})
+		orchestrator.Wait()
+
+		assert.Error(t, err)
+		mockWallet.AssertNotCalled(t, "ReserveFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("StartSaga Duplicate Idempotency Key", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockState := new(MockSagaStateRepo)
+		orchestrator := New(cfg, mockWallet, new(MockProductsReserver), new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("CreateSaga", mock.Anything, mock.MatchedBy(func(instance sagaEntity.Instance) bool {
+			return instance.IdempotencyKey == "key-1"
+		})).Return(apperrors.ErrDuplicateCheckout)
+
+		err := orchestrator.StartSaga(context.Background(), orderEntity.OrderEvent{
+			OrderID:        "order-456",
+			UserID:         1,
+			Total:          1000,
+			IdempotencyKey: "key-1",
+		})
+		orchestrator.Wait()
+
+		assert.ErrorIs(t, err, apperrors.ErrDuplicateCheckout)
+		mockState.AssertExpectations(t)
+		mockWallet.AssertNotCalled(t, "ReserveFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrchestrator_StartCancellation(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	cfg := &config.Config{}
+	products := []entity.Product{{ID: 1, Quantity: 2}}
+	cancelling := &sagaEntity.Instance{
+		OrderID:  "order-123",
+		Kind:     sagaEntity.KindCancellation,
+		UserID:   1,
+		Total:    1000,
+		Products: products,
+		Status:   sagaEntity.StatusRunning,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOrders := new(MockOrderUpdater)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, mockOrders, mockOutbox, mockState, logger)
+
+		mockState.On("BeginCancellation", mock.Anything, "order-123", int64(1)).Return(true, nil)
+		mockState.On("GetSaga", mock.Anything, "order-123").Return(cancelling, nil)
+		mockWallet.On("RefundFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("refunded", nil)
+		mockProducts.On("RestockProducts", mock.Anything, products, "order-123", "order-123").Return(true, nil)
+		mockOrders.On("ConfirmCancellation", mock.Anything, "order-123").Return(nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.MatchedBy(func(e orderEntity.OrderEvent) bool {
+			return e.EventType == orderEntity.EventTypeOrderCancelled && e.Status == "Cancelled"
+		})).Return(nil)
+
+		err := orchestrator.StartCancellation(context.Background(), "order-123", 1)
+		orchestrator.Wait()
+
+		assert.NoError(t, err)
+		mockWallet.AssertExpectations(t)
+		mockProducts.AssertExpectations(t)
+		mockOrders.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+		mockState.AssertCalled(t, "UpdateStatus", mock.Anything, "order-123", sagaEntity.StatusCompleted, "")
+	})
+
+	t.Run("Restock Failed Is Not Compensated", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOrders := new(MockOrderUpdater)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, mockOrders, new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("BeginCancellation", mock.Anything, "order-123", int64(1)).Return(true, nil)
+		mockState.On("GetSaga", mock.Anything, "order-123").Return(cancelling, nil)
+		mockWallet.On("RefundFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("refunded", nil)
+		mockProducts.On("RestockProducts", mock.Anything, products, "order-123", "order-123").Return(false, errors.New("products unavailable"))
+
+		err := orchestrator.StartCancellation(context.Background(), "order-123", 1)
+		orchestrator.Wait()
+
+		assert.NoError(t, err)
+		mockOrders.AssertNotCalled(t, "ConfirmCancellation", mock.Anything, mock.Anything)
+		mockWallet.AssertNotCalled(t, "ReserveFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockState.AssertCalled(t, "UpdateStatus", mock.Anything, "order-123", sagaEntity.StatusFailed, mock.Anything)
+	})
+
+	t.Run("Unavailable Wallet Leaves Cancellation Running", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockState := newMockSagaState()
+		orchestrator := New(&config.Config{PivotRetryInterval: time.Millisecond, PivotRetryTimeout: 20 * time.Millisecond},
+			mockWallet, mockProducts, new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("BeginCancellation", mock.Anything, "order-123", int64(1)).Return(true, nil)
+		mockState.On("GetSaga", mock.Anything, "order-123").Return(cancelling, nil)
+		mockWallet.On("RefundFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("", status.Error(codes.Unavailable, "wallet down"))
+
+		err := orchestrator.StartCancellation(context.Background(), "order-123", 1)
+		orchestrator.Wait()
+
+		// Отмена приостановлена, а не провалена: её подхватит recovery
+		assert.NoError(t, err)
+		mockProducts.AssertNotCalled(t, "RestockProducts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockState.AssertNotCalled(t, "UpdateStatus", mock.Anything, "order-123", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Already Cancelling", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockState := new(MockSagaStateRepo)
+		orchestrator := New(cfg, mockWallet, new(MockProductsReserver), new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("BeginCancellation", mock.Anything, "order-123", int64(1)).Return(false, nil)
+		mockState.On("GetSaga", mock.Anything, "order-123").Return(cancelling, nil)
+
+		err := orchestrator.StartCancellation(context.Background(), "order-123", 1)
+		orchestrator.Wait()
+
+		assert.NoError(t, err)
+		mockWallet.AssertNotCalled(t, "RefundFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Checkout Not Completed", func(t *testing.T) {
+		mockState := new(MockSagaStateRepo)
+		orchestrator := New(cfg, new(MockMoneyReserver), new(MockProductsReserver), new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("BeginCancellation", mock.Anything, "order-123", int64(1)).Return(false, nil)
+		mockState.On("GetSaga", mock.Anything, "order-123").Return(&sagaEntity.Instance{
+			OrderID: "order-123",
+			Kind:    sagaEntity.KindCheckout,
+			UserID:  1,
+			Status:  sagaEntity.StatusRunning,
+		}, nil)
+
+		err := orchestrator.StartCancellation(context.Background(), "order-123", 1)
+
+		assert.ErrorIs(t, err, apperrors.ErrOrderNotCancellable)
+	})
+
+	t.Run("Other User", func(t *testing.T) {
+		mockState := new(MockSagaStateRepo)
+		orchestrator := New(cfg, new(MockMoneyReserver), new(MockProductsReserver), new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("BeginCancellation", mock.Anything, "order-123", int64(2)).Return(false, nil)
+		mockState.On("GetSaga", mock.Anything, "order-123").Return(cancelling, nil)
+
+		err := orchestrator.StartCancellation(context.Background(), "order-123", 2)
+
+		assert.ErrorIs(t, err, apperrors.ErrSagaNotFound)
+	})
+
+	t.Run("Begin Failed", func(t *testing.T) {
+		mockState := new(MockSagaStateRepo)
+		orchestrator := New(cfg, new(MockMoneyReserver), new(MockProductsReserver), new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("BeginCancellation", mock.Anything, "order-123", int64(1)).Return(false, errors.New("db error"))
+
+		err := orchestrator.StartCancellation(context.Background(), "order-123", 1)
+
+		assert.Error(t, err)
+		mockState.AssertNotCalled(t, "GetSaga", mock.Anything, mock.Anything)
+	})
+}
+
+func TestOrchestrator_StartReturn(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	cfg := &config.Config{}
+	completed := &sagaEntity.Instance{
+		OrderID:  "order-123",
+		Kind:     sagaEntity.KindCheckout,
+		UserID:   1,
+		Total:    900,
+		Products: []entity.Product{{ID: 1, Quantity: 2, Price: 100}, {ID: 2, Quantity: 1, Price: 700}},
+		Status:   sagaEntity.StatusCompleted,
+	}
+	returned := []entity.Product{{ID: 1, Quantity: 1, Price: 100}}
+
+	t.Run("Success", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOrders := new(MockOrderUpdater)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, mockOrders, mockOutbox, mockState, logger)
+
+		mockState.On("GetSaga", mock.Anything, "return-1").Return(nil, apperrors.ErrSagaNotFound)
+		mockState.On("GetSaga", mock.Anything, "order-123").Return(completed, nil)
+		mockWallet.On("RefundPartial", mock.Anything, int64(1), int64(100), "order-123", "return-1").Return("refunded", nil)
+		mockProducts.On("RestockProducts", mock.Anything, returned, "order-123", "return-1").Return(true, nil)
+		mockOrders.On("CompleteReturn", mock.Anything, "return-1", int64(100)).Return(nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.MatchedBy(func(e orderEntity.OrderEvent) bool {
+			return e.EventType == orderEntity.EventTypeOrderReturned && e.OrderID == "order-123" && e.ReturnID == "return-1" && e.Total == 100
+		})).Return(nil)
+
+		amount, err := orchestrator.StartReturn(context.Background(), "return-1", "order-123", 1, []entity.Product{{ID: 1, Quantity: 1}})
+		orchestrator.Wait()
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), amount)
+		mockWallet.AssertExpectations(t)
+		mockProducts.AssertExpectations(t)
+		mockOrders.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+		mockState.AssertCalled(t, "CreateSaga", mock.Anything, mock.MatchedBy(func(instance sagaEntity.Instance) bool {
+			return instance.OrderID == "return-1" && instance.Kind == sagaEntity.KindReturn && instance.ParentOrderID == "order-123"
+		}))
+		// Состояние возврата пишется под ID возврата, а не заказа
+		mockState.AssertCalled(t, "UpdateStatus", mock.Anything, "return-1", sagaEntity.StatusCompleted, "")
+		mockState.AssertNotCalled(t, "UpdateStatus", mock.Anything, "order-123", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Replayed Return", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockState := new(MockSagaStateRepo)
+		orchestrator := New(cfg, mockWallet, new(MockProductsReserver), new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("GetSaga", mock.Anything, "return-1").Return(&sagaEntity.Instance{
+			OrderID:       "return-1",
+			Kind:          sagaEntity.KindReturn,
+			ParentOrderID: "order-123",
+			UserID:        1,
+			Total:         100,
+			Status:        sagaEntity.StatusRunning,
+		}, nil)
+
+		amount, err := orchestrator.StartReturn(context.Background(), "return-1", "order-123", 1, []entity.Product{{ID: 1, Quantity: 1}})
+		orchestrator.Wait()
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(100), amount)
+		mockState.AssertNotCalled(t, "CreateSaga", mock.Anything, mock.Anything)
+		mockWallet.AssertNotCalled(t, "RefundPartial", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Refund Failed Stops Return", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOrders := new(MockOrderUpdater)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, mockOrders, new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("GetSaga", mock.Anything, "return-1").Return(nil, apperrors.ErrSagaNotFound)
+		mockState.On("GetSaga", mock.Anything, "order-123").Return(completed, nil)
+		mockWallet.On("RefundPartial", mock.Anything, int64(1), int64(100), "order-123", "return-1").Return("", errors.New("wallet unavailable"))
+
+		_, err := orchestrator.StartReturn(context.Background(), "return-1", "order-123", 1, []entity.Product{{ID: 1, Quantity: 1}})
+		orchestrator.Wait()
+
+		assert.NoError(t, err)
+		mockProducts.AssertNotCalled(t, "RestockProducts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockOrders.AssertNotCalled(t, "CompleteReturn", mock.Anything, mock.Anything, mock.Anything)
+		mockState.AssertCalled(t, "UpdateStatus", mock.Anything, "return-1", sagaEntity.StatusFailed, mock.Anything)
+	})
+
+	tests := []struct {
+		name    string
+		parent  *sagaEntity.Instance
+		userID  int64
+		items   []entity.Product
+		wantErr error
+	}{
+		{
+			name:    "Cancelled Order",
+			parent:  &sagaEntity.Instance{OrderID: "order-123", Kind: sagaEntity.KindCancellation, UserID: 1, Status: sagaEntity.StatusCompleted, Products: completed.Products},
+			userID:  1,
+			items:   []entity.Product{{ID: 1, Quantity: 1}},
+			wantErr: apperrors.ErrOrderNotReturnable,
+		},
+		{
+			name:    "Checkout Not Completed",
+			parent:  &sagaEntity.Instance{OrderID: "order-123", Kind: sagaEntity.KindCheckout, UserID: 1, Status: sagaEntity.StatusRunning, Products: completed.Products},
+			userID:  1,
+			items:   []entity.Product{{ID: 1, Quantity: 1}},
+			wantErr: apperrors.ErrOrderNotReturnable,
+		},
+		{
+			name:    "Other User",
+			parent:  completed,
+			userID:  2,
+			items:   []entity.Product{{ID: 1, Quantity: 1}},
+			wantErr: apperrors.ErrSagaNotFound,
+		},
+		{
+			name:    "Product Not In Order",
+			parent:  completed,
+			userID:  1,
+			items:   []entity.Product{{ID: 3, Quantity: 1}},
+			wantErr: apperrors.ErrInvalidReturn,
+		},
+		{
+			name:    "More Than Ordered",
+			parent:  completed,
+			userID:  1,
+			items:   []entity.Product{{ID: 1, Quantity: 2}, {ID: 1, Quantity: 1}},
+			wantErr: apperrors.ErrInvalidReturn,
+		},
+		{
+			name:    "No Items",
+			parent:  completed,
+			userID:  1,
+			wantErr: apperrors.ErrInvalidReturn,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockState := new(MockSagaStateRepo)
+			orchestrator := New(cfg, new(MockMoneyReserver), new(MockProductsReserver), new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+			mockState.On("GetSaga", mock.Anything, "return-1").Return(nil, apperrors.ErrSagaNotFound)
+			mockState.On("GetSaga", mock.Anything, "order-123").Return(tt.parent, nil)
+
+			_, err := orchestrator.StartReturn(context.Background(), "return-1", "order-123", tt.userID, tt.items)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			mockState.AssertNotCalled(t, "CreateSaga", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestPriceReturn(t *testing.T) {
+	ordered := []entity.Product{{ID: 2, Quantity: 3, Price: 50}, {ID: 1, Quantity: 1, Price: 100}}
+
+	returned, total, err := priceReturn(ordered, []entity.Product{{ID: 2, Quantity: 1}, {ID: 1, Quantity: 1}, {ID: 2, Quantity: 1}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(200), total)
+	assert.Equal(t, []entity.Product{{ID: 1, Quantity: 1, Price: 100}, {ID: 2, Quantity: 2, Price: 50}}, returned)
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name  string
+		cause error
+		want  orderEntity.FailureReason
+	}{
+		{"Insufficient Funds", fmt.Errorf("wallet reserve failed: %w", apperrors.ErrInsufficientFunds), orderEntity.FailureInsufficientFunds},
+		{"No Wallet", apperrors.ErrWalletNotFound, orderEntity.FailureInsufficientFunds},
+		{"Out Of Stock", errors.Join(fmt.Errorf("products reserve failed: %w", apperrors.ErrOutOfStock)), orderEntity.FailureOutOfStock},
+		{"Interrupted", ErrSagaInterrupted, orderEntity.FailureInternal},
+		{"Unavailable", errors.New("products_commit: circuit breaker is open"), orderEntity.FailureInternal},
+		// Причина берётся только из типа ошибки, а не из её текста
+		{"Text Only", errors.New("rpc error: code = FailedPrecondition desc = insufficient funds"), orderEntity.FailureInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, failureReason(tt.cause))
+		})
+	}
+}
+
+type MockAttemptRepo struct {
+	mock.Mock
+}
+
+func (m *MockAttemptRepo) RecordAttempt(ctx context.Context, sagaID string, step sagaEntity.StepName, call string, attempt int, errMsg string) error {
+	args := m.Called(ctx, sagaID, step, call, attempt, errMsg)
+	return args.Error(0)
+}
+
+func TestAttemptRecorder(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	callErr := errors.New("rpc error: code = Unavailable desc = connection refused")
+
+	t.Run("Records Attempt Inside Saga Step", func(t *testing.T) {
+		repo := new(MockAttemptRepo)
+		repo.On("RecordAttempt", mock.Anything, "order-1", sagaEntity.StepWalletReserve, config.CallWalletReserve, 2, callErr.Error()).Return(nil)
+		recorder := NewAttemptRecorder(repo, logger)
+		def := engine.Definition{Steps: []engine.Step{engine.StepFuncs{
+			StepName: sagaEntity.StepWalletReserve,
+			ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+				recorder.AttemptFailed(ctx, config.CallWalletReserve, 2, callErr)
+				return nil
+			},
+		}}}
+
+		err := engine.New(newMockSagaState(), logger).Run(context.Background(), def, orderEntity.OrderEvent{OrderID: "order-1"}, 0)
+
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Ignores Calls Outside Saga", func(t *testing.T) {
+		repo := new(MockAttemptRepo)
+
+		NewAttemptRecorder(repo, logger).AttemptFailed(context.Background(), config.CallWalletReserve, 1, callErr)
+
+		repo.AssertNotCalled(t, "RecordAttempt", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
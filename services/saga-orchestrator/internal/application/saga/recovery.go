@@ -0,0 +1,79 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+)
+
+var ErrSagaInterrupted = errors.New("saga interrupted by service restart")
+
+// Recover доводит до финального статуса саги, которые остались незавершёнными после падения реплики.
+// Берутся только саги, не обновлявшиеся дольше SagaStuckAfter: свежие, скорее всего, ещё выполняет
+// другая реплика. Каждую сагу перед выполнением нужно забрать - её могла уже подобрать другая реплика.
+func (o *Orchestrator) Recover(ctx context.Context) error {
+	instances, err := o.state.ListUnfinished(ctx, o.config.SagaStuckAfter)
+	if err != nil {
+		o.logger.Errorw("Failed to list unfinished sagas", "error", err)
+		return err
+	}
+	if len(instances) == 0 {
+		return nil
+	}
+
+	o.logger.Infow("Saga recovery started", "count", len(instances))
+	recovered := 0
+	for _, instance := range instances {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		claimed, err := o.state.ClaimSaga(ctx, instance)
+		if err != nil {
+			o.logger.Errorw("Recovery: failed to claim saga", "orderID", instance.OrderID, "error", err)
+			continue
+		}
+		if !claimed {
+			o.logger.Infow("Recovery: saga claimed by another replica, skipping", "orderID", instance.OrderID)
+			continue
+		}
+		o.resume(ctx, instance)
+		recovered++
+	}
+	o.logger.Infow("Saga recovery finished", "count", len(instances), "recovered", recovered)
+	return nil
+}
+
+// StartRecovery запускает в фоне Recover: сразу и затем раз в interval, пока не отменят ctx.
+// Так подбираются и саги, оборванные меньше SagaStuckAfter назад, и саги, которые
+// зависли на другой реплике или были приостановлены после точки невозврата.
+func (o *Orchestrator) StartRecovery(ctx context.Context, interval time.Duration) {
+	o.inflight.Add(1)
+	go func() {
+		defer o.inflight.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		o.logger.Infow("Saga recovery loop started", "interval", interval, "stuckAfter", o.config.SagaStuckAfter)
+		for {
+			//nolint:errcheck // ошибка уже записана в лог, следующий проход попробует снова
+			_ = o.Recover(ctx)
+			select {
+			case <-ctx.Done():
+				o.logger.Info("Saga recovery loop stopped")
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// resume решает по описанию саги, продолжить её или откатить.
+// Для оформления точка невозврата - коммит денег: если он уже начат, сагу доводим вперёд,
+// иначе компенсируем всё, что могло успеть зарезервироваться. Отмену всегда доводим вперёд.
+func (o *Orchestrator) resume(ctx context.Context, instance sagaEntity.Instance) {
+	if err := o.engine.Resume(ctx, o.definition(instance.Kind), instance, ErrSagaInterrupted); err != nil {
+		o.logger.Errorw("Recovery: resumed saga failed", "orderID", instance.OrderID, "error", err)
+	}
+}
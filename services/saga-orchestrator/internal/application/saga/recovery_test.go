@@ -0,0 +1,290 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/product/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"go.uber.org/zap"
+)
+
+func TestOrchestrator_Recover(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	cfg := &config.Config{}
+	products := []entity.Product{{ID: 1, Quantity: 2}}
+
+	newInstance := func(status sagaEntity.Status, step sagaEntity.StepName, stepStatus sagaEntity.StepStatus) sagaEntity.Instance {
+		return sagaEntity.Instance{
+			OrderID:     "order-123",
+			UserID:      1,
+			Total:       1000,
+			Products:    products,
+			Status:      status,
+			CurrentStep: step,
+			StepStatus:  stepStatus,
+		}
+	}
+
+	t.Run("Compensates Saga Interrupted Before Commit", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
+
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return([]sagaEntity.Instance{
+			newInstance(sagaEntity.StatusRunning, sagaEntity.StepProductsReserve, sagaEntity.StepStarted),
+		}, nil)
+		mockProducts.On("ReleaseProducts", mock.Anything, products, "order-123").Return(true, nil)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockOutbox.On("SaveEvent", mock.Anything, failedEvent(orderEntity.FailureInternal)).Return(nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockWallet.AssertExpectations(t)
+		mockProducts.AssertExpectations(t)
+		mockProducts.AssertNotCalled(t, "CommitProducts", mock.Anything, mock.Anything, mock.Anything)
+		mockState.AssertCalled(t, "UpdateStatus", mock.Anything, "order-123", sagaEntity.StatusFailed, ErrSagaInterrupted.Error())
+	})
+
+	t.Run("Compensates Parallel Reserve Interrupted Midway", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
+
+		// Какая из веток успела выполниться, неизвестно - откатываются обе
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return([]sagaEntity.Instance{
+			newInstance(sagaEntity.StatusRunning, sagaEntity.StepReserve, sagaEntity.StepStarted),
+		}, nil)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockProducts.On("ReleaseProducts", mock.Anything, products, "order-123").Return(true, nil)
+		mockOutbox.On("SaveEvent", mock.Anything, failedEvent(orderEntity.FailureInternal)).Return(nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockWallet.AssertExpectations(t)
+		mockProducts.AssertExpectations(t)
+		mockState.AssertCalled(t, "RecordStep", mock.Anything, "order-123", sagaEntity.StepReserve, sagaEntity.StepCompensated, "")
+	})
+
+	t.Run("Compensates Reserve Of Saga Recorded Before Parallel Steps", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
+
+		// Завершённая ветка не значит, что завершилась группа: сага не должна пойти к commit
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return([]sagaEntity.Instance{
+			newInstance(sagaEntity.StatusRunning, sagaEntity.StepWalletReserve, sagaEntity.StepSucceeded),
+		}, nil)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockProducts.On("ReleaseProducts", mock.Anything, products, "order-123").Return(true, nil)
+		mockOutbox.On("SaveEvent", mock.Anything, failedEvent(orderEntity.FailureInternal)).Return(nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockWallet.AssertExpectations(t)
+		mockProducts.AssertExpectations(t)
+		mockWallet.AssertNotCalled(t, "CommitFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Resumes Saga After Funds Commit", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
+
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return([]sagaEntity.Instance{
+			newInstance(sagaEntity.StatusRunning, sagaEntity.StepWalletCommit, sagaEntity.StepSucceeded),
+		}, nil)
+		mockProducts.On("CommitProducts", mock.Anything, products, "order-123").Return(true, nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.Anything).Return(nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockProducts.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+		mockWallet.AssertNotCalled(t, "CommitFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockWallet.AssertNotCalled(t, "ReleaseFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockState.AssertCalled(t, "UpdateStatus", mock.Anything, "order-123", sagaEntity.StatusCompleted, "")
+	})
+
+	t.Run("Finishes Saga Interrupted Before Outbox", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
+
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return([]sagaEntity.Instance{
+			newInstance(sagaEntity.StatusRunning, sagaEntity.StepProductsCommit, sagaEntity.StepSucceeded),
+		}, nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.Anything).Return(nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockOutbox.AssertExpectations(t)
+		mockProducts.AssertNotCalled(t, "CommitProducts", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Retries Compensation Of Compensating Saga", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
+
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return([]sagaEntity.Instance{
+			newInstance(sagaEntity.StatusCompensating, sagaEntity.StepWalletCommit, sagaEntity.StepFailed),
+		}, nil)
+		mockProducts.On("ReleaseProducts", mock.Anything, products, "order-123").Return(true, nil)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockOutbox.On("SaveEvent", mock.Anything, failedEvent(orderEntity.FailureInternal)).Return(nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockWallet.AssertExpectations(t)
+		mockProducts.AssertExpectations(t)
+		mockWallet.AssertNotCalled(t, "CommitFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Refunds Compensating Saga After Products Commit Failure", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, new(MockOrderUpdater), mockOutbox, mockState, logger)
+
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return([]sagaEntity.Instance{
+			newInstance(sagaEntity.StatusCompensating, sagaEntity.StepProductsCommit, sagaEntity.StepFailed),
+		}, nil)
+		mockProducts.On("ReleaseProducts", mock.Anything, products, "order-123").Return(true, nil)
+		mockWallet.On("RefundFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("refunded", nil)
+		mockWallet.On("ReleaseFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("released", nil)
+		mockOutbox.On("SaveEvent", mock.Anything, failedEvent(orderEntity.FailureInternal)).Return(nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockWallet.AssertExpectations(t)
+		mockProducts.AssertExpectations(t)
+	})
+
+	t.Run("Resumes Cancellation Interrupted Before First Step", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOrders := new(MockOrderUpdater)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, mockOrders, mockOutbox, mockState, logger)
+
+		instance := newInstance(sagaEntity.StatusRunning, "", "")
+		instance.Kind = sagaEntity.KindCancellation
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return([]sagaEntity.Instance{instance}, nil)
+		mockWallet.On("RefundFunds", mock.Anything, int64(1), int64(1000), "order-123").Return("refunded", nil)
+		mockProducts.On("RestockProducts", mock.Anything, products, "order-123", "order-123").Return(true, nil)
+		mockOrders.On("ConfirmCancellation", mock.Anything, "order-123").Return(nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.Anything).Return(nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockWallet.AssertExpectations(t)
+		mockProducts.AssertExpectations(t)
+		mockOrders.AssertExpectations(t)
+		mockState.AssertCalled(t, "UpdateStatus", mock.Anything, "order-123", sagaEntity.StatusCompleted, "")
+	})
+
+	t.Run("Resumes Cancellation After Restock", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockOrders := new(MockOrderUpdater)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, mockWallet, mockProducts, mockOrders, mockOutbox, mockState, logger)
+
+		instance := newInstance(sagaEntity.StatusRunning, sagaEntity.StepProductsRestock, sagaEntity.StepSucceeded)
+		instance.Kind = sagaEntity.KindCancellation
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return([]sagaEntity.Instance{instance}, nil)
+		mockOrders.On("ConfirmCancellation", mock.Anything, "order-123").Return(nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.Anything).Return(nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockOrders.AssertExpectations(t)
+		mockWallet.AssertNotCalled(t, "RefundFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockProducts.AssertNotCalled(t, "RestockProducts", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Resumes Return Under Its Own ID", func(t *testing.T) {
+		mockOrders := new(MockOrderUpdater)
+		mockOutbox := new(MockOutboxRepo)
+		mockState := newMockSagaState()
+		orchestrator := New(cfg, new(MockMoneyReserver), new(MockProductsReserver), mockOrders, mockOutbox, mockState, logger)
+
+		instance := newInstance(sagaEntity.StatusRunning, sagaEntity.StepProductsRestock, sagaEntity.StepSucceeded)
+		instance.OrderID = "return-1"
+		instance.Kind = sagaEntity.KindReturn
+		instance.ParentOrderID = "order-123"
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return([]sagaEntity.Instance{instance}, nil)
+		mockOrders.On("CompleteReturn", mock.Anything, "return-1", int64(1000)).Return(nil)
+		mockOutbox.On("SaveEvent", mock.Anything, mock.MatchedBy(func(e orderEntity.OrderEvent) bool {
+			return e.EventType == orderEntity.EventTypeOrderReturned && e.OrderID == "order-123" && e.ReturnID == "return-1"
+		})).Return(nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockOrders.AssertExpectations(t)
+		mockOutbox.AssertExpectations(t)
+		mockState.AssertCalled(t, "UpdateStatus", mock.Anything, "return-1", sagaEntity.StatusCompleted, "")
+	})
+
+	t.Run("Skips Saga Claimed By Another Replica", func(t *testing.T) {
+		mockWallet := new(MockMoneyReserver)
+		mockProducts := new(MockProductsReserver)
+		mockState := new(MockSagaStateRepo)
+		orchestrator := New(&config.Config{SagaStuckAfter: time.Minute}, mockWallet, mockProducts, new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		instance := newInstance(sagaEntity.StatusRunning, sagaEntity.StepProductsReserve, sagaEntity.StepStarted)
+		mockState.On("ListUnfinished", mock.Anything, time.Minute).Return([]sagaEntity.Instance{instance}, nil)
+		mockState.On("ClaimSaga", mock.Anything, instance).Return(false, nil)
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.NoError(t, err)
+		mockState.AssertExpectations(t)
+		mockState.AssertNotCalled(t, "UpdateStatus", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		mockProducts.AssertNotCalled(t, "ReleaseProducts", mock.Anything, mock.Anything, mock.Anything)
+		mockWallet.AssertNotCalled(t, "ReleaseFunds", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("List Failed", func(t *testing.T) {
+		mockState := new(MockSagaStateRepo)
+		orchestrator := New(cfg, new(MockMoneyReserver), new(MockProductsReserver), new(MockOrderUpdater), new(MockOutboxRepo), mockState, logger)
+
+		mockState.On("ListUnfinished", mock.Anything, mock.Anything).Return(nil, errors.New("db error"))
+
+		err := orchestrator.Recover(context.Background())
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,238 @@
+package saga
+
+import (
+	"context"
+	"errors"
+
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/application/saga/engine"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
+	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/resilience"
+)
+
+// checkout - сага оформления заказа. Новый шаг (проверка на фрод, купон, расчёт доставки)
+// добавляется сюда же: движок сам выполнит его по порядку и откатит в обратном.
+func (o *Orchestrator) checkout() engine.Definition {
+	return engine.Definition{
+		// Деньги списаны - после рестарта сагу только довершаем
+		Pivot: sagaEntity.StepWalletCommit,
+		// Упавшую сагу сообщаем подписчикам, чтобы они не ждали OrderCompleted
+		OnFailure: o.saveFailedEvent,
+		// Коммит, упавший по таймауту, мог пройти: его повторяют, а не откатывают
+		Transient:     resilience.IsRetryable,
+		RetryInterval: o.config.PivotRetryInterval,
+		RetryTimeout:  o.config.PivotRetryTimeout,
+		Steps: []engine.Step{
+			engine.Parallel{
+				// Шаг 1: Резервируем деньги и товары одновременно - резервы друг от друга не зависят.
+				// Если один резерв не удался, откатывается только то, что успело зарезервироваться.
+				StepName: sagaEntity.StepReserve,
+				ErrMsg:   "reserve failed",
+				Branches: []engine.Step{
+					engine.StepFuncs{
+						StepName: sagaEntity.StepWalletReserve,
+						ErrMsg:   "wallet reserve failed",
+						ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+							_, err := o.wallet.ReserveFunds(ctx, order.UserID, order.Total, order.OrderID)
+							return err
+						},
+						CompensateFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+							_, err := o.wallet.ReleaseFunds(ctx, order.UserID, order.Total, order.OrderID)
+							return err
+						},
+						// Release до резерва оставляет отметку, так что откат безопасен и при ошибке
+						CompensateOnFailure: true,
+					},
+					engine.StepFuncs{
+						StepName: sagaEntity.StepProductsReserve,
+						ErrMsg:   "products reserve failed",
+						ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+							_, err := o.products.ReserveProducts(ctx, order.Products, order.OrderID)
+							return err
+						},
+						CompensateFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+							_, err := o.products.ReleaseProducts(ctx, order.Products, order.OrderID)
+							return err
+						},
+						CompensateOnFailure: true,
+					},
+				},
+			},
+			engine.StepFuncs{
+				// Шаг 2: Коммитим деньги
+				StepName: sagaEntity.StepWalletCommit,
+				ErrMsg:   "wallet commit failed",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					_, err := o.wallet.CommitFunds(ctx, order.UserID, order.Total, order.OrderID)
+					return err
+				},
+				// Списанные деньги возвращаются refund'ом, release их уже не вернёт
+				CompensateFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					_, err := o.wallet.RefundFunds(ctx, order.UserID, order.Total, order.OrderID)
+					if err != nil {
+						o.logger.Errorw("CRITICAL: failed to refund committed funds - manual intervention required", "orderID", order.OrderID, "error", err)
+					}
+					return err
+				},
+			},
+			engine.StepFuncs{
+				// Шаг 3: Коммитим товары
+				StepName: sagaEntity.StepProductsCommit,
+				ErrMsg:   "products commit failed",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					_, err := o.products.CommitProducts(ctx, order.Products, order.OrderID)
+					return err
+				},
+			},
+			engine.StepFuncs{
+				// Шаг 4: ТОЛЬКО ПОСЛЕ успешного commit отправляем событие в outbox
+				StepName: sagaEntity.StepOutbox,
+				ErrMsg:   "failed to save event to outbox",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					order.Status = "Completed"
+					order.EventType = orderEntity.EventTypeOrderCompleted
+					return o.outboxer.SaveEvent(ctx, order)
+				},
+			},
+		},
+	}
+}
+
+// cancellation - отмена оплаченного заказа. Все её шаги идемпотентны, а откатывать возврат денег
+// некуда, поэтому отмена только доводится вперёд. Временный сбой шага повторяется, а затянувшийся
+// приостанавливает сагу в RUNNING до recovery. Отказ сервиса делает отмену FAILED - её перезапускает
+// повторный запрос отмены или оператор.
+func (o *Orchestrator) cancellation() engine.Definition {
+	return engine.Definition{
+		Pivot:         sagaEntity.StepWalletRefund,
+		Transient:     resilience.IsRetryable,
+		RetryInterval: o.config.PivotRetryInterval,
+		RetryTimeout:  o.config.PivotRetryTimeout,
+		Steps: []engine.Step{
+			engine.StepFuncs{
+				// Шаг 1: Возвращаем списанные деньги
+				StepName: sagaEntity.StepWalletRefund,
+				ErrMsg:   "wallet refund failed",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					_, err := o.wallet.RefundFunds(ctx, order.UserID, order.Total, order.OrderID)
+					return err
+				},
+			},
+			engine.StepFuncs{
+				// Шаг 2: Возвращаем товары на склад; возврат всего заказа идёт под его ID
+				StepName: sagaEntity.StepProductsRestock,
+				ErrMsg:   "products restock failed",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					_, err := o.products.RestockProducts(ctx, order.Products, order.OrderID, order.OrderID)
+					return err
+				},
+			},
+			engine.StepFuncs{
+				// Шаг 3: Переводим заказ в Cancelled
+				StepName: sagaEntity.StepOrderCancel,
+				ErrMsg:   "order cancel failed",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					return o.orders.ConfirmCancellation(ctx, order.OrderID)
+				},
+			},
+			engine.StepFuncs{
+				// Шаг 4: Сообщаем подписчикам об отмене
+				StepName: sagaEntity.StepOutbox,
+				ErrMsg:   "failed to save event to outbox",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					order.Status = "Cancelled"
+					order.EventType = orderEntity.EventTypeOrderCancelled
+					return o.outboxer.SaveEvent(ctx, order)
+				},
+			},
+		},
+	}
+}
+
+// orderReturn - возврат части товаров заказа. Как и отмена, только доводится вперёд и при временном
+// сбое ждёт recovery; упавший с отказом возврат перезапускает оператор.
+// Все шаги идут по ID возврата, поэтому у одного заказа может быть несколько возвратов.
+func (o *Orchestrator) orderReturn() engine.Definition {
+	return engine.Definition{
+		Pivot:         sagaEntity.StepWalletRefund,
+		Transient:     resilience.IsRetryable,
+		RetryInterval: o.config.PivotRetryInterval,
+		RetryTimeout:  o.config.PivotRetryTimeout,
+		Steps: []engine.Step{
+			engine.StepFuncs{
+				// Шаг 1: Возвращаем деньги за возвращённые товары
+				StepName: sagaEntity.StepWalletRefund,
+				ErrMsg:   "wallet refund failed",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					_, err := o.wallet.RefundPartial(ctx, order.UserID, order.Total, order.OrderID, order.ReturnID)
+					return err
+				},
+			},
+			engine.StepFuncs{
+				// Шаг 2: Возвращаем товары на склад
+				StepName: sagaEntity.StepProductsRestock,
+				ErrMsg:   "products restock failed",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					_, err := o.products.RestockProducts(ctx, order.Products, order.OrderID, order.ReturnID)
+					return err
+				},
+			},
+			engine.StepFuncs{
+				// Шаг 3: Закрываем возврат в сервисе заказов
+				StepName: sagaEntity.StepOrderReturn,
+				ErrMsg:   "order return failed",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					return o.orders.CompleteReturn(ctx, order.ReturnID, order.Total)
+				},
+			},
+			engine.StepFuncs{
+				// Шаг 4: Сообщаем подписчикам о возврате
+				StepName: sagaEntity.StepOutbox,
+				ErrMsg:   "failed to save event to outbox",
+				ExecuteFunc: func(ctx context.Context, order orderEntity.OrderEvent) error {
+					order.Status = "Returned"
+					order.EventType = orderEntity.EventTypeOrderReturned
+					return o.outboxer.SaveEvent(ctx, order)
+				},
+			},
+		},
+	}
+}
+
+// definition - описание саги по её виду
+func (o *Orchestrator) definition(kind sagaEntity.Kind) engine.Definition {
+	switch kind {
+	case sagaEntity.KindCancellation:
+		return o.cancellation()
+	case sagaEntity.KindReturn:
+		return o.orderReturn()
+	default:
+		return o.checkout()
+	}
+}
+
+// saveFailedEvent сохраняет причину провала в саге и пишет OrderFailed в outbox
+func (o *Orchestrator) saveFailedEvent(ctx context.Context, order orderEntity.OrderEvent, cause error) error {
+	order.Status = "Failed"
+	order.EventType = orderEntity.EventTypeOrderFailed
+	order.Reason = failureReason(cause)
+	if err := o.state.SetFailureReason(ctx, order.SagaID(), string(order.Reason)); err != nil {
+		return err
+	}
+	order.Message = cause.Error()
+	return o.outboxer.SaveEvent(ctx, order)
+}
+
+// failureReason определяет причину по доменным ошибкам клиентов wallet и products.
+// После рестарта или ручного отката исходной ошибки уже нет, и причина - internal_error.
+func failureReason(cause error) orderEntity.FailureReason {
+	switch {
+	case errors.Is(cause, apperrors.ErrInsufficientFunds), errors.Is(cause, apperrors.ErrWalletNotFound):
+		return orderEntity.FailureInsufficientFunds
+	case errors.Is(cause, apperrors.ErrOutOfStock):
+		return orderEntity.FailureOutOfStock
+	default:
+		return orderEntity.FailureInternal
+	}
+}
@@ -0,0 +1,38 @@
+package saga
+
+import (
+	"context"
+
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/application/saga/engine"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"go.uber.org/zap"
+)
+
+// AttemptRepo дописывает неудачные попытки вызовов в хронологию заказа
+type AttemptRepo interface {
+	RecordAttempt(ctx context.Context, sagaID string, step sagaEntity.StepName, call string, attempt int, errMsg string) error
+}
+
+// AttemptRecorder пишет в хронологию заказа каждую неудачную попытку вызова внутри шага саги:
+// переходы шагов показывают только итог шага, а попытки - на каком вызове и с какой ошибкой
+// сага провела время до него
+type AttemptRecorder struct {
+	repo   AttemptRepo
+	logger *zap.SugaredLogger
+}
+
+func NewAttemptRecorder(repo AttemptRepo, logger *zap.SugaredLogger) *AttemptRecorder {
+	return &AttemptRecorder{repo: repo, logger: logger}
+}
+
+// AttemptFailed реализует resilience.AttemptObserver; вызовы вне шагов саги не записываются
+func (r *AttemptRecorder) AttemptFailed(ctx context.Context, call string, attempt int, err error) {
+	info, ok := engine.StepFromContext(ctx)
+	if !ok {
+		return
+	}
+	// Попытка могла упасть из-за отмены ctx - запись в хронологию от неё не зависит
+	if recordErr := r.repo.RecordAttempt(context.WithoutCancel(ctx), info.SagaID, info.Step, call, attempt, err.Error()); recordErr != nil {
+		r.logger.Errorw("Failed to record call attempt", "error", recordErr, "sagaID", info.SagaID, "step", info.Step, "call", call)
+	}
+}
@@ -1,10 +1,14 @@
 package config
 
 import (
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/vsespontanno/eCommerce/pkg/pubsub"
 )
 
 type Config struct {
@@ -12,9 +16,12 @@ type Config struct {
 	HTTPHealthPort         int
 	GRPCWalletClientPort   string
 	GRPCProductsClientPort string
+	GRPCCatalogClientPort  string // каталог (сервис Products) слушает отдельный от SagaProducts порт
+	GRPCOrderClientPort    string // сервис заказов подтверждает отмену заказа
 	KafkaBroker            string
 	KafkaGroup             string
 	KafkaTopic             string
+	KafkaReservationsTopic string // события об истёкших резервах wallet и products
 	PGUser                 string
 	PGPassword             string
 	PGName                 string
@@ -25,6 +32,74 @@ type Config struct {
 	KafkaSSLCAPath         string
 	KafkaSecurityProtocol  string
 	KafkaSASLMechanism     string
+	// Транспорт событий outbox: kafka, postgres или memory. По умолчанию kafka, если задан KAFKA_BROKER,
+	// иначе события копятся в outbox и никуда не отправляются.
+	EventTransport string
+	// Опрос очереди postgres и пауза перед повтором неподтверждённого сообщения
+	EventPollInterval time.Duration
+	// Повторы и дедлайны вызовов wallet/products по шагам саги
+	RetryPolicies map[string]RetryPolicy
+	// Circuit breaker на каждый downstream
+	CircuitFailureThreshold int
+	CircuitOpenTimeout      time.Duration
+	// Пауза между повторами шага после точки невозврата, упавшего временно, и сколько его повторять,
+	// прежде чем оставить сагу recovery. Таймаут меньше SagaStuckAfter: пока шаг повторяется,
+	// сага не обновляется, и recovery другой реплики не должен забрать её раньше.
+	PivotRetryInterval time.Duration
+	PivotRetryTimeout  time.Duration
+	// Сколько RUNNING/COMPENSATING сага должна не обновляться, чтобы её подобрал recovery или оператор
+	SagaStuckAfter time.Duration
+	// Как часто реплика ищет зависшие саги, чтобы довести их до конца
+	SagaRecoveryInterval time.Duration
+	// Операторский API слушает отдельные порты, которые не публикуются в Service
+	AdminGRPCPort int
+	AdminHTTPPort int
+	// AdminTokens - оператор -> токен операторского API. Пусто - API не запускается.
+	AdminTokens map[string]string
+	// Повторы отправки событий outbox; после MaxAttempts событие становится dead
+	OutboxRetry RetryPolicy
+	// Опрос outbox на случай пропущенных NOTIFY и отложенных повторов
+	OutboxPollInterval time.Duration
+	// Имя реплики в аренде событий outbox и срок аренды пачки
+	OutboxWorkerID string
+	OutboxLease    time.Duration
+	// Архивация outbox: processed события старше OutboxRetention переносятся в outbox_archive,
+	// архив старше OutboxArchiveRetention удаляется (0 - хранится бессрочно).
+	// OutboxRetention = 0 отключает архивацию.
+	OutboxRetention        time.Duration
+	OutboxArchiveRetention time.Duration
+	OutboxArchiveInterval  time.Duration
+}
+
+// RetryPolicy - повторы одного шага саги
+type RetryPolicy struct {
+	MaxAttempts    int           // всего попыток, включая первую
+	InitialBackoff time.Duration // пауза перед второй попыткой, дальше растёт вдвое
+	MaxBackoff     time.Duration // потолок паузы
+	Timeout        time.Duration // дедлайн одной попытки
+}
+
+// Вызовы downstream, для которых настраиваются повторы
+const (
+	CallWalletReserve   = "wallet_reserve"
+	CallWalletCommit    = "wallet_commit"
+	CallWalletRelease   = "wallet_release"
+	CallWalletRefund    = "wallet_refund"
+	CallProductsReserve = "products_reserve"
+	CallProductsCommit  = "products_commit"
+	CallProductsRelease = "products_release"
+	CallProductsPrices  = "products_prices"
+	CallProductsRestock = "products_restock"
+	CallOrderCancel     = "order_cancel"
+	CallOrderReturn     = "order_return"
+)
+
+// Policy возвращает политику вызова; неизвестный вызов выполняется один раз без дедлайна
+func (c *Config) Policy(call string) RetryPolicy {
+	if p, ok := c.RetryPolicies[call]; ok {
+		return p
+	}
+	return RetryPolicy{MaxAttempts: 1}
 }
 
 func MustLoad() (*Config, error) {
@@ -33,11 +108,17 @@ func MustLoad() (*Config, error) {
 	_ = godotenv.Load(".env")
 	cfg.GRPCServerPort = getEnvAsInt("GRPC_SERVER_PORT", 50051)
 	cfg.HTTPHealthPort = getEnvAsInt("HTTP_HEALTH_PORT", 8080)
+	cfg.AdminGRPCPort = getEnvAsInt("ADMIN_GRPC_PORT", 50061)
+	cfg.AdminHTTPPort = getEnvAsInt("ADMIN_HTTP_PORT", 8081)
+	cfg.AdminTokens = parseAdminTokens(os.Getenv("SAGA_ADMIN_TOKENS"))
 	cfg.GRPCWalletClientPort = os.Getenv("GRPC_WALLET_CLIENT_PORT")
 	cfg.GRPCProductsClientPort = os.Getenv("GRPC_PRODUCTS_CLIENT_PORT")
+	cfg.GRPCCatalogClientPort = os.Getenv("GRPC_PRODUCTS_CATALOG_CLIENT_PORT")
+	cfg.GRPCOrderClientPort = os.Getenv("GRPC_ORDER_CLIENT_PORT")
 	cfg.KafkaBroker = os.Getenv("KAFKA_BROKER")
 	cfg.KafkaGroup = os.Getenv("KAFKA_GROUP_ID")
 	cfg.KafkaTopic = os.Getenv("KAFKA_TOPIC")
+	cfg.KafkaReservationsTopic = getEnv("KAFKA_RESERVATIONS_TOPIC", "reservations")
 	cfg.PGUser = os.Getenv("PG_USER")
 	cfg.PGPassword = os.Getenv("PG_PASSWORD")
 	cfg.PGName = os.Getenv("PG_NAME")
@@ -48,9 +129,134 @@ func MustLoad() (*Config, error) {
 	cfg.KafkaSSLCAPath = os.Getenv("KAFKA_SSL_CA_PATH")
 	cfg.KafkaSecurityProtocol = os.Getenv("KAFKA_SECURITY_PROTOCOL")
 	cfg.KafkaSASLMechanism = os.Getenv("KAFKA_SASL_MECHANISM")
+	cfg.EventTransport = getEnv("EVENT_TRANSPORT", defaultEventTransport(cfg.KafkaBroker))
+	cfg.EventPollInterval = getEnvAsMillis("EVENT_POLL_INTERVAL_MS", 500*time.Millisecond)
+	cfg.RetryPolicies = loadRetryPolicies()
+	cfg.CircuitFailureThreshold = getEnvAsInt("CIRCUIT_FAILURE_THRESHOLD", 5)
+	cfg.CircuitOpenTimeout = getEnvAsMillis("CIRCUIT_OPEN_TIMEOUT_MS", 10*time.Second)
+	cfg.PivotRetryInterval = getEnvAsMillis("PIVOT_RETRY_INTERVAL_MS", 2*time.Second)
+	cfg.SagaStuckAfter = time.Duration(getEnvAsInt("SAGA_STUCK_AFTER_SECONDS", 300)) * time.Second
+	cfg.SagaRecoveryInterval = time.Duration(getEnvAsInt("SAGA_RECOVERY_INTERVAL_SECONDS", 60)) * time.Second
+	cfg.PivotRetryTimeout = getEnvAsMillis("PIVOT_RETRY_TIMEOUT_MS", time.Minute)
+	if cfg.PivotRetryTimeout >= cfg.SagaStuckAfter {
+		cfg.PivotRetryTimeout = cfg.SagaStuckAfter / 2
+	}
+	cfg.OutboxRetry = RetryPolicy{
+		MaxAttempts:    getEnvAsInt("OUTBOX_MAX_ATTEMPTS", 10),
+		InitialBackoff: getEnvAsMillis("OUTBOX_INITIAL_BACKOFF_MS", time.Second),
+		MaxBackoff:     getEnvAsMillis("OUTBOX_MAX_BACKOFF_MS", 5*time.Minute),
+	}
+	cfg.OutboxPollInterval = getEnvAsMillis("OUTBOX_POLL_INTERVAL_MS", 5*time.Second)
+	cfg.OutboxWorkerID = getEnv("OUTBOX_WORKER_ID", defaultWorkerID())
+	cfg.OutboxLease = time.Duration(getEnvAsInt("OUTBOX_LEASE_SECONDS", 30)) * time.Second
+	cfg.OutboxRetention = time.Duration(getEnvAsInt("OUTBOX_RETENTION_DAYS", 7)) * 24 * time.Hour
+	cfg.OutboxArchiveRetention = time.Duration(getEnvAsInt("OUTBOX_ARCHIVE_RETENTION_DAYS", 0)) * 24 * time.Hour
+	cfg.OutboxArchiveInterval = time.Duration(getEnvAsInt("OUTBOX_ARCHIVE_INTERVAL_MINUTES", 60)) * time.Minute
 	return &cfg, nil
 }
 
+// loadRetryPolicies собирает политики шагов: общие значения по умолчанию
+// можно переопределить для шага через RETRY_<ШАГ>_MAX_ATTEMPTS и RETRY_<ШАГ>_TIMEOUT_MS.
+// Компенсации по умолчанию повторяются дольше - им важнее дойти до конца, чем быстро упасть.
+func loadRetryPolicies() map[string]RetryPolicy {
+	forward := getEnvAsInt("RETRY_MAX_ATTEMPTS", 3)
+	compensation := getEnvAsInt("COMPENSATION_MAX_ATTEMPTS", 5)
+
+	attempts := map[string]int{
+		CallWalletReserve:   forward,
+		CallWalletCommit:    forward,
+		CallProductsReserve: forward,
+		CallProductsCommit:  forward,
+		CallProductsPrices:  forward,
+		CallWalletRelease:   compensation,
+		CallWalletRefund:    compensation,
+		CallProductsRelease: compensation,
+		// Отмена и возврат заказа идут только вперёд, поэтому их шаги повторяются как компенсации
+		CallProductsRestock: compensation,
+		CallOrderCancel:     compensation,
+		CallOrderReturn:     compensation,
+	}
+
+	policies := make(map[string]RetryPolicy, len(attempts))
+	for call, n := range attempts {
+		prefix := "RETRY_" + strings.ToUpper(call) + "_"
+		p := defaultRetryPolicy(getEnvAsInt(prefix+"MAX_ATTEMPTS", n))
+		p.Timeout = getEnvAsMillis(prefix+"TIMEOUT_MS", p.Timeout)
+		policies[call] = p
+	}
+	return policies
+}
+
+func defaultRetryPolicy(attempts int) RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    attempts,
+		InitialBackoff: getEnvAsMillis("RETRY_INITIAL_BACKOFF_MS", 100*time.Millisecond),
+		MaxBackoff:     getEnvAsMillis("RETRY_MAX_BACKOFF_MS", 2*time.Second),
+		Timeout:        getEnvAsMillis("STEP_TIMEOUT_MS", 5*time.Second),
+	}
+}
+
+// PubSub - настройки транспорта событий
+func (c *Config) PubSub() pubsub.Config {
+	return pubsub.Config{
+		Transport: c.EventTransport,
+		Kafka: pubsub.KafkaConfig{
+			Brokers:          c.KafkaBroker,
+			SASLUsername:     c.KafkaSASLUsername,
+			SASLPassword:     c.KafkaSASLPassword,
+			SSLCAPath:        c.KafkaSSLCAPath,
+			SecurityProtocol: c.KafkaSecurityProtocol,
+			SASLMechanism:    c.KafkaSASLMechanism,
+		},
+		PollInterval: c.EventPollInterval,
+	}
+}
+
+// defaultEventTransport сохраняет прежнее поведение: Kafka включается заданным брокером
+func defaultEventTransport(kafkaBroker string) string {
+	if kafkaBroker != "" {
+		return pubsub.TransportKafka
+	}
+	return ""
+}
+
+// parseAdminTokens разбирает список "оператор:токен" через запятую; записи без имени или токена пропускаются
+func parseAdminTokens(raw string) map[string]string {
+	tokens := make(map[string]string)
+	for _, entry := range strings.Split(raw, ",") {
+		operator, token, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || operator == "" || token == "" {
+			continue
+		}
+		tokens[operator] = token
+	}
+	return tokens
+}
+
+// defaultWorkerID - имя пода в Kubernetes; pid различает процессы на одном хосте
+func defaultWorkerID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "saga-orchestrator"
+	}
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}
+
+func getEnv(name, defaultVal string) string {
+	if val := os.Getenv(name); val != "" {
+		return val
+	}
+	return defaultVal
+}
+
+func getEnvAsMillis(name string, defaultVal time.Duration) time.Duration {
+	ms := getEnvAsInt(name, -1)
+	if ms <= 0 {
+		return defaultVal
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
 func getEnvAsInt(name string, defaultVal int) int {
 	val := os.Getenv(name)
 	if val == "" {
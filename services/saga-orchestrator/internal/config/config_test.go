@@ -3,8 +3,10 @@ package config
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/vsespontanno/eCommerce/pkg/pubsub"
 )
 
 func TestMustLoad(t *testing.T) {
@@ -55,4 +57,128 @@ func TestMustLoad(t *testing.T) {
 		assert.NotNil(t, cfg)
 		assert.Equal(t, 8080, cfg.HTTPHealthPort) // Should fallback to default
 	})
+
+	t.Run("Retry Policies", func(t *testing.T) {
+		os.Setenv("RETRY_MAX_ATTEMPTS", "4")
+		os.Setenv("RETRY_WALLET_COMMIT_MAX_ATTEMPTS", "7")
+		os.Setenv("RETRY_WALLET_COMMIT_TIMEOUT_MS", "1500")
+		defer func() {
+			os.Unsetenv("RETRY_MAX_ATTEMPTS")
+			os.Unsetenv("RETRY_WALLET_COMMIT_MAX_ATTEMPTS")
+			os.Unsetenv("RETRY_WALLET_COMMIT_TIMEOUT_MS")
+		}()
+
+		cfg, err := MustLoad()
+		assert.NoError(t, err)
+
+		reserve := cfg.Policy(CallWalletReserve)
+		assert.Equal(t, 4, reserve.MaxAttempts)
+		assert.Equal(t, 5*time.Second, reserve.Timeout)
+		assert.Equal(t, 100*time.Millisecond, reserve.InitialBackoff)
+
+		commit := cfg.Policy(CallWalletCommit)
+		assert.Equal(t, 7, commit.MaxAttempts)
+		assert.Equal(t, 1500*time.Millisecond, commit.Timeout)
+
+		// Компенсации по умолчанию повторяются дольше прямого хода
+		assert.Equal(t, 5, cfg.Policy(CallWalletRefund).MaxAttempts)
+		assert.Equal(t, 1, cfg.Policy("unknown").MaxAttempts)
+
+		assert.Equal(t, 5, cfg.CircuitFailureThreshold)
+		assert.Equal(t, 10*time.Second, cfg.CircuitOpenTimeout)
+	})
+
+	t.Run("Saga Stuck After", func(t *testing.T) {
+		cfg, err := MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, cfg.SagaStuckAfter)
+		assert.Equal(t, time.Minute, cfg.SagaRecoveryInterval)
+		assert.Equal(t, time.Minute, cfg.PivotRetryTimeout)
+
+		os.Setenv("SAGA_STUCK_AFTER_SECONDS", "60")
+		defer os.Unsetenv("SAGA_STUCK_AFTER_SECONDS")
+
+		cfg, err = MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, time.Minute, cfg.SagaStuckAfter)
+		// Повторы после точки невозврата заканчиваются раньше, чем сагу сочтут зависшей
+		assert.Equal(t, 30*time.Second, cfg.PivotRetryTimeout)
+	})
+	t.Run("Admin API", func(t *testing.T) {
+		cfg, err := MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, 50061, cfg.AdminGRPCPort)
+		assert.Equal(t, 8081, cfg.AdminHTTPPort)
+		assert.Empty(t, cfg.AdminTokens)
+
+		os.Setenv("SAGA_ADMIN_TOKENS", "alice:secret-1, bob:secret-2,broken,:no-name,carol:")
+		defer os.Unsetenv("SAGA_ADMIN_TOKENS")
+
+		cfg, err = MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"alice": "secret-1", "bob": "secret-2"}, cfg.AdminTokens)
+	})
+	t.Run("Outbox Retry", func(t *testing.T) {
+		cfg, err := MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, 10, cfg.OutboxRetry.MaxAttempts)
+		assert.Equal(t, time.Second, cfg.OutboxRetry.InitialBackoff)
+		assert.Equal(t, 5*time.Minute, cfg.OutboxRetry.MaxBackoff)
+		assert.Equal(t, 5*time.Second, cfg.OutboxPollInterval)
+
+		os.Setenv("OUTBOX_MAX_ATTEMPTS", "3")
+		defer os.Unsetenv("OUTBOX_MAX_ATTEMPTS")
+
+		cfg, err = MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, 3, cfg.OutboxRetry.MaxAttempts)
+	})
+	t.Run("Outbox Lease", func(t *testing.T) {
+		cfg, err := MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, 30*time.Second, cfg.OutboxLease)
+		assert.NotEmpty(t, cfg.OutboxWorkerID)
+
+		os.Setenv("OUTBOX_WORKER_ID", "saga-orchestrator-0")
+		defer os.Unsetenv("OUTBOX_WORKER_ID")
+
+		cfg, err = MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, "saga-orchestrator-0", cfg.OutboxWorkerID)
+	})
+	t.Run("Outbox Archive", func(t *testing.T) {
+		cfg, err := MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, 7*24*time.Hour, cfg.OutboxRetention)
+		assert.Zero(t, cfg.OutboxArchiveRetention)
+		assert.Equal(t, time.Hour, cfg.OutboxArchiveInterval)
+
+		os.Setenv("OUTBOX_ARCHIVE_RETENTION_DAYS", "365")
+		defer os.Unsetenv("OUTBOX_ARCHIVE_RETENTION_DAYS")
+
+		cfg, err = MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, 365*24*time.Hour, cfg.OutboxArchiveRetention)
+	})
+	t.Run("Event Transport", func(t *testing.T) {
+		os.Unsetenv("EVENT_TRANSPORT")
+		os.Unsetenv("KAFKA_BROKER")
+		cfg, err := MustLoad()
+		assert.NoError(t, err)
+		assert.Empty(t, cfg.EventTransport)
+		assert.Equal(t, 500*time.Millisecond, cfg.PubSub().PollInterval)
+
+		os.Setenv("KAFKA_BROKER", "broker:9092")
+		defer os.Unsetenv("KAFKA_BROKER")
+		cfg, err = MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, pubsub.TransportKafka, cfg.EventTransport)
+		assert.Equal(t, "broker:9092", cfg.PubSub().Kafka.Brokers)
+
+		os.Setenv("EVENT_TRANSPORT", pubsub.TransportPostgres)
+		defer os.Unsetenv("EVENT_TRANSPORT")
+		cfg, err = MustLoad()
+		assert.NoError(t, err)
+		assert.Equal(t, pubsub.TransportPostgres, cfg.PubSub().Transport)
+	})
 }
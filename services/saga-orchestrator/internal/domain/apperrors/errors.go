@@ -0,0 +1,48 @@
+package apperrors
+
+import "errors"
+
+var ErrSagaNotFound = errors.New("saga not found")
+
+// ErrOrderNotCancellable - отменить можно только заказ, оформление которого завершилось успешно
+var ErrOrderNotCancellable = errors.New("order cannot be cancelled")
+
+// Ошибки возврата товаров
+var (
+	// ErrOrderNotReturnable - вернуть можно только товары заказа, оформление которого завершилось успешно
+	ErrOrderNotReturnable = errors.New("order cannot be returned")
+	// ErrInvalidReturn - в возврате нет товаров, товара нет в заказе или его вернули больше, чем купили
+	ErrInvalidReturn = errors.New("invalid return items")
+)
+
+// Ошибки операторского API
+var (
+	ErrOperatorRequired = errors.New("operator is required")
+	ErrReasonRequired   = errors.New("reason is required")
+	ErrSagaFinished     = errors.New("saga is already finished")
+	ErrSagaInProgress   = errors.New("saga is still in progress")
+	ErrSagaCompensated  = errors.New("saga has already been compensated")
+	ErrSagaModified     = errors.New("saga was modified concurrently")
+)
+
+// Ошибки операций над outbox
+var (
+	ErrEventNotFound = errors.New("outbox event not found")
+	ErrEventNotDead  = errors.New("outbox event is not dead")
+)
+
+// Ошибки оформления заказа
+var (
+	ErrPricesChanged      = errors.New("cart prices are out of date")
+	ErrProductUnavailable = errors.New("product is no longer available")
+	// ErrDuplicateCheckout - checkout с этим ключом идемпотентности уже запускался
+	ErrDuplicateCheckout = errors.New("checkout with this idempotency key already exists")
+)
+
+// Отказы сервисов wallet и products при резерве. Клиенты переводят в них gRPC-коды,
+// а сага по ним определяет причину OrderFailed.
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrWalletNotFound    = errors.New("wallet not found")
+	ErrOutOfStock        = errors.New("not enough stock")
+)
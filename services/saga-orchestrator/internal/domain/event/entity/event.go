@@ -7,6 +7,24 @@ const (
 	EventTypeOrderCompleted = "OrderCompleted"
 	EventTypeOrderFailed    = "OrderFailed"
 	EventTypeOrderCancelled = "OrderCancelled"
+	// EventTypeOrderReturned - часть товаров заказа возвращена; Products и Total - только возвращённое
+	EventTypeOrderReturned = "OrderReturned"
+)
+
+// EventSource - источник событий заказа в их конверте CloudEvents
+const EventSource = "saga-orchestrator"
+
+// EventSchemaVersion - версия схемы OrderEvent в конверте. Поднимается при любом несовместимом
+// изменении полей; consumer'ы приводят к своей схеме только версии, которые знают.
+const EventSchemaVersion = 1
+
+// FailureReason - причина OrderFailed, по которой consumer решает, что показать пользователю
+type FailureReason string
+
+const (
+	FailureInsufficientFunds FailureReason = "insufficient_funds"
+	FailureOutOfStock        FailureReason = "out_of_stock"
+	FailureInternal          FailureReason = "internal_error"
 )
 
 type OrderEvent struct {
@@ -16,4 +34,25 @@ type OrderEvent struct {
 	Total     int64            `json:"total"`
 	Status    string           `json:"status"`
 	EventType string           `json:"event_type,omitempty"` // Тип события для routing в consumer
+	// Только для OrderFailed
+	Reason  FailureReason `json:"reason,omitempty"`
+	Message string        `json:"message,omitempty"`
+	// Только для OrderReturned
+	ReturnID string `json:"return_id,omitempty"`
+	// IdempotencyKey - ключ повтора checkout от клиента; в события не попадает
+	IdempotencyKey string `json:"-"`
+}
+
+// SagaID - ключ, под которым хранится состояние саги: возврат идёт отдельной сагой от заказа
+func (e OrderEvent) SagaID() string {
+	if e.ReturnID != "" {
+		return e.ReturnID
+	}
+	return e.OrderID
+}
+
+// DedupeKey - одно событие каждого типа на сагу: повтор шага outbox после рестарта
+// не должен опубликовать событие второй раз
+func (e OrderEvent) DedupeKey() string {
+	return e.SagaID() + ":" + e.EventType
 }
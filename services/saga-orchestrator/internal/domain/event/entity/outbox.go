@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+// OutboxStatus - состояние события в outbox
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxProcessed OutboxStatus = "processed"
+	// OutboxFailed - отправка не удалась, событие ждёт повтора после next_attempt_at
+	OutboxFailed OutboxStatus = "failed"
+	// OutboxDead - попытки исчерпаны, событие отправится только после requeue оператором
+	OutboxDead OutboxStatus = "dead"
+)
+
+// aggregate_type событий outbox. Outbox общий: кроме заказов оркестратора в него пишут
+// sweeper'ы wallet и products, и publisher отправляет их события в свой топик.
+const (
+	AggregateOrder              = "Order"
+	AggregateWalletTransaction  = "WalletTransaction"
+	AggregateProductReservation = "ProductReservation"
+)
+
+// OutboxNotifyChannel - канал NOTIFY, в который outbox сообщает о новом событии
+const OutboxNotifyChannel = "outbox_events"
+
+// OutboxEvent - строка outbox
+type OutboxEvent struct {
+	ID            int64
+	AggregateID   string
+	AggregateType string
+	EventType     string
+	Payload       []byte
+	Status        OutboxStatus
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+	CreatedAt     time.Time
+}
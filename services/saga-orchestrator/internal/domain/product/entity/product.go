@@ -3,4 +3,6 @@ package entity
 type Product struct {
 	ID       int64 `json:"product_id"`
 	Quantity int   `json:"quantity"`
+	// Price - цена за штуку из каталога на момент оформления заказа
+	Price int64 `json:"price,omitempty"`
 }
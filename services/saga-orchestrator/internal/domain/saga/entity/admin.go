@@ -0,0 +1,53 @@
+package entity
+
+import "time"
+
+// AdminActionType - ручное действие оператора над сагой
+type AdminActionType string
+
+const (
+	ActionRetry      AdminActionType = "retry"
+	ActionCompensate AdminActionType = "compensate"
+	ActionResolve    AdminActionType = "resolve"
+	// ActionRequeueEvent - возврат dead события outbox в очередь отправки
+	ActionRequeueEvent AdminActionType = "requeue_event"
+)
+
+// AdminActionResult - чем закончилось действие оператора
+type AdminActionResult string
+
+const (
+	ActionSucceeded AdminActionResult = "succeeded"
+	// ActionFailed - действие выполнено, но сага снова упала
+	ActionFailed AdminActionResult = "failed"
+	// ActionRejected - действие не допустимо для саги в её текущем состоянии
+	ActionRejected AdminActionResult = "rejected"
+)
+
+// AdminAction - запись журнала действий операторов
+type AdminAction struct {
+	ID       int64
+	OrderID  string
+	Action   AdminActionType
+	Operator string
+	Reason   string
+	Result   AdminActionResult
+	Error    string
+	// EventID - событие outbox, над которым выполнено действие; 0 для действий над сагой
+	EventID   int64
+	CreatedAt time.Time
+}
+
+// SagaFilter - выборка саг для операторов
+type SagaFilter struct {
+	Status    Status
+	OlderThan time.Duration // сколько сага не обновлялась
+	Limit     int
+}
+
+// AdminActionFilter - выборка из журнала действий
+type AdminActionFilter struct {
+	OrderID  string
+	Operator string
+	Limit    int
+}
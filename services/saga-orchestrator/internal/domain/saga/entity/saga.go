@@ -0,0 +1,99 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/product/entity"
+)
+
+// Status - состояние саги целиком
+type Status string
+
+const (
+	StatusRunning      Status = "RUNNING"
+	StatusCompensating Status = "COMPENSATING"
+	StatusCompleted    Status = "COMPLETED"
+	StatusFailed       Status = "FAILED"
+	// StatusResolved - оператор разобрался с сагой вручную, система её больше не трогает
+	StatusResolved Status = "RESOLVED"
+)
+
+// Kind - какая сага идёт по заказу
+type Kind string
+
+const (
+	KindCheckout Kind = "checkout"
+	// KindCancellation - отмена оформленного заказа; выполняется после завершённого checkout в той же саге
+	KindCancellation Kind = "cancellation"
+	// KindReturn - возврат части товаров завершённого заказа; идёт отдельной сагой под ID возврата
+	KindReturn Kind = "return"
+)
+
+// StepName - имя шага саги
+type StepName string
+
+const (
+	// StepReserve - резерв денег и товаров, ветки wallet_reserve и products_reserve идут параллельно
+	StepReserve         StepName = "reserve"
+	StepWalletReserve   StepName = "wallet_reserve"
+	StepProductsReserve StepName = "products_reserve"
+	StepWalletCommit    StepName = "wallet_commit"
+	StepProductsCommit  StepName = "products_commit"
+	StepOutbox          StepName = "outbox"
+	// Шаги отмены заказа
+	StepWalletRefund    StepName = "wallet_refund"
+	StepProductsRestock StepName = "products_restock"
+	StepOrderCancel     StepName = "order_cancel"
+	// Шаг возврата товаров
+	StepOrderReturn StepName = "order_return"
+)
+
+// StepStatus - состояние отдельного шага
+type StepStatus string
+
+const (
+	StepStarted            StepStatus = "started"
+	StepSucceeded          StepStatus = "succeeded"
+	StepFailed             StepStatus = "failed"
+	StepCompensated        StepStatus = "compensated"
+	StepCompensationFailed StepStatus = "compensation_failed"
+)
+
+// IsForward возвращает true для переходов прямого хода саги.
+// Только они двигают current_step у инстанса, компенсации пишутся лишь в историю.
+func (s StepStatus) IsForward() bool {
+	return s == StepStarted || s == StepSucceeded || s == StepFailed
+}
+
+// Instance - сохранённое состояние саги для одного заказа
+type Instance struct {
+	OrderID     string
+	Kind        Kind
+	UserID      int64
+	Total       int64
+	Products    []entity.Product
+	Status      Status
+	CurrentStep StepName
+	StepStatus  StepStatus
+	// Error - полная цепочка ошибок для операторов; пользователю отдаётся только FailureReason
+	Error string
+	// FailureReason - причина провала checkout (insufficient_funds, out_of_stock, internal_error);
+	// пустая, пока сага не упала
+	FailureReason string
+	// IdempotencyKey - ключ, с которым клиент запустил checkout; пустой, если клиент его не передал
+	IdempotencyKey string
+	// ParentOrderID - заказ, к которому относится сага возврата; пустой для остальных саг
+	ParentOrderID string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// StepRecord - одна запись в истории переходов саги
+type StepRecord struct {
+	ID        int64
+	OrderID   string
+	Step      StepName
+	Status    StepStatus
+	Error     string
+	CreatedAt time.Time
+}
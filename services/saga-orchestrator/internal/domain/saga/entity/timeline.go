@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// TimelineEventType - что произошло с сагой
+type TimelineEventType string
+
+const (
+	// TimelineSagaStatus - сага перешла в новый статус; Status - статус саги
+	TimelineSagaStatus TimelineEventType = "saga_status"
+	// TimelineStep - переход шага; Status - статус шага
+	TimelineStep TimelineEventType = "step"
+	// TimelineAttempt - неудачная попытка вызова внутри шага; Detail - имя вызова
+	TimelineAttempt TimelineEventType = "attempt"
+	// TimelineAdminAction - действие оператора; Status - результат действия
+	TimelineAdminAction TimelineEventType = "admin_action"
+)
+
+// TimelineEvent - запись хронологии заказа
+type TimelineEvent struct {
+	ID int64
+	// OrderID - заказ покупателя; у саги возврата - исходный заказ
+	OrderID string
+	// SagaID - сага, к которой относится событие: ID заказа или ID возврата
+	SagaID    string
+	Kind      Kind
+	Type      TimelineEventType
+	Step      StepName
+	Status    string
+	Attempt   int
+	Detail    string
+	Error     string
+	CreatedAt time.Time
+}
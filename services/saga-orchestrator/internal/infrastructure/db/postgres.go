@@ -9,16 +9,19 @@ import (
 	"go.uber.org/zap"
 )
 
-func NewPostgresDB(cfg *config.Config, log *zap.SugaredLogger) (*sqlx.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
+// DSN - строка подключения к Postgres; нужна и пулу, и отдельному соединению LISTEN
+func DSN(cfg *config.Config) string {
+	return fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
 		cfg.PGHost,
 		cfg.PGPort,
 		cfg.PGUser,
 		cfg.PGPassword,
 		cfg.PGName,
 	)
+}
 
-	db, err := sqlx.Connect("postgres", dsn)
+func NewPostgresDB(cfg *config.Config, log *zap.SugaredLogger) (*sqlx.DB, error) {
+	db, err := sqlx.Connect("postgres", DSN(cfg))
 	if err != nil {
 		log.Errorw("failed to connect to postgres", "error", err)
 		return nil, err
@@ -0,0 +1,64 @@
+package orders
+
+import (
+	"context"
+
+	"github.com/vsespontanno/eCommerce/proto/orders"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/resilience"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+type Client struct {
+	client orders.OrderClient
+	caller *resilience.Caller
+	logger *zap.SugaredLogger
+	addr   string
+}
+
+// NewOrdersClient - caller задаёт повторы, дедлайны и circuit breaker для всех вызовов сервиса заказов
+func NewOrdersClient(addr string, caller *resilience.Caller, logger *zap.SugaredLogger) *Client {
+	// addr уже содержит полный адрес из ConfigMap
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logger.Fatalf("Failed to dial gRPC server %s: %v", addr, err)
+	}
+
+	logger.Infow("Connected to Order service", "addr", addr)
+	return &Client{
+		client: orders.NewOrderClient(conn),
+		caller: caller,
+		addr:   addr,
+		logger: logger,
+	}
+}
+
+// ConfirmCancellation переводит заказ в Cancelled; повторный вызов для отменённого заказа ничего не делает
+func (c *Client) ConfirmCancellation(ctx context.Context, orderID string) error {
+	err := c.caller.Do(ctx, config.CallOrderCancel, func(ctx context.Context) error {
+		_, err := c.client.ConfirmCancellation(ctx, &orders.ConfirmCancellationRequest{OrderId: orderID})
+		return err
+	})
+	if err != nil {
+		c.logger.Errorw("Error while confirming order cancellation", "error", err, "orderID", orderID)
+		return err
+	}
+	c.logger.Infow("Order cancellation confirmed", "orderID", orderID)
+	return nil
+}
+
+// CompleteReturn закрывает выполненный возврат; повторный вызов для закрытого возврата ничего не делает
+func (c *Client) CompleteReturn(ctx context.Context, returnID string, amount int64) error {
+	err := c.caller.Do(ctx, config.CallOrderReturn, func(ctx context.Context) error {
+		_, err := c.client.CompleteReturn(ctx, &orders.CompleteReturnRequest{ReturnId: returnID, Amount: amount})
+		return err
+	})
+	if err != nil {
+		c.logger.Errorw("Error while completing order return", "error", err, "returnID", returnID)
+		return err
+	}
+	c.logger.Infow("Order return completed", "returnID", returnID, "amount", amount)
+	return nil
+}
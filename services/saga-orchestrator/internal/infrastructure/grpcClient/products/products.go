@@ -5,105 +5,197 @@ import (
 	"fmt"
 
 	"github.com/vsespontanno/eCommerce/proto/products"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/product/entity"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/resilience"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 type Client struct {
-	client products.SagaProductsClient
-	logger *zap.SugaredLogger
-	addr   string
+	client  products.SagaProductsClient
+	catalog products.ProductsClient
+	caller  *resilience.Caller
+	logger  *zap.SugaredLogger
+	addr    string
 }
 
-func NewProductsClient(addr string, logger *zap.SugaredLogger) *Client {
+// NewProductsClient - caller задаёт повторы, дедлайны и circuit breaker для всех вызовов сервиса товаров.
+// catalogAddr - адрес сервиса Products, из которого берутся актуальные цены.
+func NewProductsClient(addr, catalogAddr string, caller *resilience.Caller, logger *zap.SugaredLogger) *Client {
 	// addr уже содержит полный адрес из ConfigMap
 	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
 		logger.Fatalf("Failed to dial gRPC server %s: %v", addr, err)
 	}
+	catalogConn, err := grpc.NewClient(catalogAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		logger.Fatalf("Failed to dial gRPC server %s: %v", catalogAddr, err)
+	}
 
-	client := products.NewSagaProductsClient(conn)
-	logger.Infow("Connected to Products service", "addr", addr)
+	logger.Infow("Connected to Products service", "addr", addr, "catalogAddr", catalogAddr)
 	return &Client{
-		client: client,
-		addr:   addr,
-		logger: logger,
+		client:  products.NewSagaProductsClient(conn),
+		catalog: products.NewProductsClient(catalogConn),
+		caller:  caller,
+		addr:    addr,
+		logger:  logger,
+	}
+}
+
+// GetPrices возвращает текущие цены каталога по ID товаров; товаров, которых нет в каталоге, нет и в ответе
+func (p *Client) GetPrices(ctx context.Context, ids []int64) (map[int64]int64, error) {
+	var res *products.GetProductsByIDResponse
+	err := p.caller.Do(ctx, config.CallProductsPrices, func(ctx context.Context) error {
+		var err error
+		res, err = p.catalog.GetProducts(ctx, &products.GetProductsByIDRequest{Ids: ids})
+		return err
+	})
+	if err != nil {
+		p.logger.Errorw("Error while getting product prices", "error", err, "products", len(ids))
+		return nil, err
+	}
+	if res == nil {
+		p.logger.Errorw("Nil response from GetProducts", "products", len(ids))
+		return nil, fmt.Errorf("nil response from products service")
+	}
+	if res.Error != "" {
+		p.logger.Errorw("Failed to get product prices", "error", res.Error, "products", len(ids))
+		return nil, fmt.Errorf("get products failed: %s", res.Error)
+	}
+
+	prices := make(map[int64]int64, len(res.Products))
+	for _, product := range res.Products {
+		prices[product.Id] = product.Price
 	}
+	return prices, nil
 }
 
-func (p *Client) ReserveProducts(ctx context.Context, productIDs []entity.Product) (bool, error) {
-	req := &products.ReserveProductsRequest{}
+func (p *Client) ReserveProducts(ctx context.Context, productIDs []entity.Product, orderID string) (bool, error) {
+	req := &products.ReserveProductsRequest{OrderId: orderID}
 	for _, v := range productIDs {
 		req.Products = append(req.Products, &products.ProductSaga{
 			Id:       v.ID,
 			Quantity: int64(v.Quantity),
 		})
 	}
-	res, err := p.client.ReserveProducts(ctx, req)
+	var res *products.ReserveProductsResponse
+	err := p.caller.Do(ctx, config.CallProductsReserve, func(ctx context.Context) error {
+		var err error
+		res, err = p.client.ReserveProducts(ctx, req)
+		return err
+	})
 	if err != nil {
-		p.logger.Errorw("Error while reserving products", "error", err, "products", len(productIDs))
+		p.logger.Errorw("Error while reserving products", "error", err, "orderID", orderID, "products", len(productIDs))
+		// При резерве FailedPrecondition - товара не хватает на складе
+		if status.Code(err) == codes.FailedPrecondition {
+			return false, fmt.Errorf("%w: %w", apperrors.ErrOutOfStock, err)
+		}
 		return false, err
 	}
 	if res == nil {
-		p.logger.Errorw("Nil response from ReserveProducts", "products", len(productIDs))
+		p.logger.Errorw("Nil response from ReserveProducts", "orderID", orderID, "products", len(productIDs))
 		return false, fmt.Errorf("nil response from products service")
 	}
 	if !res.Success {
-		p.logger.Errorw("Failed to reserve products", "error", res.Error, "products", len(productIDs))
+		p.logger.Errorw("Failed to reserve products", "error", res.Error, "orderID", orderID, "products", len(productIDs))
 		return false, fmt.Errorf("reserve products failed: %s", res.Error)
 	}
-	p.logger.Infow("Products reserved successfully", "products", len(productIDs))
+	p.logger.Infow("Products reserved successfully", "orderID", orderID, "products", len(productIDs))
 	return true, nil
 }
 
-func (p *Client) CommitProducts(ctx context.Context, productIDs []entity.Product) (bool, error) {
-	req := &products.CommitProductsRequest{}
+func (p *Client) CommitProducts(ctx context.Context, productIDs []entity.Product, orderID string) (bool, error) {
+	req := &products.CommitProductsRequest{OrderId: orderID}
 	for _, v := range productIDs {
 		req.Products = append(req.Products, &products.ProductSaga{
 			Id:       v.ID,
 			Quantity: int64(v.Quantity),
 		})
 	}
-	res, err := p.client.CommitProducts(ctx, req)
+	var res *products.CommitProductsResponse
+	err := p.caller.Do(ctx, config.CallProductsCommit, func(ctx context.Context) error {
+		var err error
+		res, err = p.client.CommitProducts(ctx, req)
+		return err
+	})
 	if err != nil {
-		p.logger.Errorw("Error while committing products", "error", err, "products", len(productIDs))
+		p.logger.Errorw("Error while committing products", "error", err, "orderID", orderID, "products", len(productIDs))
 		return false, err
 	}
 	if res == nil {
-		p.logger.Errorw("Nil response from CommitProducts", "products", len(productIDs))
+		p.logger.Errorw("Nil response from CommitProducts", "orderID", orderID, "products", len(productIDs))
 		return false, fmt.Errorf("nil response from products service")
 	}
 	if !res.Success {
-		p.logger.Errorw("Failed to commit products", "error", res.Error, "products", len(productIDs))
+		p.logger.Errorw("Failed to commit products", "error", res.Error, "orderID", orderID, "products", len(productIDs))
 		return false, fmt.Errorf("commit products failed: %s", res.Error)
 	}
-	p.logger.Infow("Products committed successfully", "products", len(productIDs))
+	p.logger.Infow("Products committed successfully", "orderID", orderID, "products", len(productIDs))
 	return true, nil
 }
 
-func (p *Client) ReleaseProducts(ctx context.Context, productIDs []entity.Product) (bool, error) {
-	req := &products.ReleaseProductsRequest{}
+func (p *Client) ReleaseProducts(ctx context.Context, productIDs []entity.Product, orderID string) (bool, error) {
+	req := &products.ReleaseProductsRequest{OrderId: orderID}
 	for _, v := range productIDs {
 		req.Products = append(req.Products, &products.ProductSaga{
 			Id:       v.ID,
 			Quantity: int64(v.Quantity),
 		})
 	}
-	res, err := p.client.ReleaseProducts(ctx, req)
+	var res *products.ReleaseProductsResponse
+	err := p.caller.Do(ctx, config.CallProductsRelease, func(ctx context.Context) error {
+		var err error
+		res, err = p.client.ReleaseProducts(ctx, req)
+		return err
+	})
 	if err != nil {
-		p.logger.Errorw("Error while releasing products", "error", err, "products", len(productIDs))
+		p.logger.Errorw("Error while releasing products", "error", err, "orderID", orderID, "products", len(productIDs))
 		return false, err
 	}
 	if res == nil {
-		p.logger.Errorw("Nil response from ReleaseProducts", "products", len(productIDs))
+		p.logger.Errorw("Nil response from ReleaseProducts", "orderID", orderID, "products", len(productIDs))
 		return false, fmt.Errorf("nil response from products service")
 	}
 	if !res.Success {
-		p.logger.Errorw("Failed to release products", "error", res.Error, "products", len(productIDs))
+		p.logger.Errorw("Failed to release products", "error", res.Error, "orderID", orderID, "products", len(productIDs))
 		return false, fmt.Errorf("release products failed: %s", res.Error)
 	}
-	p.logger.Infow("Products released successfully", "products", len(productIDs))
+	p.logger.Infow("Products released successfully", "orderID", orderID, "products", len(productIDs))
+	return true, nil
+}
+
+// RestockProducts возвращает на склад товары оплаченного заказа; restockID делает возврат идемпотентным
+func (p *Client) RestockProducts(ctx context.Context, productIDs []entity.Product, orderID, restockID string) (bool, error) {
+	req := &products.RestockProductsRequest{OrderId: orderID, RestockId: restockID}
+	for _, v := range productIDs {
+		req.Products = append(req.Products, &products.ProductSaga{
+			Id:       v.ID,
+			Quantity: int64(v.Quantity),
+		})
+	}
+	var res *products.RestockProductsResponse
+	err := p.caller.Do(ctx, config.CallProductsRestock, func(ctx context.Context) error {
+		var err error
+		res, err = p.client.RestockProducts(ctx, req)
+		return err
+	})
+	if err != nil {
+		p.logger.Errorw("Error while restocking products", "error", err, "orderID", orderID, "restockID", restockID, "products", len(productIDs))
+		return false, err
+	}
+	if res == nil {
+		p.logger.Errorw("Nil response from RestockProducts", "orderID", orderID, "restockID", restockID, "products", len(productIDs))
+		return false, fmt.Errorf("nil response from products service")
+	}
+	if !res.Success {
+		p.logger.Errorw("Failed to restock products", "error", res.Error, "orderID", orderID, "restockID", restockID, "products", len(productIDs))
+		return false, fmt.Errorf("restock products failed: %s", res.Error)
+	}
+	p.logger.Infow("Products restocked successfully", "orderID", orderID, "restockID", restockID, "products", len(productIDs))
 	return true, nil
 }
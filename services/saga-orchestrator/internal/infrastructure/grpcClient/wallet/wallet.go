@@ -6,20 +6,27 @@ import (
 	"log"
 
 	"github.com/vsespontanno/eCommerce/proto/wallet"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/resilience"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 const Success = "success"
 
 type Client struct {
 	client wallet.WalletClient
+	caller *resilience.Caller
 	logger *zap.SugaredLogger
 	addr   string
 }
 
-func NewWalletClient(addr string, logger *zap.SugaredLogger) *Client {
+// NewWalletClient - caller задаёт повторы, дедлайны и circuit breaker для всех вызовов кошелька
+func NewWalletClient(addr string, caller *resilience.Caller, logger *zap.SugaredLogger) *Client {
 	// addr уже содержит полный адрес из ConfigMap
 	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
@@ -30,58 +37,137 @@ func NewWalletClient(addr string, logger *zap.SugaredLogger) *Client {
 	logger.Infow("Connected to Wallet service", "addr", addr)
 	return &Client{
 		client: client,
+		caller: caller,
 		addr:   addr,
 		logger: logger,
 	}
 }
 
-func (w *Client) ReserveFunds(ctx context.Context, userID int64, amount int64) (string, error) {
-	response, err := w.client.ReserveFunds(ctx, &wallet.ReserveFundsRequest{
-		UserId: userID,
-		Amount: amount,
+func (w *Client) ReserveFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	var response *wallet.ReserveFundsResponse
+	err := w.caller.Do(ctx, config.CallWalletReserve, func(ctx context.Context) error {
+		var err error
+		response, err = w.client.ReserveFunds(ctx, &wallet.ReserveFundsRequest{
+			UserId:        userID,
+			Amount:        amount,
+			TransactionId: transactionID,
+		})
+		return err
 	})
 	if err != nil {
-		w.logger.Errorw("Error reserving funds", "error", err, "userID", userID, "amount", amount)
-		return "", err
+		w.logger.Errorw("Error reserving funds", "error", err, "userID", userID, "amount", amount, "transactionID", transactionID)
+		return "", reserveError(err)
 	}
 	if !response.Success {
-		w.logger.Errorw("Failed to reserve funds", "error", response.Message, "userID", userID, "amount", amount)
+		w.logger.Errorw("Failed to reserve funds", "error", response.Message, "userID", userID, "amount", amount, "transactionID", transactionID)
 		return "", fmt.Errorf("reserve funds failed: %s", response.Message)
 	}
-	w.logger.Infow("Funds reserved successfully", "userID", userID, "amount", amount)
+	w.logger.Infow("Funds reserved successfully", "userID", userID, "amount", amount, "transactionID", transactionID)
 	return Success, nil
 }
 
-func (w *Client) CommitFunds(ctx context.Context, userID int64, amount int64) (string, error) {
-	resp, err := w.client.CommitFunds(ctx, &wallet.CommitFundsRequest{
-		UserId: userID,
-		Amount: amount,
+// reserveError переводит отказ кошелька в резерве в доменную ошибку; gRPC-код остаётся в цепочке
+func reserveError(err error) error {
+	switch status.Code(err) {
+	case codes.FailedPrecondition:
+		return fmt.Errorf("%w: %w", apperrors.ErrInsufficientFunds, err)
+	case codes.NotFound:
+		return fmt.Errorf("%w: %w", apperrors.ErrWalletNotFound, err)
+	default:
+		return err
+	}
+}
+
+func (w *Client) CommitFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	var resp *wallet.CommitFundsResponse
+	err := w.caller.Do(ctx, config.CallWalletCommit, func(ctx context.Context) error {
+		var err error
+		resp, err = w.client.CommitFunds(ctx, &wallet.CommitFundsRequest{
+			UserId:        userID,
+			Amount:        amount,
+			TransactionId: transactionID,
+		})
+		return err
 	})
 	if err != nil {
-		w.logger.Errorw("Error committing funds", "error", err, "userID", userID, "amount", amount)
+		w.logger.Errorw("Error committing funds", "error", err, "userID", userID, "amount", amount, "transactionID", transactionID)
 		return "", err
 	}
 	if !resp.Success {
-		w.logger.Errorw("Failed to commit funds", "error", resp.Message, "userID", userID, "amount", amount)
+		w.logger.Errorw("Failed to commit funds", "error", resp.Message, "userID", userID, "amount", amount, "transactionID", transactionID)
 		return "", fmt.Errorf("commit funds failed: %s", resp.Message)
 	}
-	w.logger.Infow("Funds committed successfully", "userID", userID, "amount", amount)
+	w.logger.Infow("Funds committed successfully", "userID", userID, "amount", amount, "transactionID", transactionID)
 	return Success, nil
 }
 
-func (w *Client) ReleaseFunds(ctx context.Context, userID int64, amount int64) (string, error) {
-	resp, err := w.client.ReleaseFunds(ctx, &wallet.ReleaseFundsRequest{
-		UserId: userID,
-		Amount: amount,
+func (w *Client) ReleaseFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	var resp *wallet.ReleaseFundsResponse
+	err := w.caller.Do(ctx, config.CallWalletRelease, func(ctx context.Context) error {
+		var err error
+		resp, err = w.client.ReleaseFunds(ctx, &wallet.ReleaseFundsRequest{
+			UserId:        userID,
+			Amount:        amount,
+			TransactionId: transactionID,
+		})
+		return err
 	})
 	if err != nil {
-		w.logger.Errorw("Error releasing funds", "error", err, "userID", userID, "amount", amount)
+		w.logger.Errorw("Error releasing funds", "error", err, "userID", userID, "amount", amount, "transactionID", transactionID)
 		return "", err
 	}
 	if !resp.Success {
-		w.logger.Errorw("Failed to release funds", "error", resp.Message, "userID", userID, "amount", amount)
+		w.logger.Errorw("Failed to release funds", "error", resp.Message, "userID", userID, "amount", amount, "transactionID", transactionID)
 		return "", fmt.Errorf("release funds failed: %s", resp.Message)
 	}
-	w.logger.Infow("Funds released successfully", "userID", userID, "amount", amount)
+	w.logger.Infow("Funds released successfully", "userID", userID, "amount", amount, "transactionID", transactionID)
+	return Success, nil
+}
+
+func (w *Client) RefundFunds(ctx context.Context, userID int64, amount int64, transactionID string) (string, error) {
+	var resp *wallet.RefundFundsResponse
+	err := w.caller.Do(ctx, config.CallWalletRefund, func(ctx context.Context) error {
+		var err error
+		resp, err = w.client.RefundFunds(ctx, &wallet.RefundFundsRequest{
+			UserId:        userID,
+			Amount:        amount,
+			TransactionId: transactionID,
+		})
+		return err
+	})
+	if err != nil {
+		w.logger.Errorw("Error refunding funds", "error", err, "userID", userID, "amount", amount, "transactionID", transactionID)
+		return "", err
+	}
+	if !resp.Success {
+		w.logger.Errorw("Failed to refund funds", "error", resp.Message, "userID", userID, "amount", amount, "transactionID", transactionID)
+		return "", fmt.Errorf("refund funds failed: %s", resp.Message)
+	}
+	w.logger.Infow("Funds refunded successfully", "userID", userID, "amount", amount, "transactionID", transactionID)
+	return Success, nil
+}
+
+// RefundPartial возвращает часть списанного по transactionID; повтор с тем же refundID ничего не делает
+func (w *Client) RefundPartial(ctx context.Context, userID int64, amount int64, transactionID, refundID string) (string, error) {
+	var resp *wallet.RefundFundsResponse
+	err := w.caller.Do(ctx, config.CallWalletRefund, func(ctx context.Context) error {
+		var err error
+		resp, err = w.client.RefundFunds(ctx, &wallet.RefundFundsRequest{
+			UserId:        userID,
+			Amount:        amount,
+			TransactionId: transactionID,
+			RefundId:      refundID,
+		})
+		return err
+	})
+	if err != nil {
+		w.logger.Errorw("Error refunding funds partially", "error", err, "userID", userID, "amount", amount, "transactionID", transactionID, "refundID", refundID)
+		return "", err
+	}
+	if !resp.Success {
+		w.logger.Errorw("Failed to refund funds partially", "error", resp.Message, "userID", userID, "amount", amount, "transactionID", transactionID, "refundID", refundID)
+		return "", fmt.Errorf("partial refund failed: %s", resp.Message)
+	}
+	w.logger.Infow("Funds refunded partially", "userID", userID, "amount", amount, "transactionID", transactionID, "refundID", refundID)
 	return Success, nil
 }
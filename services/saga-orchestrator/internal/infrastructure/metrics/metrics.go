@@ -0,0 +1,28 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Метки SagaStepDuration
+const (
+	PhaseExecute    = "execute"
+	PhaseCompensate = "compensate"
+
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+)
+
+var (
+	// SagaStepDuration - длительность шагов саги. У параллельного шага это время до завершения
+	// всех веток, у каждой ветки - своя серия.
+	SagaStepDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "saga_step_duration_seconds",
+			Help:    "Saga step latency in seconds",
+			Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		},
+		[]string{"step", "phase", "outcome"},
+	)
+)
@@ -0,0 +1,240 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	"go.uber.org/zap"
+)
+
+const (
+	// archiveBatchSize - сколько событий переносится в архив одним запросом
+	archiveBatchSize = 1000
+	// archiveLockKey - ключ advisory lock: архивацию выполняет одна реплика за раз
+	archiveLockKey = 0x6f7574626f78
+	// archivePartitionPrefix - секции архива называются outbox_archive_ГГГГ_ММ
+	archivePartitionPrefix = "outbox_archive_"
+	archivePartitionLayout = "2006_01"
+)
+
+// Archiver переносит отправленные события старше retention из outbox в outbox_archive
+// и удаляет месячные секции архива старше archiveRetention
+type Archiver struct {
+	db       *sqlx.DB
+	log      *zap.SugaredLogger
+	interval time.Duration
+	// retention - сколько processed события остаются в outbox
+	retention time.Duration
+	// archiveRetention - сколько хранится архив; 0 - бессрочно
+	archiveRetention time.Duration
+}
+
+func NewArchiver(db *sqlx.DB, log *zap.SugaredLogger, interval, retention, archiveRetention time.Duration) *Archiver {
+	return &Archiver{
+		db:               db,
+		log:              log,
+		interval:         interval,
+		retention:        retention,
+		archiveRetention: archiveRetention,
+	}
+}
+
+func (a *Archiver) Start(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	a.log.Infow("outbox archiver started",
+		"interval", a.interval,
+		"retention", a.retention,
+		"archiveRetention", a.archiveRetention,
+	)
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.log.Info("outbox archiver stopped")
+			return
+		case <-ticker.C:
+			archived, err := a.Run(ctx)
+			if err != nil {
+				a.log.Errorw("outbox archivation failed", "error", err, "archived", archived)
+				continue
+			}
+			if archived > 0 {
+				a.log.Infow("outbox events archived", "archived", archived)
+			}
+		}
+	}
+}
+
+// Run выполняет один проход архивации и возвращает число перенесённых событий.
+// Если архивирует другая реплика, проход пропускается.
+func (a *Archiver) Run(ctx context.Context) (int64, error) {
+	// Advisory lock сессионный, поэтому все запросы прохода идут через одно соединение
+	conn, err := a.db.Conn(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, archiveLockKey).Scan(&locked); err != nil {
+		return 0, fmt.Errorf("failed to take archive lock: %w", err)
+	}
+	if !locked {
+		a.log.Debug("outbox archivation is running on another replica")
+		return 0, nil
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), `SELECT pg_advisory_unlock($1)`, archiveLockKey); err != nil {
+			a.log.Errorw("failed to release archive lock", "error", err)
+		}
+	}()
+
+	now := time.Now().UTC()
+	cutoff := now.Add(-a.retention)
+	if err := a.ensurePartitions(ctx, conn, cutoff); err != nil {
+		return 0, err
+	}
+
+	var archived int64
+	for {
+		n, err := a.archiveBatch(ctx, conn, cutoff)
+		archived += n
+		if err != nil {
+			return archived, err
+		}
+		if n < archiveBatchSize {
+			break
+		}
+	}
+
+	if a.archiveRetention > 0 {
+		if err := a.dropExpiredPartitions(ctx, conn, now.Add(-a.archiveRetention)); err != nil {
+			return archived, err
+		}
+	}
+	return archived, nil
+}
+
+// archiveBatch переносит пачку одним запросом: строка либо уже в архиве, либо ещё в outbox
+func (a *Archiver) archiveBatch(ctx context.Context, conn *sql.Conn, cutoff time.Time) (int64, error) {
+	res, err := conn.ExecContext(ctx, `
+		WITH moved AS (
+			DELETE FROM outbox
+			WHERE id IN (
+				SELECT id
+				FROM outbox
+				WHERE status = $1 AND processed_at < $2
+				ORDER BY processed_at
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, aggregate_id, aggregate_type, event_type, payload, attempts, created_at, processed_at
+		)
+		INSERT INTO outbox_archive (id, aggregate_id, aggregate_type, event_type, payload, attempts, created_at, processed_at)
+		SELECT id, aggregate_id, aggregate_type, event_type, payload, attempts, created_at, processed_at
+		FROM moved
+	`, entity.OutboxProcessed, cutoff, archiveBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive outbox events: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// ensurePartitions создаёт секции архива для всех месяцев, события которых пойдут в архив
+func (a *Archiver) ensurePartitions(ctx context.Context, conn *sql.Conn, cutoff time.Time) error {
+	var oldest sql.NullTime
+	if err := conn.QueryRowContext(ctx, `
+		SELECT MIN(processed_at) FROM outbox WHERE status = $1 AND processed_at < $2
+	`, entity.OutboxProcessed, cutoff).Scan(&oldest); err != nil {
+		return fmt.Errorf("failed to find oldest processed event: %w", err)
+	}
+	if !oldest.Valid {
+		return nil
+	}
+
+	for _, month := range archiveMonths(oldest.Time, cutoff) {
+		// Границы секции - константы, которые формируем сами: параметры в DDL недоступны
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf(
+			`CREATE TABLE IF NOT EXISTS %s PARTITION OF outbox_archive FOR VALUES FROM ('%s') TO ('%s')`,
+			partitionName(month),
+			month.Format(time.RFC3339),
+			month.AddDate(0, 1, 0).Format(time.RFC3339),
+		)); err != nil {
+			return fmt.Errorf("failed to create archive partition %s: %w", partitionName(month), err)
+		}
+	}
+	return nil
+}
+
+// dropExpiredPartitions удаляет секции, все события которых старше cutoff
+func (a *Archiver) dropExpiredPartitions(ctx context.Context, conn *sql.Conn, cutoff time.Time) error {
+	rows, err := conn.QueryContext(ctx, `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_class p ON p.oid = i.inhparent
+		WHERE p.relname = 'outbox_archive'
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to list archive partitions: %w", err)
+	}
+	var expired []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		if partitionExpired(name, cutoff) {
+			expired = append(expired, name)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, name := range expired {
+		if _, err := conn.ExecContext(ctx, `DROP TABLE IF EXISTS `+name); err != nil {
+			return fmt.Errorf("failed to drop archive partition %s: %w", name, err)
+		}
+		a.log.Infow("outbox archive partition dropped", "partition", name)
+	}
+	return nil
+}
+
+// archiveMonths - начала месяцев (UTC) с from по to включительно
+func archiveMonths(from, to time.Time) []time.Time {
+	from, to = from.UTC(), to.UTC()
+	month := time.Date(from.Year(), from.Month(), 1, 0, 0, 0, 0, time.UTC)
+	var months []time.Time
+	for !month.After(to) {
+		months = append(months, month)
+		month = month.AddDate(0, 1, 0)
+	}
+	return months
+}
+
+func partitionName(month time.Time) string {
+	return archivePartitionPrefix + month.Format(archivePartitionLayout)
+}
+
+// partitionExpired - месяц секции целиком закончился до cutoff; чужие таблицы не трогаем
+func partitionExpired(name string, cutoff time.Time) bool {
+	suffix, ok := strings.CutPrefix(name, archivePartitionPrefix)
+	if !ok {
+		return false
+	}
+	month, err := time.Parse(archivePartitionLayout, suffix)
+	if err != nil {
+		return false
+	}
+	return !month.AddDate(0, 1, 0).After(cutoff)
+}
@@ -0,0 +1,101 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestArchiveMonths(t *testing.T) {
+	from := time.Date(2025, 11, 20, 15, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC)
+
+	months := archiveMonths(from, to)
+
+	assert.Equal(t, []time.Time{
+		time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}, months)
+	assert.Equal(t, "outbox_archive_2025_11", partitionName(months[0]))
+}
+
+func TestPartitionExpired(t *testing.T) {
+	cutoff := time.Date(2026, 2, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"outbox_archive_2026_01", true},
+		{"outbox_archive_2026_02", false}, // в секции есть события новее cutoff
+		{"outbox_archive_2026_03", false},
+		{"outbox_archive_backup", false},
+		{"orders_2025_01", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, partitionExpired(tt.name, cutoff))
+		})
+	}
+}
+
+func TestArchiver_Run(t *testing.T) {
+	db := openTestDB(t)
+	_, err := db.Exec(`
+		CREATE TABLE outbox_archive (
+			id BIGINT NOT NULL,
+			aggregate_id TEXT NOT NULL,
+			aggregate_type VARCHAR(50) NOT NULL,
+			event_type VARCHAR(100) NOT NULL,
+			payload JSONB NOT NULL,
+			attempts INT NOT NULL,
+			created_at TIMESTAMP WITH TIME ZONE,
+			processed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+			archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			PRIMARY KEY (id, processed_at)
+		) PARTITION BY RANGE (processed_at)
+	`)
+	require.NoError(t, err)
+	insertEvents(t, db, 5)
+
+	// order-1..3 отправлены давно, order-4 - недавно, order-5 не отправлен совсем
+	_, err = db.Exec(`
+		UPDATE outbox SET status = 'processed', processed_at = NOW() - INTERVAL '40 days'
+		WHERE aggregate_id IN ('order-1', 'order-2');
+		UPDATE outbox SET status = 'processed', processed_at = NOW() - INTERVAL '10 days' WHERE aggregate_id = 'order-3';
+		UPDATE outbox SET status = 'processed', processed_at = NOW() WHERE aggregate_id = 'order-4';
+	`)
+	require.NoError(t, err)
+
+	archiver := NewArchiver(db, zap.NewNop().Sugar(), time.Hour, 7*24*time.Hour, 0)
+	archived, err := archiver.Run(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), archived)
+
+	var left []string
+	require.NoError(t, db.Select(&left, `SELECT aggregate_id FROM outbox ORDER BY id`))
+	assert.Equal(t, []string{"order-4", "order-5"}, left)
+
+	var inArchive int
+	require.NoError(t, db.Get(&inArchive, `SELECT COUNT(*) FROM outbox_archive`))
+	assert.Equal(t, 3, inArchive)
+
+	// Повторный проход ничего не переносит
+	archived, err = archiver.Run(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+
+	// Архив старше archiveRetention удаляется секциями
+	expiring := NewArchiver(db, zap.NewNop().Sugar(), time.Hour, 7*24*time.Hour, time.Nanosecond)
+	_, err = expiring.Run(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, db.Get(&inArchive, `SELECT COUNT(*) FROM outbox_archive`))
+	assert.Less(t, inArchive, 3)
+}
@@ -0,0 +1,91 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	"go.uber.org/zap"
+)
+
+const (
+	minReconnectInterval = 100 * time.Millisecond
+	maxReconnectInterval = 10 * time.Second
+	// pingInterval - как часто проверяем, что соединение LISTEN живо
+	pingInterval = 90 * time.Second
+)
+
+// Listener слушает NOTIFY из outbox и будит publisher, не дожидаясь следующего опроса.
+// Уведомления, пропущенные при обрыве соединения, подберёт опрос по интервалу.
+type Listener struct {
+	listener *pq.Listener
+	wake     chan struct{}
+	log      *zap.SugaredLogger
+}
+
+func NewListener(dsn string, log *zap.SugaredLogger) (*Listener, error) {
+	l := &Listener{
+		// Буфер в одно событие: сколько бы NOTIFY ни пришло, пока publisher занят, он проснётся один раз
+		wake: make(chan struct{}, 1),
+		log:  log,
+	}
+	l.listener = pq.NewListener(dsn, minReconnectInterval, maxReconnectInterval, l.onEvent)
+	if err := l.listener.Listen(entity.OutboxNotifyChannel); err != nil {
+		//nolint:errcheck // соединение всё равно не удалось
+		_ = l.listener.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// Wake - канал пробуждений для Publisher.Start
+func (l *Listener) Wake() <-chan struct{} {
+	return l.wake
+}
+
+func (l *Listener) Run(ctx context.Context) {
+	ticker := time.NewTicker(pingInterval)
+	defer ticker.Stop()
+
+	l.log.Infow("outbox listener started", "channel", entity.OutboxNotifyChannel)
+
+	for {
+		select {
+		case <-ctx.Done():
+			l.log.Info("outbox listener stopped")
+			return
+		case <-l.listener.Notify:
+			// nil приходит после переподключения: пока соединения не было, события могли накопиться
+			l.signal()
+		case <-ticker.C:
+			go func() {
+				if err := l.listener.Ping(); err != nil {
+					l.log.Warnw("outbox listener ping failed", "error", err)
+				}
+			}()
+		}
+	}
+}
+
+func (l *Listener) Close() error {
+	return l.listener.Close()
+}
+
+func (l *Listener) signal() {
+	select {
+	case l.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (l *Listener) onEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventDisconnected:
+		l.log.Warnw("outbox listener disconnected, falling back to polling", "error", err)
+	case pq.ListenerEventReconnected:
+		l.log.Info("outbox listener reconnected")
+	case pq.ListenerEventConnectionAttemptFailed:
+		l.log.Warnw("outbox listener reconnect failed", "error", err)
+	}
+}
@@ -0,0 +1,34 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	"go.uber.org/zap"
+)
+
+func TestListener_WakesOnNotify(t *testing.T) {
+	db := openTestDB(t)
+	listener, err := NewListener(testDSN(), zap.NewNop().Sugar())
+	require.NoError(t, err)
+	defer listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go listener.Run(ctx)
+
+	// Несколько уведомлений подряд сливаются в одно пробуждение
+	for i := 0; i < 3; i++ {
+		_, err = db.Exec(`SELECT pg_notify($1, '1')`, entity.OutboxNotifyChannel)
+		require.NoError(t, err)
+	}
+
+	select {
+	case <-listener.Wake():
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener did not wake up on NOTIFY")
+	}
+}
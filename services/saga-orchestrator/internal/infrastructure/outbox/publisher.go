@@ -1,163 +1,315 @@
 package outbox
 
 import (
+	"cmp"
 	"context"
+	"fmt"
+	"slices"
+	"strconv"
 	"time"
 
-	"github.com/confluentinc/confluent-kafka-go/kafka"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/vsespontanno/eCommerce/pkg/events"
+	"github.com/vsespontanno/eCommerce/pkg/pubsub"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/infrastructure/resilience"
 	"go.uber.org/zap"
 )
 
-const (
-	// flushTimeoutMs - таймаут ожидания доставки сообщения в Kafka (5 секунд)
-	flushTimeoutMs = 5000
-	// batchSize - максимальное количество событий за одну итерацию
-	batchSize = 100
-)
+// batchSize - максимальное количество событий за одну итерацию
+const batchSize = 100
 
 type Publisher struct {
-	db       *sqlx.DB
-	producer *kafka.Producer
-	interval time.Duration
-	log      *zap.SugaredLogger
-	topic    string
+	db        *sqlx.DB
+	transport pubsub.Publisher
+	interval  time.Duration
+	log       *zap.SugaredLogger
+	topic     string
+	// topics - топик для событий других агрегатов (aggregate_type -> топик); остальные уходят в topic
+	topics map[string]string
+	// retry - сколько раз и с какими паузами повторять отправку, прежде чем событие станет dead
+	retry config.RetryPolicy
+	// workerID - имя реплики в locked_by; lease - на сколько реплика захватывает пачку.
+	// lease должна быть заметно больше времени отправки пачки, иначе пачку заберёт другая реплика,
+	// пока эта ещё ждёт отчётов, и события уйдут дважды.
+	workerID string
+	lease    time.Duration
 }
 
-func NewOutboxPublisher(db *sqlx.DB, producer *kafka.Producer, log *zap.SugaredLogger, topic string, interval time.Duration) *Publisher {
+func NewOutboxPublisher(
+	db *sqlx.DB,
+	transport pubsub.Publisher,
+	log *zap.SugaredLogger,
+	topic string,
+	topics map[string]string,
+	interval time.Duration,
+	retry config.RetryPolicy,
+	workerID string,
+	lease time.Duration,
+) *Publisher {
+	// Дольше всех пачку отправляет Kafka: Publish ждёт отчётов о доставке до KafkaDeliveryTimeout
+	if minLease := 2 * pubsub.KafkaDeliveryTimeout; lease < minLease {
+		log.Warnw("outbox lease is too short, using minimum", "lease", lease, "minimum", minLease)
+		lease = minLease
+	}
 	return &Publisher{
-		db:       db,
-		producer: producer,
-		interval: interval,
-		log:      log,
-		topic:    topic,
+		db:        db,
+		transport: transport,
+		interval:  interval,
+		log:       log,
+		topic:     topic,
+		topics:    topics,
+		retry:     retry,
+		workerID:  workerID,
+		lease:     lease,
 	}
 }
 
-func (p *Publisher) Start(ctx context.Context) {
+// pendingEvent - событие outbox, выбранное для отправки
+type pendingEvent struct {
+	id            int64
+	aggregateID   string
+	aggregateType string
+	eventType     string
+	payload       []byte
+	attempts      int
+}
+
+// deliveryResult - итог отправки одного события; err == nil - транспорт подтвердил доставку
+type deliveryResult struct {
+	event pendingEvent
+	err   error
+}
+
+// Start публикует события по пробуждению из wake (NOTIFY) и по интервалу - на случай
+// пропущенных уведомлений и событий, отложенных до next_attempt_at. wake может быть nil.
+func (p *Publisher) Start(ctx context.Context, wake <-chan struct{}) {
 	ticker := time.NewTicker(p.interval)
 	defer ticker.Stop()
 
-	p.log.Infow("outbox publisher started", "interval", p.interval, "topic", p.topic)
+	p.log.Infow("outbox publisher started",
+		"interval", p.interval,
+		"topic", p.topic,
+		"topics", p.topics,
+		"maxAttempts", p.retry.MaxAttempts,
+		"workerID", p.workerID,
+		"lease", p.lease,
+		"notify", wake != nil,
+	)
 
 	for {
 		select {
 		case <-ctx.Done():
 			p.log.Info("outbox publisher stopped")
 			return
+		case <-wake:
 		case <-ticker.C:
-			p.processOutbox(ctx)
 		}
+		p.drain(ctx)
 	}
 }
 
-func (p *Publisher) processOutbox(ctx context.Context) {
-	rows, err := p.db.QueryContext(ctx,
-		`SELECT id, aggregate_id, event_type, payload
-         FROM outbox
-         WHERE status = 'pending'
-         ORDER BY created_at
-         LIMIT $1 FOR UPDATE SKIP LOCKED`, // SKIP LOCKED для конкурентности
-		batchSize,
-	)
+// drain публикует пачки, пока они приходят полными: за одно пробуждение могло накопиться больше batchSize
+func (p *Publisher) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		if p.processOutbox(ctx) < batchSize {
+			return
+		}
+	}
+}
+
+// processOutbox отправляет пачку событий целиком и одним UPDATE записывает итог по каждому.
+// Статус меняется ТОЛЬКО по отчёту о доставке - это сохраняет at-least-once семантику.
+// Возвращает размер захваченной пачки.
+func (p *Publisher) processOutbox(ctx context.Context) int {
+	events, err := p.claimBatch(ctx)
 	if err != nil {
-		p.log.Errorw("failed to fetch outbox", "error", err)
-		return
+		p.log.Errorw("failed to claim outbox batch", "error", err)
+		return 0
+	}
+	if len(events) == 0 {
+		return 0
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var id int64
-		var aggregateID string
-		var eventType string
-		var payload []byte
+	results := p.publishBatch(ctx, events)
+	if err := p.saveResults(ctx, results); err != nil {
+		// События останутся в очереди и уйдут повторно - consumer'ы идемпотентны по order_id
+		p.log.Errorw("failed to update outbox statuses", "error", err, "batch", len(results))
+		return 0
+	}
 
-		if err = rows.Scan(&id, &aggregateID, &eventType, &payload); err != nil {
-			p.log.Errorw("failed to scan outbox row", "error", err)
-			continue
+	var failed int
+	for _, r := range results {
+		if r.err != nil {
+			failed++
 		}
+	}
+	p.log.Infow("outbox batch published", "published", len(results)-failed, "failed", failed)
+	return len(events)
+}
 
-		// Отправляем в Kafka с aggregate_id как ключом (для партиционирования по order_id)
-		deliveryChan := make(chan kafka.Event, 1)
-		err = p.producer.Produce(&kafka.Message{
-			TopicPartition: kafka.TopicPartition{
-				Topic:     &p.topic,
-				Partition: kafka.PartitionAny,
-			},
-			Key:   []byte(aggregateID), // Используем order_id как ключ для партиционирования
-			Value: payload,
-		}, deliveryChan)
-
-		if err != nil {
-			p.log.Errorw("failed to produce message", "error", err, "id", id, "aggregateID", aggregateID)
-			p.markAsFailed(ctx, id)
-			continue
+// claimBatch одним запросом берёт в аренду готовые к отправке события.
+// FOR UPDATE SKIP LOCKED держит блокировки до конца этого же UPDATE, поэтому две реплики
+// не захватят одну строку, а после коммита её не выберет никто, пока не истечёт locked_until.
+// Аренда брошенной пачки (реплика упала) истекает сама, и события подбирает другая реплика.
+func (p *Publisher) claimBatch(ctx context.Context) ([]pendingEvent, error) {
+	rows, err := p.db.QueryContext(ctx, `
+		UPDATE outbox
+		SET locked_by = $1, locked_until = NOW() + $2 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id
+			FROM outbox
+			WHERE status IN ($4, $5)
+			  AND next_attempt_at <= NOW()
+			  AND (locked_until IS NULL OR locked_until < NOW())
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, aggregate_id, aggregate_type, event_type, payload, attempts
+	`, p.workerID, p.lease.Milliseconds(), batchSize, entity.OutboxPending, entity.OutboxFailed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := make([]pendingEvent, 0, batchSize)
+	for rows.Next() {
+		var e pendingEvent
+		if err := rows.Scan(&e.id, &e.aggregateID, &e.aggregateType, &e.eventType, &e.payload, &e.attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox row: %w", err)
 		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-		// КРИТИЧНО: Ждём подтверждения доставки ПЕРЕД обновлением статуса в БД
-		// Это гарантирует at-least-once семантику
-		remaining := p.producer.Flush(flushTimeoutMs)
-		if remaining > 0 {
-			p.log.Errorw("failed to flush message - timeout", "id", id, "aggregateID", aggregateID, "remaining", remaining)
-			p.markAsFailed(ctx, id)
-			continue
+	// RETURNING не сохраняет порядок подзапроса, а события одного заказа должны уйти по порядку
+	slices.SortFunc(events, func(a, b pendingEvent) int { return cmp.Compare(a.id, b.id) })
+	return events, nil
+}
+
+// publishBatch отправляет пачку транспорту, по одному вызову на топик; aggregate_id (order_id) -
+// ключ сообщения, чтобы события одного заказа попали в одну партицию и ушли по порядку
+func (p *Publisher) publishBatch(ctx context.Context, events []pendingEvent) []deliveryResult {
+	// Порядок событий внутри топика сохраняется: пачка уже отсортирована по id
+	byTopic := make(map[string][]int)
+	var topics []string
+	for i, event := range events {
+		topic := p.topicFor(event.aggregateType)
+		if _, ok := byTopic[topic]; !ok {
+			topics = append(topics, topic)
 		}
+		byTopic[topic] = append(byTopic[topic], i)
+	}
 
-		// Проверяем результат доставки
-		select {
-		case e := <-deliveryChan:
-			m, ok := e.(*kafka.Message)
-			if !ok {
-				p.log.Errorw("unexpected event type from delivery channel", "id", id, "aggregateID", aggregateID)
-				p.markAsFailed(ctx, id)
-				continue
+	results := make([]deliveryResult, len(events))
+	for _, topic := range topics {
+		idx := byTopic[topic]
+		msgs := make([]pubsub.Message, len(idx))
+		for j, i := range idx {
+			msgs[j] = pubsub.Message{
+				Key:     events[i].aggregateID,
+				Value:   events[i].payload,
+				Headers: messageHeaders(events[i]),
 			}
-			if m.TopicPartition.Error != nil {
-				p.log.Errorw("kafka delivery failed",
-					"error", m.TopicPartition.Error,
-					"id", id,
-					"aggregateID", aggregateID,
-				)
-				p.markAsFailed(ctx, id)
-				continue
-			}
-			p.log.Debugw("kafka delivery confirmed",
-				"id", id,
-				"aggregateID", aggregateID,
-				"partition", m.TopicPartition.Partition,
-				"offset", m.TopicPartition.Offset,
-			)
-		default:
-			// Сообщение было доставлено (Flush вернул 0)
 		}
+		errs := p.transport.Publish(ctx, topic, msgs)
+		for j, i := range idx {
+			results[i] = deliveryResult{event: events[i], err: errs[j]}
+		}
+	}
+	return results
+}
 
-		// Помечаем как обработанное ТОЛЬКО после успешной доставки в Kafka
-		_, err = p.db.ExecContext(ctx,
-			"UPDATE outbox SET status = 'processed', processed_at = NOW() WHERE id = $1",
-			id,
-		)
-		if err != nil {
-			p.log.Errorw("failed to update outbox status to processed", "error", err, "id", id)
-		} else {
-			p.log.Infow("event published successfully",
-				"id", id,
-				"aggregateID", aggregateID,
-				"eventType", eventType,
-				"topic", p.topic,
-			)
+// topicFor - топик для события агрегата aggregateType
+func (p *Publisher) topicFor(aggregateType string) string {
+	if topic, ok := p.topics[aggregateType]; ok && topic != "" {
+		return topic
+	}
+	return p.topic
+}
+
+// messageHeaders - атрибуты конверта CloudEvents в заголовках, чтобы consumer узнал тип и версию
+// события, не разбирая тело. Событие, сохранённое до появления конверта, уходит с типом из outbox
+// и LegacyVersion.
+func messageHeaders(event pendingEvent) map[string]string {
+	envelope, err := events.Decode(event.payload)
+	if err == nil && envelope.SpecVersion != "" {
+		headers := make(map[string]string, len(envelope.Headers()))
+		for _, h := range envelope.Headers() {
+			headers[h.Key] = h.Value
 		}
+		return headers
+	}
+	return map[string]string{
+		events.HeaderType:          event.eventType,
+		events.HeaderSchemaVersion: strconv.Itoa(events.LegacyVersion),
 	}
+}
 
-	if err = rows.Err(); err != nil {
-		p.log.Errorw("error iterating outbox rows", "error", err)
+// saveResults одним запросом помечает доставленные события processed, а неотправленные -
+// failed с паузой до следующей попытки или dead, если попытки исчерпаны, и снимает аренду.
+// Строки, аренду которых уже перехватила другая реплика, не трогаются.
+func (p *Publisher) saveResults(ctx context.Context, results []deliveryResult) error {
+	var (
+		ids      = make([]int64, len(results))
+		statuses = make([]string, len(results))
+		attempts = make([]int64, len(results))
+		errs     = make([]string, len(results))
+		delays   = make([]int64, len(results))
+	)
+	for i, r := range results {
+		ids[i] = r.event.id
+		attempts[i] = int64(r.event.attempts + 1)
+		if r.err == nil {
+			statuses[i] = string(entity.OutboxProcessed)
+			continue
+		}
+		status, delay := nextAttempt(p.retry, r.event.attempts+1)
+		statuses[i] = string(status)
+		errs[i] = r.err.Error()
+		delays[i] = delay.Milliseconds()
+		if status == entity.OutboxDead {
+			p.log.Errorw("outbox event is dead after exhausting attempts", "id", r.event.id, "attempts", attempts[i], "lastError", r.err)
+		}
+	}
+
+	// Используем контекст без отмены: отчёты уже получены, их нужно записать и при остановке
+	res, err := p.db.ExecContext(context.WithoutCancel(ctx), `
+		UPDATE outbox AS o
+		SET status = r.status,
+		    attempts = r.attempts,
+		    last_error = CASE WHEN r.status = $6 THEN o.last_error ELSE r.last_error END,
+		    processed_at = CASE WHEN r.status = $6 THEN NOW() ELSE o.processed_at END,
+		    next_attempt_at = NOW() + r.delay_ms * INTERVAL '1 millisecond',
+		    locked_by = NULL,
+		    locked_until = NULL
+		FROM unnest($1::bigint[], $2::text[], $3::int[], $4::text[], $5::bigint[])
+		    AS r(id, status, attempts, last_error, delay_ms)
+		WHERE o.id = r.id AND o.locked_by = $7
+	`, pq.Array(ids), pq.Array(statuses), pq.Array(attempts), pq.Array(errs), pq.Array(delays), entity.OutboxProcessed, p.workerID)
+	if err != nil {
+		return err
+	}
+
+	if n, err := res.RowsAffected(); err == nil && n < int64(len(results)) {
+		p.log.Warnw("outbox lease lost for part of the batch, another worker will retry it",
+			"workerID", p.workerID,
+			"batch", len(results),
+			"updated", n,
+		)
 	}
+	return nil
 }
 
-// markAsFailed помечает событие как failed в БД
-func (p *Publisher) markAsFailed(ctx context.Context, id int64) {
-	if _, err := p.db.ExecContext(ctx,
-		"UPDATE outbox SET status = 'failed' WHERE id = $1", id,
-	); err != nil {
-		p.log.Errorw("failed to update outbox status to failed", "error", err, "id", id)
+// nextAttempt решает судьбу события после attempts неудачных попыток
+func nextAttempt(policy config.RetryPolicy, attempts int) (entity.OutboxStatus, time.Duration) {
+	if attempts >= policy.MaxAttempts {
+		return entity.OutboxDead, 0
 	}
+	return entity.OutboxFailed, resilience.Backoff(policy, attempts)
 }
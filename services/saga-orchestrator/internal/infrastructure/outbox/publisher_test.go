@@ -0,0 +1,332 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vsespontanno/eCommerce/pkg/events"
+	"github.com/vsespontanno/eCommerce/pkg/pubsub"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	"go.uber.org/zap"
+)
+
+// fakeTransport подтверждает доставку сразу, кроме сообщений с заданной ошибкой
+type fakeTransport struct {
+	errs      map[string]error // ключ сообщения -> ошибка доставки
+	published int
+	topics    map[string][]string // топик -> ключи доставленных в него сообщений
+	onPublish func(key string)
+}
+
+func (f *fakeTransport) Publish(_ context.Context, topic string, msgs []pubsub.Message) []error {
+	errs := make([]error, len(msgs))
+	for i, msg := range msgs {
+		if errs[i] = f.errs[msg.Key]; errs[i] != nil {
+			continue
+		}
+		f.published++
+		if f.topics != nil {
+			f.topics[topic] = append(f.topics[topic], msg.Key)
+		}
+		if f.onPublish != nil {
+			f.onPublish(msg.Key)
+		}
+	}
+	return errs
+}
+
+func (f *fakeTransport) Close() error {
+	return nil
+}
+
+func newTestPublisher(transport *fakeTransport) *Publisher {
+	return &Publisher{
+		transport: transport,
+		log:       zap.NewNop().Sugar(),
+		topic:     "orders",
+		retry:     config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: time.Minute},
+	}
+}
+
+func batch(keys ...string) []pendingEvent {
+	events := make([]pendingEvent, 0, len(keys))
+	for i, key := range keys {
+		events = append(events, pendingEvent{id: int64(i + 1), aggregateID: key, payload: []byte(`{}`)})
+	}
+	return events
+}
+
+func TestPublisher_PublishBatch(t *testing.T) {
+	t.Run("All Delivered", func(t *testing.T) {
+		transport := &fakeTransport{}
+		publisher := newTestPublisher(transport)
+		events := batch("order-1", "order-2", "order-3")
+
+		results := publisher.publishBatch(context.Background(), events)
+
+		assert.Equal(t, 3, transport.published)
+		for i, r := range results {
+			assert.Equal(t, events[i].id, r.event.id)
+			assert.NoError(t, r.err)
+		}
+	})
+
+	t.Run("Failures Reported Per Message", func(t *testing.T) {
+		transport := &fakeTransport{errs: map[string]error{"order-2": errors.New("queue full")}}
+		publisher := newTestPublisher(transport)
+
+		results := publisher.publishBatch(context.Background(), batch("order-1", "order-2", "order-3"))
+
+		assert.NoError(t, results[0].err)
+		assert.EqualError(t, results[1].err, "queue full")
+		assert.NoError(t, results[2].err)
+	})
+
+	t.Run("Routed By Aggregate Type", func(t *testing.T) {
+		transport := &fakeTransport{
+			topics: map[string][]string{},
+			errs:   map[string]error{"txn-1": errors.New("queue full")},
+		}
+		publisher := newTestPublisher(transport)
+		publisher.topics = map[string]string{entity.AggregateWalletTransaction: "reservations"}
+		events := []pendingEvent{
+			{id: 1, aggregateID: "order-1", aggregateType: entity.AggregateOrder},
+			{id: 2, aggregateID: "txn-1", aggregateType: entity.AggregateWalletTransaction},
+			{id: 3, aggregateID: "order-2", aggregateType: entity.AggregateOrder},
+			{id: 4, aggregateID: "txn-2", aggregateType: entity.AggregateWalletTransaction},
+		}
+
+		results := publisher.publishBatch(context.Background(), events)
+
+		// Чужие события не попадают в топик заказов, а ошибки остаются при своих событиях
+		assert.Equal(t, map[string][]string{"orders": {"order-1", "order-2"}, "reservations": {"txn-2"}}, transport.topics)
+		for i, r := range results {
+			assert.Equal(t, events[i].id, r.event.id)
+		}
+		assert.EqualError(t, results[1].err, "queue full")
+		assert.NoError(t, results[3].err)
+	})
+}
+
+func TestMessageHeaders(t *testing.T) {
+
+	t.Run("Envelope Attributes", func(t *testing.T) {
+		envelope, err := events.New("order-1:OrderCompleted", entity.EventTypeOrderCompleted, entity.EventSource, entity.EventSchemaVersion, time.Now(), entity.OrderEvent{OrderID: "order-1"})
+		require.NoError(t, err)
+		payload, err := json.Marshal(envelope)
+		require.NoError(t, err)
+
+		headers := messageHeaders(pendingEvent{eventType: entity.EventTypeOrderCompleted, payload: payload})
+
+		assert.Equal(t, entity.EventTypeOrderCompleted, headers[events.HeaderType])
+		assert.Equal(t, "1", headers[events.HeaderSchemaVersion])
+		assert.Equal(t, "order-1:OrderCompleted", headers[events.HeaderID])
+		assert.Equal(t, entity.EventSource, headers[events.HeaderSource])
+	})
+
+	t.Run("Event Saved Before Envelope", func(t *testing.T) {
+		headers := messageHeaders(pendingEvent{eventType: entity.EventTypeOrderFailed, payload: []byte(`{"order_id":"order-1"}`)})
+
+		assert.Equal(t, map[string]string{
+			events.HeaderType:          entity.EventTypeOrderFailed,
+			events.HeaderSchemaVersion: "0",
+		}, headers)
+	})
+}
+
+func TestNextAttempt(t *testing.T) {
+	policy := config.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Second, MaxBackoff: 4 * time.Second}
+
+	t.Run("Retries With Growing Backoff", func(t *testing.T) {
+		status, delay := nextAttempt(policy, 1)
+		assert.Equal(t, entity.OutboxFailed, status)
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, time.Second)
+
+		status, delay = nextAttempt(policy, 2)
+		assert.Equal(t, entity.OutboxFailed, status)
+		assert.GreaterOrEqual(t, delay, time.Second)
+		assert.LessOrEqual(t, delay, 2*time.Second)
+	})
+
+	t.Run("Dead After Max Attempts", func(t *testing.T) {
+		status, delay := nextAttempt(policy, 3)
+		assert.Equal(t, entity.OutboxDead, status)
+		assert.Zero(t, delay)
+	})
+}
+
+// openTestDB создаёт для теста отдельную схему и накатывает в неё миграции проекта.
+// Тесты с Postgres запускаются, только если задан OUTBOX_TEST_PG_DSN; в CI его задаёт джоба с Postgres.
+func openTestDB(t *testing.T) *sqlx.DB {
+	t.Helper()
+	dsn := testDSN()
+	if dsn == "" {
+		t.Skip("OUTBOX_TEST_PG_DSN is not set")
+	}
+
+	admin, err := sqlx.Connect("postgres", dsn)
+	require.NoError(t, err)
+	schema := fmt.Sprintf("outbox_test_%d", time.Now().UnixNano())
+	_, err = admin.Exec("CREATE SCHEMA " + schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		admin.Exec("DROP SCHEMA " + schema + " CASCADE") //nolint:errcheck
+		admin.Close()
+	})
+
+	// search_path передаётся как параметр соединения, чтобы его получило каждое соединение пула
+	if strings.Contains(dsn, "://") {
+		sep := "?"
+		if strings.Contains(dsn, "?") {
+			sep = "&"
+		}
+		dsn += sep + "search_path=" + schema
+	} else {
+		dsn += " search_path=" + schema
+	}
+	db, err := sqlx.Connect("postgres", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	applyMigrations(t, db)
+	return db
+}
+
+// migrationsDir - миграции проекта, которые накатывает goose при деплое
+const migrationsDir = "../../../../../migrations"
+
+// applyMigrations выполняет секции "+goose Up" всех миграций по порядку,
+// чтобы тесты шли на той же схеме outbox, что и прод, а не на её копии
+func applyMigrations(t *testing.T, db *sqlx.DB) {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(migrationsDir, "*.sql"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files, "no migrations in %s", migrationsDir)
+	sort.Strings(files)
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		_, up, found := strings.Cut(string(data), "-- +goose Up")
+		require.True(t, found, "%s has no +goose Up section", file)
+		up, _, _ = strings.Cut(up, "-- +goose Down")
+		_, err = db.Exec(up)
+		require.NoError(t, err, "apply %s", filepath.Base(file))
+	}
+}
+
+func testDSN() string {
+	return os.Getenv("OUTBOX_TEST_PG_DSN")
+}
+
+func insertEvents(t *testing.T, db *sqlx.DB, n int) {
+	t.Helper()
+	_, err := db.Exec(`
+		INSERT INTO outbox (aggregate_id, aggregate_type, event_type, payload)
+		SELECT 'order-' || i, 'Order', 'OrderCompleted', '{}'
+		FROM generate_series(1, $1) AS i
+	`, n)
+	require.NoError(t, err)
+}
+
+func newDBPublisher(db *sqlx.DB, transport *fakeTransport, workerID string) *Publisher {
+	publisher := newTestPublisher(transport)
+	publisher.db = db
+	publisher.workerID = workerID
+	publisher.lease = time.Minute
+	return publisher
+}
+
+func TestPublisher_ConcurrentWorkers(t *testing.T) {
+	db := openTestDB(t)
+	const events, workers = 2000, 8
+	insertEvents(t, db, events)
+
+	var mu sync.Mutex
+	sentBy := make(map[string][]string, events)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		workerID := fmt.Sprintf("worker-%d", w)
+		transport := &fakeTransport{onPublish: func(key string) {
+			mu.Lock()
+			sentBy[key] = append(sentBy[key], workerID)
+			mu.Unlock()
+		}}
+		publisher := newDBPublisher(db, transport, workerID)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			deadline := time.Now().Add(30 * time.Second)
+			for time.Now().Before(deadline) {
+				var left int
+				if err := db.Get(&left, `SELECT COUNT(*) FROM outbox WHERE status <> 'processed'`); err != nil || left == 0 {
+					return
+				}
+				publisher.processOutbox(context.Background())
+			}
+		}()
+	}
+	wg.Wait()
+
+	var processed int
+	require.NoError(t, db.Get(&processed, `SELECT COUNT(*) FROM outbox WHERE status = 'processed' AND attempts = 1 AND locked_by IS NULL`))
+	assert.Equal(t, events, processed)
+
+	assert.Len(t, sentBy, events)
+	for key, senders := range sentBy {
+		assert.Len(t, senders, 1, "event %s published by %v", key, senders)
+	}
+}
+
+func TestPublisher_Lease(t *testing.T) {
+	db := openTestDB(t)
+	insertEvents(t, db, 3)
+	ctx := context.Background()
+
+	first := newDBPublisher(db, &fakeTransport{}, "worker-1")
+	second := newDBPublisher(db, &fakeTransport{}, "worker-2")
+
+	claimed, err := first.claimBatch(ctx)
+	require.NoError(t, err)
+	assert.Len(t, claimed, 3)
+
+	// Пока аренда действует, пачку никто больше не берёт
+	stolen, err := second.claimBatch(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, stolen)
+
+	// Реплика "зависла": аренда истекла, и события забирает другая
+	_, err = db.Exec(`UPDATE outbox SET locked_until = NOW() - INTERVAL '1 second'`)
+	require.NoError(t, err)
+	stolen, err = second.claimBatch(ctx)
+	require.NoError(t, err)
+	assert.Len(t, stolen, 3)
+
+	// Опоздавшие результаты первой реплики не перетирают состояние, которым владеет вторая
+	require.NoError(t, first.saveResults(ctx, first.publishBatch(ctx, claimed)))
+	var owned int
+	require.NoError(t, db.Get(&owned, `SELECT COUNT(*) FROM outbox WHERE status = 'pending' AND locked_by = 'worker-2'`))
+	assert.Equal(t, 3, owned)
+
+	require.NoError(t, second.saveResults(ctx, second.publishBatch(ctx, stolen)))
+	var processed int
+	require.NoError(t, db.Get(&processed, `SELECT COUNT(*) FROM outbox WHERE status = 'processed' AND locked_by IS NULL`))
+	assert.Equal(t, 3, processed)
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"go.uber.org/zap"
+)
+
+// AuditRepository - журнал действий операторов над сагами
+type AuditRepository struct {
+	db  *sqlx.DB
+	log *zap.SugaredLogger
+}
+
+func NewAuditRepository(db *sqlx.DB, log *zap.SugaredLogger) *AuditRepository {
+	return &AuditRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// RecordAction добавляет запись в журнал и, если сага по заказу есть, в хронологию заказа
+func (r *AuditRepository) RecordAction(ctx context.Context, action sagaEntity.AdminAction) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH recorded AS (
+			INSERT INTO saga_admin_actions (order_id, action, operator, reason, result, error, event_id)
+			VALUES ($1, $2, $3, $4, $5, $6, NULLIF($7, 0))
+			RETURNING order_id, result, error
+		)
+		INSERT INTO saga_events (order_id, saga_id, kind, type, status, detail, error)
+		SELECT COALESCE(s.parent_order_id, s.order_id), s.order_id, s.kind, $8, recorded.result, $9, recorded.error
+		FROM recorded
+		JOIN saga_instances s ON s.order_id = recorded.order_id
+	`, action.OrderID, action.Action, action.Operator, action.Reason, action.Result, action.Error, action.EventID,
+		sagaEntity.TimelineAdminAction, actionDetail(action))
+	if err != nil {
+		r.log.Errorw("failed to record admin action", "error", err, "orderID", action.OrderID, "action", action.Action)
+		return err
+	}
+	return nil
+}
+
+// ListActions возвращает записи журнала, новые первыми
+func (r *AuditRepository) ListActions(ctx context.Context, filter sagaEntity.AdminActionFilter) ([]sagaEntity.AdminAction, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, order_id, action, operator, reason, result, error, COALESCE(event_id, 0), created_at
+		FROM saga_admin_actions
+		WHERE ($1 = '' OR order_id = $1) AND ($2 = '' OR operator = $2)
+		ORDER BY id DESC
+		LIMIT $3
+	`, filter.OrderID, filter.Operator, filter.Limit)
+	if err != nil {
+		r.log.Errorw("failed to query admin actions", "error", err, "orderID", filter.OrderID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var actions []sagaEntity.AdminAction
+	for rows.Next() {
+		var action sagaEntity.AdminAction
+		if err := rows.Scan(
+			&action.ID,
+			&action.OrderID,
+			&action.Action,
+			&action.Operator,
+			&action.Reason,
+			&action.Result,
+			&action.Error,
+			&action.EventID,
+			&action.CreatedAt,
+		); err != nil {
+			r.log.Errorw("failed to scan admin action", "error", err)
+			return nil, err
+		}
+		actions = append(actions, action)
+	}
+
+	return actions, rows.Err()
+}
+
+// actionDetail - кто и зачем выполнил действие, для хронологии заказа
+func actionDetail(action sagaEntity.AdminAction) string {
+	detail := fmt.Sprintf("%s by %s", action.Action, action.Operator)
+	if action.EventID != 0 {
+		detail += fmt.Sprintf(" (outbox event %d)", action.EventID)
+	}
+	if action.Reason != "" {
+		detail += ": " + action.Reason
+	}
+	return detail
+}
@@ -2,9 +2,14 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/vsespontanno/eCommerce/pkg/events"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
 	"go.uber.org/zap"
 )
@@ -21,29 +26,44 @@ func NewOutboxRepository(db *sqlx.DB, log *zap.SugaredLogger) *OutboxRepository
 	}
 }
 
-// SaveEvent сохраняет событие в outbox таблицу
+// SaveEvent сохраняет событие в outbox таблицу. Повторное событие того же типа той же саги
+// не сохраняется: шаг outbox мог выполниться до падения, но не успеть записаться в историю.
 func (r *OutboxRepository) SaveEvent(ctx context.Context, event entity.OrderEvent) error {
 	// Устанавливаем EventType если не задан
 	if event.EventType == "" {
 		event.EventType = entity.EventTypeOrderCompleted
 	}
 
-	payload, err := json.Marshal(event)
+	// ID конверта совпадает у повторов события - consumer может отбросить дубль и по нему
+	envelope, err := events.New(event.DedupeKey(), event.EventType, entity.EventSource, entity.EventSchemaVersion, time.Now(), event)
+	if err != nil {
+		r.log.Errorw("failed to wrap event", "error", err, "orderID", event.OrderID)
+		return err
+	}
+	payload, err := json.Marshal(envelope)
 	if err != nil {
 		r.log.Errorw("failed to marshal event", "error", err, "orderID", event.OrderID)
 		return err
 	}
 
+	// NOTIFY будит publisher сразу; уведомление уходит только после коммита вставки
 	query := `
-		INSERT INTO outbox (aggregate_id, aggregate_type, event_type, payload, status)
-		VALUES ($1, $2, $3, $4, 'pending')
+		WITH inserted AS (
+			INSERT INTO outbox (aggregate_id, aggregate_type, event_type, payload, status, dedupe_key)
+			VALUES ($1, $2, $3, $4, 'pending', $5)
+			ON CONFLICT (dedupe_key) WHERE dedupe_key IS NOT NULL DO NOTHING
+			RETURNING id
+		)
+		SELECT pg_notify($6, id::text) FROM inserted
 	`
 
 	_, err = r.db.ExecContext(ctx, query,
 		event.OrderID,
-		"Order",
+		entity.AggregateOrder,
 		event.EventType,
 		payload,
+		event.DedupeKey(),
+		entity.OutboxNotifyChannel,
 	)
 
 	if err != nil {
@@ -58,3 +78,91 @@ func (r *OutboxRepository) SaveEvent(ctx context.Context, event entity.OrderEven
 	)
 	return nil
 }
+
+// GetEvent возвращает событие outbox по ID
+func (r *OutboxRepository) GetEvent(ctx context.Context, id int64) (*entity.OutboxEvent, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+outboxColumns+`
+		FROM outbox
+		WHERE id = $1
+	`, id)
+
+	event, err := scanOutboxEvent(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrEventNotFound
+		}
+		r.log.Errorw("failed to get outbox event", "error", err, "id", id)
+		return nil, err
+	}
+	return event, nil
+}
+
+// ListDeadEvents возвращает события, исчерпавшие попытки отправки, старые первыми
+func (r *OutboxRepository) ListDeadEvents(ctx context.Context, limit int) ([]entity.OutboxEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+outboxColumns+`
+		FROM outbox
+		WHERE status = $1
+		ORDER BY created_at
+		LIMIT $2
+	`, entity.OutboxDead, limit)
+	if err != nil {
+		r.log.Errorw("failed to query dead outbox events", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []entity.OutboxEvent
+	for rows.Next() {
+		event, err := scanOutboxEvent(rows)
+		if err != nil {
+			r.log.Errorw("failed to scan outbox event", "error", err)
+			return nil, err
+		}
+		events = append(events, *event)
+	}
+
+	return events, rows.Err()
+}
+
+// RequeueEvent возвращает dead событие в очередь с обнулённым счётчиком попыток.
+// last_error сохраняется, чтобы было видно, почему событие туда попадало.
+// false - событие не в статусе dead: его уже вернул кто-то другой.
+func (r *OutboxRepository) RequeueEvent(ctx context.Context, id int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE outbox
+		SET status = $2, attempts = 0, next_attempt_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, id, entity.OutboxPending, entity.OutboxDead)
+	if err != nil {
+		r.log.Errorw("failed to requeue outbox event", "error", err, "id", id)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+const outboxColumns = `id, aggregate_id, aggregate_type, event_type, payload, status, attempts, last_error, next_attempt_at, created_at`
+
+func scanOutboxEvent(row rowScanner) (*entity.OutboxEvent, error) {
+	var event entity.OutboxEvent
+	if err := row.Scan(
+		&event.ID,
+		&event.AggregateID,
+		&event.AggregateType,
+		&event.EventType,
+		&event.Payload,
+		&event.Status,
+		&event.Attempts,
+		&event.LastError,
+		&event.NextAttemptAt,
+		&event.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
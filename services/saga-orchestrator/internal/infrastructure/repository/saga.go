@@ -0,0 +1,456 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"go.uber.org/zap"
+)
+
+type SagaStateRepository struct {
+	db  *sqlx.DB
+	log *zap.SugaredLogger
+}
+
+func NewSagaStateRepository(db *sqlx.DB, log *zap.SugaredLogger) *SagaStateRepository {
+	return &SagaStateRepository{
+		db:  db,
+		log: log,
+	}
+}
+
+// CreateSaga сохраняет новую сагу в статусе RUNNING.
+// Если у пользователя уже есть сага с тем же ключом идемпотентности, возвращает ErrDuplicateCheckout.
+func (r *SagaStateRepository) CreateSaga(ctx context.Context, instance sagaEntity.Instance) error {
+	products, err := json.Marshal(instance.Products)
+	if err != nil {
+		r.log.Errorw("failed to marshal saga products", "error", err, "orderID", instance.OrderID)
+		return err
+	}
+
+	// Уникальный индекс по (user_id, idempotency_key) разрешает гонку двух одновременных повторов.
+	// Дубль не вставляется, и события в хронологии для него тоже нет.
+	query := `
+		WITH created AS (
+			INSERT INTO saga_instances (order_id, user_id, total, products, status, idempotency_key, kind, parent_order_id)
+			VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), $7, NULLIF($8, ''))
+			ON CONFLICT (user_id, idempotency_key) WHERE idempotency_key IS NOT NULL DO NOTHING
+			RETURNING ` + timelineSource + `
+		)
+		` + insertStatusEvent + ` FROM created
+	`
+
+	res, err := r.db.ExecContext(ctx, query,
+		instance.OrderID,
+		instance.UserID,
+		instance.Total,
+		products,
+		sagaEntity.StatusRunning,
+		instance.IdempotencyKey,
+		instance.Kind,
+		instance.ParentOrderID,
+	)
+	if err != nil {
+		r.log.Errorw("failed to create saga instance", "error", err, "orderID", instance.OrderID)
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return apperrors.ErrDuplicateCheckout
+	}
+	return nil
+}
+
+// BeginCancellation переводит завершённую сагу оформления заказа в отмену, а упавшую отмену - снова в RUNNING.
+// Отмена начинается с первого шага; упавшая продолжается с шага, на котором остановилась.
+// false - сага не найдена, принадлежит другому пользователю, ещё не завершена или уже отменяется.
+func (r *SagaStateRepository) BeginCancellation(ctx context.Context, orderID string, userID int64) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		WITH updated AS (
+			UPDATE saga_instances
+			SET kind = $3,
+				status = $4,
+				current_step = CASE WHEN kind = $5 THEN '' ELSE current_step END,
+				step_status = CASE WHEN kind = $5 THEN '' ELSE step_status END,
+				error = '',
+				updated_at = NOW()
+			WHERE order_id = $1 AND user_id = $2
+			  AND ((kind = $5 AND status = $6) OR (kind = $3 AND status = $7))
+			RETURNING `+timelineSource+`
+		)
+		`+insertStatusEvent+` FROM updated
+	`, orderID, userID, sagaEntity.KindCancellation, sagaEntity.StatusRunning,
+		sagaEntity.KindCheckout, sagaEntity.StatusCompleted, sagaEntity.StatusFailed)
+	if err != nil {
+		r.log.Errorw("failed to begin saga cancellation", "error", err, "orderID", orderID)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// RecordStep добавляет переход в историю шагов и хронологию заказа и, для прямого хода, сдвигает current_step
+func (r *SagaStateRepository) RecordStep(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error {
+	return r.recordStep(ctx, orderID, step, status, errMsg, status.IsForward())
+}
+
+// RecordBranch добавляет переход ветки параллельного шага в историю и хронологию;
+// current_step остаётся на самом параллельном шаге
+func (r *SagaStateRepository) RecordBranch(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string) error {
+	return r.recordStep(ctx, orderID, step, status, errMsg, false)
+}
+
+func (r *SagaStateRepository) recordStep(ctx context.Context, orderID string, step sagaEntity.StepName, status sagaEntity.StepStatus, errMsg string, moveCurrent bool) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		//nolint:errcheck // Rollback после Commit возвращает ErrTxDone
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO saga_steps (order_id, step, status, error)
+		VALUES ($1, $2, $3, $4)
+	`, orderID, step, status, errMsg)
+	if err != nil {
+		r.log.Errorw("failed to insert saga step", "error", err, "orderID", orderID, "step", step)
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO saga_events (order_id, saga_id, kind, type, step, status, error)
+		SELECT COALESCE(parent_order_id, order_id), order_id, kind, $2, $3, $4, $5
+		FROM saga_instances
+		WHERE order_id = $1
+	`, orderID, sagaEntity.TimelineStep, step, status, errMsg)
+	if err != nil {
+		r.log.Errorw("failed to insert saga step event", "error", err, "orderID", orderID, "step", step)
+		return err
+	}
+
+	if moveCurrent {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE saga_instances
+			SET current_step = $2, step_status = $3, updated_at = NOW()
+			WHERE order_id = $1
+		`, orderID, step, status)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE saga_instances SET updated_at = NOW() WHERE order_id = $1
+		`, orderID)
+	}
+	if err != nil {
+		r.log.Errorw("failed to update saga instance step", "error", err, "orderID", orderID, "step", step)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// UpdateStatus меняет статус саги целиком
+func (r *SagaStateRepository) UpdateStatus(ctx context.Context, orderID string, status sagaEntity.Status, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+		WITH updated AS (
+			UPDATE saga_instances
+			SET status = $2, error = $3, updated_at = NOW()
+			WHERE order_id = $1
+			RETURNING `+timelineSource+`
+		)
+		`+insertStatusEvent+` FROM updated
+	`, orderID, status, errMsg)
+	if err != nil {
+		r.log.Errorw("failed to update saga status", "error", err, "orderID", orderID, "status", status)
+		return err
+	}
+	return nil
+}
+
+// SetFailureReason сохраняет причину провала, которую увидит пользователь
+func (r *SagaStateRepository) SetFailureReason(ctx context.Context, orderID string, reason string) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE saga_instances
+		SET failure_reason = $2
+		WHERE order_id = $1
+	`, orderID, reason)
+	if err != nil {
+		r.log.Errorw("failed to save saga failure reason", "error", err, "orderID", orderID, "reason", reason)
+		return err
+	}
+	return nil
+}
+
+// GetSaga возвращает сагу по ID заказа
+func (r *SagaStateRepository) GetSaga(ctx context.Context, orderID string) (*sagaEntity.Instance, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+instanceColumns+`
+		FROM saga_instances
+		WHERE order_id = $1
+	`, orderID)
+
+	instance, err := scanInstance(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrSagaNotFound
+		}
+		r.log.Errorw("failed to get saga instance", "error", err, "orderID", orderID)
+		return nil, err
+	}
+	return instance, nil
+}
+
+// GetSagaByIdempotencyKey возвращает сагу, запущенную пользователем с этим ключом идемпотентности
+func (r *SagaStateRepository) GetSagaByIdempotencyKey(ctx context.Context, userID int64, key string) (*sagaEntity.Instance, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+instanceColumns+`
+		FROM saga_instances
+		WHERE user_id = $1 AND idempotency_key = $2
+	`, userID, key)
+
+	instance, err := scanInstance(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.ErrSagaNotFound
+		}
+		r.log.Errorw("failed to get saga by idempotency key", "error", err, "userID", userID)
+		return nil, err
+	}
+	return instance, nil
+}
+
+// ListUnfinished возвращает саги, которые не дошли до финального статуса и не обновлялись дольше olderThan.
+// Сагу, обновлённую недавно, скорее всего ещё выполняет одна из реплик.
+func (r *SagaStateRepository) ListUnfinished(ctx context.Context, olderThan time.Duration) ([]sagaEntity.Instance, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+instanceColumns+`
+		FROM saga_instances
+		WHERE status IN ($1, $2) AND updated_at < $3
+		ORDER BY created_at ASC
+	`, sagaEntity.StatusRunning, sagaEntity.StatusCompensating, time.Now().Add(-olderThan))
+	if err != nil {
+		r.log.Errorw("failed to query unfinished sagas", "error", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instances []sagaEntity.Instance
+	for rows.Next() {
+		instance, err := scanInstance(rows)
+		if err != nil {
+			r.log.Errorw("failed to scan saga instance", "error", err)
+			continue
+		}
+		instances = append(instances, *instance)
+	}
+
+	return instances, rows.Err()
+}
+
+// ListSagas возвращает саги для операторов: по статусу и по тому, как давно они не обновлялись.
+// Самые давно не обновлявшиеся - первыми.
+func (r *SagaStateRepository) ListSagas(ctx context.Context, filter sagaEntity.SagaFilter) ([]sagaEntity.Instance, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+instanceColumns+`
+		FROM saga_instances
+		WHERE ($1 = '' OR status = $1) AND updated_at <= $2
+		ORDER BY updated_at ASC
+		LIMIT $3
+	`, filter.Status, time.Now().Add(-filter.OlderThan), filter.Limit)
+	if err != nil {
+		r.log.Errorw("failed to query sagas", "error", err, "status", filter.Status)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var instances []sagaEntity.Instance
+	for rows.Next() {
+		instance, err := scanInstance(rows)
+		if err != nil {
+			r.log.Errorw("failed to scan saga instance", "error", err)
+			continue
+		}
+		instances = append(instances, *instance)
+	}
+
+	return instances, rows.Err()
+}
+
+// ListSteps возвращает историю переходов саги в порядке записи
+func (r *SagaStateRepository) ListSteps(ctx context.Context, orderID string) ([]sagaEntity.StepRecord, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, order_id, step, status, error, created_at
+		FROM saga_steps
+		WHERE order_id = $1
+		ORDER BY id ASC
+	`, orderID)
+	if err != nil {
+		r.log.Errorw("failed to query saga steps", "error", err, "orderID", orderID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var steps []sagaEntity.StepRecord
+	for rows.Next() {
+		var step sagaEntity.StepRecord
+		if err := rows.Scan(&step.ID, &step.OrderID, &step.Step, &step.Status, &step.Error, &step.CreatedAt); err != nil {
+			r.log.Errorw("failed to scan saga step", "error", err, "orderID", orderID)
+			return nil, err
+		}
+		steps = append(steps, step)
+	}
+
+	return steps, rows.Err()
+}
+
+// TransitionStatus меняет статус саги, только если она не менялась с момента чтения:
+// статус и updated_at должны совпасть с прочитанными. false - сагу успел изменить кто-то другой.
+func (r *SagaStateRepository) TransitionStatus(ctx context.Context, instance sagaEntity.Instance, to sagaEntity.Status, errMsg string) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		WITH updated AS (
+			UPDATE saga_instances
+			SET status = $4, error = $5, updated_at = NOW()
+			WHERE order_id = $1 AND status = $2 AND updated_at = $3
+			RETURNING `+timelineSource+`
+		)
+		`+insertStatusEvent+` FROM updated
+	`, instance.OrderID, instance.Status, instance.UpdatedAt, to, errMsg)
+	if err != nil {
+		r.log.Errorw("failed to transition saga status", "error", err, "orderID", instance.OrderID, "to", to)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ClaimSaga забирает сагу на выполнение: сдвигает updated_at, только если статус и updated_at
+// совпадают с прочитанными. false - сагу уже забрала или изменила другая реплика.
+func (r *SagaStateRepository) ClaimSaga(ctx context.Context, instance sagaEntity.Instance) (bool, error) {
+	res, err := r.db.ExecContext(ctx, `
+		UPDATE saga_instances
+		SET updated_at = NOW()
+		WHERE order_id = $1 AND status = $2 AND updated_at = $3
+	`, instance.OrderID, instance.Status, instance.UpdatedAt)
+	if err != nil {
+		r.log.Errorw("failed to claim saga", "error", err, "orderID", instance.OrderID)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// RecordAttempt пишет в хронологию неудачную попытку вызова call внутри шага саги
+func (r *SagaStateRepository) RecordAttempt(ctx context.Context, sagaID string, step sagaEntity.StepName, call string, attempt int, errMsg string) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO saga_events (order_id, saga_id, kind, type, step, status, attempt, detail, error)
+		SELECT COALESCE(parent_order_id, order_id), order_id, kind, $2, $3, $4, $5, $6, $7
+		FROM saga_instances
+		WHERE order_id = $1
+	`, sagaID, sagaEntity.TimelineAttempt, step, sagaEntity.StepFailed, attempt, call, errMsg)
+	if err != nil {
+		r.log.Errorw("failed to record call attempt", "error", err, "sagaID", sagaID, "step", step, "call", call)
+		return err
+	}
+	return nil
+}
+
+// ListTimeline возвращает хронологию заказа в порядке записи: события саги оформления,
+// отмены и всех возвратов по нему
+func (r *SagaStateRepository) ListTimeline(ctx context.Context, orderID string) ([]sagaEntity.TimelineEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, order_id, saga_id, kind, type, step, status, attempt, detail, error, created_at
+		FROM saga_events
+		WHERE order_id = $1
+		ORDER BY id ASC
+	`, orderID)
+	if err != nil {
+		r.log.Errorw("failed to query order timeline", "error", err, "orderID", orderID)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []sagaEntity.TimelineEvent
+	for rows.Next() {
+		var event sagaEntity.TimelineEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.OrderID,
+			&event.SagaID,
+			&event.Kind,
+			&event.Type,
+			&event.Step,
+			&event.Status,
+			&event.Attempt,
+			&event.Detail,
+			&event.Error,
+			&event.CreatedAt,
+		); err != nil {
+			r.log.Errorw("failed to scan timeline event", "error", err, "orderID", orderID)
+			return nil, err
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// timelineSource - колонки изменённой саги, из которых insertStatusEvent собирает событие
+const timelineSource = `order_id, parent_order_id, kind, status, error`
+
+// insertStatusEvent дописывает в хронологию новый статус саги; строки саги передаются через FROM
+const insertStatusEvent = `INSERT INTO saga_events (order_id, saga_id, kind, type, status, error)
+		SELECT COALESCE(parent_order_id, order_id), order_id, kind, '` + string(sagaEntity.TimelineSagaStatus) + `', status, error`
+
+const instanceColumns = `order_id, kind, user_id, total, products, status, current_step, step_status, error, failure_reason, COALESCE(idempotency_key, ''), COALESCE(parent_order_id, ''), created_at, updated_at`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanInstance(row rowScanner) (*sagaEntity.Instance, error) {
+	var (
+		instance sagaEntity.Instance
+		products []byte
+	)
+	if err := row.Scan(
+		&instance.OrderID,
+		&instance.Kind,
+		&instance.UserID,
+		&instance.Total,
+		&products,
+		&instance.Status,
+		&instance.CurrentStep,
+		&instance.StepStatus,
+		&instance.Error,
+		&instance.FailureReason,
+		&instance.IdempotencyKey,
+		&instance.ParentOrderID,
+		&instance.CreatedAt,
+		&instance.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(products, &instance.Products); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saga products: %w", err)
+	}
+	return &instance, nil
+}
@@ -0,0 +1,115 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ErrCircuitOpen - downstream недавно подряд не отвечал, вызов не отправлялся
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type breakerState int
+
+const (
+	stateClosed breakerState = iota
+	stateOpen
+	stateHalfOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case stateOpen:
+		return "open"
+	case stateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Breaker - circuit breaker одного downstream.
+// После threshold временных сбоев подряд вызовы отклоняются на openTimeout,
+// затем пропускается одна пробная попытка: успех закрывает breaker, сбой снова открывает.
+type Breaker struct {
+	name        string
+	threshold   int
+	openTimeout time.Duration
+	logger      *zap.SugaredLogger
+	now         func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewBreaker(name string, threshold int, openTimeout time.Duration, logger *zap.SugaredLogger) *Breaker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &Breaker{
+		name:        name,
+		threshold:   threshold,
+		openTimeout: openTimeout,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Allow сообщает, можно ли сейчас отправить вызов
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case stateOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return ErrCircuitOpen
+		}
+		b.setState(stateHalfOpen)
+		b.probing = true
+		return nil
+	case stateHalfOpen:
+		// Пока идёт пробный вызов, остальные ждут его результата
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record учитывает результат вызова. Бизнес-отказ означает, что downstream жив,
+// поэтому breaker считает только временные сбои.
+func (b *Breaker) Record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if err == nil || !IsRetryable(err) {
+		b.failures = 0
+		if b.state != stateClosed {
+			b.setState(stateClosed)
+		}
+		return
+	}
+
+	b.failures++
+	if b.state == stateHalfOpen || b.failures >= b.threshold {
+		b.openedAt = b.now()
+		if b.state != stateOpen {
+			b.setState(stateOpen)
+		}
+	}
+}
+
+func (b *Breaker) setState(state breakerState) {
+	b.logger.Warnw("Circuit breaker state changed", "downstream", b.name, "from", b.state, "to", state, "failures", b.failures)
+	b.state = state
+}
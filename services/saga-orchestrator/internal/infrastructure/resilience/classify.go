@@ -0,0 +1,54 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Outcome - как оркестратору относиться к ошибке шага
+type Outcome string
+
+const (
+	// OutcomeRetryable - сбой инфраструктуры, тот же вызов может пройти позже
+	OutcomeRetryable Outcome = "retryable"
+	// OutcomeTerminal - downstream ответил отказом, повтор вернёт то же самое
+	OutcomeTerminal Outcome = "terminal"
+)
+
+// Classify разделяет ошибки на временные и окончательные.
+// Бизнес-отказы (нет денег, нет товара, неверное состояние резерва) - окончательные:
+// их повтор ничего не изменит, а саге нужно сразу переходить к компенсации.
+func Classify(err error) Outcome {
+	if err == nil {
+		return OutcomeTerminal
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return OutcomeRetryable
+	}
+	// Отмена вызывающим - не повод повторять
+	if errors.Is(err, context.Canceled) {
+		return OutcomeTerminal
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return OutcomeRetryable
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return OutcomeTerminal
+	}
+	switch st.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return OutcomeRetryable
+	default:
+		return OutcomeTerminal
+	}
+}
+
+// IsRetryable - короткая форма Classify(err) == OutcomeRetryable
+func IsRetryable(err error) bool {
+	return Classify(err) == OutcomeRetryable
+}
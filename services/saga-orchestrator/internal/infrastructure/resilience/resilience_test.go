@@ -0,0 +1,221 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errUnavailable = status.Error(codes.Unavailable, "connection refused")
+	errNoFunds     = status.Error(codes.FailedPrecondition, "insufficient funds")
+)
+
+func newTestCaller(breaker *Breaker, policy config.RetryPolicy) (*Caller, *[]time.Duration) {
+	var sleeps []time.Duration
+	caller := NewCaller(breaker, func(string) config.RetryPolicy { return policy }, zap.NewNop().Sugar())
+	caller.sleep = func(ctx context.Context, d time.Duration) error {
+		sleeps = append(sleeps, d)
+		return ctx.Err()
+	}
+	return caller, &sleeps
+}
+
+// attemptLog запоминает номера неудачных попыток
+type attemptLog struct {
+	attempts []int
+}
+
+func (l *attemptLog) AttemptFailed(ctx context.Context, call string, attempt int, err error) {
+	l.attempts = append(l.attempts, attempt)
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected Outcome
+	}{
+		{"Unavailable", errUnavailable, OutcomeRetryable},
+		{"Deadline Exceeded", status.Error(codes.DeadlineExceeded, "timeout"), OutcomeRetryable},
+		{"Resource Exhausted", status.Error(codes.ResourceExhausted, "busy"), OutcomeRetryable},
+		{"Context Deadline", context.DeadlineExceeded, OutcomeRetryable},
+		{"Circuit Open", ErrCircuitOpen, OutcomeRetryable},
+		{"Failed Precondition", errNoFunds, OutcomeTerminal},
+		{"Invalid Argument", status.Error(codes.InvalidArgument, "bad"), OutcomeTerminal},
+		{"Internal", status.Error(codes.Internal, "boom"), OutcomeTerminal},
+		{"Canceled", context.Canceled, OutcomeTerminal},
+		{"Plain Error", errors.New("reserve funds failed"), OutcomeTerminal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Classify(tt.err))
+		})
+	}
+}
+
+func TestCaller_Do(t *testing.T) {
+	policy := config.RetryPolicy{MaxAttempts: 3, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Timeout: time.Second}
+
+	t.Run("Retries Transient Error", func(t *testing.T) {
+		caller, sleeps := newTestCaller(NewBreaker("wallet", 10, time.Minute, zap.NewNop().Sugar()), policy)
+		calls := 0
+
+		err := caller.Do(context.Background(), config.CallWalletReserve, func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return errUnavailable
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, calls)
+		assert.Len(t, *sleeps, 2)
+	})
+
+	t.Run("Terminal Error Not Retried", func(t *testing.T) {
+		caller, sleeps := newTestCaller(NewBreaker("wallet", 10, time.Minute, zap.NewNop().Sugar()), policy)
+		calls := 0
+
+		err := caller.Do(context.Background(), config.CallWalletReserve, func(ctx context.Context) error {
+			calls++
+			return errNoFunds
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.Equal(t, 1, calls)
+		assert.Empty(t, *sleeps)
+	})
+
+	t.Run("Attempts Exhausted", func(t *testing.T) {
+		caller, _ := newTestCaller(NewBreaker("wallet", 10, time.Minute, zap.NewNop().Sugar()), policy)
+		calls := 0
+
+		err := caller.Do(context.Background(), config.CallWalletReserve, func(ctx context.Context) error {
+			calls++
+			return errUnavailable
+		})
+
+		assert.Error(t, err)
+		assert.True(t, IsRetryable(err))
+		assert.Contains(t, err.Error(), config.CallWalletReserve)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Attempt Has Deadline", func(t *testing.T) {
+		caller, _ := newTestCaller(NewBreaker("wallet", 10, time.Minute, zap.NewNop().Sugar()), config.RetryPolicy{MaxAttempts: 1, Timeout: 50 * time.Millisecond})
+
+		err := caller.Do(context.Background(), config.CallWalletReserve, func(ctx context.Context) error {
+			deadline, ok := ctx.Deadline()
+			assert.True(t, ok)
+			assert.WithinDuration(t, time.Now().Add(50*time.Millisecond), deadline, 50*time.Millisecond)
+			return nil
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Cancelled Context Stops Retries", func(t *testing.T) {
+		caller, _ := newTestCaller(NewBreaker("wallet", 10, time.Minute, zap.NewNop().Sugar()), policy)
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+
+		err := caller.Do(ctx, config.CallWalletReserve, func(ctx context.Context) error {
+			calls++
+			cancel()
+			return errUnavailable
+		})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Observer Sees Every Failed Attempt", func(t *testing.T) {
+		caller, _ := newTestCaller(NewBreaker("wallet", 10, time.Minute, zap.NewNop().Sugar()), policy)
+		observer := &attemptLog{}
+		caller.SetObserver(observer)
+		calls := 0
+
+		err := caller.Do(context.Background(), config.CallWalletReserve, func(ctx context.Context) error {
+			calls++
+			if calls < 3 {
+				return errUnavailable
+			}
+			return nil
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, observer.attempts)
+	})
+
+	t.Run("Open Circuit Skips Downstream", func(t *testing.T) {
+		breaker := NewBreaker("wallet", 2, time.Minute, zap.NewNop().Sugar())
+		caller, _ := newTestCaller(breaker, config.RetryPolicy{MaxAttempts: 4})
+		calls := 0
+
+		err := caller.Do(context.Background(), config.CallWalletReserve, func(ctx context.Context) error {
+			calls++
+			return errUnavailable
+		})
+
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := NewBreaker("products", 2, 10*time.Second, zap.NewNop().Sugar())
+	breaker.now = func() time.Time { return now }
+
+	// Бизнес-отказы не открывают breaker
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, breaker.Allow())
+		breaker.Record(errNoFunds)
+	}
+
+	assert.NoError(t, breaker.Allow())
+	breaker.Record(errUnavailable)
+	assert.NoError(t, breaker.Allow())
+	breaker.Record(errUnavailable)
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	// После openTimeout пропускается одна пробная попытка
+	now = now.Add(11 * time.Second)
+	assert.NoError(t, breaker.Allow())
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	// Неудачная проба снова открывает breaker
+	breaker.Record(errUnavailable)
+	assert.ErrorIs(t, breaker.Allow(), ErrCircuitOpen)
+
+	// Удачная проба закрывает
+	now = now.Add(11 * time.Second)
+	assert.NoError(t, breaker.Allow())
+	breaker.Record(nil)
+	assert.NoError(t, breaker.Allow())
+	assert.NoError(t, breaker.Allow())
+}
+
+func TestBackoff(t *testing.T) {
+	policy := config.RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: 400 * time.Millisecond}
+
+	for attempt, ceiling := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 6: 400 * time.Millisecond} {
+		for i := 0; i < 20; i++ {
+			d := Backoff(policy, attempt)
+			assert.GreaterOrEqual(t, d, ceiling/2)
+			assert.LessOrEqual(t, d, ceiling)
+		}
+	}
+	assert.Zero(t, Backoff(config.RetryPolicy{}, 1))
+}
@@ -0,0 +1,127 @@
+package resilience
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/config"
+	"go.uber.org/zap"
+)
+
+// AttemptObserver узнаёт о каждой неудачной попытке вызова, в том числе о тех, что будут повторены
+type AttemptObserver interface {
+	AttemptFailed(ctx context.Context, call string, attempt int, err error)
+}
+
+// Caller выполняет вызовы одного downstream с повторами, дедлайнами и circuit breaker'ом.
+// Повторять можно только идемпотентные вызовы - у саги все вызовы идут с ID заказа.
+type Caller struct {
+	breaker  *Breaker
+	policies func(call string) config.RetryPolicy
+	logger   *zap.SugaredLogger
+	sleep    func(ctx context.Context, d time.Duration) error
+	observer AttemptObserver
+}
+
+func NewCaller(breaker *Breaker, policies func(call string) config.RetryPolicy, logger *zap.SugaredLogger) *Caller {
+	return &Caller{
+		breaker:  breaker,
+		policies: policies,
+		logger:   logger,
+		sleep:    sleepContext,
+	}
+}
+
+// SetObserver подключает observer к неудачным попыткам вызовов
+func (c *Caller) SetObserver(observer AttemptObserver) {
+	c.observer = observer
+}
+
+// Do выполняет fn по политике вызова call. Временные ошибки повторяются с
+// экспоненциальной паузой и джиттером, окончательные возвращаются сразу.
+func (c *Caller) Do(ctx context.Context, call string, fn func(ctx context.Context) error) error {
+	policy := c.policies(call)
+	attempts := max(policy.MaxAttempts, 1)
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		err = c.attempt(ctx, policy, fn)
+		if err == nil {
+			return nil
+		}
+		if c.observer != nil {
+			c.observer.AttemptFailed(ctx, call, attempt, err)
+		}
+
+		outcome := Classify(err)
+		if outcome == OutcomeTerminal || attempt == attempts || ctx.Err() != nil {
+			c.logger.Warnw("Downstream call failed",
+				"call", call,
+				"attempt", attempt,
+				"outcome", outcome,
+				"error", err,
+			)
+			break
+		}
+
+		delay := Backoff(policy, attempt)
+		c.logger.Infow("Retrying downstream call",
+			"call", call,
+			"attempt", attempt,
+			"delay", delay,
+			"error", err,
+		)
+		if sleepErr := c.sleep(ctx, delay); sleepErr != nil {
+			break
+		}
+	}
+	return fmt.Errorf("%s: %w", call, err)
+}
+
+func (c *Caller) attempt(ctx context.Context, policy config.RetryPolicy, fn func(ctx context.Context) error) error {
+	if err := c.breaker.Allow(); err != nil {
+		return err
+	}
+
+	attemptCtx := ctx
+	if policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		attemptCtx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		defer cancel()
+	}
+
+	err := fn(attemptCtx)
+	c.breaker.Record(err)
+	return err
+}
+
+// Backoff - пауза после попытки attempt (с 1): экспонента с потолком MaxBackoff,
+// из которой случайна вторая половина, чтобы повторы разных саг не шли в ногу
+func Backoff(policy config.RetryPolicy, attempt int) time.Duration {
+	if policy.InitialBackoff <= 0 {
+		return 0
+	}
+	backoff := policy.InitialBackoff
+	for i := 1; i < attempt && backoff < policy.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if policy.MaxBackoff > 0 && backoff > policy.MaxBackoff {
+		backoff = policy.MaxBackoff
+	}
+	half := backoff / 2
+	// #nosec G404 - джиттер не требует криптостойкого генератора
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,273 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	proto "github.com/vsespontanno/eCommerce/proto/saga"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/application/admin"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
+	eventEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type SagaAdmin interface {
+	ListSagas(ctx context.Context, filter sagaEntity.SagaFilter) ([]sagaEntity.Instance, error)
+	GetSaga(ctx context.Context, orderID string) (*admin.SagaDetails, error)
+	ListActions(ctx context.Context, filter sagaEntity.AdminActionFilter) ([]sagaEntity.AdminAction, error)
+	OrderTimeline(ctx context.Context, orderID string) ([]sagaEntity.TimelineEvent, error)
+	Retry(ctx context.Context, orderID, operator, reason string) (*sagaEntity.Instance, error)
+	Compensate(ctx context.Context, orderID, operator, reason string) (*sagaEntity.Instance, error)
+	Resolve(ctx context.Context, orderID, operator, reason string) (*sagaEntity.Instance, error)
+	ListDeadEvents(ctx context.Context, limit int) ([]eventEntity.OutboxEvent, error)
+	RequeueEvent(ctx context.Context, id int64, operator, reason string) (*eventEntity.OutboxEvent, error)
+}
+
+type Server struct {
+	proto.UnimplementedSagaAdminServer
+	admin  SagaAdmin
+	logger *zap.SugaredLogger
+}
+
+func NewAdminServer(logger *zap.SugaredLogger, admin SagaAdmin) *Server {
+	return &Server{logger: logger, admin: admin}
+}
+
+func (s *Server) ListSagas(ctx context.Context, req *proto.ListSagasRequest) (*proto.ListSagasResponse, error) {
+	if req.OlderThanSeconds < 0 || req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "olderThanSeconds and limit must not be negative")
+	}
+
+	instances, err := s.admin.ListSagas(ctx, sagaEntity.SagaFilter{
+		Status:    sagaEntity.Status(req.Status),
+		OlderThan: time.Duration(req.OlderThanSeconds) * time.Second,
+		Limit:     int(req.Limit),
+	})
+	if err != nil {
+		return nil, s.toStatus(err, "failed to list sagas")
+	}
+
+	resp := &proto.ListSagasResponse{Sagas: make([]*proto.SagaSummary, 0, len(instances))}
+	for _, instance := range instances {
+		resp.Sagas = append(resp.Sagas, toSummary(instance))
+	}
+	return resp, nil
+}
+
+func (s *Server) GetSaga(ctx context.Context, req *proto.GetSagaRequest) (*proto.GetSagaResponse, error) {
+	if req.OrderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "order ID is required")
+	}
+
+	details, err := s.admin.GetSaga(ctx, req.OrderID)
+	if err != nil {
+		return nil, s.toStatus(err, "failed to get saga")
+	}
+
+	resp := &proto.GetSagaResponse{
+		Saga:    toSummary(details.Instance),
+		Steps:   make([]*proto.SagaStep, 0, len(details.Steps)),
+		Actions: toActions(details.Actions),
+	}
+	for _, step := range details.Steps {
+		resp.Steps = append(resp.Steps, &proto.SagaStep{
+			Step:      string(step.Step),
+			Status:    string(step.Status),
+			Error:     step.Error,
+			CreatedAt: step.CreatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) RetrySaga(ctx context.Context, req *proto.SagaActionRequest) (*proto.SagaActionResponse, error) {
+	return s.action(ctx, req, s.admin.Retry)
+}
+
+func (s *Server) CompensateSaga(ctx context.Context, req *proto.SagaActionRequest) (*proto.SagaActionResponse, error) {
+	return s.action(ctx, req, s.admin.Compensate)
+}
+
+func (s *Server) ResolveSaga(ctx context.Context, req *proto.SagaActionRequest) (*proto.SagaActionResponse, error) {
+	return s.action(ctx, req, s.admin.Resolve)
+}
+
+func (s *Server) GetOrderTimeline(ctx context.Context, req *proto.GetOrderTimelineRequest) (*proto.GetOrderTimelineResponse, error) {
+	if req.OrderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "order ID is required")
+	}
+
+	events, err := s.admin.OrderTimeline(ctx, req.OrderID)
+	if err != nil {
+		return nil, s.toStatus(err, "failed to get order timeline")
+	}
+
+	resp := &proto.GetOrderTimelineResponse{Events: make([]*proto.TimelineEvent, 0, len(events))}
+	for _, event := range events {
+		resp.Events = append(resp.Events, &proto.TimelineEvent{
+			Id:        event.ID,
+			OrderID:   event.OrderID,
+			SagaID:    event.SagaID,
+			Kind:      string(event.Kind),
+			Type:      string(event.Type),
+			Step:      string(event.Step),
+			Status:    event.Status,
+			Attempt:   int32(event.Attempt), // #nosec G115 - номер попытки ограничен MaxAttempts политики
+			Detail:    event.Detail,
+			Error:     event.Error,
+			CreatedAt: event.CreatedAt.Unix(),
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) ListAdminActions(ctx context.Context, req *proto.ListAdminActionsRequest) (*proto.ListAdminActionsResponse, error) {
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	actions, err := s.admin.ListActions(ctx, sagaEntity.AdminActionFilter{
+		OrderID:  req.OrderID,
+		Operator: req.Operator,
+		Limit:    int(req.Limit),
+	})
+	if err != nil {
+		return nil, s.toStatus(err, "failed to list admin actions")
+	}
+	return &proto.ListAdminActionsResponse{Actions: toActions(actions)}, nil
+}
+
+func (s *Server) ListDeadEvents(ctx context.Context, req *proto.ListDeadEventsRequest) (*proto.ListDeadEventsResponse, error) {
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	events, err := s.admin.ListDeadEvents(ctx, int(req.Limit))
+	if err != nil {
+		return nil, s.toStatus(err, "failed to list dead events")
+	}
+
+	resp := &proto.ListDeadEventsResponse{Events: make([]*proto.OutboxEvent, 0, len(events))}
+	for _, event := range events {
+		resp.Events = append(resp.Events, toOutboxEvent(event))
+	}
+	return resp, nil
+}
+
+func (s *Server) RequeueEvent(ctx context.Context, req *proto.RequeueEventRequest) (*proto.RequeueEventResponse, error) {
+	if req.Id <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "event ID is required")
+	}
+
+	operator, err := authenticatedOperator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := s.admin.RequeueEvent(ctx, req.Id, operator, req.Reason)
+	if err != nil {
+		return nil, s.toStatus(err, "failed to requeue event")
+	}
+	return &proto.RequeueEventResponse{Event: toOutboxEvent(*event)}, nil
+}
+
+// action выполняет действие оператора; сага, снова упавшая после retry, - не ошибка RPC,
+// её итог виден в статусе саги в ответе
+func (s *Server) action(
+	ctx context.Context,
+	req *proto.SagaActionRequest,
+	do func(ctx context.Context, orderID, operator, reason string) (*sagaEntity.Instance, error),
+) (*proto.SagaActionResponse, error) {
+	if req.OrderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "order ID is required")
+	}
+
+	operator, err := authenticatedOperator(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	instance, err := do(ctx, req.OrderID, operator, req.Reason)
+	if err != nil {
+		return nil, s.toStatus(err, "admin action failed")
+	}
+	return &proto.SagaActionResponse{Saga: toSummary(*instance)}, nil
+}
+
+// authenticatedOperator - оператор, опознанный по токену. Поле operator из тела запроса
+// не используется: иначе в журнал попадало бы любое имя, которое прислал клиент.
+func authenticatedOperator(ctx context.Context) (string, error) {
+	operator, ok := OperatorFromContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "operator is not authenticated")
+	}
+	return operator, nil
+}
+
+func (s *Server) toStatus(err error, msg string) error {
+	switch {
+	case errors.Is(err, apperrors.ErrOperatorRequired), errors.Is(err, apperrors.ErrReasonRequired):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, apperrors.ErrSagaNotFound), errors.Is(err, apperrors.ErrEventNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, apperrors.ErrSagaFinished),
+		errors.Is(err, apperrors.ErrSagaInProgress),
+		errors.Is(err, apperrors.ErrSagaCompensated):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, apperrors.ErrSagaModified):
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		s.logger.Errorw(msg, "error", err)
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+func toSummary(instance sagaEntity.Instance) *proto.SagaSummary {
+	return &proto.SagaSummary{
+		OrderID:     instance.OrderID,
+		UserID:      instance.UserID,
+		Total:       instance.Total,
+		Status:      string(instance.Status),
+		CurrentStep: string(instance.CurrentStep),
+		StepStatus:  string(instance.StepStatus),
+		Error:       instance.Error,
+		CreatedAt:   instance.CreatedAt.Unix(),
+		UpdatedAt:   instance.UpdatedAt.Unix(),
+	}
+}
+
+func toActions(actions []sagaEntity.AdminAction) []*proto.AdminAction {
+	result := make([]*proto.AdminAction, 0, len(actions))
+	for _, action := range actions {
+		result = append(result, &proto.AdminAction{
+			Id:        action.ID,
+			OrderID:   action.OrderID,
+			Action:    string(action.Action),
+			Operator:  action.Operator,
+			Reason:    action.Reason,
+			Result:    string(action.Result),
+			Error:     action.Error,
+			CreatedAt: action.CreatedAt.Unix(),
+			EventID:   action.EventID,
+		})
+	}
+	return result
+}
+
+func toOutboxEvent(event eventEntity.OutboxEvent) *proto.OutboxEvent {
+	return &proto.OutboxEvent{
+		Id:            event.ID,
+		AggregateID:   event.AggregateID,
+		EventType:     event.EventType,
+		Payload:       string(event.Payload),
+		Status:        string(event.Status),
+		Attempts:      int32(event.Attempts), // #nosec G115 - счётчик ограничен OUTBOX_MAX_ATTEMPTS
+		LastError:     event.LastError,
+		NextAttemptAt: event.NextAttemptAt.Unix(),
+		CreatedAt:     event.CreatedAt.Unix(),
+	}
+}
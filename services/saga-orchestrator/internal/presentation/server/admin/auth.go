@@ -0,0 +1,81 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type operatorKey struct{}
+
+// OperatorFromContext возвращает оператора, которого Authenticator опознал по токену запроса
+func OperatorFromContext(ctx context.Context) (string, bool) {
+	operator, ok := ctx.Value(operatorKey{}).(string)
+	return operator, ok && operator != ""
+}
+
+func withOperator(ctx context.Context, operator string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, operator)
+}
+
+// Authenticator пускает в операторский API только запросы с известным токеном
+// и кладёт в контекст имя оператора, которому токен выдан. Именно оно попадает в журнал действий.
+type Authenticator struct {
+	// operators - оператор -> токен
+	operators map[string]string
+}
+
+func NewAuthenticator(operators map[string]string) *Authenticator {
+	return &Authenticator{operators: operators}
+}
+
+// authenticate возвращает оператора по заголовку "Bearer <токен>"
+func (a *Authenticator) authenticate(header string) (string, bool) {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || token == "" {
+		return "", false
+	}
+	// Сравниваем со всеми токенами за постоянное время, чтобы не подсказывать токен по задержке
+	found := ""
+	for operator, expected := range a.operators {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1 {
+			found = operator
+		}
+	}
+	return found, found != ""
+}
+
+// UnaryInterceptor проверяет токен из метаданных authorization
+func (a *Authenticator) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var header string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get("authorization"); len(values) > 0 {
+				header = values[0]
+			}
+		}
+		operator, ok := a.authenticate(header)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "valid operator token is required")
+		}
+		return handler(withOperator(ctx, operator), req)
+	}
+}
+
+// Middleware проверяет токен из заголовка Authorization для HTTP-версии API
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		operator, ok := a.authenticate(r.Header.Get("Authorization"))
+		if !ok {
+			http.Error(w, "valid operator token is required", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withOperator(r.Context(), operator)))
+	})
+}
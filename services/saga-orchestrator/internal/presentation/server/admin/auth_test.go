@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	proto "github.com/vsespontanno/eCommerce/proto/saga"
+	eventEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAdmin запоминает оператора, от имени которого выполнено действие
+type fakeAdmin struct {
+	SagaAdmin
+	operator string
+}
+
+func (f *fakeAdmin) Retry(ctx context.Context, orderID, operator, reason string) (*sagaEntity.Instance, error) {
+	f.operator = operator
+	return &sagaEntity.Instance{OrderID: orderID}, nil
+}
+
+func (f *fakeAdmin) RequeueEvent(ctx context.Context, id int64, operator, reason string) (*eventEntity.OutboxEvent, error) {
+	f.operator = operator
+	return &eventEntity.OutboxEvent{ID: id}, nil
+}
+
+func TestAuthenticator_UnaryInterceptor(t *testing.T) {
+	auth := NewAuthenticator(map[string]string{"alice": "secret-1", "bob": "secret-2"})
+	fake := &fakeAdmin{}
+	server := NewAdminServer(zap.NewNop().Sugar(), fake)
+	interceptor := auth.UnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/saga.SagaAdmin/RetrySaga"}
+	retry := func(ctx context.Context, req any) (any, error) {
+		return server.RetrySaga(ctx, req.(*proto.SagaActionRequest))
+	}
+	withToken := func(header string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", header))
+	}
+
+	t.Run("Operator Comes From Token", func(t *testing.T) {
+		req := &proto.SagaActionRequest{OrderID: "order-1"}
+
+		_, err := interceptor(withToken("Bearer secret-2"), req, info, retry)
+
+		require.NoError(t, err)
+		assert.Equal(t, "bob", fake.operator)
+	})
+
+	for name, ctx := range map[string]context.Context{
+		"No Metadata":   context.Background(),
+		"Unknown Token": withToken("Bearer guess"),
+		"Not Bearer":    withToken("secret-1"),
+		"Empty Token":   withToken("Bearer "),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := interceptor(ctx, &proto.SagaActionRequest{OrderID: "order-1"}, info, retry)
+
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		})
+	}
+
+	t.Run("Action Without Interceptor Is Rejected", func(t *testing.T) {
+		_, err := server.RequeueEvent(context.Background(), &proto.RequeueEventRequest{Id: 1})
+
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
+
+func TestAuthenticator_Middleware(t *testing.T) {
+	auth := NewAuthenticator(map[string]string{"alice": "secret-1"})
+	var seen string
+	handler := auth.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen, _ = OperatorFromContext(r.Context())
+	}))
+
+	t.Run("Valid Token", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/admin/sagas/order-1/retry", nil)
+		req.Header.Set("Authorization", "Bearer secret-1")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "alice", seen)
+	})
+
+	t.Run("Missing Token", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/sagas", nil))
+
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+}
@@ -2,26 +2,55 @@ package saga
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/google/uuid"
 	proto "github.com/vsespontanno/eCommerce/proto/saga"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
 	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
 	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/product/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type Orchestrator interface {
-	SagaTransaction(ctx context.Context, Order orderEntity.OrderEvent) error
+	StartSaga(ctx context.Context, Order orderEntity.OrderEvent) error
+	GetSaga(ctx context.Context, orderID string) (*sagaEntity.Instance, error)
+	FindCheckout(ctx context.Context, userID int64, key string) (*sagaEntity.Instance, error)
+	StartCancellation(ctx context.Context, orderID string, userID int64) error
+	StartReturn(ctx context.Context, returnID, orderID string, userID int64, items []entity.Product) (int64, error)
 }
 
+// Pricer - актуальные цены каталога; сумму заказа считаем только по ним
+type Pricer interface {
+	GetPrices(ctx context.Context, ids []int64) (map[int64]int64, error)
+}
+
+// Статусы checkout, которые видит клиент
+const (
+	CheckoutStatusPending   = "PENDING"
+	CheckoutStatusCompleted = "COMPLETED"
+	CheckoutStatusFailed    = "FAILED"
+	// Заказ, который пользователь отменил после оформления
+	CheckoutStatusCancelling = "CANCELLING"
+	CheckoutStatusCancelled  = "CANCELLED"
+)
+
+// maxIdempotencyKeyLen - ограничение на длину ключа идемпотентности от клиента
+const maxIdempotencyKeyLen = 255
+
 type Server struct {
 	proto.UnimplementedSagaServer
 	saga   Orchestrator
+	pricer Pricer
 	logger *zap.SugaredLogger
 }
 
-func NewSagaServer(logger *zap.SugaredLogger, saga Orchestrator) *Server {
-	return &Server{logger: logger, saga: saga}
+func NewSagaServer(logger *zap.SugaredLogger, saga Orchestrator, pricer Pricer) *Server {
+	return &Server{logger: logger, saga: saga, pricer: pricer}
 }
 
 func (s *Server) StartCheckout(ctx context.Context, req *proto.StartCheckoutRequest) (*proto.StartCheckoutResponse, error) {
@@ -36,23 +65,49 @@ func (s *Server) StartCheckout(ctx context.Context, req *proto.StartCheckoutRequ
 		return &proto.StartCheckoutResponse{OrderID: "", Error: "cart is empty"}, nil
 	}
 
-	var Order orderEntity.OrderEvent
-	Order.UserID = req.UserID
-	Order.OrderID = uuid.NewString()
-	Order.Status = "Pending"
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLen {
+		s.logger.Errorw("Idempotency key is too long", "userID", req.UserID, "length", len(req.IdempotencyKey))
+		return &proto.StartCheckoutResponse{OrderID: "", Error: "invalid idempotency key"}, nil
+	}
+
+	// Повтор запроса возвращает исходный заказ ещё до проверки цен: они могли измениться с тех пор
+	if req.IdempotencyKey != "" {
+		resp, found, err := s.replayCheckout(ctx, req.UserID, req.IdempotencyKey)
+		if err != nil {
+			return &proto.StartCheckoutResponse{OrderID: "", Error: "failed to check idempotency key"}, nil
+		}
+		if found {
+			return resp, nil
+		}
+	}
 
-	// Формируем заказ и считаем сумму
+	ids := make([]int64, 0, len(req.Cart))
 	for _, item := range req.Cart {
 		if item.ProductID <= 0 || item.Quantity <= 0 || item.Price < 0 {
 			s.logger.Errorw("Invalid cart item", "productID", item.ProductID, "quantity", item.Quantity, "price", item.Price)
 			return &proto.StartCheckoutResponse{OrderID: "", Error: "invalid cart item"}, nil
 		}
+		ids = append(ids, item.ProductID)
+	}
 
-		Order.Products = append(Order.Products, entity.Product{
-			ID:       item.ProductID,
-			Quantity: int(item.Quantity),
-		})
-		Order.Total += item.Price * item.Quantity
+	// Цены из корзины - снимок на момент добавления товара, им нельзя доверять
+	prices, err := s.pricer.GetPrices(ctx, ids)
+	if err != nil {
+		s.logger.Errorw("Failed to get current prices", "userID", req.UserID, "error", err)
+		return &proto.StartCheckoutResponse{OrderID: "", Error: "failed to get current prices"}, nil
+	}
+
+	var Order orderEntity.OrderEvent
+	Order.UserID = req.UserID
+	Order.OrderID = uuid.NewString()
+	Order.Status = "Pending"
+	Order.IdempotencyKey = req.IdempotencyKey
+
+	// Формируем заказ и считаем сумму по ценам каталога
+	quote, err := priceOrder(&Order, req.Cart, prices)
+	if err != nil {
+		s.logger.Infow("Checkout rejected", "userID", req.UserID, "reason", err)
+		return &proto.StartCheckoutResponse{OrderID: "", Error: err.Error(), Quote: quote}, nil
 	}
 
 	if Order.Total <= 0 {
@@ -62,12 +117,192 @@ func (s *Server) StartCheckout(ctx context.Context, req *proto.StartCheckoutRequ
 
 	s.logger.Infow("Starting checkout", "orderID", Order.OrderID, "userID", Order.UserID, "total", Order.Total, "items", len(Order.Products))
 
-	err := s.saga.SagaTransaction(ctx, Order)
+	// Сага выполняется в фоне, клиент узнаёт результат через GetCheckoutStatus
+	err = s.saga.StartSaga(ctx, Order)
+	if errors.Is(err, apperrors.ErrDuplicateCheckout) {
+		// Параллельный повтор успел создать сагу первым
+		resp, found, err := s.replayCheckout(ctx, req.UserID, req.IdempotencyKey)
+		if err != nil || !found {
+			return &proto.StartCheckoutResponse{OrderID: "", Error: "failed to check idempotency key"}, nil
+		}
+		return resp, nil
+	}
+	if err != nil {
+		s.logger.Errorw("Failed to start saga", "orderID", Order.OrderID, "error", err)
+		return &proto.StartCheckoutResponse{OrderID: "", Error: err.Error()}, nil
+	}
+
+	s.logger.Infow("Checkout accepted", "orderID", Order.OrderID)
+	return &proto.StartCheckoutResponse{OrderID: Order.OrderID, Error: "", Status: CheckoutStatusPending}, nil
+}
+
+// replayCheckout ищет заказ, уже созданный пользователем с этим ключом идемпотентности
+func (s *Server) replayCheckout(ctx context.Context, userID int64, key string) (*proto.StartCheckoutResponse, bool, error) {
+	instance, err := s.saga.FindCheckout(ctx, userID, key)
+	if errors.Is(err, apperrors.ErrSagaNotFound) {
+		return nil, false, nil
+	}
 	if err != nil {
-		s.logger.Errorw("Saga transaction failed", "orderID", Order.OrderID, "error", err)
-		return &proto.StartCheckoutResponse{OrderID: Order.OrderID, Error: err.Error()}, nil
+		s.logger.Errorw("Failed to find checkout by idempotency key", "userID", userID, "error", err)
+		return nil, false, err
+	}
+
+	s.logger.Infow("Checkout replayed", "orderID", instance.OrderID, "userID", userID, "status", instance.Status)
+	resp := &proto.StartCheckoutResponse{
+		OrderID:  instance.OrderID,
+		Replayed: true,
+		Status:   checkoutStatus(instance),
+	}
+	if resp.Status == CheckoutStatusFailed {
+		resp.Reason = failureReason(instance)
 	}
+	return resp, true, nil
+}
 
-	s.logger.Infow("Checkout completed successfully", "orderID", Order.OrderID)
-	return &proto.StartCheckoutResponse{OrderID: Order.OrderID, Error: ""}, nil
+func (s *Server) GetCheckoutStatus(ctx context.Context, req *proto.GetCheckoutStatusRequest) (*proto.GetCheckoutStatusResponse, error) {
+	if req.OrderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "order ID is required")
+	}
+
+	instance, err := s.saga.GetSaga(ctx, req.OrderID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrSagaNotFound) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		s.logger.Errorw("Failed to get saga", "orderID", req.OrderID, "error", err)
+		return nil, status.Error(codes.Internal, "failed to get checkout status")
+	}
+
+	// Чужой заказ не отличаем от несуществующего; возврат - не заказ
+	if instance.UserID != req.UserID || instance.Kind == sagaEntity.KindReturn {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+
+	resp := &proto.GetCheckoutStatusResponse{
+		OrderID: instance.OrderID,
+		Status:  checkoutStatus(instance),
+	}
+	if resp.Status == CheckoutStatusFailed {
+		resp.Reason = failureReason(instance)
+	}
+	return resp, nil
+}
+
+// CancelOrder запускает отмену оформленного заказа. Отменить можно только свой заказ,
+// оформление которого завершилось успешно; повторный вызов во время отмены ничего не делает.
+func (s *Server) CancelOrder(ctx context.Context, req *proto.CancelOrderRequest) (*proto.CancelOrderResponse, error) {
+	if req.OrderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "order ID is required")
+	}
+	if req.UserID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+
+	err := s.saga.StartCancellation(ctx, req.OrderID, req.UserID)
+	switch {
+	case errors.Is(err, apperrors.ErrSagaNotFound):
+		return nil, status.Error(codes.NotFound, "order not found")
+	case errors.Is(err, apperrors.ErrOrderNotCancellable):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		s.logger.Errorw("Failed to start order cancellation", "orderID", req.OrderID, "userID", req.UserID, "error", err)
+		return nil, status.Error(codes.Internal, "failed to cancel order")
+	}
+
+	s.logger.Infow("Order cancellation started", "orderID", req.OrderID, "userID", req.UserID)
+	return &proto.CancelOrderResponse{}, nil
+}
+
+// StartReturn запускает возврат части товаров оформленного заказа и отдаёт сумму к возврату.
+// Повтор с тем же returnID ничего не запускает и отдаёт ту же сумму.
+func (s *Server) StartReturn(ctx context.Context, req *proto.StartReturnRequest) (*proto.StartReturnResponse, error) {
+	if req.ReturnID == "" || req.OrderID == "" {
+		return nil, status.Error(codes.InvalidArgument, "return ID and order ID are required")
+	}
+	if req.UserID <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID")
+	}
+
+	items := make([]entity.Product, 0, len(req.Items))
+	for _, item := range req.Items {
+		items = append(items, entity.Product{ID: item.ProductID, Quantity: int(item.Quantity)})
+	}
+
+	amount, err := s.saga.StartReturn(ctx, req.ReturnID, req.OrderID, req.UserID, items)
+	switch {
+	case errors.Is(err, apperrors.ErrSagaNotFound):
+		return nil, status.Error(codes.NotFound, "order not found")
+	case errors.Is(err, apperrors.ErrOrderNotReturnable):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, apperrors.ErrInvalidReturn):
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	case err != nil:
+		s.logger.Errorw("Failed to start order return", "returnID", req.ReturnID, "orderID", req.OrderID, "error", err)
+		return nil, status.Error(codes.Internal, "failed to start return")
+	}
+
+	s.logger.Infow("Order return started", "returnID", req.ReturnID, "orderID", req.OrderID, "userID", req.UserID, "amount", amount)
+	return &proto.StartReturnResponse{Amount: amount}, nil
+}
+
+// priceOrder заполняет товары и сумму заказа по ценам каталога.
+// Если цена хотя бы одного товара в корзине устарела, возвращает ErrPricesChanged и корзину
+// по текущим ценам: клиент показывает её пользователю и оформляет заказ заново.
+func priceOrder(order *orderEntity.OrderEvent, cart []*proto.Cart, prices map[int64]int64) ([]*proto.Cart, error) {
+	quote := make([]*proto.Cart, 0, len(cart))
+	stale := false
+	for _, item := range cart {
+		price, ok := prices[item.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("%w: %d", apperrors.ErrProductUnavailable, item.ProductID)
+		}
+		if price != item.Price {
+			stale = true
+		}
+
+		order.Products = append(order.Products, entity.Product{
+			ID:       item.ProductID,
+			Quantity: int(item.Quantity),
+			Price:    price,
+		})
+		order.Total += price * item.Quantity
+		quote = append(quote, &proto.Cart{ProductID: item.ProductID, Price: price, Quantity: item.Quantity})
+	}
+
+	if stale {
+		return quote, apperrors.ErrPricesChanged
+	}
+	return nil, nil
+}
+
+// checkoutStatus сворачивает внутренние статусы саги в статусы для клиента
+// failureReason - код причины провала для пользователя. Цепочка ошибок из instance.Error
+// раскрывает устройство сервисов и остаётся только в операторском API.
+// У саги, упавшей до появления кода (или разобранной оператором без него), причина - internal_error.
+func failureReason(instance *sagaEntity.Instance) string {
+	if instance.FailureReason == "" {
+		return string(orderEntity.FailureInternal)
+	}
+	return instance.FailureReason
+}
+
+func checkoutStatus(instance *sagaEntity.Instance) string {
+	if instance.Kind == sagaEntity.KindCancellation {
+		// Отмену доводят до конца, поэтому до её завершения заказ остаётся в CANCELLING
+		if instance.Status == sagaEntity.StatusCompleted {
+			return CheckoutStatusCancelled
+		}
+		return CheckoutStatusCancelling
+	}
+
+	switch instance.Status {
+	case sagaEntity.StatusCompleted:
+		return CheckoutStatusCompleted
+	case sagaEntity.StatusFailed, sagaEntity.StatusResolved:
+		// RESOLVED - упавшая сага, которую разобрал оператор: заказ так и не оформлен
+		return CheckoutStatusFailed
+	default:
+		// RUNNING и COMPENSATING - сага ещё не дошла до финала
+		return CheckoutStatusPending
+	}
 }
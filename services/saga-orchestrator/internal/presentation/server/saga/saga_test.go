@@ -3,13 +3,19 @@ package saga
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	proto "github.com/vsespontanno/eCommerce/proto/saga"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/apperrors"
 	orderEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/event/entity"
+	"github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/product/entity"
+	sagaEntity "github.com/vsespontanno/eCommerce/services/saga-orchestrator/internal/domain/saga/entity"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Mocks
@@ -17,17 +23,50 @@ type MockOrchestrator struct {
 	mock.Mock
 }
 
-func (m *MockOrchestrator) SagaTransaction(ctx context.Context, order orderEntity.OrderEvent) error {
+func (m *MockOrchestrator) StartSaga(ctx context.Context, order orderEntity.OrderEvent) error {
 	args := m.Called(ctx, order)
 	return args.Error(0)
 }
 
+func (m *MockOrchestrator) GetSaga(ctx context.Context, orderID string) (*sagaEntity.Instance, error) {
+	args := m.Called(ctx, orderID)
+	instance, _ := args.Get(0).(*sagaEntity.Instance)
+	return instance, args.Error(1)
+}
+
+func (m *MockOrchestrator) FindCheckout(ctx context.Context, userID int64, key string) (*sagaEntity.Instance, error) {
+	args := m.Called(ctx, userID, key)
+	instance, _ := args.Get(0).(*sagaEntity.Instance)
+	return instance, args.Error(1)
+}
+
+func (m *MockOrchestrator) StartCancellation(ctx context.Context, orderID string, userID int64) error {
+	args := m.Called(ctx, orderID, userID)
+	return args.Error(0)
+}
+
+func (m *MockOrchestrator) StartReturn(ctx context.Context, returnID, orderID string, userID int64, items []entity.Product) (int64, error) {
+	args := m.Called(ctx, returnID, orderID, userID, items)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockPricer struct {
+	mock.Mock
+}
+
+func (m *MockPricer) GetPrices(ctx context.Context, ids []int64) (map[int64]int64, error) {
+	args := m.Called(ctx, ids)
+	prices, _ := args.Get(0).(map[int64]int64)
+	return prices, args.Error(1)
+}
+
 func TestServer_StartCheckout(t *testing.T) {
 	logger := zap.NewNop().Sugar()
 
 	t.Run("Success", func(t *testing.T) {
 		mockOrchestrator := new(MockOrchestrator)
-		server := NewSagaServer(logger, mockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
 
 		req := &proto.StartCheckoutRequest{
 			UserID: 1,
@@ -36,8 +75,9 @@ func TestServer_StartCheckout(t *testing.T) {
 			},
 		}
 
-		mockOrchestrator.On("SagaTransaction", mock.Anything, mock.MatchedBy(func(order orderEntity.OrderEvent) bool {
-			return order.UserID == 1 && order.Total == 100 && len(order.Products) == 1
+		mockPricer.On("GetPrices", mock.Anything, []int64{1}).Return(map[int64]int64{1: 100}, nil)
+		mockOrchestrator.On("StartSaga", mock.Anything, mock.MatchedBy(func(order orderEntity.OrderEvent) bool {
+			return order.UserID == 1 && order.Total == 100 && len(order.Products) == 1 && order.Products[0].Price == 100
 		})).Return(nil)
 
 		resp, err := server.StartCheckout(context.Background(), req)
@@ -50,7 +90,8 @@ func TestServer_StartCheckout(t *testing.T) {
 
 	t.Run("Invalid UserID", func(t *testing.T) {
 		mockOrchestrator := new(MockOrchestrator)
-		server := NewSagaServer(logger, mockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
 
 		req := &proto.StartCheckoutRequest{
 			UserID: 0,
@@ -64,12 +105,13 @@ func TestServer_StartCheckout(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Empty(t, resp.OrderID)
 		assert.Equal(t, "invalid user ID", resp.Error)
-		mockOrchestrator.AssertNotCalled(t, "SagaTransaction", mock.Anything, mock.Anything)
+		mockOrchestrator.AssertNotCalled(t, "StartSaga", mock.Anything, mock.Anything)
 	})
 
 	t.Run("Empty Cart", func(t *testing.T) {
 		mockOrchestrator := new(MockOrchestrator)
-		server := NewSagaServer(logger, mockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
 
 		req := &proto.StartCheckoutRequest{
 			UserID: 1,
@@ -81,12 +123,13 @@ func TestServer_StartCheckout(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Empty(t, resp.OrderID)
 		assert.Equal(t, "cart is empty", resp.Error)
-		mockOrchestrator.AssertNotCalled(t, "SagaTransaction", mock.Anything, mock.Anything)
+		mockOrchestrator.AssertNotCalled(t, "StartSaga", mock.Anything, mock.Anything)
 	})
 
 	t.Run("Invalid Cart Item", func(t *testing.T) {
 		mockOrchestrator := new(MockOrchestrator)
-		server := NewSagaServer(logger, mockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
 
 		req := &proto.StartCheckoutRequest{
 			UserID: 1,
@@ -100,12 +143,14 @@ func TestServer_StartCheckout(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Empty(t, resp.OrderID)
 		assert.Equal(t, "invalid cart item", resp.Error)
-		mockOrchestrator.AssertNotCalled(t, "SagaTransaction", mock.Anything, mock.Anything)
+		mockOrchestrator.AssertNotCalled(t, "StartSaga", mock.Anything, mock.Anything)
+		mockPricer.AssertNotCalled(t, "GetPrices", mock.Anything, mock.Anything)
 	})
 
 	t.Run("Invalid Total Amount", func(t *testing.T) {
 		mockOrchestrator := new(MockOrchestrator)
-		server := NewSagaServer(logger, mockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
 
 		req := &proto.StartCheckoutRequest{
 			UserID: 1,
@@ -114,17 +159,20 @@ func TestServer_StartCheckout(t *testing.T) {
 			},
 		}
 
+		mockPricer.On("GetPrices", mock.Anything, []int64{1}).Return(map[int64]int64{1: 0}, nil)
+
 		resp, err := server.StartCheckout(context.Background(), req)
 
 		assert.NoError(t, err)
 		assert.Empty(t, resp.OrderID)
 		assert.Equal(t, "invalid total amount", resp.Error)
-		mockOrchestrator.AssertNotCalled(t, "SagaTransaction", mock.Anything, mock.Anything)
+		mockOrchestrator.AssertNotCalled(t, "StartSaga", mock.Anything, mock.Anything)
 	})
 
-	t.Run("Saga Transaction Failed", func(t *testing.T) {
+	t.Run("Start Saga Failed", func(t *testing.T) {
 		mockOrchestrator := new(MockOrchestrator)
-		server := NewSagaServer(logger, mockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
 
 		req := &proto.StartCheckoutRequest{
 			UserID: 1,
@@ -133,13 +181,373 @@ func TestServer_StartCheckout(t *testing.T) {
 			},
 		}
 
-		mockOrchestrator.On("SagaTransaction", mock.Anything, mock.Anything).Return(errors.New("saga error"))
+		mockPricer.On("GetPrices", mock.Anything, []int64{1}).Return(map[int64]int64{1: 100}, nil)
+		mockOrchestrator.On("StartSaga", mock.Anything, mock.Anything).Return(errors.New("saga error"))
 
 		resp, err := server.StartCheckout(context.Background(), req)
 
 		assert.NoError(t, err)
-		assert.NotEmpty(t, resp.OrderID)
+		assert.Empty(t, resp.OrderID)
 		assert.Equal(t, "saga error", resp.Error)
 		mockOrchestrator.AssertExpectations(t)
 	})
+
+	t.Run("Prices Changed", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
+
+		req := &proto.StartCheckoutRequest{
+			UserID: 1,
+			Cart: []*proto.Cart{
+				{ProductID: 1, Quantity: 2, Price: 100},
+				{ProductID: 2, Quantity: 1, Price: 50},
+			},
+		}
+
+		mockPricer.On("GetPrices", mock.Anything, []int64{1, 2}).Return(map[int64]int64{1: 120, 2: 50}, nil)
+
+		resp, err := server.StartCheckout(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Empty(t, resp.OrderID)
+		assert.Equal(t, apperrors.ErrPricesChanged.Error(), resp.Error)
+		if assert.Len(t, resp.Quote, 2) {
+			assert.Equal(t, int64(120), resp.Quote[0].Price)
+			assert.Equal(t, int64(2), resp.Quote[0].Quantity)
+			assert.Equal(t, int64(50), resp.Quote[1].Price)
+		}
+		mockOrchestrator.AssertNotCalled(t, "StartSaga", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Product Unavailable", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
+
+		req := &proto.StartCheckoutRequest{
+			UserID: 1,
+			Cart: []*proto.Cart{
+				{ProductID: 1, Quantity: 1, Price: 100},
+				{ProductID: 7, Quantity: 1, Price: 100},
+			},
+		}
+
+		mockPricer.On("GetPrices", mock.Anything, []int64{1, 7}).Return(map[int64]int64{1: 100}, nil)
+
+		resp, err := server.StartCheckout(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Empty(t, resp.OrderID)
+		assert.Contains(t, resp.Error, apperrors.ErrProductUnavailable.Error())
+		assert.Empty(t, resp.Quote)
+		mockOrchestrator.AssertNotCalled(t, "StartSaga", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Get Prices Failed", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
+
+		req := &proto.StartCheckoutRequest{
+			UserID: 1,
+			Cart: []*proto.Cart{
+				{ProductID: 1, Quantity: 1, Price: 100},
+			},
+		}
+
+		mockPricer.On("GetPrices", mock.Anything, []int64{1}).Return(nil, errors.New("products unavailable"))
+
+		resp, err := server.StartCheckout(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Empty(t, resp.OrderID)
+		assert.Equal(t, "failed to get current prices", resp.Error)
+		mockOrchestrator.AssertNotCalled(t, "StartSaga", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Replays Checkout With Same Idempotency Key", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
+
+		req := &proto.StartCheckoutRequest{
+			UserID:         1,
+			IdempotencyKey: "key-1",
+			Cart: []*proto.Cart{
+				{ProductID: 1, Quantity: 1, Price: 100},
+			},
+		}
+
+		mockOrchestrator.On("FindCheckout", mock.Anything, int64(1), "key-1").Return(&sagaEntity.Instance{
+			OrderID: "order-123",
+			UserID:  1,
+			Status:  sagaEntity.StatusFailed,
+			Error:   "wallet reserve failed: rpc error: code = FailedPrecondition desc = insufficient funds",
+			// Повтор отдаёт тот же код причины, что и GetCheckoutStatus
+			FailureReason: "insufficient_funds",
+		}, nil)
+
+		resp, err := server.StartCheckout(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "order-123", resp.OrderID)
+		assert.True(t, resp.Replayed)
+		assert.Equal(t, CheckoutStatusFailed, resp.Status)
+		assert.Equal(t, "insufficient_funds", resp.Reason)
+		mockPricer.AssertNotCalled(t, "GetPrices", mock.Anything, mock.Anything)
+		mockOrchestrator.AssertNotCalled(t, "StartSaga", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Starts Checkout With New Idempotency Key", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
+
+		req := &proto.StartCheckoutRequest{
+			UserID:         1,
+			IdempotencyKey: "key-1",
+			Cart: []*proto.Cart{
+				{ProductID: 1, Quantity: 1, Price: 100},
+			},
+		}
+
+		mockOrchestrator.On("FindCheckout", mock.Anything, int64(1), "key-1").Return(nil, apperrors.ErrSagaNotFound)
+		mockPricer.On("GetPrices", mock.Anything, []int64{1}).Return(map[int64]int64{1: 100}, nil)
+		mockOrchestrator.On("StartSaga", mock.Anything, mock.MatchedBy(func(order orderEntity.OrderEvent) bool {
+			return order.IdempotencyKey == "key-1"
+		})).Return(nil)
+
+		resp, err := server.StartCheckout(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resp.OrderID)
+		assert.False(t, resp.Replayed)
+		assert.Equal(t, CheckoutStatusPending, resp.Status)
+		mockOrchestrator.AssertExpectations(t)
+	})
+
+	t.Run("Replays Checkout Created Concurrently", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		mockPricer := new(MockPricer)
+		server := NewSagaServer(logger, mockOrchestrator, mockPricer)
+
+		req := &proto.StartCheckoutRequest{
+			UserID:         1,
+			IdempotencyKey: "key-1",
+			Cart: []*proto.Cart{
+				{ProductID: 1, Quantity: 1, Price: 100},
+			},
+		}
+
+		mockOrchestrator.On("FindCheckout", mock.Anything, int64(1), "key-1").Return(nil, apperrors.ErrSagaNotFound).Once()
+		mockPricer.On("GetPrices", mock.Anything, []int64{1}).Return(map[int64]int64{1: 100}, nil)
+		mockOrchestrator.On("StartSaga", mock.Anything, mock.Anything).Return(apperrors.ErrDuplicateCheckout)
+		mockOrchestrator.On("FindCheckout", mock.Anything, int64(1), "key-1").Return(&sagaEntity.Instance{
+			OrderID: "order-123",
+			UserID:  1,
+			Status:  sagaEntity.StatusRunning,
+		}, nil).Once()
+
+		resp, err := server.StartCheckout(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "order-123", resp.OrderID)
+		assert.True(t, resp.Replayed)
+		assert.Equal(t, CheckoutStatusPending, resp.Status)
+		mockOrchestrator.AssertExpectations(t)
+	})
+
+	t.Run("Idempotency Key Too Long", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		server := NewSagaServer(logger, mockOrchestrator, new(MockPricer))
+
+		req := &proto.StartCheckoutRequest{
+			UserID:         1,
+			IdempotencyKey: strings.Repeat("k", maxIdempotencyKeyLen+1),
+			Cart: []*proto.Cart{
+				{ProductID: 1, Quantity: 1, Price: 100},
+			},
+		}
+
+		resp, err := server.StartCheckout(context.Background(), req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "invalid idempotency key", resp.Error)
+		mockOrchestrator.AssertNotCalled(t, "FindCheckout", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestServer_GetCheckoutStatus(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	tests := []struct {
+		name          string
+		sagaKind      sagaEntity.Kind
+		sagaStatus    sagaEntity.Status
+		failureReason string
+		wantStatus    string
+		wantReason    string
+	}{
+		{name: "Running Is Pending", sagaStatus: sagaEntity.StatusRunning, wantStatus: CheckoutStatusPending},
+		{name: "Compensating Is Pending", sagaStatus: sagaEntity.StatusCompensating, wantStatus: CheckoutStatusPending},
+		{name: "Completed", sagaStatus: sagaEntity.StatusCompleted, wantStatus: CheckoutStatusCompleted},
+		{name: "Failed With Reason", sagaStatus: sagaEntity.StatusFailed, failureReason: "insufficient_funds", wantStatus: CheckoutStatusFailed, wantReason: "insufficient_funds"},
+		{name: "Resolved Is Failed", sagaStatus: sagaEntity.StatusResolved, failureReason: "out_of_stock", wantStatus: CheckoutStatusFailed, wantReason: "out_of_stock"},
+		{name: "Failed Without Reason Is Internal", sagaStatus: sagaEntity.StatusFailed, wantStatus: CheckoutStatusFailed, wantReason: "internal_error"},
+		{name: "Running Cancellation", sagaKind: sagaEntity.KindCancellation, sagaStatus: sagaEntity.StatusRunning, wantStatus: CheckoutStatusCancelling},
+		{name: "Failed Cancellation Is Still Cancelling", sagaKind: sagaEntity.KindCancellation, sagaStatus: sagaEntity.StatusFailed, wantStatus: CheckoutStatusCancelling},
+		{name: "Completed Cancellation", sagaKind: sagaEntity.KindCancellation, sagaStatus: sagaEntity.StatusCompleted, wantStatus: CheckoutStatusCancelled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrchestrator := new(MockOrchestrator)
+			server := NewSagaServer(logger, mockOrchestrator, new(MockPricer))
+
+			mockOrchestrator.On("GetSaga", mock.Anything, "order-123").Return(&sagaEntity.Instance{
+				OrderID: "order-123",
+				Kind:    tt.sagaKind,
+				UserID:  1,
+				Status:  tt.sagaStatus,
+				// Цепочка ошибок не должна дойти до пользователя
+				Error:         "wallet reserve failed: rpc error: code = Unavailable desc = connection refused",
+				FailureReason: tt.failureReason,
+			}, nil)
+
+			resp, err := server.GetCheckoutStatus(context.Background(), &proto.GetCheckoutStatusRequest{OrderID: "order-123", UserID: 1})
+
+			assert.NoError(t, err)
+			assert.Equal(t, "order-123", resp.OrderID)
+			assert.Equal(t, tt.wantStatus, resp.Status)
+			assert.Equal(t, tt.wantReason, resp.Reason)
+		})
+	}
+
+	t.Run("Not Found", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		server := NewSagaServer(logger, mockOrchestrator, new(MockPricer))
+
+		mockOrchestrator.On("GetSaga", mock.Anything, "order-123").Return(nil, apperrors.ErrSagaNotFound)
+
+		_, err := server.GetCheckoutStatus(context.Background(), &proto.GetCheckoutStatusRequest{OrderID: "order-123", UserID: 1})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Other User Order", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		server := NewSagaServer(logger, mockOrchestrator, new(MockPricer))
+
+		mockOrchestrator.On("GetSaga", mock.Anything, "order-123").Return(&sagaEntity.Instance{
+			OrderID: "order-123",
+			UserID:  2,
+			Status:  sagaEntity.StatusCompleted,
+		}, nil)
+
+		_, err := server.GetCheckoutStatus(context.Background(), &proto.GetCheckoutStatusRequest{OrderID: "order-123", UserID: 1})
+
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("Empty Order ID", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		server := NewSagaServer(logger, mockOrchestrator, new(MockPricer))
+
+		_, err := server.GetCheckoutStatus(context.Background(), &proto.GetCheckoutStatusRequest{UserID: 1})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockOrchestrator.AssertNotCalled(t, "GetSaga", mock.Anything, mock.Anything)
+	})
+}
+
+func TestServer_CancelOrder(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+
+	tests := []struct {
+		name     string
+		req      *proto.CancelOrderRequest
+		startErr error
+		wantCode codes.Code
+	}{
+		{name: "Success", req: &proto.CancelOrderRequest{OrderID: "order-123", UserID: 1}, wantCode: codes.OK},
+		{name: "Not Found", req: &proto.CancelOrderRequest{OrderID: "order-123", UserID: 1}, startErr: apperrors.ErrSagaNotFound, wantCode: codes.NotFound},
+		{name: "Not Cancellable", req: &proto.CancelOrderRequest{OrderID: "order-123", UserID: 1}, startErr: apperrors.ErrOrderNotCancellable, wantCode: codes.FailedPrecondition},
+		{name: "Internal Error", req: &proto.CancelOrderRequest{OrderID: "order-123", UserID: 1}, startErr: errors.New("db error"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrchestrator := new(MockOrchestrator)
+			server := NewSagaServer(logger, mockOrchestrator, new(MockPricer))
+
+			mockOrchestrator.On("StartCancellation", mock.Anything, "order-123", int64(1)).Return(tt.startErr)
+
+			_, err := server.CancelOrder(context.Background(), tt.req)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			mockOrchestrator.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Invalid Request", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		server := NewSagaServer(logger, mockOrchestrator, new(MockPricer))
+
+		_, err := server.CancelOrder(context.Background(), &proto.CancelOrderRequest{OrderID: "order-123"})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockOrchestrator.AssertNotCalled(t, "StartCancellation", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestServer_StartReturn(t *testing.T) {
+	logger := zap.NewNop().Sugar()
+	req := &proto.StartReturnRequest{
+		ReturnID: "return-1",
+		OrderID:  "order-123",
+		UserID:   1,
+		Items:    []*proto.ReturnItem{{ProductID: 1, Quantity: 2}},
+	}
+	items := []entity.Product{{ID: 1, Quantity: 2}}
+
+	tests := []struct {
+		name     string
+		amount   int64
+		startErr error
+		wantCode codes.Code
+	}{
+		{name: "Success", amount: 200, wantCode: codes.OK},
+		{name: "Not Found", startErr: apperrors.ErrSagaNotFound, wantCode: codes.NotFound},
+		{name: "Not Returnable", startErr: apperrors.ErrOrderNotReturnable, wantCode: codes.FailedPrecondition},
+		{name: "Invalid Items", startErr: apperrors.ErrInvalidReturn, wantCode: codes.InvalidArgument},
+		{name: "Internal Error", startErr: errors.New("db error"), wantCode: codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockOrchestrator := new(MockOrchestrator)
+			server := NewSagaServer(logger, mockOrchestrator, new(MockPricer))
+
+			mockOrchestrator.On("StartReturn", mock.Anything, "return-1", "order-123", int64(1), items).Return(tt.amount, tt.startErr)
+
+			resp, err := server.StartReturn(context.Background(), req)
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			if tt.wantCode == codes.OK {
+				assert.Equal(t, tt.amount, resp.Amount)
+			}
+			mockOrchestrator.AssertExpectations(t)
+		})
+	}
+
+	t.Run("Invalid Request", func(t *testing.T) {
+		mockOrchestrator := new(MockOrchestrator)
+		server := NewSagaServer(logger, mockOrchestrator, new(MockPricer))
+
+		_, err := server.StartReturn(context.Background(), &proto.StartReturnRequest{OrderID: "order-123", UserID: 1})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		mockOrchestrator.AssertNotCalled(t, "StartReturn", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
 }
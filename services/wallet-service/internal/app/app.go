@@ -17,6 +17,7 @@ import (
 	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/config"
 	db "github.com/vsespontanno/eCommerce/services/wallet-service/internal/infrastructure/db/postgres"
 	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/infrastructure/grpcClient"
+	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/infrastructure/jobs"
 	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/infrastructure/repository/postgres"
 	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/presentation/gateway"
 	sagaServ "github.com/vsespontanno/eCommerce/services/wallet-service/internal/presentation/server/saga"
@@ -35,6 +36,7 @@ type App struct {
 	usrServ     *grpc.Server
 	sagaSrv     *grpc.Server
 	gateway     *gateway.Gateway
+	sweeper     *jobs.ReservationSweeper
 	db          *sql.DB
 	userPort    int
 	sagaPort    int
@@ -52,7 +54,7 @@ func New(logger *zap.SugaredLogger, cfg *config.Config) (*App, error) {
 	}
 
 	usrRepo := postgres.NewWalletUserStore(dataBase)
-	sagaRepo := postgres.NewSagaWalletStore(dataBase, logger)
+	sagaRepo := postgres.NewSagaWalletStore(dataBase, logger, cfg.ReservationTTL)
 	gRPCClient := grpcClient.NewJwtClient(cfg.GRPCClient)
 
 	// initialize servers with interceptor chain
@@ -72,11 +74,15 @@ func New(logger *zap.SugaredLogger, cfg *config.Config) (*App, error) {
 	grpcUserAddr := fmt.Sprintf("localhost:%d", cfg.GRPCUserServer)
 	gw := gateway.NewGateway(grpcUserAddr, cfg.HTTPGateway, logger)
 
+	// Background release of reserves that outlived their TTL
+	sweeper := jobs.NewReservationSweeper(sagaRepo, logger, cfg.ReservationSweep, cfg.ReservationSweepBatch)
+
 	return &App{
 		Log:         logger,
 		usrServ:     userGRPCServer,
 		sagaSrv:     sagaGRPCServer,
 		gateway:     gw,
+		sweeper:     sweeper,
 		db:          dataBase,
 		userPort:    cfg.GRPCUserServer,
 		sagaPort:    cfg.GRPCSagaServer,
@@ -157,6 +163,8 @@ func (a *App) startServers(ctx context.Context, errCh chan error, op string) {
 	go a.startSagaServer(errCh, op)
 	// Start HTTP gateway
 	go a.startGateway(ctx)
+	// Start reservation sweeper, it stops with ctx
+	go a.sweeper.Start(ctx)
 }
 
 // startUserServer starts the user gRPC server
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/domain/wallet/entity/apperrors"
 	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/domain/wallet/interfaces"
 	"go.uber.org/zap"
 )
@@ -26,23 +27,25 @@ func NewSagaWalletService(walletRepo interfaces.TransactionWallet, logger *zap.S
 }
 
 // Reserve reserves funds for a transaction (saga step 1)
-func (s *WalletService) Reserve(ctx context.Context, userID int64, amount int64) error {
+func (s *WalletService) Reserve(ctx context.Context, userID int64, amount int64, transactionID string) error {
 	// Validate input
 	if err := s.validateTransactionAmount(userID, amount); err != nil {
 		s.logger.Warnw("Invalid reserve request",
 			"userID", userID,
 			"amount", amount,
+			"transactionID", transactionID,
 			"error", err,
 		)
 		return err
 	}
 
 	// Reserve funds (repository will check balance)
-	err := s.walletRepo.ReserveMoney(ctx, userID, amount)
+	err := s.walletRepo.ReserveMoney(ctx, userID, amount, transactionID)
 	if err != nil {
 		s.logger.Errorw("Failed to reserve funds",
 			"userID", userID,
 			"amount", amount,
+			"transactionID", transactionID,
 			"error", err,
 		)
 		return err
@@ -51,28 +54,31 @@ func (s *WalletService) Reserve(ctx context.Context, userID int64, amount int64)
 	s.logger.Infow("Funds reserved successfully",
 		"userID", userID,
 		"amount", amount,
+		"transactionID", transactionID,
 	)
 
 	return nil
 }
 
 // Release releases previously reserved funds (saga rollback)
-func (s *WalletService) Release(ctx context.Context, userID int64, amount int64) error {
+func (s *WalletService) Release(ctx context.Context, userID int64, amount int64, transactionID string) error {
 	// Validate input
 	if err := s.validateTransactionAmount(userID, amount); err != nil {
 		s.logger.Warnw("Invalid release request",
 			"userID", userID,
 			"amount", amount,
+			"transactionID", transactionID,
 			"error", err,
 		)
 		return err
 	}
 
-	err := s.walletRepo.ReleaseMoney(ctx, userID, amount)
+	err := s.walletRepo.ReleaseMoney(ctx, userID, amount, transactionID)
 	if err != nil {
 		s.logger.Errorw("Failed to release funds",
 			"userID", userID,
 			"amount", amount,
+			"transactionID", transactionID,
 			"error", err,
 		)
 		return err
@@ -81,28 +87,31 @@ func (s *WalletService) Release(ctx context.Context, userID int64, amount int64)
 	s.logger.Infow("Funds released successfully",
 		"userID", userID,
 		"amount", amount,
+		"transactionID", transactionID,
 	)
 
 	return nil
 }
 
 // Commit commits reserved funds (saga step 2 - final deduction)
-func (s *WalletService) Commit(ctx context.Context, userID int64, amount int64) error {
+func (s *WalletService) Commit(ctx context.Context, userID int64, amount int64, transactionID string) error {
 	// Validate input
 	if err := s.validateTransactionAmount(userID, amount); err != nil {
 		s.logger.Warnw("Invalid commit request",
 			"userID", userID,
 			"amount", amount,
+			"transactionID", transactionID,
 			"error", err,
 		)
 		return err
 	}
 
-	err := s.walletRepo.CommitMoney(ctx, userID, amount)
+	err := s.walletRepo.CommitMoney(ctx, userID, amount, transactionID)
 	if err != nil {
 		s.logger.Errorw("Failed to commit funds",
 			"userID", userID,
 			"amount", amount,
+			"transactionID", transactionID,
 			"error", err,
 		)
 		return err
@@ -111,6 +120,53 @@ func (s *WalletService) Commit(ctx context.Context, userID int64, amount int64)
 	s.logger.Infow("Funds committed successfully",
 		"userID", userID,
 		"amount", amount,
+		"transactionID", transactionID,
+	)
+
+	return nil
+}
+
+// Refund credits committed funds back to the wallet (compensation after commit).
+// With a refundID only the given part of the transaction is refunded, e.g. for returned items.
+func (s *WalletService) Refund(ctx context.Context, userID int64, amount int64, transactionID, refundID string) error {
+	// Validate input
+	if err := s.validateTransactionAmount(userID, amount); err != nil {
+		s.logger.Warnw("Invalid refund request",
+			"userID", userID,
+			"amount", amount,
+			"transactionID", transactionID,
+			"error", err,
+		)
+		return err
+	}
+	// Without a transaction ID a retried refund would credit the money twice
+	if transactionID == "" {
+		s.logger.Warnw("Refund request without transaction ID", "userID", userID, "amount", amount)
+		return apperrors.ErrMissingTransactionID
+	}
+
+	var err error
+	if refundID != "" {
+		err = s.walletRepo.RefundPartial(ctx, userID, amount, transactionID, refundID)
+	} else {
+		err = s.walletRepo.RefundMoney(ctx, userID, amount, transactionID)
+	}
+	if err != nil {
+		s.logger.Errorw("Failed to refund funds",
+			"userID", userID,
+			"amount", amount,
+			"transactionID", transactionID,
+			"refundID", refundID,
+			"error", err,
+		)
+		return err
+	}
+
+	s.logger.Infow("Funds refunded successfully",
+		"userID", userID,
+		"amount", amount,
+		"transactionID", transactionID,
+		"refundID", refundID,
 	)
 
 	return nil
@@ -0,0 +1,255 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vsespontanno/eCommerce/pkg/logger"
+	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/domain/wallet/entity/apperrors"
+)
+
+func init() {
+	logger.InitLogger()
+}
+
+// MockTransactionWallet is a mock implementation of interfaces.TransactionWallet
+type MockTransactionWallet struct {
+	GetBalanceFunc   func(ctx context.Context, userID int64) (int64, error)
+	ReserveMoneyFunc func(ctx context.Context, userID int64, amount int64, transactionID string) error
+	ReleaseMoneyFunc func(ctx context.Context, userID int64, amount int64, transactionID string) error
+	CommitMoneyFunc  func(ctx context.Context, userID int64, amount int64, transactionID string) error
+	RefundMoneyFunc  func(ctx context.Context, userID int64, amount int64, transactionID string) error
+	// RefundPartialFunc is left nil by tests that never refund partially
+	RefundPartialFunc func(ctx context.Context, userID int64, amount int64, transactionID, refundID string) error
+}
+
+func (m *MockTransactionWallet) GetBalance(ctx context.Context, userID int64) (int64, error) {
+	return m.GetBalanceFunc(ctx, userID)
+}
+
+func (m *MockTransactionWallet) ReserveMoney(ctx context.Context, userID int64, amount int64, transactionID string) error {
+	return m.ReserveMoneyFunc(ctx, userID, amount, transactionID)
+}
+
+func (m *MockTransactionWallet) ReleaseMoney(ctx context.Context, userID int64, amount int64, transactionID string) error {
+	return m.ReleaseMoneyFunc(ctx, userID, amount, transactionID)
+}
+
+func (m *MockTransactionWallet) CommitMoney(ctx context.Context, userID int64, amount int64, transactionID string) error {
+	return m.CommitMoneyFunc(ctx, userID, amount, transactionID)
+}
+
+func (m *MockTransactionWallet) RefundMoney(ctx context.Context, userID int64, amount int64, transactionID string) error {
+	return m.RefundMoneyFunc(ctx, userID, amount, transactionID)
+}
+
+func (m *MockTransactionWallet) RefundPartial(ctx context.Context, userID int64, amount int64, transactionID, refundID string) error {
+	return m.RefundPartialFunc(ctx, userID, amount, transactionID, refundID)
+}
+
+func TestWalletService_Reserve(t *testing.T) {
+	tests := []struct {
+		name          string
+		userID        int64
+		amount        int64
+		transactionID string
+		repoErr       error
+		expectCall    bool
+		expectedError error
+	}{
+		{
+			name:          "Success",
+			userID:        1,
+			amount:        1000,
+			transactionID: "order-123",
+			expectCall:    true,
+		},
+		{
+			name:          "Insufficient Funds",
+			userID:        1,
+			amount:        1000,
+			transactionID: "order-123",
+			repoErr:       apperrors.ErrInsufficientFunds,
+			expectCall:    true,
+			expectedError: apperrors.ErrInsufficientFunds,
+		},
+		{
+			name:          "Transaction Reused With Other Amount",
+			userID:        1,
+			amount:        2000,
+			transactionID: "order-123",
+			repoErr:       apperrors.ErrTransactionMismatch,
+			expectCall:    true,
+			expectedError: apperrors.ErrTransactionMismatch,
+		},
+		{
+			name:          "Invalid Amount",
+			userID:        1,
+			amount:        0,
+			transactionID: "order-123",
+			expectCall:    false,
+		},
+		{
+			name:          "Amount Over Limit",
+			userID:        1,
+			amount:        MaxTransactionAmount + 1,
+			transactionID: "order-123",
+			expectCall:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			repo := &MockTransactionWallet{
+				ReserveMoneyFunc: func(ctx context.Context, userID int64, amount int64, transactionID string) error {
+					called = true
+					if transactionID != tt.transactionID {
+						t.Errorf("expected transactionID %q, got %q", tt.transactionID, transactionID)
+					}
+					return tt.repoErr
+				},
+			}
+			service := NewSagaWalletService(repo, logger.Log)
+
+			err := service.Reserve(context.Background(), tt.userID, tt.amount, tt.transactionID)
+
+			if called != tt.expectCall {
+				t.Errorf("expected repository call: %v, got %v", tt.expectCall, called)
+			}
+			if !tt.expectCall {
+				if err == nil {
+					t.Error("expected validation error, got nil")
+				}
+				return
+			}
+			if !errors.Is(err, tt.expectedError) {
+				t.Errorf("expected error %v, got %v", tt.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestWalletService_CommitAndRelease(t *testing.T) {
+	var committed, released []string
+	repo := &MockTransactionWallet{
+		CommitMoneyFunc: func(ctx context.Context, userID int64, amount int64, transactionID string) error {
+			committed = append(committed, transactionID)
+			return nil
+		},
+		ReleaseMoneyFunc: func(ctx context.Context, userID int64, amount int64, transactionID string) error {
+			released = append(released, transactionID)
+			return apperrors.ErrInvalidTransactionState
+		},
+	}
+	service := NewSagaWalletService(repo, logger.Log)
+
+	if err := service.Commit(context.Background(), 1, 1000, "order-1"); err != nil {
+		t.Errorf("unexpected commit error: %v", err)
+	}
+	if err := service.Release(context.Background(), 1, 1000, "order-2"); !errors.Is(err, apperrors.ErrInvalidTransactionState) {
+		t.Errorf("expected ErrInvalidTransactionState, got %v", err)
+	}
+
+	if len(committed) != 1 || committed[0] != "order-1" {
+		t.Errorf("unexpected commit calls: %v", committed)
+	}
+	if len(released) != 1 || released[0] != "order-2" {
+		t.Errorf("unexpected release calls: %v", released)
+	}
+}
+
+func TestWalletService_Refund(t *testing.T) {
+	tests := []struct {
+		name          string
+		transactionID string
+		refundID      string
+		repoErr       error
+		expectedErr   error
+		expectedCalls int
+		expectPartial bool
+	}{
+		{
+			name:          "Success",
+			transactionID: "order-1",
+			expectedCalls: 1,
+		},
+		{
+			name:          "Partial Refund",
+			transactionID: "order-1",
+			refundID:      "return-1",
+			expectedCalls: 1,
+			expectPartial: true,
+		},
+		{
+			name:          "Partial Refund Exceeds Committed",
+			transactionID: "order-1",
+			refundID:      "return-2",
+			repoErr:       apperrors.ErrRefundExceedsTransaction,
+			expectedErr:   apperrors.ErrRefundExceedsTransaction,
+			expectedCalls: 1,
+			expectPartial: true,
+		},
+		{
+			name:          "Partial Refund Missing Transaction ID",
+			transactionID: "",
+			refundID:      "return-1",
+			expectedErr:   apperrors.ErrMissingTransactionID,
+			expectedCalls: 0,
+		},
+		{
+			name:          "Missing Transaction ID",
+			transactionID: "",
+			expectedErr:   apperrors.ErrMissingTransactionID,
+			expectedCalls: 0,
+		},
+		{
+			name:          "Not Committed",
+			transactionID: "order-1",
+			repoErr:       apperrors.ErrInvalidTransactionState,
+			expectedErr:   apperrors.ErrInvalidTransactionState,
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			repo := &MockTransactionWallet{
+				RefundMoneyFunc: func(ctx context.Context, userID int64, amount int64, transactionID string) error {
+					calls++
+					if tt.expectPartial {
+						t.Error("expected partial refund, got full refund")
+					}
+					if transactionID != tt.transactionID {
+						t.Errorf("expected transactionID %q, got %q", tt.transactionID, transactionID)
+					}
+					return tt.repoErr
+				},
+				RefundPartialFunc: func(ctx context.Context, userID int64, amount int64, transactionID, refundID string) error {
+					calls++
+					if !tt.expectPartial {
+						t.Error("expected full refund, got partial refund")
+					}
+					if transactionID != tt.transactionID || refundID != tt.refundID {
+						t.Errorf("expected %q/%q, got %q/%q", tt.transactionID, tt.refundID, transactionID, refundID)
+					}
+					return tt.repoErr
+				},
+			}
+			service := NewSagaWalletService(repo, logger.Log)
+
+			err := service.Refund(context.Background(), 1, 1000, tt.transactionID, tt.refundID)
+			if tt.expectedErr == nil && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			if tt.expectedErr != nil && !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected %v, got %v", tt.expectedErr, err)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("expected %d repo calls, got %d", tt.expectedCalls, calls)
+			}
+		})
+	}
+}
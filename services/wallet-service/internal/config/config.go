@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -17,6 +18,10 @@ type Config struct {
 	User           string
 	Password       string
 	Name           string
+	// Reservation expiry
+	ReservationTTL        time.Duration
+	ReservationSweep      time.Duration
+	ReservationSweepBatch int
 }
 
 func MustLoad() (*Config, error) {
@@ -32,6 +37,9 @@ func MustLoad() (*Config, error) {
 	cfg.User = os.Getenv("PG_USER")
 	cfg.Password = os.Getenv("PG_PASSWORD")
 	cfg.Name = os.Getenv("PG_NAME")
+	cfg.ReservationTTL = time.Duration(getEnvAsInt("RESERVATION_TTL_SECONDS", 900)) * time.Second
+	cfg.ReservationSweep = time.Duration(getEnvAsInt("RESERVATION_SWEEP_INTERVAL_SECONDS", 60)) * time.Second
+	cfg.ReservationSweepBatch = getEnvAsInt("RESERVATION_SWEEP_BATCH", 100)
 	return &cfg, nil
 }
 
@@ -41,7 +49,7 @@ func getEnvAsInt(name string, defaultVal int) int {
 		return defaultVal
 	}
 	intVal, err := strconv.Atoi(val)
-	if err != nil {
+	if err != nil || intVal <= 0 {
 		return defaultVal
 	}
 	return intVal
@@ -6,4 +6,12 @@ var (
 	ErrNoWallet          = errors.New("no wallet found. would u like to create ur own?")
 	ErrNotAuthorized     = errors.New("not authorized")
 	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrTransactionMismatch is returned when a transaction ID is reused with another user or amount
+	ErrTransactionMismatch = errors.New("transaction id already used with different parameters")
+	// ErrInvalidTransactionState is returned when an operation is not allowed in the current transaction state
+	ErrInvalidTransactionState = errors.New("operation not allowed in current transaction state")
+	// ErrMissingTransactionID is returned when an operation cannot be made idempotent without a transaction ID
+	ErrMissingTransactionID = errors.New("transaction id is required")
+	// ErrRefundExceedsTransaction is returned when partial refunds would return more than was committed
+	ErrRefundExceedsTransaction = errors.New("refund exceeds committed amount")
 )
@@ -0,0 +1,50 @@
+package wallet
+
+import "time"
+
+// TransactionStatus is the state of a saga money transaction
+type TransactionStatus string
+
+const (
+	TransactionReserved  TransactionStatus = "RESERVED"
+	TransactionCommitted TransactionStatus = "COMMITTED"
+	TransactionReleased  TransactionStatus = "RELEASED"
+	// TransactionRefunded means committed funds were credited back to the wallet
+	TransactionRefunded TransactionStatus = "REFUNDED"
+	// TransactionExpired means the reserve outlived its TTL and was released by the sweeper
+	TransactionExpired TransactionStatus = "EXPIRED"
+)
+
+// EventTypeReservationExpired is the outbox event type written for every expired reserve
+const EventTypeReservationExpired = "WalletReservationExpired"
+
+// Expiry events share the outbox with the saga orchestrator, so they are written in the same
+// CloudEvents envelope and under their own aggregate type, which the outbox publisher routes
+// to the reservations topic instead of the order topic.
+const (
+	EventSource                = "wallet-service"
+	EventSchemaVersion         = 1
+	OutboxAggregateTransaction = "WalletTransaction"
+)
+
+// Transaction records the outcome of saga operations for one transaction ID,
+// so repeated reserve/commit/release calls can be answered without touching the balance again
+type Transaction struct {
+	TransactionID string            // Idempotency key, the order ID for checkout sagas
+	UserID        int64             // Wallet owner
+	Amount        int64             // Amount in cents/kopecks
+	Status        TransactionStatus // Last applied operation
+	ExpiresAt     *time.Time        // Deadline of a RESERVED transaction, nil otherwise
+	// ParentTransactionID is the committed transaction a partial refund belongs to, empty otherwise
+	ParentTransactionID string
+}
+
+// ReservationExpiredEvent is the payload of EventTypeReservationExpired
+type ReservationExpiredEvent struct {
+	TransactionID string    `json:"transaction_id"`
+	UserID        int64     `json:"user_id"`
+	Amount        int64     `json:"amount"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	ExpiredAt     time.Time `json:"expired_at"`
+	EventType     string    `json:"event_type"`
+}
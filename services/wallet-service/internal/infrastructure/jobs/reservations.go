@@ -0,0 +1,84 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/domain/wallet/entity/wallet"
+	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/infrastructure/metrics"
+	"go.uber.org/zap"
+)
+
+// ReservationExpirer releases reserves whose TTL has passed and returns them
+type ReservationExpirer interface {
+	ExpireReservations(ctx context.Context, limit int) ([]wallet.Transaction, error)
+}
+
+// ReservationSweeper periodically releases expired reserves, so wallets.reserved
+// heals itself when the orchestrator crashes or a compensation fails
+type ReservationSweeper struct {
+	store     ReservationExpirer
+	logger    *zap.SugaredLogger
+	interval  time.Duration
+	batchSize int
+}
+
+func NewReservationSweeper(store ReservationExpirer, logger *zap.SugaredLogger, interval time.Duration, batchSize int) *ReservationSweeper {
+	return &ReservationSweeper{
+		store:     store,
+		logger:    logger,
+		interval:  interval,
+		batchSize: batchSize,
+	}
+}
+
+// Start runs the sweeper until ctx is cancelled
+func (j *ReservationSweeper) Start(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.logger.Infow("Reservation sweeper started", "interval", j.interval)
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := j.Sweep(ctx); err != nil {
+				j.logger.Errorw("Reservation sweep failed", "error", err)
+			}
+		case <-ctx.Done():
+			j.logger.Info("Reservation sweeper stopped")
+			return
+		}
+	}
+}
+
+// Sweep releases expired reserves batch by batch until none are left and returns how many it released
+func (j *ReservationSweeper) Sweep(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		expired, err := j.store.ExpireReservations(ctx, j.batchSize)
+		// A failed batch may still have released some reserves before the error
+		for _, txn := range expired {
+			metrics.ReservationsExpiredTotal.Inc()
+			metrics.ReservedAmountExpiredTotal.Add(float64(txn.Amount))
+			j.logger.Warnw("Reservation expired, funds released",
+				"transactionID", txn.TransactionID,
+				"userID", txn.UserID,
+				"amount", txn.Amount,
+			)
+		}
+		total += len(expired)
+		if err != nil {
+			return total, err
+		}
+
+		if len(expired) < j.batchSize || ctx.Err() != nil {
+			break
+		}
+	}
+
+	if total > 0 {
+		j.logger.Infow("Reservation sweep completed", "expired", total)
+	}
+	return total, nil
+}
@@ -0,0 +1,110 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vsespontanno/eCommerce/pkg/logger"
+	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/domain/wallet/entity/wallet"
+	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/infrastructure/metrics"
+)
+
+func init() {
+	logger.InitLogger()
+}
+
+// MockReservationExpirer is a mock implementation of ReservationExpirer
+type MockReservationExpirer struct {
+	ExpireReservationsFunc func(ctx context.Context, limit int) ([]wallet.Transaction, error)
+}
+
+func (m *MockReservationExpirer) ExpireReservations(ctx context.Context, limit int) ([]wallet.Transaction, error) {
+	return m.ExpireReservationsFunc(ctx, limit)
+}
+
+func expiredTransactions(n int) []wallet.Transaction {
+	txns := make([]wallet.Transaction, n)
+	for i := range txns {
+		txns[i] = wallet.Transaction{TransactionID: "order-1", UserID: int64(i + 1), Amount: 500, Status: wallet.TransactionExpired}
+	}
+	return txns
+}
+
+func TestReservationSweeper_Sweep(t *testing.T) {
+	tests := []struct {
+		name          string
+		batches       [][]wallet.Transaction
+		failOn        int
+		expectedCount int
+		expectedCalls int
+		expectErr     bool
+	}{
+		{
+			name:          "Nothing Expired",
+			batches:       [][]wallet.Transaction{nil},
+			failOn:        -1,
+			expectedCount: 0,
+			expectedCalls: 1,
+		},
+		{
+			name:          "Full Batches Continue",
+			batches:       [][]wallet.Transaction{expiredTransactions(2), expiredTransactions(2), nil},
+			failOn:        -1,
+			expectedCount: 4,
+			expectedCalls: 3,
+		},
+		{
+			name:          "Error After Partial Release",
+			batches:       [][]wallet.Transaction{expiredTransactions(1)},
+			failOn:        0,
+			expectedCount: 1,
+			expectedCalls: 1,
+			expectErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			store := &MockReservationExpirer{
+				ExpireReservationsFunc: func(ctx context.Context, limit int) ([]wallet.Transaction, error) {
+					if limit != 2 {
+						t.Errorf("Expected limit 2, got %d", limit)
+					}
+					defer func() { calls++ }()
+					if calls == tt.failOn {
+						return tt.batches[calls], errors.New("db error")
+					}
+					return tt.batches[calls], nil
+				},
+			}
+			expiredBefore := testutil.ToFloat64(metrics.ReservationsExpiredTotal)
+			amountBefore := testutil.ToFloat64(metrics.ReservedAmountExpiredTotal)
+
+			sweeper := NewReservationSweeper(store, logger.Log, time.Minute, 2)
+			count, err := sweeper.Sweep(context.Background())
+
+			if tt.expectErr && err == nil {
+				t.Error("Expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("Unexpected error: %v", err)
+			}
+			if count != tt.expectedCount {
+				t.Errorf("Expected %d expired, got %d", tt.expectedCount, count)
+			}
+			if calls != tt.expectedCalls {
+				t.Errorf("Expected %d store calls, got %d", tt.expectedCalls, calls)
+			}
+			if got := testutil.ToFloat64(metrics.ReservationsExpiredTotal) - expiredBefore; got != float64(tt.expectedCount) {
+				t.Errorf("Expected expired metric +%d, got +%v", tt.expectedCount, got)
+			}
+			if got := testutil.ToFloat64(metrics.ReservedAmountExpiredTotal) - amountBefore; got != float64(500*tt.expectedCount) {
+				t.Errorf("Expected amount metric +%d, got +%v", 500*tt.expectedCount, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	ReservationsExpiredTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "wallet_reservations_expired_total",
+			Help: "Total number of wallet reserves released after their TTL expired",
+		},
+	)
+
+	ReservedAmountExpiredTotal = promauto.NewCounter(
+		prometheus.CounterOpts{
+			Name: "wallet_reserved_amount_expired_total",
+			Help: "Total reserved amount in kopecks returned to balances by the expiry sweeper",
+		},
+	)
+)
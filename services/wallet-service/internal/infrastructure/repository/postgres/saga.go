@@ -3,30 +3,43 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
+	"github.com/vsespontanno/eCommerce/pkg/events"
 	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/domain/wallet/entity/apperrors"
+	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/domain/wallet/entity/wallet"
 	"go.uber.org/zap"
 )
 
+// DefaultReservationTTL is how long reserved funds are held if the saga never commits or releases them
+const DefaultReservationTTL = 15 * time.Minute
+
 type SagaWalletStore struct {
 	*baseRepo
+	reservationTTL time.Duration
 }
 
-func NewSagaWalletStore(db *sql.DB, logger *zap.SugaredLogger) *SagaWalletStore {
+func NewSagaWalletStore(db *sql.DB, logger *zap.SugaredLogger, reservationTTL time.Duration) *SagaWalletStore {
+	if reservationTTL <= 0 {
+		reservationTTL = DefaultReservationTTL
+	}
 	return &SagaWalletStore{
 		baseRepo: &baseRepo{
 			db:      db,
 			builder: sq.StatementBuilder.PlaceholderFormat(sq.Dollar),
 			logger:  logger,
 		},
+		reservationTTL: reservationTTL,
 	}
 }
 
 // ReserveMoney reserves funds for a transaction
-// It checks if user has sufficient balance before reserving
-func (s *SagaWalletStore) ReserveMoney(ctx context.Context, userID int64, amount int64) error {
+// It checks if user has sufficient balance before reserving.
+// A repeated call with the same transactionID is a no-op.
+func (s *SagaWalletStore) ReserveMoney(ctx context.Context, userID int64, amount int64, transactionID string) error {
 	if amount <= 0 {
 		return fmt.Errorf("amount must be positive: %d", amount)
 	}
@@ -36,26 +49,24 @@ func (s *SagaWalletStore) ReserveMoney(ctx context.Context, userID int64, amount
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer func() {
-		if rbErr := tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
-			s.logger.Errorw("failed to rollback transaction", "error", rbErr)
-		}
-	}()
+	defer s.rollback(tx)
 
 	// Check if wallet exists and has sufficient balance
-	var balance, reserved int64
-	err = s.builder.Select("balance", "reserved").
-		From("wallets").
-		Where(sq.Eq{"user_id": userID}).
-		RunWith(tx).
-		QueryRowContext(ctx).
-		Scan(&balance, &reserved)
-
-	if err == sql.ErrNoRows {
-		return apperrors.ErrNoWallet
-	}
+	balance, reserved, err := s.lockWallet(ctx, tx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get wallet: %w", err)
+		return err
+	}
+
+	if transactionID != "" {
+		txn, err := s.getTransaction(ctx, tx, transactionID)
+		if err != nil {
+			return err
+		}
+		if txn != nil {
+			// Any recorded state means the reserve was already applied,
+			// or the transaction was released before the reserve arrived
+			return s.checkReplay(txn, userID, amount, "reserve")
+		}
 	}
 
 	availableBalance := balance - reserved
@@ -74,12 +85,14 @@ func (s *SagaWalletStore) ReserveMoney(ctx context.Context, userID int64, amount
 		return fmt.Errorf("failed to reserve funds: %w", err)
 	}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if err := checkRowsAffected(res); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		return apperrors.ErrNoWallet
+
+	if transactionID != "" {
+		if err := s.saveTransaction(ctx, tx, transactionID, userID, amount, wallet.TransactionReserved); err != nil {
+			return err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -90,7 +103,8 @@ func (s *SagaWalletStore) ReserveMoney(ctx context.Context, userID int64, amount
 }
 
 // CommitMoney commits reserved funds (final deduction)
-func (s *SagaWalletStore) CommitMoney(ctx context.Context, userID int64, amount int64) error {
+// A repeated call with the same transactionID is a no-op.
+func (s *SagaWalletStore) CommitMoney(ctx context.Context, userID int64, amount int64, transactionID string) error {
 	if amount <= 0 {
 		return fmt.Errorf("amount must be positive: %d", amount)
 	}
@@ -99,26 +113,33 @@ func (s *SagaWalletStore) CommitMoney(ctx context.Context, userID int64, amount
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer func() {
-		if rbErr := tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
-			s.logger.Errorw("failed to rollback transaction", "error", rbErr)
-		}
-	}()
+	defer s.rollback(tx)
 
 	// Verify wallet state before commit
-	var balance, reserved int64
-	err = s.builder.Select("balance", "reserved").
-		From("wallets").
-		Where(sq.Eq{"user_id": userID}).
-		RunWith(tx).
-		QueryRowContext(ctx).
-		Scan(&balance, &reserved)
-
-	if err == sql.ErrNoRows {
-		return apperrors.ErrNoWallet
-	}
+	balance, reserved, err := s.lockWallet(ctx, tx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get wallet: %w", err)
+		return err
+	}
+
+	if transactionID != "" {
+		txn, err := s.getTransaction(ctx, tx, transactionID)
+		if err != nil {
+			return err
+		}
+		if txn == nil {
+			return fmt.Errorf("%w: transaction %s was never reserved", apperrors.ErrInvalidTransactionState, transactionID)
+		}
+		switch txn.Status {
+		case wallet.TransactionCommitted, wallet.TransactionRefunded:
+			return s.checkReplay(txn, userID, amount, "commit")
+		case wallet.TransactionReleased:
+			return fmt.Errorf("%w: transaction %s is already released", apperrors.ErrInvalidTransactionState, transactionID)
+		case wallet.TransactionExpired:
+			return fmt.Errorf("%w: transaction %s reservation has expired", apperrors.ErrInvalidTransactionState, transactionID)
+		}
+		if err := checkSameParams(txn, userID, amount); err != nil {
+			return err
+		}
 	}
 
 	if reserved < amount {
@@ -140,12 +161,14 @@ func (s *SagaWalletStore) CommitMoney(ctx context.Context, userID int64, amount
 		return fmt.Errorf("failed to commit funds: %w", err)
 	}
 
-	rowsAffected, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if err := checkRowsAffected(res); err != nil {
+		return err
 	}
-	if rowsAffected == 0 {
-		return apperrors.ErrNoWallet
+
+	if transactionID != "" {
+		if err := s.saveTransaction(ctx, tx, transactionID, userID, amount, wallet.TransactionCommitted); err != nil {
+			return err
+		}
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -156,7 +179,9 @@ func (s *SagaWalletStore) CommitMoney(ctx context.Context, userID int64, amount
 }
 
 // ReleaseMoney releases previously reserved funds (rollback)
-func (s *SagaWalletStore) ReleaseMoney(ctx context.Context, userID int64, amount int64) error {
+// A repeated call with the same transactionID is a no-op. Releasing a transaction
+// that was never reserved records it as released, so a late reserve cannot leak funds.
+func (s *SagaWalletStore) ReleaseMoney(ctx context.Context, userID int64, amount int64, transactionID string) error {
 	if amount <= 0 {
 		return fmt.Errorf("amount must be positive: %d", amount)
 	}
@@ -165,26 +190,36 @@ func (s *SagaWalletStore) ReleaseMoney(ctx context.Context, userID int64, amount
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	defer func() {
-		if rbErr := tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
-			s.logger.Errorw("failed to rollback transaction", "error", rbErr)
-		}
-	}()
+	defer s.rollback(tx)
 
 	// Verify reserved amount before release
-	var reserved int64
-	err = s.builder.Select("reserved").
-		From("wallets").
-		Where(sq.Eq{"user_id": userID}).
-		RunWith(tx).
-		QueryRowContext(ctx).
-		Scan(&reserved)
-
-	if err == sql.ErrNoRows {
-		return apperrors.ErrNoWallet
-	}
+	_, reserved, err := s.lockWallet(ctx, tx, userID)
 	if err != nil {
-		return fmt.Errorf("failed to get wallet: %w", err)
+		return err
+	}
+
+	if transactionID != "" {
+		txn, err := s.getTransaction(ctx, tx, transactionID)
+		if err != nil {
+			return err
+		}
+		if txn == nil {
+			if err := s.saveTransaction(ctx, tx, transactionID, userID, amount, wallet.TransactionReleased); err != nil {
+				return err
+			}
+			return tx.Commit()
+		}
+		switch txn.Status {
+		case wallet.TransactionReleased, wallet.TransactionExpired, wallet.TransactionRefunded:
+			// An expired reserve was given back by the sweeper and a refunded one by the refund,
+			// so the saga compensating its reserve step after a refund has nothing left to release
+			return s.checkReplay(txn, userID, amount, "release")
+		case wallet.TransactionCommitted:
+			return fmt.Errorf("%w: transaction %s is already committed", apperrors.ErrInvalidTransactionState, transactionID)
+		}
+		if err := checkSameParams(txn, userID, amount); err != nil {
+			return err
+		}
 	}
 
 	if reserved < amount {
@@ -202,12 +237,169 @@ func (s *SagaWalletStore) ReleaseMoney(ctx context.Context, userID int64, amount
 		return fmt.Errorf("failed to release funds: %w", err)
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	if err := checkRowsAffected(res); err != nil {
+		return err
+	}
+
+	if transactionID != "" {
+		if err := s.saveTransaction(ctx, tx, transactionID, userID, amount, wallet.TransactionReleased); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RefundMoney credits committed funds back to the wallet (compensation after commit).
+// Only a COMMITTED transaction can be refunded; a repeated call is a no-op.
+func (s *SagaWalletStore) RefundMoney(ctx context.Context, userID int64, amount int64, transactionID string) error {
+	if amount <= 0 {
+		return fmt.Errorf("amount must be positive: %d", amount)
+	}
+	if transactionID == "" {
+		return apperrors.ErrMissingTransactionID
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
-	if rowsAffected == 0 {
-		return apperrors.ErrNoWallet
+	defer s.rollback(tx)
+
+	if _, _, err := s.lockWallet(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	txn, err := s.getTransaction(ctx, tx, transactionID)
+	if err != nil {
+		return err
+	}
+	if txn == nil {
+		return fmt.Errorf("%w: transaction %s was never committed", apperrors.ErrInvalidTransactionState, transactionID)
+	}
+	switch txn.Status {
+	case wallet.TransactionRefunded:
+		return s.checkReplay(txn, userID, amount, "refund")
+	case wallet.TransactionCommitted:
+	default:
+		return fmt.Errorf("%w: transaction %s is %s, not committed", apperrors.ErrInvalidTransactionState, transactionID, txn.Status)
+	}
+	if err := checkSameParams(txn, userID, amount); err != nil {
+		return err
+	}
+	// The rest of a partially refunded transaction is refunded partially as well
+	refunded, err := s.sumPartialRefunds(ctx, tx, transactionID)
+	if err != nil {
+		return err
+	}
+	if refunded > 0 {
+		return fmt.Errorf("%w: transaction %s is already partially refunded", apperrors.ErrInvalidTransactionState, transactionID)
+	}
+
+	// Refund: credit the committed amount back to balance
+	res, err := s.builder.Update("wallets").
+		Set("balance", sq.Expr("balance + ?", amount)).
+		Where(sq.Eq{"user_id": userID}).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	if err != nil {
+		return fmt.Errorf("failed to refund funds: %w", err)
+	}
+
+	if err := checkRowsAffected(res); err != nil {
+		return err
+	}
+
+	if err := s.saveTransaction(ctx, tx, transactionID, userID, amount, wallet.TransactionRefunded); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// RefundPartial credits part of a committed transaction back to the wallet, e.g. for returned items.
+// Each refund is recorded as its own REFUNDED transaction refundID linked to transactionID;
+// a repeated call with the same refundID is a no-op. Partial refunds together may not exceed
+// the committed amount, and a transaction refunded in full cannot be refunded partially.
+func (s *SagaWalletStore) RefundPartial(ctx context.Context, userID int64, amount int64, transactionID, refundID string) error {
+	if amount <= 0 {
+		return fmt.Errorf("amount must be positive: %d", amount)
+	}
+	if transactionID == "" || refundID == "" {
+		return apperrors.ErrMissingTransactionID
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer s.rollback(tx)
+
+	if _, _, err := s.lockWallet(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	parent, err := s.getTransaction(ctx, tx, transactionID)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return fmt.Errorf("%w: transaction %s was never committed", apperrors.ErrInvalidTransactionState, transactionID)
+	}
+	if parent.UserID != userID {
+		return apperrors.ErrTransactionMismatch
+	}
+
+	refund, err := s.getTransaction(ctx, tx, refundID)
+	if err != nil {
+		return err
+	}
+	if refund != nil {
+		if refund.ParentTransactionID != transactionID {
+			return apperrors.ErrTransactionMismatch
+		}
+		return s.checkReplay(refund, userID, amount, "partial refund")
+	}
+
+	if parent.Status != wallet.TransactionCommitted {
+		return fmt.Errorf("%w: transaction %s is %s, not committed", apperrors.ErrInvalidTransactionState, transactionID, parent.Status)
+	}
+	refunded, err := s.sumPartialRefunds(ctx, tx, transactionID)
+	if err != nil {
+		return err
+	}
+	if refunded+amount > parent.Amount {
+		return fmt.Errorf("%w: %d already refunded of %d, requested %d", apperrors.ErrRefundExceedsTransaction, refunded, parent.Amount, amount)
+	}
+
+	res, err := s.builder.Update("wallets").
+		Set("balance", sq.Expr("balance + ?", amount)).
+		Where(sq.Eq{"user_id": userID}).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refund funds: %w", err)
+	}
+	if err := checkRowsAffected(res); err != nil {
+		return err
+	}
+
+	_, err = s.builder.Insert("wallet_transactions").
+		Columns("transaction_id", "user_id", "amount", "status", "parent_transaction_id").
+		Values(refundID, userID, amount, wallet.TransactionRefunded, transactionID).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to save partial refund: %w", err)
 	}
 
 	if err := tx.Commit(); err != nil {
@@ -216,3 +408,245 @@ func (s *SagaWalletStore) ReleaseMoney(ctx context.Context, userID int64, amount
 
 	return nil
 }
+
+// sumPartialRefunds returns how much of the transaction was already refunded partially
+func (s *SagaWalletStore) sumPartialRefunds(ctx context.Context, tx *sql.Tx, transactionID string) (int64, error) {
+	var refunded int64
+	err := s.builder.Select("COALESCE(SUM(amount), 0)").
+		From("wallet_transactions").
+		Where(sq.Eq{"parent_transaction_id": transactionID}).
+		RunWith(tx).
+		QueryRowContext(ctx).
+		Scan(&refunded)
+	if err != nil {
+		return 0, fmt.Errorf("failed to sum partial refunds: %w", err)
+	}
+	return refunded, nil
+}
+
+// ExpireReservations releases up to limit reserves whose TTL has passed and writes
+// an outbox event for each of them. Every reserve is expired in its own transaction
+// that locks the wallet before the transaction record, the same order saga calls use.
+func (s *SagaWalletStore) ExpireReservations(ctx context.Context, limit int) ([]wallet.Transaction, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+
+	rows, err := s.builder.Select("transaction_id", "user_id").
+		From("wallet_transactions").
+		Where(sq.Eq{"status": wallet.TransactionReserved}).
+		Where(sq.Expr("expires_at < NOW()")).
+		OrderBy("expires_at").
+		Limit(uint64(limit)). // #nosec G115 - limit is checked to be positive above
+		RunWith(s.db).
+		QueryContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired reservations: %w", err)
+	}
+
+	var candidates []wallet.Transaction
+	for rows.Next() {
+		var txn wallet.Transaction
+		if err := rows.Scan(&txn.TransactionID, &txn.UserID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan expired reservation: %w", err)
+		}
+		candidates = append(candidates, txn)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read expired reservations: %w", err)
+	}
+
+	expired := make([]wallet.Transaction, 0, len(candidates))
+	for _, c := range candidates {
+		txn, err := s.expireTransaction(ctx, c.UserID, c.TransactionID)
+		if err != nil {
+			return expired, err
+		}
+		if txn != nil {
+			expired = append(expired, *txn)
+		}
+	}
+	return expired, nil
+}
+
+// expireTransaction releases one expired reserve. It returns nil if the saga
+// committed or released the transaction after it was picked up.
+func (s *SagaWalletStore) expireTransaction(ctx context.Context, userID int64, transactionID string) (*wallet.Transaction, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer s.rollback(tx)
+
+	if _, _, err := s.lockWallet(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+
+	txn, err := s.getTransaction(ctx, tx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if txn == nil || txn.Status != wallet.TransactionReserved || txn.ExpiresAt == nil || txn.ExpiresAt.After(now) {
+		return nil, nil
+	}
+
+	// GREATEST guards against a reserved counter that was already corrected by hand
+	_, err = s.builder.Update("wallets").
+		Set("reserved", sq.Expr("GREATEST(reserved - ?, 0)", txn.Amount)).
+		Where(sq.Eq{"user_id": txn.UserID}).
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to release expired funds: %w", err)
+	}
+
+	if err := s.saveTransaction(ctx, tx, transactionID, txn.UserID, txn.Amount, wallet.TransactionExpired); err != nil {
+		return nil, err
+	}
+	if err := s.saveExpiredEvent(ctx, tx, txn, now); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	txn.Status = wallet.TransactionExpired
+	return txn, nil
+}
+
+// saveExpiredEvent writes the expiry event to the outbox within the same transaction
+func (s *SagaWalletStore) saveExpiredEvent(ctx context.Context, tx *sql.Tx, txn *wallet.Transaction, expiredAt time.Time) error {
+	// A reserve expires once, so the transaction ID identifies the event
+	envelope, err := events.New(txn.TransactionID+":"+wallet.EventTypeReservationExpired, wallet.EventTypeReservationExpired,
+		wallet.EventSource, wallet.EventSchemaVersion, expiredAt, wallet.ReservationExpiredEvent{
+			TransactionID: txn.TransactionID,
+			UserID:        txn.UserID,
+			Amount:        txn.Amount,
+			ExpiresAt:     *txn.ExpiresAt,
+			ExpiredAt:     expiredAt,
+			EventType:     wallet.EventTypeReservationExpired,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to wrap expiry event: %w", err)
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("failed to marshal expiry event: %w", err)
+	}
+
+	_, err = s.builder.Insert("outbox").
+		Columns("aggregate_id", "aggregate_type", "event_type", "payload", "status").
+		Values(txn.TransactionID, wallet.OutboxAggregateTransaction, wallet.EventTypeReservationExpired, payload, "pending").
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to save expiry event: %w", err)
+	}
+	return nil
+}
+
+// lockWallet reads the wallet row and locks it until the end of the transaction,
+// so concurrent saga calls for the same user are serialized
+func (s *SagaWalletStore) lockWallet(ctx context.Context, tx *sql.Tx, userID int64) (balance int64, reserved int64, err error) {
+	err = s.builder.Select("balance", "reserved").
+		From("wallets").
+		Where(sq.Eq{"user_id": userID}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRowContext(ctx).
+		Scan(&balance, &reserved)
+
+	if err == sql.ErrNoRows {
+		return 0, 0, apperrors.ErrNoWallet
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get wallet: %w", err)
+	}
+	return balance, reserved, nil
+}
+
+// getTransaction returns the recorded transaction or nil if there is none
+func (s *SagaWalletStore) getTransaction(ctx context.Context, tx *sql.Tx, transactionID string) (*wallet.Transaction, error) {
+	txn := wallet.Transaction{TransactionID: transactionID}
+	var expiresAt sql.NullTime
+	var parentID sql.NullString
+	err := s.builder.Select("user_id", "amount", "status", "expires_at", "parent_transaction_id").
+		From("wallet_transactions").
+		Where(sq.Eq{"transaction_id": transactionID}).
+		Suffix("FOR UPDATE").
+		RunWith(tx).
+		QueryRowContext(ctx).
+		Scan(&txn.UserID, &txn.Amount, &txn.Status, &expiresAt, &parentID)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get wallet transaction: %w", err)
+	}
+	if expiresAt.Valid {
+		txn.ExpiresAt = &expiresAt.Time
+	}
+	txn.ParentTransactionID = parentID.String
+	return &txn, nil
+}
+
+// saveTransaction inserts or moves the transaction record to the given status.
+// Only a RESERVED transaction carries a deadline for the expiry sweeper.
+func (s *SagaWalletStore) saveTransaction(ctx context.Context, tx *sql.Tx, transactionID string, userID int64, amount int64, status wallet.TransactionStatus) error {
+	var expiresAt sql.NullTime
+	if status == wallet.TransactionReserved {
+		expiresAt = sql.NullTime{Time: time.Now().Add(s.reservationTTL), Valid: true}
+	}
+	_, err := s.builder.Insert("wallet_transactions").
+		Columns("transaction_id", "user_id", "amount", "status", "expires_at").
+		Values(transactionID, userID, amount, status, expiresAt).
+		Suffix("ON CONFLICT (transaction_id) DO UPDATE SET status = EXCLUDED.status, expires_at = EXCLUDED.expires_at, updated_at = NOW()").
+		RunWith(tx).
+		ExecContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to save wallet transaction: %w", err)
+	}
+	return nil
+}
+
+// checkReplay validates a repeated call and reports it as the original successful result
+func (s *SagaWalletStore) checkReplay(txn *wallet.Transaction, userID int64, amount int64, operation string) error {
+	if err := checkSameParams(txn, userID, amount); err != nil {
+		return err
+	}
+	s.logger.Infow("Idempotent replay, skipping",
+		"operation", operation,
+		"transactionID", txn.TransactionID,
+		"status", txn.Status,
+	)
+	return nil
+}
+
+func (s *SagaWalletStore) rollback(tx *sql.Tx) {
+	if rbErr := tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
+		s.logger.Errorw("failed to rollback transaction", "error", rbErr)
+	}
+}
+
+func checkSameParams(txn *wallet.Transaction, userID int64, amount int64) error {
+	if txn.UserID != userID || txn.Amount != amount {
+		return apperrors.ErrTransactionMismatch
+	}
+	return nil
+}
+
+func checkRowsAffected(res sql.Result) error {
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return apperrors.ErrNoWallet
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/vsespontanno/eCommerce/proto/wallet"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
@@ -67,6 +68,9 @@ func (g *Gateway) Start(ctx context.Context) error {
 	// Register health check endpoint
 	httpMux.HandleFunc("/health", g.healthCheck)
 
+	// Register Prometheus metrics endpoint
+	httpMux.Handle("/metrics", promhttp.Handler())
+
 	// Create HTTP server with production-ready timeouts
 	g.httpServer = &http.Server{
 		Addr:         fmt.Sprintf(":%d", g.httpPort),
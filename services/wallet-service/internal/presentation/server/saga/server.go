@@ -2,6 +2,7 @@ package saga
 
 import (
 	"context"
+	"errors"
 
 	proto "github.com/vsespontanno/eCommerce/proto/wallet"
 	"github.com/vsespontanno/eCommerce/services/wallet-service/internal/domain/wallet/entity/apperrors"
@@ -18,9 +19,10 @@ type WalletSagaServer struct {
 }
 
 type Wallet interface {
-	Reserve(ctx context.Context, userID int64, amount int64) error
-	Release(ctx context.Context, userID int64, amount int64) error
-	Commit(ctx context.Context, userID int64, amount int64) error
+	Reserve(ctx context.Context, userID int64, amount int64, transactionID string) error
+	Release(ctx context.Context, userID int64, amount int64, transactionID string) error
+	Commit(ctx context.Context, userID int64, amount int64, transactionID string) error
+	Refund(ctx context.Context, userID int64, amount int64, transactionID, refundID string) error
 }
 
 func NewWalletSagaServer(gRPCServer *grpc.Server, sagaWallet Wallet, logger *zap.SugaredLogger) {
@@ -34,78 +36,108 @@ func NewWalletSagaServer(gRPCServer *grpc.Server, sagaWallet Wallet, logger *zap
 }
 
 func (s *WalletSagaServer) ReserveFunds(ctx context.Context, req *proto.ReserveFundsRequest) (*proto.ReserveFundsResponse, error) {
-	err := s.sagaWallet.Reserve(ctx, req.UserId, req.Amount)
+	err := s.sagaWallet.Reserve(ctx, req.UserId, req.Amount, req.TransactionId)
 	if err != nil {
 		s.logger.Errorw("ReserveFunds failed",
 			"userID", req.UserId,
 			"amount", req.Amount,
+			"transactionID", req.TransactionId,
 			"error", err,
 		)
 
-		switch err {
-		case apperrors.ErrNoWallet:
-			return nil, status.Error(codes.NotFound, "wallet not found")
-		case apperrors.ErrInsufficientFunds:
-			return nil, status.Error(codes.FailedPrecondition, "insufficient funds")
-		default:
-			return nil, status.Error(codes.Internal, "failed to reserve funds")
-		}
+		return nil, sagaError(err, "failed to reserve funds")
 	}
 
 	s.logger.Infow("ReserveFunds success",
 		"userID", req.UserId,
 		"amount", req.Amount,
+		"transactionID", req.TransactionId,
 	)
 
 	return &proto.ReserveFundsResponse{Success: true}, nil
 }
 
 func (s *WalletSagaServer) ReleaseFunds(ctx context.Context, req *proto.ReleaseFundsRequest) (*proto.ReleaseFundsResponse, error) {
-	err := s.sagaWallet.Release(ctx, req.UserId, req.Amount)
+	err := s.sagaWallet.Release(ctx, req.UserId, req.Amount, req.TransactionId)
 	if err != nil {
 		s.logger.Errorw("ReleaseFunds failed",
 			"userID", req.UserId,
 			"amount", req.Amount,
+			"transactionID", req.TransactionId,
 			"error", err,
 		)
 
-		switch err {
-		case apperrors.ErrNoWallet:
-			return nil, status.Error(codes.NotFound, "wallet not found")
-		default:
-			return nil, status.Error(codes.Internal, "failed to release funds")
-		}
+		return nil, sagaError(err, "failed to release funds")
 	}
 
 	s.logger.Infow("ReleaseFunds success",
 		"userID", req.UserId,
 		"amount", req.Amount,
+		"transactionID", req.TransactionId,
 	)
 
 	return &proto.ReleaseFundsResponse{Success: true}, nil
 }
 
 func (s *WalletSagaServer) CommitFunds(ctx context.Context, req *proto.CommitFundsRequest) (*proto.CommitFundsResponse, error) {
-	err := s.sagaWallet.Commit(ctx, req.UserId, req.Amount)
+	err := s.sagaWallet.Commit(ctx, req.UserId, req.Amount, req.TransactionId)
 	if err != nil {
 		s.logger.Errorw("CommitFunds failed",
 			"userID", req.UserId,
 			"amount", req.Amount,
+			"transactionID", req.TransactionId,
 			"error", err,
 		)
 
-		switch err {
-		case apperrors.ErrNoWallet:
-			return nil, status.Error(codes.NotFound, "wallet not found")
-		default:
-			return nil, status.Error(codes.Internal, "failed to commit funds")
-		}
+		return nil, sagaError(err, "failed to commit funds")
 	}
 
 	s.logger.Infow("CommitFunds success",
 		"userID", req.UserId,
 		"amount", req.Amount,
+		"transactionID", req.TransactionId,
 	)
 
 	return &proto.CommitFundsResponse{Success: true}, nil
 }
+
+func (s *WalletSagaServer) RefundFunds(ctx context.Context, req *proto.RefundFundsRequest) (*proto.RefundFundsResponse, error) {
+	err := s.sagaWallet.Refund(ctx, req.UserId, req.Amount, req.TransactionId, req.RefundId)
+	if err != nil {
+		s.logger.Errorw("RefundFunds failed",
+			"userID", req.UserId,
+			"amount", req.Amount,
+			"transactionID", req.TransactionId,
+			"refundID", req.RefundId,
+			"error", err,
+		)
+
+		return nil, sagaError(err, "failed to refund funds")
+	}
+
+	s.logger.Infow("RefundFunds success",
+		"userID", req.UserId,
+		"amount", req.Amount,
+		"transactionID", req.TransactionId,
+	)
+
+	return &proto.RefundFundsResponse{Success: true}, nil
+}
+
+// sagaError maps domain errors to gRPC status codes; internalMsg is used for unexpected errors
+func sagaError(err error, internalMsg string) error {
+	switch {
+	case errors.Is(err, apperrors.ErrNoWallet):
+		return status.Error(codes.NotFound, "wallet not found")
+	case errors.Is(err, apperrors.ErrInsufficientFunds):
+		return status.Error(codes.FailedPrecondition, "insufficient funds")
+	case errors.Is(err, apperrors.ErrTransactionMismatch):
+		return status.Error(codes.InvalidArgument, apperrors.ErrTransactionMismatch.Error())
+	case errors.Is(err, apperrors.ErrMissingTransactionID):
+		return status.Error(codes.InvalidArgument, apperrors.ErrMissingTransactionID.Error())
+	case errors.Is(err, apperrors.ErrInvalidTransactionState), errors.Is(err, apperrors.ErrRefundExceedsTransaction):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, internalMsg)
+	}
+}
@@ -48,12 +48,15 @@ func (c *ProductsClient) GetProducts(ctx context.Context) ([]Product, error) {
 		return nil, fmt.Errorf("get products failed with status: %d", resp.StatusCode)
 	}
 
-	var products []Product
-	if err := json.NewDecoder(resp.Body).Decode(&products); err != nil {
+	// Каталог отдаётся страницами; сценариям хватает первой
+	var page struct {
+		Products []Product `json:"products"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
 		return nil, err
 	}
 
-	return products, nil
+	return page.Products, nil
 }
 
 func (c *ProductsClient) GetProduct(ctx context.Context, id int64) (*Product, error) {